@@ -1,11 +1,12 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"reflect"
+	"strconv"
 
-	"github.com/YaCodeDev/GoYaCodeDevUtils/valueparser"
 	"github.com/YaCodeDev/GoYaCodeDevUtils/yaerrors"
 	"github.com/YaCodeDev/GoYaCodeDevUtils/yalogger"
 )
@@ -17,6 +18,21 @@ import (
 // If a field is required and not set, it logs an error and exits the program.
 // It supports various field types including maps, slices, and basic types (int, uint, float, bool, string)
 // and their derivatives. Same value parsing capabilities apply to default tag values.
+// Any parsable key and value type works, including time.Duration, url.URL, custom
+// Unmarshalable or encoding.TextUnmarshaler types (net.IP, time.Time) and nested lists,
+// which split by the next separator of ListSeparators ("a;b,c;d" for [][]string). A slice
+// of structs is read from indexed keys: UPSTREAMS_0_NAME, UPSTREAMS_1_NAME and so on.
+//
+// Field tags tune the lookup:
+//   - env:"NAME" replaces the key derived from the field name;
+//   - envPrefix:"DB" replaces the key prefix of a nested struct, envPrefix:"" flattens it;
+//   - required:"true" / optional:"true" override the zero-without-default rule;
+//   - secret:"true" redacts the value in logs and reads it from the file named by
+//     <KEY>_FILE when <KEY> itself is unset;
+//   - min, max, oneof, regex, url and email validate the loaded value.
+//
+// Loading does not stop at the first bad field: every missing, unparsable or invalid
+// field is collected into FieldErrors, joined with ErrInvalidConfig.
 //
 // This is a wrapper around LoadConfigStructFromEnvHandlingError that panics on error.
 //
@@ -45,6 +61,11 @@ import (
 //		DefaultMap    map[bool]float64 `default:"true:1.0,false:2.1"`
 //		Level         logrus.Level     `default:"info"`
 //		AlsoLevel     logrus.Level     `default:"4"`
+//		Database      SubConfig        `envPrefix:"DB"`
+//		Port          int              `env:"HTTP_PORT" optional:"true"`
+//		APIToken      string           `secret:"true"`
+//		Workers       int              `default:"4" min:"1" max:"64"`
+//		Mode          string           `default:"dev" oneof:"dev prod"`
 //	}
 //
 //	config := Config{
@@ -68,6 +89,21 @@ func LoadConfigStructFromEnv[T any](instance *T, log yalogger.Logger) {
 // If a field is required and not set, it logs an error and exits the program.
 // It supports various field types including maps, slices, and basic types (int, uint, float, bool, string)
 // and their derivatives. Same value parsing capabilities apply to default tag values.
+// Any parsable key and value type works, including time.Duration, url.URL, custom
+// Unmarshalable or encoding.TextUnmarshaler types (net.IP, time.Time) and nested lists,
+// which split by the next separator of ListSeparators ("a;b,c;d" for [][]string). A slice
+// of structs is read from indexed keys: UPSTREAMS_0_NAME, UPSTREAMS_1_NAME and so on.
+//
+// Field tags tune the lookup:
+//   - env:"NAME" replaces the key derived from the field name;
+//   - envPrefix:"DB" replaces the key prefix of a nested struct, envPrefix:"" flattens it;
+//   - required:"true" / optional:"true" override the zero-without-default rule;
+//   - secret:"true" redacts the value in logs and reads it from the file named by
+//     <KEY>_FILE when <KEY> itself is unset;
+//   - min, max, oneof, regex, url and email validate the loaded value.
+//
+// Loading does not stop at the first bad field: every missing, unparsable or invalid
+// field is collected into FieldErrors, joined with ErrInvalidConfig.
 //
 // Example usage:
 //
@@ -94,6 +130,11 @@ func LoadConfigStructFromEnv[T any](instance *T, log yalogger.Logger) {
 //		DefaultMap    map[bool]float64 `default:"true:1.0,false:2.1"`
 //		Level         logrus.Level     `default:"info"`
 //		AlsoLevel     logrus.Level     `default:"4"`
+//		Database      SubConfig        `envPrefix:"DB"`
+//		Port          int              `env:"HTTP_PORT" optional:"true"`
+//		APIToken      string           `secret:"true"`
+//		Workers       int              `default:"4" min:"1" max:"64"`
+//		Mode          string           `default:"dev" oneof:"dev prod"`
 //	}
 //
 //	config := Config{
@@ -114,7 +155,9 @@ func LoadConfigStructFromEnvHandlingError[T any](instance *T, log yalogger.Logge
 		log.Warnf("Error loading .env file: %v", err)
 	}
 
-	return loadConfigStructValue(instance, log)
+	_, err := loadConfigStructValue(instance, nil, log)
+
+	return err
 }
 
 // LoadConfigStructFromEnvWithYaTools loads a configuration struct from the environment
@@ -139,15 +182,64 @@ func LoadConfigStructFromEnvWithYaTools[T any](
 		log.Warnf("Error loading .yatools config for %q: %v", name, err)
 	}
 
-	return loadConfigStructValue(instance, log)
+	_, err := loadConfigStructValue(instance, nil, log)
+
+	return err
+}
+
+// LoadConfigStructFromSources loads a configuration struct like
+// LoadConfigStructFromEnvHandlingError, but reads the values from sources instead of the
+// process environment. Sources are listed lowest precedence first and each one overrides
+// the keys of those before it; default tags sit below all of them. DefaultSources returns
+// the usual order: YAML, TOML and JSON files, .env, the environment and flags.
+//
+// The process environment is neither read nor modified unless NewEnvSource is one of the
+// sources. The returned Origins reports which source every loaded field came from; it is
+// filled even when some fields fail to load.
+//
+// Example usage:
+//
+//	var cfg Config
+//
+//	origins, err := config.LoadConfigStructFromSources(
+//		&cfg,
+//		log,
+//		config.NewMapSource("defaults", map[string]string{"WORKERS": "8"}),
+//		config.NewYAMLFileSource("/etc/app/config.yaml"),
+//		config.NewEnvSource(),
+//		config.NewFlagSource(os.Args[1:]),
+//	)
+//	if err != nil {
+//		// handle error
+//	}
+//
+//	log.Infof("DB_HOST is set by %s", origins["DB_HOST"])
+func LoadConfigStructFromSources[T any](
+	instance *T,
+	log yalogger.Logger,
+	sources ...Source,
+) (Origins, yaerrors.Error) {
+	safetyCheck(&log)
+
+	layers, err := newLayeredValues(sources)
+	if err != nil {
+		return nil, err.WrapWithLog("config loader", log)
+	}
+
+	return loadConfigStructValue(instance, layers, log)
 }
 
-// loadConfigStructValue validates that instance points at a struct and loads it from the
-// environment. It assumes .env and any other overlays have already been applied.
-func loadConfigStructValue[T any](instance *T, log yalogger.Logger) yaerrors.Error {
+// loadConfigStructValue validates that instance points at a struct and loads it from
+// layers, or from the environment when layers is nil. It assumes .env and any other
+// overlays have already been applied.
+func loadConfigStructValue[T any](
+	instance *T,
+	layers *layeredValues,
+	log yalogger.Logger,
+) (Origins, yaerrors.Error) {
 	value := reflect.ValueOf(instance).Elem()
 	if value.Kind() != reflect.Struct {
-		return yaerrors.FromErrorWithLog(
+		return nil, yaerrors.FromErrorWithLog(
 			http.StatusInternalServerError,
 			ErrConfigStructMustBeStruct,
 			fmt.Sprintf(
@@ -158,25 +250,38 @@ func loadConfigStructValue[T any](instance *T, log yalogger.Logger) yaerrors.Err
 		)
 	}
 
-	return loadConfigStructFromEnv(value, "", log)
+	var report loadReport
+
+	loadConfigStructFromEnv(value, "", layers, &report, log)
+
+	if len(report.errors) > 0 {
+		return report.origins, yaerrors.FromErrorWithLog(
+			http.StatusInternalServerError,
+			errors.Join(ErrInvalidConfig, report.errors),
+			fmt.Sprintf("config loader: invalid fields: %d", len(report.errors)),
+			log,
+		)
+	}
+
+	return report.origins, nil
 }
 
 // Internal function to load config struct from environment variables.
 // It recursively processes each field of the struct, checking for the presence of environment variables.
-// Does the actual work of LoadConfigStructFromEnv.
+// Does the actual work of LoadConfigStructFromEnv. Every field that is missing, unparsable or
+// invalid is added to report and the remaining fields are still loaded.
 func loadConfigStructFromEnv(
 	structValue reflect.Value,
 	keyPath string,
+	layers *layeredValues,
+	report *loadReport,
 	log yalogger.Logger,
-) yaerrors.Error {
+) {
 	structType := structValue.Type()
 
-	var err yaerrors.Error
-
 	for i := range structValue.NumField() {
 		field := structType.Field(i)
 		fieldVal := structValue.Field(i)
-		defaultValStr := field.Tag.Get(DefaultTagName)
 
 		if !fieldVal.CanSet() {
 			log.Warnf("Field %s cannot be set", field.Name)
@@ -184,1455 +289,133 @@ func loadConfigStructFromEnv(
 			continue
 		}
 
-		envKey := toScreamingSnakeCase(field.Name)
+		options, err := parseFieldOptions(&field, fieldVal, keyPath, layers)
+		if err != nil {
+			report.add(joinEnvKey(keyPath, toScreamingSnakeCase(field.Name)), err)
 
-		if keyPath != "" {
-			envKey = fmt.Sprintf(
-				"%s_%s",
-				keyPath,
-				envKey,
-			)
+			continue
 		}
 
-		required := fieldVal.IsZero() && defaultValStr == ""
-
-		useDefaultFromTag := fieldVal.IsZero() && defaultValStr != ""
-
-		switch field.Type.Kind() {
-		case reflect.Struct:
-			if err = loadConfigStructFromEnv(fieldVal, envKey, log); err != nil {
-				return err.WrapWithLog(
-					"failed to load struct field "+field.Name,
-					log,
-				)
-			}
-		case reflect.Map:
-			mapType := getMapType(fieldVal)
-			switch mapType {
-			case stringStringMap:
-				if useDefaultFromTag {
-					var val map[string]string
-
-					val, err = valueparser.ParseMap[string, string](defaultValStr, nil, nil)
-					if err != nil {
-						return err.WrapWithLog(
-							fmt.Sprintf(
-								"config loader: field %s default: %v",
-								field.Name,
-								err,
-							),
-							log,
-						)
-					}
-
-					copyMap(reflect.ValueOf(val), fieldVal)
-				}
-
-				mapCopy := make(map[string]string)
-				copyMap(fieldVal, reflect.ValueOf(mapCopy))
-
-				mapCopy, err = GetEnvMapWithCustomType(
-					envKey,
-					mapCopy,
-					required,
-					nil,
-					nil,
-					fieldVal.Type().Key(),
-					fieldVal.Type().Elem(),
-					log,
-				)
-				if err != nil {
-					return err.WrapWithLog(
-						"load config struct from env",
-						log,
-					)
-				}
-
-				copyMap(reflect.ValueOf(mapCopy), fieldVal)
-
-			case stringIntMap:
-				if useDefaultFromTag {
-					var val map[string]int64
-
-					val, err = valueparser.ParseMap[string, int64](defaultValStr, nil, nil)
-					if err != nil {
-						return err.WrapWithLog(
-							fmt.Sprintf(
-								"config loader: field %s default: %v",
-								field.Name,
-								err,
-							),
-							log,
-						)
-					}
-
-					copyMap(reflect.ValueOf(val), fieldVal)
-				}
-
-				mapCopy := make(map[string]int64)
-				copyMap(fieldVal, reflect.ValueOf(mapCopy))
-
-				mapCopy, err = GetEnvMapWithCustomType(
-					envKey,
-					mapCopy,
-					required,
-					nil,
-					nil,
-					fieldVal.Type().Key(),
-					fieldVal.Type().Elem(),
-					log,
-				)
-				if err != nil {
-					return err.WrapWithLog(
-						"load config struct from env",
-						log,
-					)
-				}
-
-				copyMap(reflect.ValueOf(mapCopy), fieldVal)
-
-			case stringUintMap:
-				if useDefaultFromTag {
-					var val map[string]uint64
-
-					val, err = valueparser.ParseMap[string, uint64](defaultValStr, nil, nil)
-					if err != nil {
-						return err.WrapWithLog(
-							fmt.Sprintf(
-								"config loader: field %s default: %v",
-								field.Name,
-								err,
-							),
-							log,
-						)
-					}
-
-					copyMap(reflect.ValueOf(val), fieldVal)
-				}
-
-				mapCopy := make(map[string]uint64)
-				copyMap(fieldVal, reflect.ValueOf(mapCopy))
-
-				mapCopy, err = GetEnvMapWithCustomType(
-					envKey,
-					mapCopy,
-					required,
-					nil,
-					nil,
-					fieldVal.Type().Key(),
-					fieldVal.Type().Elem(),
-					log,
-				)
-				if err != nil {
-					return err.WrapWithLog(
-						"load config struct from env",
-						log,
-					)
-				}
-
-				copyMap(reflect.ValueOf(mapCopy), fieldVal)
-
-			case stringFloatMap:
-				if useDefaultFromTag {
-					var val map[string]float64
-
-					val, err = valueparser.ParseMap[string, float64](defaultValStr, nil, nil)
-					if err != nil {
-						return err.WrapWithLog(
-							fmt.Sprintf(
-								"config loader: field %s default: %v",
-								field.Name,
-								err,
-							),
-							log,
-						)
-					}
-
-					copyMap(reflect.ValueOf(val), fieldVal)
-				}
-
-				mapCopy := make(map[string]float64)
-				copyMap(fieldVal, reflect.ValueOf(mapCopy))
-
-				mapCopy, err = GetEnvMapWithCustomType(
-					envKey,
-					mapCopy,
-					required,
-					nil,
-					nil,
-					fieldVal.Type().Key(),
-					fieldVal.Type().Elem(),
-					log,
-				)
-				if err != nil {
-					return err.WrapWithLog(
-						"load config struct from env",
-						log,
-					)
-				}
-
-				copyMap(reflect.ValueOf(mapCopy), fieldVal)
-
-			case stringBoolMap:
-				if useDefaultFromTag {
-					var val map[string]bool
-
-					val, err = valueparser.ParseMap[string, bool](defaultValStr, nil, nil)
-					if err != nil {
-						return err.WrapWithLog(
-							fmt.Sprintf(
-								"config loader: field %s default: %v",
-								field.Name,
-								err,
-							),
-							log,
-						)
-					}
-
-					copyMap(reflect.ValueOf(val), fieldVal)
-				}
-
-				mapCopy := make(map[string]bool)
-				copyMap(fieldVal, reflect.ValueOf(mapCopy))
-
-				mapCopy, err = GetEnvMapWithCustomType(
-					envKey,
-					mapCopy,
-					required,
-					nil,
-					nil,
-					fieldVal.Type().Key(),
-					fieldVal.Type().Elem(),
-					log,
-				)
-				if err != nil {
-					return err.WrapWithLog(
-						"load config struct from env",
-						log,
-					)
-				}
-
-				copyMap(reflect.ValueOf(mapCopy), fieldVal)
-
-			case intStringMap:
-				if useDefaultFromTag {
-					var val map[int64]string
-
-					val, err = valueparser.ParseMap[int64, string](defaultValStr, nil, nil)
-					if err != nil {
-						return err.WrapWithLog(
-							fmt.Sprintf(
-								"config loader: field %s default: %v",
-								field.Name,
-								err,
-							),
-							log,
-						)
-					}
-
-					copyMap(reflect.ValueOf(val), fieldVal)
-				}
-
-				mapCopy := make(map[int64]string)
-				copyMap(fieldVal, reflect.ValueOf(mapCopy))
-
-				mapCopy, err = GetEnvMapWithCustomType(
-					envKey,
-					mapCopy,
-					required,
-					nil,
-					nil,
-					fieldVal.Type().Key(),
-					fieldVal.Type().Elem(),
-					log,
-				)
-				if err != nil {
-					return err.WrapWithLog(
-						"load config struct from env",
-						log,
-					)
-				}
-
-				copyMap(reflect.ValueOf(mapCopy), fieldVal)
-
-			case intIntMap:
-				if useDefaultFromTag {
-					var val map[int64]int64
-
-					val, err = valueparser.ParseMap[int64, int64](defaultValStr, nil, nil)
-					if err != nil {
-						return err.WrapWithLog(
-							fmt.Sprintf(
-								"config loader: field %s default: %v",
-								field.Name,
-								err,
-							),
-							log,
-						)
-					}
-
-					copyMap(reflect.ValueOf(val), fieldVal)
-				}
-
-				mapCopy := make(map[int64]int64)
-				copyMap(fieldVal, reflect.ValueOf(mapCopy))
-
-				mapCopy, err = GetEnvMapWithCustomType(
-					envKey,
-					mapCopy,
-					required,
-					nil,
-					nil,
-					fieldVal.Type().Key(),
-					fieldVal.Type().Elem(),
-					log,
-				)
-				if err != nil {
-					return err.WrapWithLog(
-						"load config struct from env",
-						log,
-					)
-				}
-
-				copyMap(reflect.ValueOf(mapCopy), fieldVal)
-
-			case intUintMap:
-				if useDefaultFromTag {
-					var val map[int64]uint64
-
-					val, err = valueparser.ParseMap[int64, uint64](defaultValStr, nil, nil)
-					if err != nil {
-						return err.WrapWithLog(
-							fmt.Sprintf(
-								"config loader: field %s default: %v",
-								field.Name,
-								err,
-							),
-							log,
-						)
-					}
-
-					copyMap(reflect.ValueOf(val), fieldVal)
-				}
-
-				mapCopy := make(map[int64]uint64)
-				copyMap(fieldVal, reflect.ValueOf(mapCopy))
-
-				mapCopy, err = GetEnvMapWithCustomType(
-					envKey,
-					mapCopy,
-					required,
-					nil,
-					nil,
-					fieldVal.Type().Key(),
-					fieldVal.Type().Elem(),
-					log,
-				)
-				if err != nil {
-					return err.WrapWithLog(
-						"load config struct from env",
-						log,
-					)
-				}
-
-				copyMap(reflect.ValueOf(mapCopy), fieldVal)
-
-			case intFloatMap:
-				if useDefaultFromTag {
-					var val map[int64]float64
-
-					val, err = valueparser.ParseMap[int64, float64](defaultValStr, nil, nil)
-					if err != nil {
-						return err.WrapWithLog(
-							fmt.Sprintf(
-								"config loader: field %s default: %v",
-								field.Name,
-								err,
-							),
-							log,
-						)
-					}
-
-					copyMap(reflect.ValueOf(val), fieldVal)
-				}
-
-				mapCopy := make(map[int64]float64)
-				copyMap(fieldVal, reflect.ValueOf(mapCopy))
-
-				mapCopy, err = GetEnvMapWithCustomType(
-					envKey,
-					mapCopy,
-					required,
-					nil,
-					nil,
-					fieldVal.Type().Key(),
-					fieldVal.Type().Elem(),
-					log,
-				)
-				if err != nil {
-					return err.WrapWithLog(
-						"load config struct from env",
-						log,
-					)
-				}
-
-				copyMap(reflect.ValueOf(mapCopy), fieldVal)
-
-			case intBoolMap:
-				if useDefaultFromTag {
-					var val map[int64]bool
-
-					val, err = valueparser.ParseMap[int64, bool](defaultValStr, nil, nil)
-					if err != nil {
-						return err.WrapWithLog(
-							fmt.Sprintf(
-								"config loader: field %s default: %v",
-								field.Name,
-								err,
-							),
-							log,
-						)
-					}
-
-					copyMap(reflect.ValueOf(val), fieldVal)
-				}
-
-				mapCopy := make(map[int64]bool)
-				copyMap(fieldVal, reflect.ValueOf(mapCopy))
-
-				mapCopy, err = GetEnvMapWithCustomType(
-					envKey,
-					mapCopy,
-					required,
-					nil,
-					nil,
-					fieldVal.Type().Key(),
-					fieldVal.Type().Elem(),
-					log,
-				)
-				if err != nil {
-					return err.WrapWithLog(
-						"load config struct from env",
-						log,
-					)
-				}
-
-				copyMap(reflect.ValueOf(mapCopy), fieldVal)
-
-			case uintStringMap:
-				if useDefaultFromTag {
-					var val map[uint64]string
-
-					val, err = valueparser.ParseMap[uint64, string](defaultValStr, nil, nil)
-					if err != nil {
-						return err.WrapWithLog(
-							fmt.Sprintf(
-								"config loader: field %s default: %v",
-								field.Name,
-								err,
-							),
-							log,
-						)
-					}
-
-					copyMap(reflect.ValueOf(val), fieldVal)
-				}
-
-				mapCopy := make(map[uint64]string)
-				copyMap(fieldVal, reflect.ValueOf(mapCopy))
-
-				mapCopy, err = GetEnvMapWithCustomType(
-					envKey,
-					mapCopy,
-					required,
-					nil,
-					nil,
-					fieldVal.Type().Key(),
-					fieldVal.Type().Elem(),
-					log,
-				)
-				if err != nil {
-					return err.WrapWithLog(
-						"load config struct from env",
-						log,
-					)
-				}
-
-				copyMap(reflect.ValueOf(mapCopy), fieldVal)
-
-			case uintIntMap:
-				if useDefaultFromTag {
-					var val map[uint64]int64
-
-					val, err = valueparser.ParseMap[uint64, int64](defaultValStr, nil, nil)
-					if err != nil {
-						return err.WrapWithLog(
-							fmt.Sprintf(
-								"config loader: field %s default: %v",
-								field.Name,
-								err,
-							),
-							log,
-						)
-					}
-
-					copyMap(reflect.ValueOf(val), fieldVal)
-				}
-
-				mapCopy := make(map[uint64]int64)
-				copyMap(fieldVal, reflect.ValueOf(mapCopy))
-
-				mapCopy, err = GetEnvMapWithCustomType(
-					envKey,
-					mapCopy,
-					required,
-					nil,
-					nil,
-					fieldVal.Type().Key(),
-					fieldVal.Type().Elem(),
-					log,
-				)
-				if err != nil {
-					return err.WrapWithLog(
-						"load config struct from env",
-						log,
-					)
-				}
-
-				copyMap(reflect.ValueOf(mapCopy), fieldVal)
-
-			case uintUintMap:
-				if useDefaultFromTag {
-					var val map[uint64]uint64
-
-					val, err = valueparser.ParseMap[uint64, uint64](defaultValStr, nil, nil)
-					if err != nil {
-						return err.WrapWithLog(
-							fmt.Sprintf(
-								"config loader: field %s default: %v",
-								field.Name,
-								err,
-							),
-							log,
-						)
-					}
-
-					copyMap(reflect.ValueOf(val), fieldVal)
-				}
-
-				mapCopy := make(map[uint64]uint64)
-				copyMap(fieldVal, reflect.ValueOf(mapCopy))
-
-				mapCopy, err = GetEnvMapWithCustomType(
-					envKey,
-					mapCopy,
-					required,
-					nil,
-					nil,
-					fieldVal.Type().Key(),
-					fieldVal.Type().Elem(),
-					log,
-				)
-				if err != nil {
-					return err.WrapWithLog(
-						"load config struct from env",
-						log,
-					)
-				}
-
-				copyMap(reflect.ValueOf(mapCopy), fieldVal)
-
-			case uintFloatMap:
-				if useDefaultFromTag {
-					var val map[uint64]float64
-
-					val, err = valueparser.ParseMap[uint64, float64](defaultValStr, nil, nil)
-					if err != nil {
-						return err.WrapWithLog(
-							fmt.Sprintf(
-								"config loader: field %s default: %v",
-								field.Name,
-								err,
-							),
-							log,
-						)
-					}
-
-					copyMap(reflect.ValueOf(val), fieldVal)
-				}
-
-				mapCopy := make(map[uint64]float64)
-				copyMap(fieldVal, reflect.ValueOf(mapCopy))
-
-				mapCopy, err = GetEnvMapWithCustomType(
-					envKey,
-					mapCopy,
-					required,
-					nil,
-					nil,
-					fieldVal.Type().Key(),
-					fieldVal.Type().Elem(),
-					log,
-				)
-				if err != nil {
-					return err.WrapWithLog(
-						"load config struct from env",
-						log,
-					)
-				}
-
-				copyMap(reflect.ValueOf(mapCopy), fieldVal)
-
-			case uintBoolMap:
-				if useDefaultFromTag {
-					var val map[uint64]bool
-
-					val, err = valueparser.ParseMap[uint64, bool](defaultValStr, nil, nil)
-					if err != nil {
-						return err.WrapWithLog(
-							fmt.Sprintf(
-								"config loader: field %s default: %v",
-								field.Name,
-								err,
-							),
-							log,
-						)
-					}
-
-					copyMap(reflect.ValueOf(val), fieldVal)
-				}
-
-				mapCopy := make(map[uint64]bool)
-				copyMap(fieldVal, reflect.ValueOf(mapCopy))
-
-				mapCopy, err = GetEnvMapWithCustomType(
-					envKey,
-					mapCopy,
-					required,
-					nil,
-					nil,
-					fieldVal.Type().Key(),
-					fieldVal.Type().Elem(),
-					log,
-				)
-				if err != nil {
-					return err.WrapWithLog(
-						"load config struct from env",
-						log,
-					)
-				}
-
-				copyMap(reflect.ValueOf(mapCopy), fieldVal)
-
-			case floatStringMap:
-				if useDefaultFromTag {
-					var val map[float64]string
-
-					val, err = valueparser.ParseMap[float64, string](defaultValStr, nil, nil)
-					if err != nil {
-						return err.WrapWithLog(
-							fmt.Sprintf(
-								"config loader: field %s default: %v",
-								field.Name,
-								err,
-							),
-							log,
-						)
-					}
-
-					copyMap(reflect.ValueOf(val), fieldVal)
-				}
-
-				mapCopy := make(map[float64]string)
-				copyMap(fieldVal, reflect.ValueOf(mapCopy))
-
-				mapCopy, err = GetEnvMapWithCustomType(
-					envKey,
-					mapCopy,
-					required,
-					nil,
-					nil,
-					fieldVal.Type().Key(),
-					fieldVal.Type().Elem(),
-					log,
-				)
-				if err != nil {
-					return err.WrapWithLog(
-						"load config struct from env",
-						log,
-					)
-				}
-
-				copyMap(reflect.ValueOf(mapCopy), fieldVal)
+		switch {
+		case isNestedStruct(field.Type):
+			loadConfigStructFromEnv(fieldVal, options.prefix, layers, report, log)
 
-			case floatIntMap:
-				if useDefaultFromTag {
-					var val map[float64]int64
-
-					val, err = valueparser.ParseMap[float64, int64](defaultValStr, nil, nil)
-					if err != nil {
-						return err.WrapWithLog(
-							fmt.Sprintf(
-								"config loader: field %s default: %v",
-								field.Name,
-								err,
-							),
-							log,
-						)
-					}
-
-					copyMap(reflect.ValueOf(val), fieldVal)
-				}
-
-				mapCopy := make(map[float64]int64)
-				copyMap(fieldVal, reflect.ValueOf(mapCopy))
-
-				mapCopy, err = GetEnvMapWithCustomType(
-					envKey,
-					mapCopy,
-					required,
-					nil,
-					nil,
-					fieldVal.Type().Key(),
-					fieldVal.Type().Elem(),
-					log,
-				)
-				if err != nil {
-					return err.WrapWithLog(
-						"load config struct from env",
-						log,
-					)
-				}
-
-				copyMap(reflect.ValueOf(mapCopy), fieldVal)
-
-			case floatUintMap:
-				if useDefaultFromTag {
-					var val map[float64]uint64
-
-					val, err = valueparser.ParseMap[float64, uint64](defaultValStr, nil, nil)
-					if err != nil {
-						return err.WrapWithLog(
-							fmt.Sprintf(
-								"config loader: field %s default: %v",
-								field.Name,
-								err,
-							),
-							log,
-						)
-					}
-
-					copyMap(reflect.ValueOf(val), fieldVal)
-				}
-
-				mapCopy := make(map[float64]uint64)
-				copyMap(fieldVal, reflect.ValueOf(mapCopy))
-
-				mapCopy, err = GetEnvMapWithCustomType(
-					envKey,
-					mapCopy,
-					required,
-					nil,
-					nil,
-					fieldVal.Type().Key(),
-					fieldVal.Type().Elem(),
-					log,
-				)
-				if err != nil {
-					return err.WrapWithLog(
-						"load config struct from env",
-						log,
-					)
-				}
-
-				copyMap(reflect.ValueOf(mapCopy), fieldVal)
-
-			case floatFloatMap:
-				if useDefaultFromTag {
-					var val map[float64]float64
-
-					val, err = valueparser.ParseMap[float64, float64](defaultValStr, nil, nil)
-					if err != nil {
-						return err.WrapWithLog(
-							fmt.Sprintf(
-								"config loader: field %s default: %v",
-								field.Name,
-								err,
-							),
-							log,
-						)
-					}
-
-					copyMap(reflect.ValueOf(val), fieldVal)
-				}
-
-				mapCopy := make(map[float64]float64)
-				copyMap(fieldVal, reflect.ValueOf(mapCopy))
-
-				mapCopy, err = GetEnvMapWithCustomType(
-					envKey,
-					mapCopy,
-					required,
-					nil,
-					nil,
-					fieldVal.Type().Key(),
-					fieldVal.Type().Elem(),
-					log,
-				)
-				if err != nil {
-					return err.WrapWithLog(
-						"load config struct from env",
-						log,
-					)
-				}
-
-				copyMap(reflect.ValueOf(mapCopy), fieldVal)
-
-			case floatBoolMap:
-				if useDefaultFromTag {
-					var val map[float64]bool
-
-					val, err = valueparser.ParseMap[float64, bool](defaultValStr, nil, nil)
-					if err != nil {
-						return err.WrapWithLog(
-							fmt.Sprintf(
-								"config loader: field %s default: %v",
-								field.Name,
-								err,
-							),
-							log,
-						)
-					}
-
-					copyMap(reflect.ValueOf(val), fieldVal)
-				}
-
-				mapCopy := make(map[float64]bool)
-				copyMap(fieldVal, reflect.ValueOf(mapCopy))
-
-				mapCopy, err = GetEnvMapWithCustomType(
-					envKey,
-					mapCopy,
-					required,
-					nil,
-					nil,
-					fieldVal.Type().Key(),
-					fieldVal.Type().Elem(),
-					log,
-				)
-				if err != nil {
-					return err.WrapWithLog(
-						"load config struct from env",
-						log,
-					)
-				}
-
-				copyMap(reflect.ValueOf(mapCopy), fieldVal)
-
-			case boolStringMap:
-				if useDefaultFromTag {
-					var val map[bool]string
-
-					val, err = valueparser.ParseMap[bool, string](defaultValStr, nil, nil)
-					if err != nil {
-						return err.WrapWithLog(
-							fmt.Sprintf(
-								"config loader: field %s default: %v",
-								field.Name,
-								err,
-							),
-							log,
-						)
-					}
-
-					copyMap(reflect.ValueOf(val), fieldVal)
-				}
-
-				mapCopy := make(map[bool]string)
-				copyMap(fieldVal, reflect.ValueOf(mapCopy))
-
-				mapCopy, err = GetEnvMapWithCustomType(
-					envKey,
-					mapCopy,
-					required,
-					nil,
-					nil,
-					fieldVal.Type().Key(),
-					fieldVal.Type().Elem(),
-					log,
-				)
-				if err != nil {
-					return err.WrapWithLog(
-						"load config struct from env",
-						log,
-					)
-				}
-
-				copyMap(reflect.ValueOf(mapCopy), fieldVal)
-
-			case boolIntMap:
-				if useDefaultFromTag {
-					var val map[bool]int64
-
-					val, err = valueparser.ParseMap[bool, int64](defaultValStr, nil, nil)
-					if err != nil {
-						return err.WrapWithLog(
-							fmt.Sprintf(
-								"config loader: field %s default: %v",
-								field.Name,
-								err,
-							),
-							log,
-						)
-					}
-
-					copyMap(reflect.ValueOf(val), fieldVal)
-				}
-
-				mapCopy := make(map[bool]int64)
-				copyMap(fieldVal, reflect.ValueOf(mapCopy))
-
-				mapCopy, err = GetEnvMapWithCustomType(
-					envKey,
-					mapCopy,
-					required,
-					nil,
-					nil,
-					fieldVal.Type().Key(),
-					fieldVal.Type().Elem(),
-					log,
-				)
-				if err != nil {
-					return err.WrapWithLog(
-						"load config struct from env",
-						log,
-					)
-				}
-
-				copyMap(reflect.ValueOf(mapCopy), fieldVal)
-
-			case boolUintMap:
-				mapCopy := make(map[bool]uint64)
-
-				if useDefaultFromTag {
-					var val map[bool]uint64
-
-					val, err = valueparser.ParseMap[bool, uint64](defaultValStr, nil, nil)
-					if err != nil {
-						return err.WrapWithLog(
-							fmt.Sprintf(
-								"config loader: field %s default: %v",
-								field.Name,
-								err,
-							),
-							log,
-						)
-					}
-
-					copyMap(reflect.ValueOf(val), fieldVal)
-				}
-
-				copyMap(fieldVal, reflect.ValueOf(mapCopy))
-
-				mapCopy, err = GetEnvMapWithCustomType(
-					envKey,
-					mapCopy,
-					required,
-					nil,
-					nil,
-					fieldVal.Type().Key(),
-					fieldVal.Type().Elem(),
-					log,
-				)
-				if err != nil {
-					return err.WrapWithLog(
-						"load config struct from env",
-						log,
-					)
-				}
-
-				copyMap(reflect.ValueOf(mapCopy), fieldVal)
-
-			case boolFloatMap:
-				mapCopy := make(map[bool]float64)
-
-				if useDefaultFromTag {
-					var val map[bool]float64
-
-					val, err = valueparser.ParseMap[bool, float64](defaultValStr, nil, nil)
-					if err != nil {
-						return err.WrapWithLog(
-							fmt.Sprintf(
-								"config loader: field %s default: %v",
-								field.Name,
-								err,
-							),
-							log,
-						)
-					}
-
-					copyMap(reflect.ValueOf(val), fieldVal)
-				}
-
-				copyMap(fieldVal, reflect.ValueOf(mapCopy))
-
-				mapCopy, err = GetEnvMapWithCustomType(
-					envKey,
-					mapCopy,
-					required,
-					nil,
-					nil,
-					fieldVal.Type().Key(),
-					fieldVal.Type().Elem(),
-					log,
-				)
-				if err != nil {
-					return err.WrapWithLog(
-						"load config struct from env",
-						log,
-					)
-				}
-
-				copyMap(reflect.ValueOf(mapCopy), fieldVal)
-
-			case boolBoolMap:
-				mapCopy := make(map[bool]bool)
-
-				if useDefaultFromTag {
-					var val map[bool]bool
-
-					val, err = valueparser.ParseMap[bool, bool](defaultValStr, nil, nil)
-					if err != nil {
-						return err.WrapWithLog(
-							fmt.Sprintf(
-								"config loader: field %s default: %v",
-								field.Name,
-								err,
-							),
-							log,
-						)
-					}
-
-					copyMap(reflect.ValueOf(val), fieldVal)
-				}
-
-				copyMap(fieldVal, reflect.ValueOf(mapCopy))
-
-				mapCopy, err = GetEnvMapWithCustomType(
-					envKey,
-					mapCopy,
-					required,
-					nil,
-					nil,
-					fieldVal.Type().Key(),
-					fieldVal.Type().Elem(),
-					log,
-				)
-				if err != nil {
-					return err.WrapWithLog(
-						"load config struct from env",
-						log,
-					)
-				}
-
-				copyMap(reflect.ValueOf(mapCopy), fieldVal)
-
-			case invalidMap:
-				log.Warnf("Unsupported map type for field %s", field.Name)
-			}
-
-		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-			if useDefaultFromTag {
-				var val int64
-
-				val, err = valueparser.ParseValueWithCustomType[int64](defaultValStr, field.Type)
-				if err != nil {
-					return err.WrapWithLog(
-						fmt.Sprintf(
-							"config loader: field %s default: %v",
-							field.Name,
-							err,
-						),
-						log,
-					)
-				}
-
-				fieldVal.SetInt(val)
-			}
-
-			var val int64
-
-			val, err = GetEnvWithCustomType(envKey, fieldVal.Int(), required, field.Type, log)
-			if err != nil {
-				return err.WrapWithLog(
-					"load config struct from env",
-					log,
-				)
-			}
-
-			fieldVal.SetInt(val)
-
-		case reflect.Uint,
-			reflect.Uint8,
-			reflect.Uint16,
-			reflect.Uint32,
-			reflect.Uint64,
-			reflect.Uintptr:
-			if useDefaultFromTag {
-				var val uint64
-
-				val, err = valueparser.ParseValueWithCustomType[uint64](defaultValStr, field.Type)
-				if err != nil {
-					return err.WrapWithLog(
-						fmt.Sprintf(
-							"config loader: field %s default: %v",
-							field.Name,
-							err,
-						),
-						log,
-					)
-				}
-
-				fieldVal.SetUint(val)
-			}
-
-			var val uint64
-
-			val, err = GetEnvWithCustomType(envKey, fieldVal.Uint(), required, field.Type, log)
-			if err != nil {
-				return err.WrapWithLog(
-					"load config struct from env",
-					log,
-				)
-			}
-
-			fieldVal.SetUint(val)
-
-		case reflect.Float32, reflect.Float64:
-			if useDefaultFromTag {
-				var val float64
-
-				val, err = valueparser.ParseValueWithCustomType[float64](defaultValStr, field.Type)
-				if err != nil {
-					return err.WrapWithLog(
-						fmt.Sprintf(
-							"config loader: field %s default: %v",
-							field.Name,
-							err,
-						),
-						log,
-					)
-				}
-
-				fieldVal.SetFloat(val)
-			}
-
-			var val float64
-
-			val, err = GetEnvWithCustomType(envKey, fieldVal.Float(), required, field.Type, log)
-			if err != nil {
-				return err.WrapWithLog(
-					"load config struct from env",
-					log,
-				)
-			}
-
-			fieldVal.SetFloat(val)
-
-		case reflect.Bool:
-			if useDefaultFromTag {
-				var val bool
-
-				val, err = valueparser.ParseValueWithCustomType[bool](defaultValStr, field.Type)
-				if err != nil {
-					return err.WrapWithLog(
-						fmt.Sprintf(
-							"config loader: field %s default: %v",
-							field.Name,
-							err,
-						),
-						log,
-					)
-				}
-
-				fieldVal.SetBool(val)
-			}
-
-			var val bool
-
-			val, err = GetEnvWithCustomType(envKey, fieldVal.Bool(), required, field.Type, log)
-			if err != nil {
-				return err.WrapWithLog(
-					"load config struct from env",
-					log,
-				)
-			}
-
-			fieldVal.SetBool(val)
-
-		case reflect.String:
-			if useDefaultFromTag {
-				var val string
-
-				val, err = valueparser.TryUnmarshal[string](defaultValStr, field.Type)
-				if err != nil {
-					fieldVal.SetString(defaultValStr)
-				} else {
-					fieldVal.SetString(val)
-				}
-			}
-
-			var val string
-
-			val, err = GetEnvWithCustomType(envKey, fieldVal.String(), required, field.Type, log)
-			if err != nil {
-				return err.WrapWithLog(
-					"load config struct from env",
-					log,
-				)
+			continue
+		case isNestedStruct(elemType(field.Type)):
+			loadStructSlice(fieldVal, &options, report, log)
+		case isParsableType(field.Type):
+			if !loadField(&field, fieldVal, &options, report, log) {
+				continue
 			}
+		default:
+			log.Warnf("Unsupported field type for field %s", field.Name)
 
-			fieldVal.SetString(val)
-
-		case reflect.Slice:
-			switch field.Type.Elem().Kind() {
-			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-				array := make([]int64, fieldVal.Len())
-
-				if useDefaultFromTag {
-					array, err = valueparser.ParseArrayWithCustomType[int64](
-						defaultValStr,
-						nil,
-						field.Type.Elem(),
-					)
-					if err != nil {
-						return err.WrapWithLog(
-							fmt.Sprintf(
-								"config loader: field %s default: %v",
-								field.Name,
-								err,
-							),
-							log,
-						)
-					}
-
-					copyArray(reflect.ValueOf(array), fieldVal)
-				}
-
-				array, err = GetEnvArrayWithCustomType(
-					envKey,
-					array,
-					nil,
-					required,
-					fieldVal.Type().Elem(),
-					log,
-				)
-				if err != nil {
-					return err.WrapWithLog(
-						"load config struct from env",
-						log,
-					)
-				}
-
-				copyArray(reflect.ValueOf(array), fieldVal)
-
-			case reflect.Uint,
-				reflect.Uint8,
-				reflect.Uint16,
-				reflect.Uint32,
-				reflect.Uint64,
-				reflect.Uintptr:
-				array := make([]uint64, fieldVal.Len())
-
-				if useDefaultFromTag {
-					array, err = valueparser.ParseArrayWithCustomType[uint64](
-						defaultValStr,
-						nil,
-						field.Type.Elem(),
-					)
-					if err != nil {
-						return err.WrapWithLog(
-							fmt.Sprintf(
-								"config loader: field %s default: %v",
-								field.Name,
-								err,
-							),
-							log,
-						)
-					}
-
-					copyArray(reflect.ValueOf(array), fieldVal)
-				}
-
-				array, err = GetEnvArrayWithCustomType(
-					envKey,
-					array,
-					nil,
-					required,
-					fieldVal.Type().Elem(),
-					log,
-				)
-				if err != nil {
-					return err.WrapWithLog(
-						"load config struct from env",
-						log,
-					)
-				}
+			continue
+		}
 
-				copyArray(reflect.ValueOf(array), fieldVal)
+		if err = validateField(&field, fieldVal, options.source); err != nil {
+			report.add(options.source.key, err)
+		}
+	}
+}
 
-			case reflect.Float32, reflect.Float64:
-				array := make([]float64, fieldVal.Len())
+// loadField loads a parsable field from its default tag and the environment, records
+// where the value came from and reports whether it loaded cleanly.
+func loadField(
+	field *reflect.StructField,
+	fieldVal reflect.Value,
+	options *fieldOptions,
+	report *loadReport,
+	log yalogger.Logger,
+) bool {
+	defaultValStr := field.Tag.Get(DefaultTagName)
 
-				if useDefaultFromTag {
-					array, err = valueparser.ParseArrayWithCustomType[float64](
-						defaultValStr,
-						nil,
-						field.Type.Elem(),
-					)
-					if err != nil {
-						return err.WrapWithLog(
-							fmt.Sprintf(
-								"config loader: field %s default: %v",
-								field.Name,
-								err,
-							),
-							log,
-						)
-					}
+	origin := ""
+	if !fieldVal.IsZero() {
+		origin = SourceNamePreset
+	}
 
-					copyArray(reflect.ValueOf(array), fieldVal)
-				}
+	if fieldVal.IsZero() && defaultValStr != "" {
+		value, err := parseValue(defaultValStr, field.Type, 0)
+		if err != nil {
+			report.invalidDefault(options.source, defaultValStr, err)
 
-				array, err = GetEnvArrayWithCustomType(
-					envKey,
-					array,
-					nil,
-					required,
-					fieldVal.Type().Elem(),
-					log,
-				)
-				if err != nil {
-					return err.WrapWithLog(
-						"load config struct from env",
-						log,
-					)
-				}
+			return false
+		}
 
-				copyArray(reflect.ValueOf(array), fieldVal)
+		fieldVal.Set(value)
 
-			case reflect.Bool:
-				array := make([]bool, fieldVal.Len())
+		origin = SourceNameDefault
+	}
 
-				if useDefaultFromTag {
-					array, err = valueparser.ParseArrayWithCustomType[bool](
-						defaultValStr,
-						nil,
-						field.Type.Elem(),
-					)
-					if err != nil {
-						return err.WrapWithLog(
-							fmt.Sprintf(
-								"config loader: field %s default: %v",
-								field.Name,
-								err,
-							),
-							log,
-						)
-					}
+	value, err := getEnvValue(options.source, fieldVal, options.required, log)
+	if err != nil {
+		report.add(options.source.key, err)
 
-					copyArray(reflect.ValueOf(array), fieldVal)
-				}
+		return false
+	}
 
-				array, err = GetEnvArrayWithCustomType(
-					envKey,
-					array,
-					nil,
-					required,
-					fieldVal.Type().Elem(),
-					log,
-				)
-				if err != nil {
-					return err.WrapWithLog(
-						"load config struct from env",
-						log,
-					)
-				}
+	fieldVal.Set(value)
 
-				copyArray(reflect.ValueOf(array), fieldVal)
+	if sourceOrigin := options.source.origin(); sourceOrigin != "" {
+		origin = sourceOrigin
+	}
 
-			case reflect.String:
-				array := make([]string, fieldVal.Len())
+	report.origin(options.source.key, origin)
 
-				if useDefaultFromTag {
-					array, err = valueparser.ParseArrayWithCustomType[string](
-						defaultValStr,
-						nil,
-						field.Type.Elem(),
-					)
-					if err != nil {
-						return err.WrapWithLog(
-							fmt.Sprintf(
-								"config loader: field %s default: %v",
-								field.Name,
-								err,
-							),
-							log,
-						)
-					}
+	return true
+}
 
-					copyArray(reflect.ValueOf(array), fieldVal)
-				}
+// loadStructSlice loads a slice of structs from indexed keys: element i of a field read
+// from KEY is loaded like a nested struct under KEY_<i>. Elements are read while
+// KEY_<i>_... keys exist; preset elements are kept and loaded over.
+func loadStructSlice(
+	fieldVal reflect.Value,
+	options *fieldOptions,
+	report *loadReport,
+	log yalogger.Logger,
+) {
+	length := 0
+	for options.source.layers.hasPrefix(
+		joinEnvKey(options.prefix, strconv.Itoa(length)) + EnvKeySeparator,
+	) {
+		length++
+	}
 
-				array, err = GetEnvArrayWithCustomType(
-					envKey,
-					array,
-					nil,
-					required,
-					fieldVal.Type().Elem(),
-					log,
-				)
-				if err != nil {
-					return err.WrapWithLog(
-						"load config struct from env",
-						log,
-					)
-				}
+	if length == 0 && fieldVal.Len() == 0 {
+		if options.required {
+			report.add(options.source.key, yaerrors.FromError(
+				http.StatusInternalServerError,
+				options.source.missing(),
+				"config loader: struct list",
+			))
+		}
 
-				copyArray(reflect.ValueOf(array), fieldVal)
+		return
+	}
 
-			case reflect.Invalid,
-				reflect.Chan,
-				reflect.Func,
-				reflect.Interface,
-				reflect.Pointer,
-				reflect.Struct,
-				reflect.Slice,
-				reflect.Complex64,
-				reflect.Complex128,
-				reflect.Array,
-				reflect.Map,
-				reflect.UnsafePointer:
-				log.Warnf("Unsupported field type for field %s", field.Name)
+	if length > fieldVal.Len() {
+		grown := reflect.MakeSlice(fieldVal.Type(), length, length)
+		reflect.Copy(grown, fieldVal)
+		fieldVal.Set(grown)
+	}
 
-			default:
-				log.Warnf("Unsupported slice type for field %s", field.Name)
+	for i := range fieldVal.Len() {
+		element := fieldVal.Index(i)
+		if element.Kind() == reflect.Pointer {
+			if element.IsNil() {
+				element.Set(reflect.New(element.Type().Elem()))
 			}
-		case reflect.Invalid,
-			reflect.Chan,
-			reflect.Func,
-			reflect.Interface,
-			reflect.Pointer,
-			reflect.Complex64,
-			reflect.Complex128,
-			reflect.Array,
-			reflect.UnsafePointer:
-			log.Warnf("Unsupported field type for field %s", field.Name)
 
-		default:
-			log.Warnf("Unsupported field type for field %s", field.Name)
+			element = element.Elem()
 		}
-	}
 
-	return nil
+		loadConfigStructFromEnv(
+			element,
+			joinEnvKey(options.prefix, strconv.Itoa(i)),
+			options.source.layers,
+			report,
+			log,
+		)
+	}
 }
@@ -1,9 +1,15 @@
 package config_test
 
 import (
+	"errors"
+	"net"
+	"net/url"
 	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/YaCodeDev/GoYaCodeDevUtils/config"
 	"github.com/YaCodeDev/GoYaCodeDevUtils/yalogger"
@@ -516,3 +522,442 @@ func TestConfigLoader(t *testing.T) {
 		)
 	}
 }
+
+type taggedDatabase struct {
+	Host     string
+	Password string `secret:"true"`
+}
+
+type taggedFlat struct {
+	Region string `default:"eu"`
+}
+
+type taggedStruct struct {
+	Port     int            `env:"HTTP_PORT"`
+	Database taggedDatabase `envPrefix:"DB_"`
+	Flat     taggedFlat     `envPrefix:""`
+	Comment  string         `optional:"true"`
+	Token    string         `secret:"true"`
+	Name     string         `required:"true"`
+}
+
+func TestConfigLoaderTags(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+
+	if err := os.WriteFile(tokenFile, []byte("file-token\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("HTTP_PORT", "8080")
+	t.Setenv("DB_HOST", "db.local")
+	t.Setenv("DB_PASSWORD", "hunter2")
+	t.Setenv("TOKEN_FILE", tokenFile)
+	t.Setenv("NAME", "ya")
+
+	configInstance := taggedStruct{Name: "preset"}
+
+	if err := config.LoadConfigStructFromEnvHandlingError(&configInstance, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	want := taggedStruct{
+		Port:     8080,
+		Database: taggedDatabase{Host: "db.local", Password: "hunter2"},
+		Flat:     taggedFlat{Region: "eu"},
+		Token:    "file-token",
+		Name:     "ya",
+	}
+
+	if diff := cmp.Diff(want, configInstance); diff != "" {
+		t.Errorf("unexpected config, diff: %s", diff)
+	}
+}
+
+func TestConfigLoaderTagsRequired(t *testing.T) {
+	t.Setenv("HTTP_PORT", "8080")
+	t.Setenv("DB_HOST", "db.local")
+	t.Setenv("DB_PASSWORD", "hunter2")
+	t.Setenv("TOKEN", "env-token")
+
+	configInstance := taggedStruct{Name: "preset"}
+
+	err := config.LoadConfigStructFromEnvHandlingError(&configInstance, nil)
+	if !errors.Is(err, config.ErrValueIsRequired) {
+		t.Fatalf("expected ErrValueIsRequired for a required field with a preset value, got %v", err)
+	}
+}
+
+func TestConfigLoaderTagsConflict(t *testing.T) {
+	var configInstance struct {
+		Value string `required:"true" optional:"true"`
+	}
+
+	err := config.LoadConfigStructFromEnvHandlingError(&configInstance, nil)
+	if !errors.Is(err, config.ErrConflictingFieldTags) {
+		t.Fatalf("expected ErrConflictingFieldTags, got %v", err)
+	}
+}
+
+func TestConfigLoaderEmptyStructSliceIsOptional(t *testing.T) {
+	type item struct {
+		Name string
+	}
+
+	var untagged struct {
+		Port  int `default:"80"`
+		Items []item
+	}
+
+	if err := config.LoadConfigStructFromEnvHandlingError(&untagged, nil); err != nil {
+		t.Fatalf("an untagged empty struct slice should be optional, got %v", err)
+	}
+
+	if untagged.Port != 80 || len(untagged.Items) != 0 {
+		t.Errorf("unexpected config %+v", untagged)
+	}
+
+	var required struct {
+		Items []item `required:"true"`
+	}
+
+	err := config.LoadConfigStructFromEnvHandlingError(&required, nil)
+	if !errors.Is(err, config.ErrValueIsRequired) {
+		t.Fatalf("expected ErrValueIsRequired for a required struct slice, got %v", err)
+	}
+}
+
+func TestConfigLoaderSecretFileMissing(t *testing.T) {
+	t.Setenv("API_KEY_FILE", filepath.Join(t.TempDir(), "missing"))
+
+	var configInstance struct {
+		APIKey string `secret:"true" optional:"true"`
+	}
+
+	err := config.LoadConfigStructFromEnvHandlingError(&configInstance, nil)
+	if !errors.Is(err, config.ErrFailedToReadSecretFile) {
+		t.Fatalf("expected ErrFailedToReadSecretFile, got %v", err)
+	}
+}
+
+func TestConfigLoaderSecretRedacted(t *testing.T) {
+	logFile, err := os.Create(filepath.Join(t.TempDir(), "log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stderr := os.Stderr
+	os.Stderr = logFile
+
+	t.Cleanup(func() { os.Stderr = stderr })
+
+	var configInstance struct {
+		SessionKeys map[string]int `secret:"true" default:"fallback:42"`
+	}
+
+	if err := config.LoadConfigStructFromEnvHandlingError(&configInstance, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("SESSION_KEYS", "first:not-a-number")
+
+	err = config.LoadConfigStructFromEnvHandlingError(&configInstance, nil)
+	if !errors.Is(err, config.ErrUnparsableValue) {
+		t.Fatalf("expected ErrUnparsableValue, got %v", err)
+	}
+
+	if strings.Contains(err.Error(), "not-a-number") {
+		t.Errorf("secret leaked into the error: %v", err)
+	}
+
+	logFile.Close()
+
+	logs, err := os.ReadFile(logFile.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, leaked := range []string{"not-a-number", "fallback", "42"} {
+		if strings.Contains(string(logs), leaked) {
+			t.Errorf("secret %q leaked into logs: %s", leaked, logs)
+		}
+	}
+
+	if !strings.Contains(string(logs), config.RedactedValue) {
+		t.Errorf("expected %s in logs, got: %s", config.RedactedValue, logs)
+	}
+}
+
+func TestConfigLoaderAggregatesErrors(t *testing.T) {
+	t.Setenv("PORT", "eighty")
+	t.Setenv("RATIO", "1.5")
+
+	var configInstance struct {
+		Port     int
+		Ratio    float64 `max:"1"`
+		Host     string
+		Password string `secret:"true"`
+		Region   string `default:"eu"`
+	}
+
+	err := config.LoadConfigStructFromEnvHandlingError(&configInstance, nil)
+	if !errors.Is(err, config.ErrInvalidConfig) {
+		t.Fatalf("expected ErrInvalidConfig, got %v", err)
+	}
+
+	var fieldErrs config.FieldErrors
+	if !errors.As(err, &fieldErrs) {
+		t.Fatalf("expected FieldErrors in %v", err)
+	}
+
+	got := make(map[string]*config.FieldError, len(fieldErrs))
+	for _, fieldErr := range fieldErrs {
+		got[fieldErr.Key] = fieldErr
+	}
+
+	cases := []struct {
+		key   string
+		value string
+		err   error
+	}{
+		{key: "PORT", value: "eighty", err: config.ErrUnparsableValue},
+		{key: "RATIO", value: "1.5", err: config.ErrValueOutOfRange},
+		{key: "HOST", err: config.ErrValueIsRequired},
+		{key: "PASSWORD", err: config.ErrValueIsRequired},
+	}
+
+	if len(got) != len(cases) {
+		t.Fatalf("expected %d field errors, got %v", len(cases), fieldErrs)
+	}
+
+	for _, tc := range cases {
+		fieldErr, ok := got[tc.key]
+		if !ok {
+			t.Errorf("missing field error for %s in %v", tc.key, fieldErrs)
+
+			continue
+		}
+
+		if fieldErr.Value != tc.value || fieldErr.Reason == "" || !errors.Is(fieldErr, tc.err) {
+			t.Errorf("unexpected field error for %s: %+v", tc.key, fieldErr)
+		}
+	}
+
+	if configInstance.Region != "eu" {
+		t.Errorf("valid fields must still load, got Region %q", configInstance.Region)
+	}
+}
+
+func TestConfigLoaderValidationTags(t *testing.T) {
+	type validated struct {
+		Workers  int           `min:"1" max:"16"                optional:"true"`
+		Timeout  time.Duration `min:"1s" max:"1m"               optional:"true"`
+		Name     string        `min:"3" max:"8"                 optional:"true"`
+		Hosts    []string      `min:"1" max:"2"                 optional:"true"`
+		Mode     string        `oneof:"dev prod"                optional:"true"`
+		Levels   []string      `oneof:"info warn error"         optional:"true"`
+		Code     string        `regex:"^[A-Z]{3}$"              optional:"true"`
+		Endpoint string        `url:"true"                      optional:"true"`
+		Contact  string        `email:"true"                    optional:"true"`
+		Broken   string        `regex:"(["                      optional:"true"`
+		BadBound string        `min:"ten"                       optional:"true"`
+		Flag     bool          `max:"1"                         optional:"true"`
+		Unused   string        `oneof:"a b" url:"true" email:"" optional:"true"`
+	}
+
+	cases := []struct {
+		name string
+		key  string
+		env  string
+		err  error
+	}{
+		{name: "int in range", key: "WORKERS", env: "4"},
+		{name: "int below min", key: "WORKERS", env: "0", err: config.ErrValueOutOfRange},
+		{name: "int above max", key: "WORKERS", env: "17", err: config.ErrValueOutOfRange},
+		{name: "duration in range", key: "TIMEOUT", env: "30000000000"},
+		{name: "duration above max", key: "TIMEOUT", env: "120000000000", err: config.ErrValueOutOfRange},
+		{name: "string length", key: "NAME", env: "yacode"},
+		{name: "string too short", key: "NAME", env: "ya", err: config.ErrValueOutOfRange},
+		{name: "slice length", key: "HOSTS", env: "a,b,c", err: config.ErrValueOutOfRange},
+		{name: "unset optional field", key: "UNUSED", env: ""},
+		{name: "oneof match", key: "MODE", env: "prod"},
+		{name: "oneof mismatch", key: "MODE", env: "staging", err: config.ErrValueNotAllowed},
+		{name: "oneof elements", key: "LEVELS", env: "info,debug", err: config.ErrValueNotAllowed},
+		{name: "regex match", key: "CODE", env: "ABC"},
+		{name: "regex mismatch", key: "CODE", env: "abc", err: config.ErrValueMismatch},
+		{name: "url", key: "ENDPOINT", env: "https://ya.dev/api"},
+		{name: "relative url", key: "ENDPOINT", env: "/api", err: config.ErrInvalidURL},
+		{name: "email", key: "CONTACT", env: "ya@ya.dev"},
+		{name: "named email", key: "CONTACT", env: "Ya <ya@ya.dev>", err: config.ErrInvalidEmail},
+		{name: "bad regex tag", key: "BROKEN", env: "x", err: config.ErrInvalidFieldTag},
+		{name: "bad bound tag", key: "BAD_BOUND", env: "x", err: config.ErrInvalidFieldTag},
+		{name: "bound on bool", key: "FLAG", env: "true", err: config.ErrInvalidFieldTag},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv(tc.key, tc.env)
+
+			var configInstance validated
+
+			err := config.LoadConfigStructFromEnvHandlingError(&configInstance, nil)
+
+			var fieldErrs config.FieldErrors
+			errors.As(err, &fieldErrs)
+
+			var matched []*config.FieldError
+
+			for _, fieldErr := range fieldErrs {
+				if fieldErr.Key == tc.key {
+					matched = append(matched, fieldErr)
+				}
+			}
+
+			if tc.err == nil {
+				if len(matched) > 0 {
+					t.Fatalf("unexpected errors for %s: %v", tc.key, matched)
+				}
+
+				return
+			}
+
+			if len(matched) != 1 || !errors.Is(matched[0], tc.err) {
+				t.Fatalf("expected %v for %s, got %v", tc.err, tc.key, err)
+			}
+		})
+	}
+}
+
+type shard uint8
+
+func (s *shard) Unmarshal(data string) error {
+	switch data {
+	case "primary":
+		*s = 1
+	case "replica":
+		*s = 2
+	default:
+		return errUnknownShard
+	}
+
+	return nil
+}
+
+var errUnknownShard = errors.New("unknown shard")
+
+type upstream struct {
+	Name    string
+	Weight  int           `default:"1"`
+	Timeout time.Duration `default:"5s"`
+}
+
+type genericStruct struct {
+	Timeout     time.Duration
+	Address     net.IP
+	Endpoint    url.URL
+	StartedAt   time.Time
+	Timeouts    map[string]time.Duration
+	Groups      map[string][]string
+	Matrix      [][]int
+	Shards      map[shard][]shard
+	Endpoints   map[string]url.URL
+	Allowed     []net.IP
+	Upstreams   []upstream
+	Replicas    []*upstream
+	Defaults    map[string]time.Duration `default:"read:1s,write:2s"`
+	PresetSlice []upstream               `optional:"true"`
+}
+
+func TestConfigLoaderGenericTypes(t *testing.T) {
+	env := map[string]string{
+		"TIMEOUT":              "1m30s",
+		"ADDRESS":              "10.0.0.1",
+		"ENDPOINT":             "https://ya.dev/api?v=1",
+		"STARTED_AT":           "2025-01-02T03:04:05Z",
+		"TIMEOUTS":             "read:1s, write:250ms",
+		"GROUPS":               "admins:ya;skalse,users:",
+		"MATRIX":               "1;2,3;4;5",
+		"SHARDS":               "primary:replica;replica",
+		"ENDPOINTS":            "api:https://ya.dev:8443/v1",
+		"ALLOWED":              "127.0.0.1,::1",
+		"UPSTREAMS_0_NAME":     "first",
+		"UPSTREAMS_1_NAME":     "second",
+		"UPSTREAMS_1_WEIGHT":   "3",
+		"UPSTREAMS_1_TIMEOUT":  "1s",
+		"REPLICAS_0_NAME":      "replica",
+		"PRESET_SLICE_0_NAME":  "overridden",
+		"UPSTREAMS_3_NAME":     "not contiguous",
+		"UNRELATED_0_UNRELATE": "ignored",
+	}
+
+	for key, value := range env {
+		t.Setenv(key, value)
+	}
+
+	configInstance := genericStruct{
+		PresetSlice: []upstream{{Name: "preset"}, {Name: "kept", Weight: 2}},
+	}
+
+	if err := config.LoadConfigStructFromEnvHandlingError(&configInstance, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	want := genericStruct{
+		Timeout:   90 * time.Second,
+		Address:   net.ParseIP("10.0.0.1"),
+		Endpoint:  url.URL{Scheme: "https", Host: "ya.dev", Path: "/api", RawQuery: "v=1"},
+		StartedAt: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		Timeouts:  map[string]time.Duration{"read": time.Second, "write": 250 * time.Millisecond},
+		Groups:    map[string][]string{"admins": {"ya", "skalse"}, "users": {}},
+		Matrix:    [][]int{{1, 2}, {3, 4, 5}},
+		Shards:    map[shard][]shard{1: {2, 2}},
+		Endpoints: map[string]url.URL{"api": {Scheme: "https", Host: "ya.dev:8443", Path: "/v1"}},
+		Allowed:   []net.IP{net.ParseIP("127.0.0.1"), net.ParseIP("::1")},
+		Upstreams: []upstream{
+			{Name: "first", Weight: 1, Timeout: 5 * time.Second},
+			{Name: "second", Weight: 3, Timeout: time.Second},
+		},
+		Replicas: []*upstream{{Name: "replica", Weight: 1, Timeout: 5 * time.Second}},
+		Defaults: map[string]time.Duration{"read": time.Second, "write": 2 * time.Second},
+		PresetSlice: []upstream{
+			{Name: "overridden", Weight: 1, Timeout: 5 * time.Second},
+			{Name: "kept", Weight: 2, Timeout: 5 * time.Second},
+		},
+	}
+
+	if diff := cmp.Diff(want, configInstance); diff != "" {
+		t.Errorf("unexpected config, diff: %s", diff)
+	}
+}
+
+// loadValue loads a struct holding a single Value field of type T.
+func loadValue[T any]() error {
+	var target struct{ Value T }
+
+	return config.LoadConfigStructFromEnvHandlingError(&target, nil)
+}
+
+func TestConfigLoaderGenericTypesErrors(t *testing.T) {
+	cases := []struct {
+		name string
+		env  string
+		load func() error
+		err  error
+	}{
+		{name: "int overflow", env: "300", load: loadValue[int8], err: config.ErrUnparsableValue},
+		{name: "bad duration", env: "soon", load: loadValue[time.Duration], err: config.ErrUnparsableValue},
+		{name: "bad ip", env: "10.0.0", load: loadValue[net.IP], err: config.ErrUnparsableValue},
+		{name: "bad custom type", env: "arbiter", load: loadValue[[]shard], err: config.ErrUnparsableValue},
+		{name: "map entry without key", env: "a", load: loadValue[map[string]int], err: config.ErrUnparsableValue},
+		{name: "nested too deep", env: "1", load: loadValue[[][][][]int], err: config.ErrNestingTooDeep},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("VALUE", tc.env)
+
+			if err := tc.load(); !errors.Is(err, tc.err) {
+				t.Fatalf("expected %v, got %v", tc.err, err)
+			}
+		})
+	}
+}
@@ -1,11 +1,70 @@
 package config
 
-import "regexp"
+import (
+	"encoding"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/YaCodeDev/GoYaCodeDevUtils/valueparser"
+)
 
 const (
-	DefaultTagName = "default"
-	DotEnvFile     = ".env"
-	DotEnvKVParts  = 2
+	DefaultTagName   = "default"
+	EnvTagName       = "env"
+	EnvPrefixTagName = "envPrefix"
+	RequiredTagName  = "required"
+	OptionalTagName  = "optional"
+	SecretTagName    = "secret"
+	MinTagName       = "min"
+	MaxTagName       = "max"
+	OneOfTagName     = "oneof"
+	RegexTagName     = "regex"
+	URLTagName       = "url"
+	EmailTagName     = "email"
+	SecretFileSuffix = "_FILE"
+	RedactedValue    = "[REDACTED]"
+	EnvKeySeparator  = "_"
+	ListSeparators   = ",;|"
+	MapKVSeparators  = ":=~"
+	DotEnvFile       = ".env"
+	DotEnvKVParts    = 2
+
+	SourceNameDefault = "default"
+	SourceNamePreset  = "preset"
+	SourceNameEnv     = "env"
+	SourceNameFlags   = "flags"
+	SourceKindYAML    = "yaml"
+	SourceKindTOML    = "toml"
+	SourceKindJSON    = "json"
+	SourceKindDotEnv  = "dotenv"
+	SourceKindMap     = "map"
+	SourceNameSep     = ":"
+	FlagPrefix        = "-"
+	FlagKVSeparator   = "="
+	FlagTrueValue     = "true"
+	FlagTerminator    = "--"
+
+	DescriptionTagName = "description"
+
+	SchemaFormatJSON     SchemaFormat = "json"
+	SchemaFormatMarkdown SchemaFormat = "markdown"
+	SchemaFormatDotEnv   SchemaFormat = "dotenv"
+	SchemaDirPerm                     = 0o755
+	SchemaFilePerm                    = 0o644
+	JSONSchemaDialect                 = "https://json-schema.org/draft/2020-12/schema"
+	jsonTypeObject                    = "object"
+	jsonTypeString                    = "string"
+	jsonTypeBoolean                   = "boolean"
+	jsonTypeInteger                   = "integer"
+	jsonTypeNumber                    = "number"
+	jsonFormatURI                     = "uri"
+	jsonFormatEmail                   = "email"
+
+	DefaultWatcherPollInterval = time.Second
+	yaToolsSourceCount         = 2
 
 	YaToolsDirName        = ".yatools"
 	YaToolsFileExtension  = ".json"
@@ -18,36 +77,11 @@ const (
 var (
 	matchFirstCap = regexp.MustCompile("(.)([A-Z][a-z]+)")
 	matchAllCap   = regexp.MustCompile("([a-z0-9])([A-Z])")
-)
 
-// This type is used to define the types of supported maps.
-type mapType uint8
+	sourceKeyReplacer = strings.NewReplacer("-", EnvKeySeparator, ".", EnvKeySeparator)
 
-const (
-	stringStringMap mapType = iota
-	stringIntMap
-	stringUintMap
-	stringFloatMap
-	stringBoolMap
-	intStringMap
-	intIntMap
-	intUintMap
-	intFloatMap
-	intBoolMap
-	uintStringMap
-	uintIntMap
-	uintUintMap
-	uintFloatMap
-	uintBoolMap
-	floatStringMap
-	floatIntMap
-	floatUintMap
-	floatFloatMap
-	floatBoolMap
-	boolStringMap
-	boolIntMap
-	boolUintMap
-	boolFloatMap
-	boolBoolMap
-	invalidMap
+	durationType        = reflect.TypeFor[time.Duration]()
+	urlType             = reflect.TypeFor[url.URL]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+	unmarshalableType   = reflect.TypeFor[valueparser.Unmarshalable]()
 )
@@ -6,7 +6,27 @@ var (
 	ErrConfigStructMustBeStruct = errors.New("config struct must be a struct")
 	ErrValueIsRequired          = errors.New("value is required")
 	ErrInvalidDotEnvFileFormat  = errors.New("invalid .env file format")
-	ErrUnsupportedYaToolsValue  = errors.New("unsupported yatools config value")
+	ErrUnsupportedSourceValue   = errors.New("unsupported config source value")
 	ErrNilYaToolsDestination    = errors.New("yatools config destination must not be nil")
 	ErrNilYaToolsValue          = errors.New("yatools config value must not be nil")
+	ErrInvalidFieldTag          = errors.New("invalid config field tag")
+	ErrConflictingFieldTags     = errors.New("config field cannot be both required and optional")
+	ErrFailedToReadSecretFile   = errors.New("failed to read secret file")
+	ErrInvalidConfig            = errors.New("invalid config")
+	ErrUnparsableValue          = errors.New("value cannot be parsed")
+	ErrValueOutOfRange          = errors.New("value is out of range")
+	ErrValueNotAllowed          = errors.New("value is not one of the allowed values")
+	ErrValueMismatch            = errors.New("value does not match the pattern")
+	ErrInvalidURL               = errors.New("value is not a valid URL")
+	ErrInvalidEmail             = errors.New("value is not a valid email address")
+	ErrUnsupportedType          = errors.New("unsupported config field type")
+	ErrNestingTooDeep           = errors.New("config value is nested too deep")
+	ErrFailedToReadSource       = errors.New("failed to read config source")
+	ErrFailedToDecodeSource     = errors.New("failed to decode config source")
+	ErrUnsupportedSchemaFormat  = errors.New("unsupported config schema format")
+	ErrSeparatorInListItem      = errors.New("config source list item contains the list separator")
 )
+
+// ErrUnsupportedYaToolsValue is ErrUnsupportedSourceValue under its older name: .yatools
+// files are flattened like every other file Source.
+var ErrUnsupportedYaToolsValue = ErrUnsupportedSourceValue
@@ -1,10 +1,12 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
 	"reflect"
+	"strings"
 
 	"github.com/YaCodeDev/GoYaCodeDevUtils/valueparser"
 	"github.com/YaCodeDev/GoYaCodeDevUtils/yaerrors"
@@ -75,28 +77,57 @@ func GetEnvWithCustomType[T valueparser.ParsableType](
 	required bool,
 	vType reflect.Type,
 	log yalogger.Logger,
+) (T, yaerrors.Error) {
+	return getEnvWithCustomType(envSource{key: key}, fallback, required, vType, log)
+}
+
+// getEnvWithCustomType does the work of GetEnvWithCustomType for any envSource.
+func getEnvWithCustomType[T valueparser.ParsableType](
+	source envSource,
+	fallback T,
+	required bool,
+	vType reflect.Type,
+	log yalogger.Logger,
 ) (T, yaerrors.Error) {
 	safetyCheck(&log)
 
-	if value, exists := os.LookupEnv(key); exists {
-		if parsed, err := valueparser.ParseValueWithCustomType[T](value, vType); err == nil {
+	value, exists, lookupErr := source.lookup()
+	if lookupErr != nil {
+		return fallback, lookupErr.WrapWithLog(
+			"get env: environment variable "+source.key,
+			log,
+		)
+	}
+
+	if exists {
+		parsed, err := valueparser.ParseValueWithCustomType[T](value, vType)
+		if err == nil {
 			return parsed, nil
 		}
+
+		if source.strict {
+			return fallback, yaerrors.FromErrorWithLog(
+				http.StatusInternalServerError,
+				source.unparsable(value, vType.String(), err),
+				"get env",
+				log,
+			)
+		}
 	}
 
 	if required {
 		return fallback, yaerrors.FromErrorWithLog(
 			http.StatusInternalServerError,
-			ErrValueIsRequired,
-			fmt.Sprintf("get env: environment variable %s is required", key),
+			source.missing(),
+			fmt.Sprintf("get env: environment variable %s is required", source.key),
 			log,
 		)
 	}
 
 	log.Warnf(
 		"Environment variable %s is not set or failed to parse, using default value %v",
-		key,
-		fallback,
+		source.key,
+		source.display(fallback),
 	)
 
 	return fallback, nil
@@ -172,31 +203,64 @@ func GetEnvArrayWithCustomType[T valueparser.ParsableType](
 	required bool,
 	vType reflect.Type,
 	log yalogger.Logger,
+) ([]T, yaerrors.Error) {
+	return getEnvArrayWithCustomType(envSource{key: key}, fallback, separator, required, vType, log)
+}
+
+// getEnvArrayWithCustomType does the work of GetEnvArrayWithCustomType for any envSource.
+func getEnvArrayWithCustomType[T valueparser.ParsableType](
+	source envSource,
+	fallback []T,
+	separator *string,
+	required bool,
+	vType reflect.Type,
+	log yalogger.Logger,
 ) ([]T, yaerrors.Error) {
 	safetyCheck(&log)
 
-	if value, exists := os.LookupEnv(key); exists {
+	value, exists, lookupErr := source.lookup()
+	if lookupErr != nil {
+		return nil, lookupErr.WrapWithLog(
+			"get env array: environment variable "+source.key,
+			log,
+		)
+	}
+
+	if exists {
 		parsed, err := valueparser.ParseArrayWithCustomType[T](value, separator, vType)
 		if err == nil {
 			return parsed, nil
 		}
 
-		log.Errorf("Failed to parse environment variable %s: %v", key, err)
+		if source.strict {
+			return nil, yaerrors.FromErrorWithLog(
+				http.StatusInternalServerError,
+				source.unparsable(value, "list of "+vType.String(), err),
+				"get env",
+				log,
+			)
+		}
+
+		log.Errorf("Failed to parse environment variable %s: %v", source.key, source.display(err))
 	}
 
 	if required {
 		return nil, yaerrors.FromErrorWithLog(
 			http.StatusInternalServerError,
-			ErrValueIsRequired,
+			source.missing(),
 			fmt.Sprintf(
 				"get env array: environment variable %s is required",
-				key,
+				source.key,
 			),
 			log,
 		)
 	}
 
-	log.Warnf("Environment variable %s is not set, using default value %v", key, fallback)
+	log.Warnf(
+		"Environment variable %s is not set, using default value %v",
+		source.key,
+		source.display(fallback),
+	)
 
 	return fallback, nil
 }
@@ -280,10 +344,41 @@ func GetEnvMapWithCustomType[K valueparser.ParsableComparableType, V valueparser
 	kType reflect.Type,
 	vType reflect.Type,
 	log yalogger.Logger,
+) (map[K]V, yaerrors.Error) {
+	return getEnvMapWithCustomType(
+		envSource{key: key},
+		fallback,
+		required,
+		entrySeparator,
+		kvSeparator,
+		kType,
+		vType,
+		log,
+	)
+}
+
+// getEnvMapWithCustomType does the work of GetEnvMapWithCustomType for any envSource.
+func getEnvMapWithCustomType[K valueparser.ParsableComparableType, V valueparser.ParsableType](
+	source envSource,
+	fallback map[K]V,
+	required bool,
+	entrySeparator *string,
+	kvSeparator *string,
+	kType reflect.Type,
+	vType reflect.Type,
+	log yalogger.Logger,
 ) (map[K]V, yaerrors.Error) {
 	safetyCheck(&log)
 
-	if value, exists := os.LookupEnv(key); exists {
+	value, exists, lookupErr := source.lookup()
+	if lookupErr != nil {
+		return nil, lookupErr.WrapWithLog(
+			"get env map: environment variable "+source.key,
+			log,
+		)
+	}
+
+	if exists {
 		parsed, err := valueparser.ParseMapWithCustomType[K, V](
 			value,
 			entrySeparator,
@@ -295,22 +390,163 @@ func GetEnvMapWithCustomType[K valueparser.ParsableComparableType, V valueparser
 			return parsed, nil
 		}
 
-		log.Errorf("Failed to parse environment variable %s: %v", key, err)
+		if source.strict {
+			return nil, yaerrors.FromErrorWithLog(
+				http.StatusInternalServerError,
+				source.unparsable(value, fmt.Sprintf("map of %s to %s", kType, vType), err),
+				"get env",
+				log,
+			)
+		}
+
+		log.Errorf("Failed to parse environment variable %s: %v", source.key, source.display(err))
 	}
 
 	if required {
 		return nil, yaerrors.FromErrorWithLog(
 			http.StatusInternalServerError,
-			ErrValueIsRequired,
+			source.missing(),
 			fmt.Sprintf(
 				"get env map: environment variable %s is required",
-				key,
+				source.key,
 			),
 			log,
 		)
 	}
 
-	log.Warnf("Environment variable %s is not set, using default value %v", key, fallback)
+	log.Warnf(
+		"Environment variable %s is not set, using default value %v",
+		source.key,
+		source.display(fallback),
+	)
+
+	return fallback, nil
+}
+
+// getEnvValue reads source into a value of the type of fallback with the reflection
+// parser of the struct loader. It returns fallback when the variable is unset and not
+// required.
+func getEnvValue(
+	source envSource,
+	fallback reflect.Value,
+	required bool,
+	log yalogger.Logger,
+) (reflect.Value, yaerrors.Error) {
+	value, exists, lookupErr := source.lookup()
+	if lookupErr != nil {
+		return fallback, lookupErr.WrapWithLog(
+			"get env value: environment variable "+source.key,
+			log,
+		)
+	}
+
+	if exists {
+		parsed, err := parseValue(value, fallback.Type(), 0)
+		if err == nil {
+			return parsed, nil
+		}
+
+		if source.strict {
+			return fallback, yaerrors.FromErrorWithLog(
+				http.StatusInternalServerError,
+				source.unparsable(value, fallback.Type().String(), err),
+				"get env value",
+				log,
+			)
+		}
+
+		log.Errorf("Failed to parse environment variable %s: %v", source.key, source.display(err))
+	}
+
+	if required {
+		return fallback, yaerrors.FromErrorWithLog(
+			http.StatusInternalServerError,
+			source.missing(),
+			fmt.Sprintf("get env value: environment variable %s is required", source.key),
+			log,
+		)
+	}
+
+	log.Warnf(
+		"Environment variable %s is not set, using default value %v",
+		source.key,
+		source.display(fallback.Interface()),
+	)
 
 	return fallback, nil
 }
+
+// envSource names the environment variable a value is read from. A secret source is
+// never printed in logs and falls back to the file named by <key>_FILE when the
+// variable itself is unset, the way Docker and Kubernetes mount secrets. A strict
+// source fails on a value that does not parse instead of falling back. Values are read
+// from layers, or from the process environment when layers is nil.
+type envSource struct {
+	key    string
+	secret bool
+	strict bool
+	layers *layeredValues
+}
+
+// lookup returns the raw value of the source and whether it was found.
+func (s envSource) lookup() (string, bool, yaerrors.Error) {
+	if value, exists := s.layers.lookup(s.key); exists || !s.secret {
+		return value, exists, nil
+	}
+
+	fileKey := s.key + SecretFileSuffix
+
+	path, exists := s.layers.lookup(fileKey)
+	if !exists {
+		return "", false, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", false, yaerrors.FromError(
+			http.StatusInternalServerError,
+			&FieldError{
+				Key:    fileKey,
+				Value:  path,
+				Reason: "secret file cannot be read",
+				Err:    errors.Join(err, ErrFailedToReadSecretFile),
+			},
+			"read secret file",
+		)
+	}
+
+	return strings.TrimRight(string(data), "\r\n"), true, nil
+}
+
+// origin returns the name of the source that set the value, or an empty string.
+func (s envSource) origin() string {
+	if origin := s.layers.origin(s.key); origin != "" || !s.secret {
+		return origin
+	}
+
+	return s.layers.origin(s.key + SecretFileSuffix)
+}
+
+// display returns what may be logged in place of value.
+func (s envSource) display(value any) any {
+	if s.secret {
+		return RedactedValue
+	}
+
+	return value
+}
+
+// missing returns the field error of a required source that is not set.
+func (s envSource) missing() *FieldError {
+	return &FieldError{Key: s.key, Reason: "required but not set", Err: ErrValueIsRequired}
+}
+
+// unparsable returns the field error of a value that does not parse as typeName.
+func (s envSource) unparsable(value, typeName string, err error) *FieldError {
+	return &FieldError{
+		Key:    s.key,
+		Value:  fmt.Sprint(s.display(value)),
+		Reason: "cannot be parsed as " + typeName,
+		Err:    errors.Join(err, ErrUnparsableValue),
+	}
+}
@@ -0,0 +1,294 @@
+package config
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/YaCodeDev/GoYaCodeDevUtils/valueparser"
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yaerrors"
+)
+
+// parseValue parses raw into a value of vType.
+//
+// Scalars go through valueparser, so custom Unmarshalable and encoding.TextUnmarshaler
+// types work as before; time.Duration accepts "1m30s" as well as nanoseconds and
+// url.URL is parsed with url.Parse. Slices and maps split raw by the separators of depth
+// in ListSeparators and MapKVSeparators, so a list nested in a list or map value uses the
+// next separator. Structs are parsable only through an unmarshaler.
+func parseValue(raw string, vType reflect.Type, depth int) (reflect.Value, yaerrors.Error) {
+	switch vType {
+	case durationType:
+		return parseDuration(raw)
+	case urlType:
+		return parseURL(raw)
+	}
+
+	switch vType.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return parseScalar[int64](raw, vType)
+	case reflect.Uint,
+		reflect.Uint8,
+		reflect.Uint16,
+		reflect.Uint32,
+		reflect.Uint64,
+		reflect.Uintptr:
+		return parseScalar[uint64](raw, vType)
+	case reflect.Float32, reflect.Float64:
+		return parseScalar[float64](raw, vType)
+	case reflect.Bool:
+		return parseScalar[bool](raw, vType)
+	case reflect.String:
+		return parseScalar[string](raw, vType)
+	case reflect.Slice:
+		if isUnmarshaler(vType) {
+			return unmarshalValue(raw, vType)
+		}
+
+		return parseList(raw, vType, depth)
+	case reflect.Map:
+		return parseMap(raw, vType, depth)
+	case reflect.Struct:
+		if isUnmarshaler(vType) {
+			return unmarshalValue(raw, vType)
+		}
+	case reflect.Invalid,
+		reflect.Complex64,
+		reflect.Complex128,
+		reflect.Array,
+		reflect.Chan,
+		reflect.Func,
+		reflect.Interface,
+		reflect.Pointer,
+		reflect.UnsafePointer:
+	}
+
+	return reflect.Value{}, yaerrors.FromError(
+		http.StatusInternalServerError,
+		ErrUnsupportedType,
+		"parse value: unsupported type "+vType.String(),
+	)
+}
+
+// parseScalar parses raw as T with valueparser and converts it to vType, rejecting
+// numbers that overflow vType.
+func parseScalar[T valueparser.ParsableType](
+	raw string,
+	vType reflect.Type,
+) (reflect.Value, yaerrors.Error) {
+	parsed, err := valueparser.ParseValueWithCustomType[T](raw, vType)
+	if err != nil {
+		return reflect.Value{}, err.Wrap("parse value")
+	}
+
+	if overflows(reflect.ValueOf(parsed), vType) {
+		return reflect.Value{}, yaerrors.FromError(
+			http.StatusInternalServerError,
+			ErrUnparsableValue,
+			fmt.Sprintf("parse value: %s overflows %s", raw, vType),
+		)
+	}
+
+	value, err := valueparser.ConvertValue(reflect.ValueOf(parsed), vType)
+	if err != nil {
+		return reflect.Value{}, err.Wrap("parse value")
+	}
+
+	return value, nil
+}
+
+// overflows reports whether the parsed number does not fit into vType.
+func overflows(parsed reflect.Value, vType reflect.Type) bool {
+	target := reflect.Zero(vType)
+
+	switch {
+	case parsed.CanInt():
+		return target.OverflowInt(parsed.Int())
+	case parsed.CanUint():
+		return target.OverflowUint(parsed.Uint())
+	case parsed.CanFloat():
+		return target.OverflowFloat(parsed.Float())
+	default:
+		return false
+	}
+}
+
+// parseList splits raw by the list separator of depth and parses every entry.
+func parseList(raw string, vType reflect.Type, depth int) (reflect.Value, yaerrors.Error) {
+	separator, _, depthErr := separatorsAt(depth)
+	if depthErr != nil {
+		return reflect.Value{}, depthErr
+	}
+
+	if raw == "" {
+		return reflect.MakeSlice(vType, 0, 0), nil
+	}
+
+	parts := strings.Split(raw, separator)
+	list := reflect.MakeSlice(vType, 0, len(parts))
+
+	for _, part := range parts {
+		element, err := parseValue(strings.TrimSpace(part), vType.Elem(), depth+1)
+		if err != nil {
+			return reflect.Value{}, err.Wrap(fmt.Sprintf("parse list: entry %q", part))
+		}
+
+		list = reflect.Append(list, element)
+	}
+
+	return list, nil
+}
+
+// parseMap splits raw by the list separator of depth into entries and every entry at the
+// first key-value separator of depth.
+func parseMap(raw string, vType reflect.Type, depth int) (reflect.Value, yaerrors.Error) {
+	separator, kvSeparator, depthErr := separatorsAt(depth)
+	if depthErr != nil {
+		return reflect.Value{}, depthErr
+	}
+
+	result := reflect.MakeMap(vType)
+
+	if raw == "" {
+		return result, nil
+	}
+
+	for entry := range strings.SplitSeq(raw, separator) {
+		rawKey, rawValue, found := strings.Cut(entry, kvSeparator)
+		if !found {
+			return reflect.Value{}, yaerrors.FromError(
+				http.StatusInternalServerError,
+				ErrUnparsableValue,
+				fmt.Sprintf("parse map: entry %q has no %q separator", entry, kvSeparator),
+			)
+		}
+
+		key, err := parseValue(strings.TrimSpace(rawKey), vType.Key(), depth+1)
+		if err != nil {
+			return reflect.Value{}, err.Wrap(fmt.Sprintf("parse map: key %q", rawKey))
+		}
+
+		value, err := parseValue(strings.TrimSpace(rawValue), vType.Elem(), depth+1)
+		if err != nil {
+			return reflect.Value{}, err.Wrap(fmt.Sprintf("parse map: value %q", rawValue))
+		}
+
+		result.SetMapIndex(key, value)
+	}
+
+	return result, nil
+}
+
+// separatorsAt returns the list and key-value separators of nesting level depth.
+func separatorsAt(depth int) (string, string, yaerrors.Error) {
+	if depth >= len(ListSeparators) || depth >= len(MapKVSeparators) {
+		return "", "", yaerrors.FromError(
+			http.StatusInternalServerError,
+			ErrNestingTooDeep,
+			fmt.Sprintf("parse value: more than %d nested lists or maps", len(ListSeparators)),
+		)
+	}
+
+	return ListSeparators[depth : depth+1], MapKVSeparators[depth : depth+1], nil
+}
+
+// parseDuration parses raw as a time.Duration string, or as nanoseconds.
+func parseDuration(raw string) (reflect.Value, yaerrors.Error) {
+	duration, err := time.ParseDuration(raw)
+	if err != nil {
+		nanoseconds, parseErr := strconv.ParseInt(raw, 10, 64)
+		if parseErr != nil {
+			return reflect.Value{}, yaerrors.FromError(
+				http.StatusInternalServerError,
+				errors.Join(err, ErrUnparsableValue),
+				"parse value: duration",
+			)
+		}
+
+		duration = time.Duration(nanoseconds)
+	}
+
+	return reflect.ValueOf(duration), nil
+}
+
+// parseURL parses raw with url.Parse.
+func parseURL(raw string) (reflect.Value, yaerrors.Error) {
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return reflect.Value{}, yaerrors.FromError(
+			http.StatusInternalServerError,
+			errors.Join(err, ErrUnparsableValue),
+			"parse value: url",
+		)
+	}
+
+	return reflect.ValueOf(*parsed), nil
+}
+
+// isUnmarshaler reports whether a pointer to vType implements encoding.TextUnmarshaler
+// or valueparser.Unmarshalable.
+func isUnmarshaler(vType reflect.Type) bool {
+	pointer := reflect.PointerTo(vType)
+
+	return pointer.Implements(textUnmarshalerType) || pointer.Implements(unmarshalableType)
+}
+
+// unmarshalValue parses raw into a new vType through its unmarshaler.
+func unmarshalValue(raw string, vType reflect.Type) (reflect.Value, yaerrors.Error) {
+	pointer := reflect.New(vType)
+
+	var err error
+
+	switch unmarshaler := pointer.Interface().(type) {
+	case encoding.TextUnmarshaler:
+		err = unmarshaler.UnmarshalText([]byte(raw))
+	case valueparser.Unmarshalable:
+		err = unmarshaler.Unmarshal(raw)
+	}
+
+	if err != nil {
+		return reflect.Value{}, yaerrors.FromError(
+			http.StatusInternalServerError,
+			errors.Join(err, ErrUnparsableValue),
+			"parse value: unmarshal "+vType.String(),
+		)
+	}
+
+	return pointer.Elem(), nil
+}
+
+// isParsableType reports whether parseValue can produce vType.
+func isParsableType(vType reflect.Type) bool {
+	switch vType.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Uintptr, reflect.Float32, reflect.Float64, reflect.Bool, reflect.String:
+		return true
+	case reflect.Slice:
+		return isUnmarshaler(vType) || isParsableType(vType.Elem())
+	case reflect.Map:
+		return vType.Key().Comparable() &&
+			isParsableType(vType.Key()) &&
+			isParsableType(vType.Elem())
+	case reflect.Struct:
+		return vType == urlType || isUnmarshaler(vType)
+	case reflect.Invalid,
+		reflect.Complex64,
+		reflect.Complex128,
+		reflect.Array,
+		reflect.Chan,
+		reflect.Func,
+		reflect.Interface,
+		reflect.Pointer,
+		reflect.UnsafePointer:
+		return false
+	default:
+		return false
+	}
+}
@@ -0,0 +1,110 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// FieldError describes one config field that failed to load or validate.
+//
+// Key is the environment variable the field is read from, Value the raw value that was
+// rejected (RedactedValue for secrets, empty when the variable is unset) and Reason a
+// human-readable explanation. Err carries the sentinel, so errors.Is works on it.
+//
+// Example:
+//
+//	var fieldErrs config.FieldErrors
+//	if errors.As(err, &fieldErrs) {
+//		for _, fieldErr := range fieldErrs {
+//			fmt.Println(fieldErr.Key, fieldErr.Reason)
+//		}
+//	}
+type FieldError struct {
+	Key    string
+	Value  string
+	Reason string
+	Err    error
+}
+
+// Error formats the field error as KEY="value": reason.
+func (e *FieldError) Error() string {
+	if e.Value == "" {
+		return fmt.Sprintf("%s: %s", e.Key, e.Reason)
+	}
+
+	return fmt.Sprintf("%s=%q: %s", e.Key, e.Value, e.Reason)
+}
+
+// Unwrap returns the underlying error of the field.
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// FieldErrors is every FieldError collected while loading one config struct. The loader
+// returns it joined with ErrInvalidConfig, so a deployment sees all its mistakes at once.
+type FieldErrors []*FieldError
+
+// Error lists the field errors, one per line.
+func (e FieldErrors) Error() string {
+	lines := make([]string, 0, len(e))
+
+	for _, fieldErr := range e {
+		lines = append(lines, fieldErr.Error())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// Unwrap exposes the field errors to errors.Is and errors.As.
+func (e FieldErrors) Unwrap() []error {
+	errs := make([]error, 0, len(e))
+
+	for _, fieldErr := range e {
+		errs = append(errs, fieldErr)
+	}
+
+	return errs
+}
+
+// loadReport collects the field errors of one load instead of failing on the first, and
+// the origin of every field that loaded.
+type loadReport struct {
+	errors  FieldErrors
+	origins Origins
+}
+
+// add records err against key. Errors that already carry a FieldError keep it.
+func (r *loadReport) add(key string, err error) {
+	var fieldErr *FieldError
+	if errors.As(err, &fieldErr) {
+		r.errors = append(r.errors, fieldErr)
+
+		return
+	}
+
+	r.errors = append(r.errors, &FieldError{Key: key, Reason: err.Error(), Err: err})
+}
+
+// origin records that the value of key came from the source named origin.
+func (r *loadReport) origin(key, origin string) {
+	if origin == "" {
+		return
+	}
+
+	if r.origins == nil {
+		r.origins = Origins{}
+	}
+
+	r.origins[key] = origin
+}
+
+// invalidDefault records a default tag of source that does not parse.
+func (r *loadReport) invalidDefault(source envSource, defaultValStr string, err error) {
+	r.errors = append(r.errors, &FieldError{
+		Key:    source.key,
+		Value:  fmt.Sprint(source.display(defaultValStr)),
+		Reason: "default tag cannot be parsed",
+		Err:    errors.Join(err, ErrUnparsableValue),
+	})
+}
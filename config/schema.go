@@ -0,0 +1,485 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yaerrors"
+)
+
+// SchemaFormat selects how Schema.Render prints a schema: SchemaFormatJSON for a JSON
+// Schema, SchemaFormatMarkdown for a README table or SchemaFormatDotEnv for a sample
+// .env file.
+type SchemaFormat string
+
+// FieldSchema describes one variable a config struct reads.
+//
+// Key is the variable, named by the rules of LoadConfigStructFromEnv, and Field the Go
+// path of the struct field (Database.Host, Upstreams[0].Name). Type is the Go type, and
+// Default the raw default tag. Required and Secret follow the required, optional and
+// secret tags exactly as the loader resolves them. The remaining fields carry the
+// description and validation tags.
+type FieldSchema struct {
+	Key         string
+	Field       string
+	Type        string
+	Default     string
+	Required    bool
+	Secret      bool
+	Description string
+	Min         string
+	Max         string
+	OneOf       []string
+	Regex       string
+	URL         bool
+	Email       bool
+
+	kind     reflect.Kind
+	jsonType string
+}
+
+// Schema is every variable a config struct reads, in field order.
+type Schema []FieldSchema
+
+// DescribeConfigStruct walks instance the way LoadConfigStructFromEnv loads it and
+// returns the variables it would read. Values already in instance count as presets, so
+// a field that is set is not required unless it is tagged so. A slice of structs is
+// described by its first element, KEY_0_FIELD.
+//
+// A typical use is a generator run by go generate that keeps docs in sync:
+//
+//	//go:generate go run ./cmd/configdocs
+//
+//	func main() {
+//		schema, err := config.DescribeConfigStruct(&app.Config{})
+//		if err != nil {
+//			log.Fatal(err)
+//		}
+//
+//		_ = schema.WriteFile("docs/config.md", config.SchemaFormatMarkdown)
+//		_ = schema.WriteFile("deploy/config.schema.json", config.SchemaFormatJSON)
+//		_ = schema.WriteFile(".env.example", config.SchemaFormatDotEnv)
+//	}
+func DescribeConfigStruct[T any](instance *T) (Schema, yaerrors.Error) {
+	value := reflect.ValueOf(instance).Elem()
+	if value.Kind() != reflect.Struct {
+		return nil, yaerrors.FromError(
+			http.StatusInternalServerError,
+			ErrConfigStructMustBeStruct,
+			fmt.Sprintf("describe config struct, got %T", instance),
+		)
+	}
+
+	var (
+		schema Schema
+		report loadReport
+	)
+
+	describeStruct(value, "", "", &schema, &report)
+
+	if len(report.errors) > 0 {
+		return nil, yaerrors.FromError(
+			http.StatusInternalServerError,
+			errors.Join(ErrInvalidFieldTag, report.errors),
+			fmt.Sprintf("describe config struct: invalid fields: %d", len(report.errors)),
+		)
+	}
+
+	return schema, nil
+}
+
+// Render prints the schema in format.
+//
+// Example:
+//
+//	table, err := schema.Render(config.SchemaFormatMarkdown)
+func (s Schema) Render(format SchemaFormat) ([]byte, yaerrors.Error) {
+	switch format {
+	case SchemaFormatJSON:
+		return s.renderJSONSchema()
+	case SchemaFormatMarkdown:
+		return []byte(s.renderMarkdown()), nil
+	case SchemaFormatDotEnv:
+		return []byte(s.renderDotEnv()), nil
+	default:
+		return nil, yaerrors.FromError(
+			http.StatusInternalServerError,
+			ErrUnsupportedSchemaFormat,
+			fmt.Sprintf("render schema: format %q", format),
+		)
+	}
+}
+
+// WriteFile renders the schema in format and writes it to path, creating the directory
+// when necessary.
+//
+// Example:
+//
+//	err := schema.WriteFile("docs/config.md", config.SchemaFormatMarkdown)
+func (s Schema) WriteFile(path string, format SchemaFormat) yaerrors.Error {
+	data, err := s.Render(format)
+	if err != nil {
+		return err.Wrap("write schema " + path)
+	}
+
+	if mkdirErr := os.MkdirAll(filepath.Dir(path), SchemaDirPerm); mkdirErr != nil {
+		return yaerrors.FromError(
+			http.StatusInternalServerError,
+			mkdirErr,
+			"create schema directory for "+path,
+		)
+	}
+
+	if writeErr := os.WriteFile(path, data, SchemaFilePerm); writeErr != nil {
+		return yaerrors.FromError(
+			http.StatusInternalServerError,
+			writeErr,
+			"write schema "+path,
+		)
+	}
+
+	return nil
+}
+
+// describeStruct appends the variables of structValue, nested under keyPath, to schema.
+// It mirrors loadConfigStructFromEnv field for field.
+func describeStruct(
+	structValue reflect.Value,
+	keyPath string,
+	fieldPath string,
+	schema *Schema,
+	report *loadReport,
+) {
+	structType := structValue.Type()
+
+	for i := range structValue.NumField() {
+		field := structType.Field(i)
+		fieldVal := structValue.Field(i)
+
+		if !fieldVal.CanSet() {
+			continue
+		}
+
+		path := joinFieldPath(fieldPath, field.Name)
+
+		options, err := parseFieldOptions(&field, fieldVal, keyPath, nil)
+		if err != nil {
+			report.add(joinEnvKey(keyPath, toScreamingSnakeCase(field.Name)), err)
+
+			continue
+		}
+
+		switch {
+		case isNestedStruct(field.Type):
+			describeStruct(fieldVal, options.prefix, path, schema, report)
+		case isNestedStruct(elemType(field.Type)):
+			element := reflect.New(elemType(field.Type)).Elem()
+
+			describeStruct(
+				element,
+				joinEnvKey(options.prefix, "0"),
+				path+"[0]",
+				schema,
+				report,
+			)
+		case isParsableType(field.Type):
+			*schema = append(*schema, describeField(&field, path, &options))
+		}
+	}
+}
+
+// describeField builds the schema of a parsable field.
+func describeField(field *reflect.StructField, path string, options *fieldOptions) FieldSchema {
+	fieldSchema := FieldSchema{
+		Key:         options.source.key,
+		Field:       path,
+		Type:        field.Type.String(),
+		Default:     field.Tag.Get(DefaultTagName),
+		Required:    options.required,
+		Secret:      options.source.secret,
+		Description: field.Tag.Get(DescriptionTagName),
+		Min:         field.Tag.Get(MinTagName),
+		Max:         field.Tag.Get(MaxTagName),
+		Regex:       field.Tag.Get(RegexTagName),
+		kind:        field.Type.Kind(),
+		jsonType:    jsonSchemaType(field.Type),
+	}
+
+	if oneOf, ok := field.Tag.Lookup(OneOfTagName); ok {
+		fieldSchema.OneOf = strings.Fields(oneOf)
+	}
+
+	// A malformed url or email tag fails the load itself; the schema just leaves it out.
+	fieldSchema.URL, _, _ = parseFlagTag(field, fieldSchema.Key, URLTagName)
+	fieldSchema.Email, _, _ = parseFlagTag(field, fieldSchema.Key, EmailTagName)
+
+	return fieldSchema
+}
+
+// jsonSchemaType returns the JSON Schema type of the values vType is parsed from.
+// Durations, URLs, unmarshalers, lists and maps are all written as strings.
+func jsonSchemaType(vType reflect.Type) string {
+	if vType == durationType || isUnmarshaler(vType) {
+		return jsonTypeString
+	}
+
+	switch vType.Kind() {
+	case reflect.Bool:
+		return jsonTypeBoolean
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Uintptr:
+		return jsonTypeInteger
+	case reflect.Float32, reflect.Float64:
+		return jsonTypeNumber
+	default:
+		return jsonTypeString
+	}
+}
+
+// jsonSchemaDocument is the root of a rendered JSON Schema.
+type jsonSchemaDocument struct {
+	Schema     string                        `json:"$schema"`
+	Type       string                        `json:"type"`
+	Properties map[string]jsonSchemaProperty `json:"properties"`
+	Required   []string                      `json:"required,omitempty"`
+}
+
+// jsonSchemaProperty is the JSON Schema of one variable.
+type jsonSchemaProperty struct {
+	Type        string   `json:"type"`
+	Description string   `json:"description,omitempty"`
+	Default     any      `json:"default,omitempty"`
+	Minimum     any      `json:"minimum,omitempty"`
+	Maximum     any      `json:"maximum,omitempty"`
+	MinLength   any      `json:"minLength,omitempty"`
+	MaxLength   any      `json:"maxLength,omitempty"`
+	Enum        []string `json:"enum,omitempty"`
+	Pattern     string   `json:"pattern,omitempty"`
+	Format      string   `json:"format,omitempty"`
+	WriteOnly   bool     `json:"writeOnly,omitempty"`
+}
+
+// renderJSONSchema prints the schema as a JSON Schema object keyed by variable.
+func (s Schema) renderJSONSchema() ([]byte, yaerrors.Error) {
+	document := jsonSchemaDocument{
+		Schema:     JSONSchemaDialect,
+		Type:       jsonTypeObject,
+		Properties: make(map[string]jsonSchemaProperty, len(s)),
+	}
+
+	for _, field := range s {
+		property := jsonSchemaProperty{
+			Type:        field.jsonType,
+			Description: field.describe(),
+			Pattern:     field.Regex,
+			WriteOnly:   field.Secret,
+		}
+
+		if field.Default != "" {
+			property.Default = field.jsonValue(field.Default)
+		}
+
+		if len(field.OneOf) > 0 && field.jsonType == jsonTypeString {
+			property.Enum = field.OneOf
+		}
+
+		switch {
+		case field.jsonType == jsonTypeInteger || field.jsonType == jsonTypeNumber:
+			property.Minimum = jsonBound(field.Min)
+			property.Maximum = jsonBound(field.Max)
+		case field.kind == reflect.String:
+			property.MinLength = jsonBound(field.Min)
+			property.MaxLength = jsonBound(field.Max)
+		}
+
+		switch {
+		case field.URL:
+			property.Format = jsonFormatURI
+		case field.Email:
+			property.Format = jsonFormatEmail
+		}
+
+		document.Properties[field.Key] = property
+
+		if field.Required {
+			document.Required = append(document.Required, field.Key)
+		}
+	}
+
+	data, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return nil, yaerrors.FromError(
+			http.StatusInternalServerError,
+			err,
+			"render json schema",
+		)
+	}
+
+	return append(data, '\n'), nil
+}
+
+// renderMarkdown prints the schema as a Markdown table.
+func (s Schema) renderMarkdown() string {
+	var builder strings.Builder
+
+	builder.WriteString("| Variable | Type | Default | Required | Secret | Description |\n")
+	builder.WriteString("| --- | --- | --- | --- | --- | --- |\n")
+
+	for _, field := range s {
+		fmt.Fprintf(
+			&builder,
+			"| `%s` | `%s` | %s | %s | %s | %s |\n",
+			field.Key,
+			field.Type,
+			markdownCode(field.Default),
+			markdownFlag(field.Required),
+			markdownFlag(field.Secret),
+			markdownEscape(field.describe()),
+		)
+	}
+
+	return builder.String()
+}
+
+// renderDotEnv prints the schema as a sample .env file: every variable preceded by a
+// comment and set to its default. Variables without a default and secrets are commented
+// out, so the sample never satisfies a required variable with an empty value.
+func (s Schema) renderDotEnv() string {
+	var builder strings.Builder
+
+	for i, field := range s {
+		if i > 0 {
+			builder.WriteString("\n")
+		}
+
+		fmt.Fprintf(&builder, "# %s\n", field.describe())
+
+		status := "optional"
+		if field.Required {
+			status = "required"
+		}
+
+		if field.Secret {
+			status += ", secret: or set " + field.Key + SecretFileSuffix
+		}
+
+		fmt.Fprintf(&builder, "# %s (%s)\n", field.Type, status)
+
+		if field.Default == "" || field.Secret {
+			fmt.Fprintf(&builder, "# %s=\n", field.Key)
+
+			continue
+		}
+
+		value := field.Default
+		if strings.ContainsAny(value, " #\"'") {
+			value = strconv.Quote(value)
+		}
+
+		fmt.Fprintf(&builder, "%s=%s\n", field.Key, value)
+	}
+
+	return builder.String()
+}
+
+// describe returns the description tag, or the Go field path without one, followed by
+// the validation rules.
+func (f *FieldSchema) describe() string {
+	parts := []string{f.Field}
+	if f.Description != "" {
+		parts = []string{f.Description}
+	}
+
+	if f.Min != "" {
+		parts = append(parts, "min "+f.Min)
+	}
+
+	if f.Max != "" {
+		parts = append(parts, "max "+f.Max)
+	}
+
+	if len(f.OneOf) > 0 {
+		parts = append(parts, "one of "+strings.Join(f.OneOf, ", "))
+	}
+
+	if f.Regex != "" {
+		parts = append(parts, "matches "+f.Regex)
+	}
+
+	if f.URL {
+		parts = append(parts, "absolute URL")
+	}
+
+	if f.Email {
+		parts = append(parts, "email address")
+	}
+
+	return strings.Join(parts, "; ")
+}
+
+// jsonValue returns raw as a JSON boolean or number when the field has that type and raw
+// parses as one, and as a string otherwise.
+func (f *FieldSchema) jsonValue(raw string) any {
+	switch f.jsonType {
+	case jsonTypeBoolean:
+		if value, err := strconv.ParseBool(raw); err == nil {
+			return value
+		}
+	case jsonTypeInteger, jsonTypeNumber:
+		if _, err := strconv.ParseFloat(raw, 64); err == nil {
+			return json.Number(raw)
+		}
+	}
+
+	return raw
+}
+
+// jsonBound returns a min or max tag as a JSON number, or nil when it is unset or not a
+// plain number.
+func jsonBound(raw string) any {
+	if _, err := strconv.ParseFloat(raw, 64); err != nil {
+		return nil
+	}
+
+	return json.Number(raw)
+}
+
+// joinFieldPath joins a parent Go field path and a field name.
+func joinFieldPath(fieldPath, name string) string {
+	if fieldPath == "" {
+		return name
+	}
+
+	return fieldPath + "." + name
+}
+
+// markdownCode wraps value in backticks, leaving an empty value empty.
+func markdownCode(value string) string {
+	if value == "" {
+		return ""
+	}
+
+	return "`" + markdownEscape(value) + "`"
+}
+
+// markdownFlag prints a boolean column.
+func markdownFlag(value bool) string {
+	if value {
+		return "yes"
+	}
+
+	return "no"
+}
+
+// markdownEscape keeps value from breaking a table cell.
+func markdownEscape(value string) string {
+	return strings.ReplaceAll(value, "|", `\|`)
+}
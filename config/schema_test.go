@@ -0,0 +1,178 @@
+package config_test
+
+import (
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/YaCodeDev/GoYaCodeDevUtils/config"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+type schemaUpstream struct {
+	Name string
+}
+
+type schemaConfig struct {
+	Database  taggedDatabase `envPrefix:"DB"`
+	Port      int            `env:"HTTP_PORT" default:"8080" min:"1" max:"65535"`
+	Mode      string         `default:"dev" oneof:"dev prod" description:"Deployment mode"`
+	Timeout   time.Duration  `default:"5s"`
+	Debug     bool           `default:"false"`
+	Upstreams []schemaUpstream
+	Comment   string `optional:"true" max:"80"`
+}
+
+func TestDescribeConfigStruct(t *testing.T) {
+	schema, err := config.DescribeConfigStruct(&schemaConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := config.Schema{
+		{Key: "DB_HOST", Field: "Database.Host", Type: "string", Required: true},
+		{Key: "DB_PASSWORD", Field: "Database.Password", Type: "string", Required: true, Secret: true},
+		{
+			Key:     "HTTP_PORT",
+			Field:   "Port",
+			Type:    "int",
+			Default: "8080",
+			Min:     "1",
+			Max:     "65535",
+		},
+		{
+			Key:         "MODE",
+			Field:       "Mode",
+			Type:        "string",
+			Default:     "dev",
+			Description: "Deployment mode",
+			OneOf:       []string{"dev", "prod"},
+		},
+		{Key: "TIMEOUT", Field: "Timeout", Type: "time.Duration", Default: "5s"},
+		{Key: "DEBUG", Field: "Debug", Type: "bool", Default: "false"},
+		{Key: "UPSTREAMS_0_NAME", Field: "Upstreams[0].Name", Type: "string", Required: true},
+		{Key: "COMMENT", Field: "Comment", Type: "string", Max: "80"},
+	}
+
+	if diff := cmp.Diff(want, schema, cmpopts.IgnoreUnexported(config.FieldSchema{})); diff != "" {
+		t.Errorf("unexpected schema, diff: %s", diff)
+	}
+}
+
+func TestDescribeConfigStructPresetIsOptional(t *testing.T) {
+	schema, err := config.DescribeConfigStruct(&taggedFlat{Region: "us"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(schema) != 1 || schema[0].Required {
+		t.Errorf("expected one optional field, got %+v", schema)
+	}
+}
+
+func TestDescribeConfigStructTagConflict(t *testing.T) {
+	var configInstance struct {
+		Value string `required:"true" optional:"true"`
+	}
+
+	_, err := config.DescribeConfigStruct(&configInstance)
+	if !errors.Is(err, config.ErrConflictingFieldTags) {
+		t.Fatalf("expected ErrConflictingFieldTags, got %v", err)
+	}
+}
+
+func TestSchemaRenderJSON(t *testing.T) {
+	schema, err := config.DescribeConfigStruct(&schemaConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := schema.Render(config.SchemaFormatJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var document struct {
+		Properties map[string]map[string]any `json:"properties"`
+		Required   []string                  `json:"required"`
+	}
+
+	if unmarshalErr := json.Unmarshal(data, &document); unmarshalErr != nil {
+		t.Fatal(unmarshalErr)
+	}
+
+	port := document.Properties["HTTP_PORT"]
+	if port["type"] != "integer" || port["default"] != 8080.0 || port["maximum"] != 65535.0 {
+		t.Errorf("unexpected HTTP_PORT schema %v", port)
+	}
+
+	if mode := document.Properties["MODE"]; mode["enum"] == nil || mode["type"] != "string" {
+		t.Errorf("unexpected MODE schema %v", mode)
+	}
+
+	if comment := document.Properties["COMMENT"]; comment["maxLength"] != 80.0 {
+		t.Errorf("unexpected COMMENT schema %v", comment)
+	}
+
+	if password := document.Properties["DB_PASSWORD"]; password["writeOnly"] != true {
+		t.Errorf("expected DB_PASSWORD to be write-only, got %v", password)
+	}
+
+	wantRequired := []string{"DB_HOST", "DB_PASSWORD", "UPSTREAMS_0_NAME"}
+	if diff := cmp.Diff(wantRequired, document.Required); diff != "" {
+		t.Errorf("unexpected required list, diff: %s", diff)
+	}
+}
+
+func TestSchemaRenderMarkdownAndDotEnv(t *testing.T) {
+	schema, err := config.DescribeConfigStruct(&schemaConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	markdownPath := filepath.Join(dir, "docs", "config.md")
+	dotEnvPath := filepath.Join(dir, ".env.example")
+
+	if err = schema.WriteFile(markdownPath, config.SchemaFormatMarkdown); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = schema.WriteFile(dotEnvPath, config.SchemaFormatDotEnv); err != nil {
+		t.Fatal(err)
+	}
+
+	markdown, err := schema.Render(config.SchemaFormatMarkdown)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantRow := "| `HTTP_PORT` | `int` | `8080` | no | no | Port; min 1; max 65535 |"
+	if !strings.Contains(string(markdown), wantRow) {
+		t.Errorf("expected the markdown row %q in:\n%s", wantRow, markdown)
+	}
+
+	values, err := config.LoadConfigStructFromSources(
+		&struct {
+			Port int    `env:"HTTP_PORT"`
+			Mode string `optional:"true"`
+		}{},
+		nil,
+		config.NewDotEnvFileSource(dotEnvPath),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if values["HTTP_PORT"] == "" {
+		t.Errorf("expected the sample .env to set HTTP_PORT, got origins %v", values)
+	}
+
+	if _, err = schema.Render("yaml"); !errors.Is(err, config.ErrUnsupportedSchemaFormat) {
+		t.Fatalf("expected ErrUnsupportedSchemaFormat, got %v", err)
+	}
+}
@@ -0,0 +1,537 @@
+package config
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yaerrors"
+	"github.com/goccy/go-yaml"
+	"github.com/pelletier/go-toml/v2"
+)
+
+// Source is one layer of configuration values for LoadConfigStructFromSources.
+//
+// Values returns the layer flattened to the SCREAMING_SNAKE_CASE keys the struct loader
+// reads, exactly as if they were environment variables: nested objects are joined with
+// EnvKeySeparator, lists of scalars are joined with a comma and lists of objects become
+// indexed keys (UPSTREAMS_0_NAME). An object of scalars is also kept whole under its own
+// key in the k:v,k2:v2 form map fields read, with its keys as written, so
+// labels: {team: core} loads into a map[string]string field as well as a struct. Name
+// identifies the layer in Origins.
+type Source interface {
+	Name() string
+	Values() (map[string]string, yaerrors.Error)
+}
+
+// Origins maps the key of every loaded config field to the name of the Source its final
+// value came from, SourceNameDefault for a default tag or SourceNamePreset for a value
+// the struct already held.
+//
+// Example:
+//
+//	origins, err := config.LoadConfigStructFromSources(&cfg, log, sources...)
+//	fmt.Println(origins["DB_HOST"]) // yaml:config.yaml
+type Origins map[string]string
+
+// DefaultSources returns the standard layering of a service, lowest precedence first:
+// <base>.yaml, <base>.toml, <base>.json, .env, the process environment and args parsed
+// as command-line flags. Missing files are skipped, so a deployment ships only the
+// files it needs.
+//
+// Example:
+//
+//	origins, err := config.LoadConfigStructFromSources(
+//		&cfg,
+//		log,
+//		config.DefaultSources("config", os.Args[1:])...,
+//	)
+func DefaultSources(base string, args []string) []Source {
+	return []Source{
+		NewYAMLFileSource(base + "." + SourceKindYAML),
+		NewTOMLFileSource(base + "." + SourceKindTOML),
+		NewJSONFileSource(base + "." + SourceKindJSON),
+		NewDotEnvFileSource(DotEnvFile),
+		NewEnvSource(),
+		NewFlagSource(args),
+	}
+}
+
+// NewYAMLFileSource returns a Source that reads the YAML file at path. Keys in any case
+// style (db_host, dbHost, db-host) map to DB_HOST. A missing file yields no values.
+func NewYAMLFileSource(path string) Source {
+	return &fileSource{kind: SourceKindYAML, path: path, decode: yaml.Unmarshal}
+}
+
+// NewTOMLFileSource returns a Source that reads the TOML file at path. Tables nest like
+// structs, so [db] host = "..." maps to DB_HOST. A missing file yields no values.
+func NewTOMLFileSource(path string) Source {
+	return &fileSource{kind: SourceKindTOML, path: path, decode: toml.Unmarshal}
+}
+
+// NewJSONFileSource returns a Source that reads the JSON object at path. Numbers keep
+// their literal text. A missing file yields no values.
+func NewJSONFileSource(path string) Source {
+	return &fileSource{kind: SourceKindJSON, path: path, decode: decodeJSONNumbers}
+}
+
+// NewDotEnvFileSource returns a Source that reads KEY=value lines from the file at path
+// with the syntax of LoadDotEnv. Unlike LoadDotEnv it leaves the process environment
+// untouched. A missing file yields no values.
+func NewDotEnvFileSource(path string) Source {
+	return &dotEnvSource{path: path}
+}
+
+// NewEnvSource returns a Source that reads the process environment.
+func NewEnvSource() Source {
+	return &envValuesSource{}
+}
+
+// NewFlagSource returns a Source that reads command-line flags from args, usually
+// os.Args[1:]. Both -name and --name are accepted, with the value after = or in the next
+// argument; a flag followed by another flag or by nothing is set to true. A number is a
+// value, not a flag, so --offset -5 sets OFFSET to -5. Names map like file keys, so
+// --db-host reads into DB_HOST. Arguments that are not flags are ignored and parsing
+// stops at --.
+func NewFlagSource(args []string) Source {
+	return &flagSource{args: args}
+}
+
+// NewMapSource returns a Source named name over fixed values, which suits programmatic
+// defaults and tests. Keys map like file keys.
+func NewMapSource(name string, values map[string]string) Source {
+	return &mapSource{name: name, values: values}
+}
+
+// fileSource reads a structured file and flattens it into keys.
+type fileSource struct {
+	kind   string
+	path   string
+	decode func(data []byte, out any) error
+}
+
+// Name returns the kind and path of the file, e.g. yaml:config.yaml.
+func (s *fileSource) Name() string {
+	return s.kind + SourceNameSep + s.path
+}
+
+// Values decodes the file and flattens it.
+func (s *fileSource) Values() (map[string]string, yaerrors.Error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, yaerrors.FromError(
+			http.StatusInternalServerError,
+			errors.Join(err, ErrFailedToReadSource),
+			"read config source "+s.Name(),
+		)
+	}
+
+	raw := map[string]any{}
+	if err = s.decode(data, &raw); err != nil {
+		return nil, yaerrors.FromError(
+			http.StatusInternalServerError,
+			errors.Join(err, ErrFailedToDecodeSource),
+			"decode config source "+s.Name(),
+		)
+	}
+
+	values := map[string]string{}
+	flattener := sourceFlattener{normalize: sourceKey, indexObjects: true}
+
+	if flattenErr := flattener.flatten("", raw, values); flattenErr != nil {
+		return nil, flattenErr.Wrap("flatten config source " + s.Name())
+	}
+
+	return values, nil
+}
+
+// filePath returns the path of the file, so a Watcher can poll it.
+func (s *fileSource) filePath() string {
+	return s.path
+}
+
+// dotEnvSource reads a .env file.
+type dotEnvSource struct {
+	path string
+}
+
+// Name returns the path of the file, e.g. dotenv:.env.
+func (s *dotEnvSource) Name() string {
+	return SourceKindDotEnv + SourceNameSep + s.path
+}
+
+// Values parses the file.
+func (s *dotEnvSource) Values() (map[string]string, yaerrors.Error) {
+	if _, err := os.Stat(s.path); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	return readDotEnvFile(s.path)
+}
+
+// filePath returns the path of the file, so a Watcher can poll it.
+func (s *dotEnvSource) filePath() string {
+	return s.path
+}
+
+// envValuesSource reads the process environment.
+type envValuesSource struct{}
+
+// Name returns SourceNameEnv.
+func (s *envValuesSource) Name() string {
+	return SourceNameEnv
+}
+
+// Values snapshots the environment.
+func (s *envValuesSource) Values() (map[string]string, yaerrors.Error) {
+	values := map[string]string{}
+
+	for _, entry := range os.Environ() {
+		key, value, _ := strings.Cut(entry, "=")
+		values[key] = value
+	}
+
+	return values, nil
+}
+
+// flagSource reads command-line flags.
+type flagSource struct {
+	args []string
+}
+
+// Name returns SourceNameFlags.
+func (s *flagSource) Name() string {
+	return SourceNameFlags
+}
+
+// Values parses the flags of args.
+func (s *flagSource) Values() (map[string]string, yaerrors.Error) {
+	values := map[string]string{}
+
+	for i := 0; i < len(s.args); i++ {
+		arg := s.args[i]
+		if arg == FlagTerminator {
+			break
+		}
+
+		if !isFlagArg(arg) {
+			continue
+		}
+
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, FlagPrefix), FlagKVSeparator)
+		if !hasValue {
+			value = FlagTrueValue
+
+			if i+1 < len(s.args) && !isFlagArg(s.args[i+1]) && s.args[i+1] != FlagTerminator {
+				i++
+				value = s.args[i]
+			}
+		}
+
+		values[sourceKey(name)] = value
+	}
+
+	return values, nil
+}
+
+// isFlagArg reports whether arg names a flag: it starts with FlagPrefix, has a name after
+// it and is not a number, so a negative value such as -5 is read as a value.
+func isFlagArg(arg string) bool {
+	if !strings.HasPrefix(arg, FlagPrefix) || strings.TrimLeft(arg, FlagPrefix) == "" {
+		return false
+	}
+
+	_, err := strconv.ParseFloat(arg, 64)
+
+	return err != nil
+}
+
+// mapSource serves fixed values.
+type mapSource struct {
+	name   string
+	values map[string]string
+}
+
+// Name returns the name of the map, e.g. map:defaults.
+func (s *mapSource) Name() string {
+	return SourceKindMap + SourceNameSep + s.name
+}
+
+// Values returns the values under their normalized keys.
+func (s *mapSource) Values() (map[string]string, yaerrors.Error) {
+	values := make(map[string]string, len(s.values))
+
+	for key, value := range s.values {
+		values[sourceKey(key)] = value
+	}
+
+	return values, nil
+}
+
+// layeredValues is the merged view of the sources of one load. A nil *layeredValues reads
+// the process environment, which is what the env-only loaders use.
+type layeredValues struct {
+	values  map[string]string
+	origins map[string]string
+}
+
+// newLayeredValues merges sources, each overriding the keys of the ones before it.
+func newLayeredValues(sources []Source) (*layeredValues, yaerrors.Error) {
+	layers := &layeredValues{values: map[string]string{}, origins: map[string]string{}}
+
+	for _, source := range sources {
+		values, err := source.Values()
+		if err != nil {
+			return nil, err.Wrap("load config source " + source.Name())
+		}
+
+		for key, value := range values {
+			layers.values[key] = value
+			layers.origins[key] = source.Name()
+		}
+	}
+
+	return layers, nil
+}
+
+// lookup returns the value of key and whether any source set it.
+func (l *layeredValues) lookup(key string) (string, bool) {
+	if l == nil {
+		return os.LookupEnv(key)
+	}
+
+	value, exists := l.values[key]
+
+	return value, exists
+}
+
+// origin returns the name of the source that set key, or an empty string.
+func (l *layeredValues) origin(key string) string {
+	if l == nil {
+		if _, exists := os.LookupEnv(key); exists {
+			return SourceNameEnv
+		}
+
+		return ""
+	}
+
+	return l.origins[key]
+}
+
+// hasPrefix reports whether any key starts with prefix.
+func (l *layeredValues) hasPrefix(prefix string) bool {
+	if l == nil {
+		return hasEnvPrefix(prefix)
+	}
+
+	for key := range l.values {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// sourceKey maps a file key or flag name in any case style to the SCREAMING_SNAKE_CASE
+// key of the struct loader: db-host, db.host, dbHost and DB_HOST all become DB_HOST.
+func sourceKey(name string) string {
+	return toScreamingSnakeCase(sourceKeyReplacer.Replace(name))
+}
+
+// decodeJSONNumbers decodes JSON keeping numbers as json.Number, so large integers
+// survive flattening.
+func decodeJSONNumbers(data []byte, out any) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	return decoder.Decode(out)
+}
+
+// sourceFlattener flattens a decoded document into keys. normalize names each key;
+// indexObjects turns a list of objects into indexed keys instead of rejecting it.
+type sourceFlattener struct {
+	normalize    func(string) string
+	indexObjects bool
+}
+
+// flatten stores raw into out, joining nested keys under prefix.
+func (f *sourceFlattener) flatten(
+	prefix string,
+	raw map[string]any,
+	out map[string]string,
+) yaerrors.Error {
+	for key, value := range raw {
+		envKey := joinEnvKey(prefix, f.normalize(key))
+
+		switch typed := value.(type) {
+		case nil:
+			continue
+		case map[string]any:
+			if err := f.flatten(envKey, typed, out); err != nil {
+				return err
+			}
+
+			if entries, ok := mapEntries(typed); ok {
+				out[envKey] = entries
+			}
+		case []any:
+			if err := f.flattenArray(envKey, typed, out); err != nil {
+				return err.Wrap("flatten array " + envKey)
+			}
+		default:
+			scalar, scalarErr := sourceScalar(typed)
+			if scalarErr != nil {
+				return scalarErr.Wrap("flatten value " + envKey)
+			}
+
+			out[envKey] = scalar
+		}
+	}
+
+	return nil
+}
+
+// flattenArray stores a list of scalars as one comma-separated value and, with
+// indexObjects, a list of objects as the indexed keys slices of structs are read from.
+// An item holding the comma itself fails with ErrSeparatorInListItem, since the list
+// parser would split it into several items.
+func (f *sourceFlattener) flattenArray(
+	envKey string,
+	values []any,
+	out map[string]string,
+) yaerrors.Error {
+	parts := make([]string, 0, len(values))
+
+	for i, value := range values {
+		if object, ok := value.(map[string]any); ok && f.indexObjects {
+			if err := f.flatten(joinEnvKey(envKey, strconv.Itoa(i)), object, out); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		scalar, scalarErr := sourceScalar(value)
+		if scalarErr != nil {
+			return scalarErr
+		}
+
+		if strings.Contains(scalar, YaToolsArraySeparator) {
+			return yaerrors.FromError(
+				http.StatusInternalServerError,
+				ErrSeparatorInListItem,
+				fmt.Sprintf("%s item %d: %q contains %q", envKey, i, scalar, YaToolsArraySeparator),
+			)
+		}
+
+		parts = append(parts, scalar)
+	}
+
+	if len(parts) > 0 || len(values) == 0 {
+		out[envKey] = strings.Join(parts, YaToolsArraySeparator)
+	}
+
+	return nil
+}
+
+// mapEntries formats an object of scalars and lists of scalars in the k:v,k2:v2 entry
+// form map fields are parsed from, keeping the keys as written so labels: {Team: core}
+// reads into map key Team. Entries are sorted by key, so an unchanged object always
+// yields the same value. It reports false for an object holding nested objects, which
+// only a struct can load, or a key or value holding a separator it would be split at.
+func mapEntries(object map[string]any) (string, bool) {
+	entrySeparator, kvSeparator := ListSeparators[:1], MapKVSeparators[:1]
+	entries := make([]string, 0, len(object))
+
+	for key, value := range object {
+		var (
+			formatted string
+			ok        bool
+		)
+
+		switch typed := value.(type) {
+		case nil:
+			continue
+		case map[string]any:
+			return "", false
+		case []any:
+			formatted, ok = entryList(typed)
+		default:
+			scalar, err := sourceScalar(typed)
+			formatted, ok = scalar, err == nil
+		}
+
+		if !ok || strings.ContainsAny(key, entrySeparator+kvSeparator) ||
+			strings.Contains(formatted, entrySeparator) {
+			return "", false
+		}
+
+		entries = append(entries, key+kvSeparator+formatted)
+	}
+
+	slices.Sort(entries)
+
+	return strings.Join(entries, entrySeparator), true
+}
+
+// entryList formats a list of scalars as a map value, joined with the list separator of
+// the nesting level below the map entries.
+func entryList(values []any) (string, bool) {
+	parts := make([]string, 0, len(values))
+
+	for _, value := range values {
+		scalar, err := sourceScalar(value)
+		if err != nil || strings.Contains(scalar, ListSeparators[1:2]) {
+			return "", false
+		}
+
+		parts = append(parts, scalar)
+	}
+
+	return strings.Join(parts, ListSeparators[1:2]), true
+}
+
+// sourceScalar formats a decoded scalar the way the struct loader parses it back.
+func sourceScalar(value any) (string, yaerrors.Error) {
+	switch typed := value.(type) {
+	case string:
+		return typed, nil
+	case bool:
+		return strconv.FormatBool(typed), nil
+	case json.Number:
+		return typed.String(), nil
+	case encoding.TextMarshaler:
+		text, err := typed.MarshalText()
+		if err == nil {
+			return string(text), nil
+		}
+	}
+
+	switch reflected := reflect.ValueOf(value); reflected.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(reflected.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(reflected.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(reflected.Float(), 'f', -1, reflected.Type().Bits()), nil
+	default:
+		return "", yaerrors.FromError(
+			http.StatusInternalServerError,
+			ErrUnsupportedSourceValue,
+			fmt.Sprintf("config source value of type %T", value),
+		)
+	}
+}
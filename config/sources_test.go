@@ -0,0 +1,270 @@
+package config_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/YaCodeDev/GoYaCodeDevUtils/config"
+	"github.com/google/go-cmp/cmp"
+)
+
+type sourcesUpstream struct {
+	Name   string
+	Weight int `default:"1"`
+}
+
+type sourcesDatabase struct {
+	Host     string
+	Port     int    `default:"5432"`
+	Password string `secret:"true" optional:"true"`
+}
+
+type sourcesConfig struct {
+	Database  sourcesDatabase `envPrefix:"DB"`
+	Timeout   time.Duration
+	Tags      []string
+	Upstreams []sourcesUpstream
+	Labels    map[string]string
+	Workers   int  `default:"4"`
+	Verbose   bool `optional:"true"`
+	Region    string
+}
+
+func writeSourceFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestLoadConfigStructFromSourcesPrecedence(t *testing.T) {
+	dir := t.TempDir()
+
+	yamlPath := writeSourceFile(t, dir, "config.yaml", `
+db:
+  host: yaml.local
+  port: 6543
+timeout: 5s
+tags: [a, b]
+upstreams:
+  - name: first
+    weight: 3
+  - name: second
+labels:
+  team: core
+  Env: prod
+region: yaml
+`)
+	tomlPath := writeSourceFile(t, dir, "config.toml", `
+region = "toml"
+
+[db]
+host = "toml.local"
+`)
+	dotEnvPath := writeSourceFile(t, dir, ".env", "REGION=dotenv\n")
+
+	t.Setenv("REGION", "env")
+
+	var cfg sourcesConfig
+
+	origins, err := config.LoadConfigStructFromSources(
+		&cfg,
+		nil,
+		config.NewYAMLFileSource(yamlPath),
+		config.NewTOMLFileSource(tomlPath),
+		config.NewJSONFileSource(filepath.Join(dir, "missing.json")),
+		config.NewDotEnvFileSource(dotEnvPath),
+		config.NewEnvSource(),
+		config.NewFlagSource([]string{"--verbose", "--db-password=flag-secret", "positional"}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := sourcesConfig{
+		Database:  sourcesDatabase{Host: "toml.local", Port: 6543, Password: "flag-secret"},
+		Timeout:   5 * time.Second,
+		Tags:      []string{"a", "b"},
+		Upstreams: []sourcesUpstream{{Name: "first", Weight: 3}, {Name: "second", Weight: 1}},
+		Labels:    map[string]string{"team": "core", "Env": "prod"},
+		Workers:   4,
+		Verbose:   true,
+		Region:    "env",
+	}
+
+	if diff := cmp.Diff(want, cfg); diff != "" {
+		t.Errorf("unexpected config, diff: %s", diff)
+	}
+
+	wantOrigins := config.Origins{
+		"DB_HOST":            "toml:" + tomlPath,
+		"DB_PORT":            "yaml:" + yamlPath,
+		"DB_PASSWORD":        config.SourceNameFlags,
+		"TIMEOUT":            "yaml:" + yamlPath,
+		"TAGS":               "yaml:" + yamlPath,
+		"UPSTREAMS_0_NAME":   "yaml:" + yamlPath,
+		"UPSTREAMS_0_WEIGHT": "yaml:" + yamlPath,
+		"UPSTREAMS_1_NAME":   "yaml:" + yamlPath,
+		"UPSTREAMS_1_WEIGHT": config.SourceNameDefault,
+		"LABELS":             "yaml:" + yamlPath,
+		"WORKERS":            config.SourceNameDefault,
+		"VERBOSE":            config.SourceNameFlags,
+		"REGION":             config.SourceNameEnv,
+	}
+
+	if diff := cmp.Diff(wantOrigins, origins); diff != "" {
+		t.Errorf("unexpected origins, diff: %s", diff)
+	}
+}
+
+func TestLoadConfigStructFromSourcesReadsMapsFromObjects(t *testing.T) {
+	base := config.NewMapSource("base", map[string]string{
+		"DB_HOST":          "base.local",
+		"TIMEOUT":          "1s",
+		"TAGS":             "a",
+		"UPSTREAMS_0_NAME": "base",
+		"REGION":           "base",
+	})
+
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		source  func(path string) config.Source
+	}{
+		{
+			name:    "YAML mapping",
+			file:    "config.yaml",
+			content: "labels:\n  team: core\n  Env: prod\n  zones: [a, b]\n",
+			source:  config.NewYAMLFileSource,
+		},
+		{
+			name:    "TOML table",
+			file:    "config.toml",
+			content: "[labels]\nteam = \"core\"\nEnv = \"prod\"\nzones = [\"a\", \"b\"]\n",
+			source:  config.NewTOMLFileSource,
+		},
+		{
+			name:    "JSON object",
+			file:    "config.json",
+			content: `{"labels": {"team": "core", "Env": "prod", "zones": ["a", "b"]}}`,
+			source:  config.NewJSONFileSource,
+		},
+	}
+
+	want := map[string]string{"team": "core", "Env": "prod", "zones": "a;b"}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeSourceFile(t, t.TempDir(), tt.file, tt.content)
+
+			var cfg sourcesConfig
+
+			if _, err := config.LoadConfigStructFromSources(&cfg, nil, base, tt.source(path)); err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(want, cfg.Labels); diff != "" {
+				t.Errorf("unexpected labels, diff: %s", diff)
+			}
+		})
+	}
+}
+
+func TestLoadConfigStructFromSourcesIgnoresEnvironment(t *testing.T) {
+	t.Setenv("REGION", "env")
+
+	cfg := struct {
+		Region  string `default:"eu"`
+		Workers int
+	}{Workers: 2}
+
+	origins, err := config.LoadConfigStructFromSources(
+		&cfg,
+		nil,
+		config.NewMapSource("defaults", map[string]string{"workers": "8"}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Region != "eu" || cfg.Workers != 8 {
+		t.Errorf("expected the default region and mapped workers, got %+v", cfg)
+	}
+
+	if origins["REGION"] != config.SourceNameDefault || origins["WORKERS"] != "map:defaults" {
+		t.Errorf("unexpected origins %v", origins)
+	}
+}
+
+func TestFlagSourceReadsNegativeNumbersAsValues(t *testing.T) {
+	var cfg struct {
+		Offset int
+		Ratio  float64
+		Debug  bool
+		Late   bool `optional:"true"`
+	}
+
+	_, err := config.LoadConfigStructFromSources(
+		&cfg,
+		nil,
+		config.NewFlagSource([]string{"--offset", "-5", "-ratio", "-0.5", "--debug", "--", "--late"}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Offset != -5 || cfg.Ratio != -0.5 || !cfg.Debug || cfg.Late {
+		t.Errorf("unexpected config %+v", cfg)
+	}
+}
+
+func TestLoadConfigStructFromSourcesDecodeError(t *testing.T) {
+	path := writeSourceFile(t, t.TempDir(), "config.toml", "region = ")
+
+	var cfg sourcesConfig
+
+	_, err := config.LoadConfigStructFromSources(&cfg, nil, config.NewTOMLFileSource(path))
+	if !errors.Is(err, config.ErrFailedToDecodeSource) {
+		t.Fatalf("expected ErrFailedToDecodeSource, got %v", err)
+	}
+}
+
+func TestLoadConfigStructFromSourcesRejectsSeparatorInListItem(t *testing.T) {
+	path := writeSourceFile(t, t.TempDir(), "config.yaml", "hosts: [\"a,b\", c]\n")
+
+	var cfg struct {
+		Hosts []string
+	}
+
+	_, err := config.LoadConfigStructFromSources(&cfg, nil, config.NewYAMLFileSource(path))
+	if !errors.Is(err, config.ErrSeparatorInListItem) {
+		t.Fatalf("expected ErrSeparatorInListItem, got %v", err)
+	}
+
+	if !strings.Contains(err.Error(), "HOSTS") {
+		t.Errorf("the error should name the key, got %v", err)
+	}
+}
+
+func TestLoadConfigStructFromSourcesReportsFieldErrors(t *testing.T) {
+	path := writeSourceFile(t, t.TempDir(), "config.json", `{"workers": "many"}`)
+
+	var cfg struct {
+		Workers int
+	}
+
+	_, err := config.LoadConfigStructFromSources(&cfg, nil, config.NewJSONFileSource(path))
+	if !errors.Is(err, config.ErrUnparsableValue) {
+		t.Fatalf("expected ErrUnparsableValue, got %v", err)
+	}
+}
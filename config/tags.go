@@ -0,0 +1,133 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yaerrors"
+)
+
+// fieldOptions is what the struct tags of a config field resolve to.
+type fieldOptions struct {
+	// source is the variable a leaf field is read from.
+	source envSource
+	// prefix is the key path handed down to the fields of a nested struct.
+	prefix string
+	// required fails the load when the variable is missing.
+	required bool
+}
+
+// parseFieldOptions resolves the env, envPrefix, required, optional and secret tags of
+// field, nested under keyPath. The source of the field reads from layers.
+//
+// The env tag replaces the SCREAMING_SNAKE_CASE name of the field, envPrefix replaces it
+// for a nested struct and may be empty to flatten the struct into its parent. Both are
+// still joined under the prefix of the parent struct. Without a required or optional tag
+// a field is required when it is zero and has no default tag, except a slice of structs:
+// no default tag can fill one, so it is optional unless tagged required.
+func parseFieldOptions(
+	field *reflect.StructField,
+	fieldVal reflect.Value,
+	keyPath string,
+	layers *layeredValues,
+) (fieldOptions, yaerrors.Error) {
+	name := toScreamingSnakeCase(field.Name)
+
+	if env := field.Tag.Get(EnvTagName); env != "" {
+		name = env
+	}
+
+	if prefix, ok := field.Tag.Lookup(EnvPrefixTagName); ok && field.Type.Kind() == reflect.Struct {
+		name = strings.TrimSuffix(prefix, EnvKeySeparator)
+	}
+
+	key := joinEnvKey(keyPath, name)
+
+	secret, _, err := parseFlagTag(field, key, SecretTagName)
+	if err != nil {
+		return fieldOptions{}, err
+	}
+
+	required, requiredSet, err := parseFlagTag(field, key, RequiredTagName)
+	if err != nil {
+		return fieldOptions{}, err
+	}
+
+	optional, optionalSet, err := parseFlagTag(field, key, OptionalTagName)
+	if err != nil {
+		return fieldOptions{}, err
+	}
+
+	switch {
+	case requiredSet && optionalSet:
+		return fieldOptions{}, yaerrors.FromError(
+			http.StatusInternalServerError,
+			&FieldError{
+				Key:    key,
+				Reason: "field tags cannot combine required and optional",
+				Err:    ErrConflictingFieldTags,
+			},
+			"config loader: field "+field.Name,
+		)
+	case optionalSet:
+		required = !optional
+	case !requiredSet:
+		required = fieldVal.IsZero() && field.Tag.Get(DefaultTagName) == "" &&
+			!isNestedStruct(elemType(field.Type))
+	}
+
+	return fieldOptions{
+		source:   envSource{key: key, secret: secret, strict: true, layers: layers},
+		prefix:   key,
+		required: required,
+	}, nil
+}
+
+// parseFlagTag reads a boolean tag such as `secret:"true"` of the field read from key.
+// An empty value counts as true; set reports whether the tag is present at all.
+func parseFlagTag(
+	field *reflect.StructField,
+	key string,
+	tag string,
+) (value, set bool, err yaerrors.Error) {
+	raw, set := field.Tag.Lookup(tag)
+	if !set {
+		return false, false, nil
+	}
+
+	if raw == "" {
+		return true, true, nil
+	}
+
+	value, parseErr := strconv.ParseBool(raw)
+	if parseErr != nil {
+		return false, true, yaerrors.FromError(
+			http.StatusInternalServerError,
+			&FieldError{
+				Key:    key,
+				Value:  raw,
+				Reason: fmt.Sprintf("%s tag must be a boolean", tag),
+				Err:    errors.Join(parseErr, ErrInvalidFieldTag),
+			},
+			"config loader: field "+field.Name,
+		)
+	}
+
+	return value, true, nil
+}
+
+// joinEnvKey joins a parent key path and a field name, skipping empty parts.
+func joinEnvKey(keyPath, name string) string {
+	switch {
+	case keyPath == "":
+		return name
+	case name == "":
+		return keyPath
+	default:
+		return keyPath + EnvKeySeparator + name
+	}
+}
@@ -7,7 +7,6 @@ import (
 	"reflect"
 	"strings"
 
-	"github.com/YaCodeDev/GoYaCodeDevUtils/valueparser"
 	"github.com/YaCodeDev/GoYaCodeDevUtils/yaerrors"
 	"github.com/YaCodeDev/GoYaCodeDevUtils/yalogger"
 )
@@ -34,338 +33,39 @@ func toScreamingSnakeCase(s string) string {
 	return strings.ToUpper(s)
 }
 
-func copyMap(src reflect.Value, dst reflect.Value) {
-	if src.Kind() != reflect.Map || dst.Kind() != reflect.Map {
-		panic("Both src and dst must be maps")
-	}
-
-	if dst.IsNil() {
-		dst.Set(reflect.MakeMap(dst.Type()))
-	}
-
-	if src.IsNil() {
-		return
-	}
-
-	var (
-		convertedKey reflect.Value
-		convertedVal reflect.Value
-		err          error
-	)
-
-	for _, key := range src.MapKeys() {
-		val := src.MapIndex(key)
-
-		convertedKey, err = valueparser.ConvertValue(key, dst.Type().Key())
-		if err != nil {
-			panic("Cannot convert key: " + err.Error())
-		}
-
-		convertedVal, err = valueparser.ConvertValue(val, dst.Type().Elem())
-		if err != nil {
-			panic("Cannot convert value: " + err.Error())
-		}
-
-		dst.SetMapIndex(convertedKey, convertedVal)
-	}
-}
-
-// copyArray copies elements from the source slice to the destination slice.
-// It ensures that the destination slice is initialized and has the same length as the source slice.
-// If the source slice is nil, the destination slice remains unchanged.
-// If the source slice is not nil, it copies each element from the source to the destination,
-// converting the type if necessary.
-// It panics if the source or destination is not a slice.
-func copyArray(src, dst reflect.Value) {
-	if !dst.IsValid() {
-		panic("Destination slice is not valid")
-	}
-
-	if src.Kind() != reflect.Slice || dst.Kind() != reflect.Slice {
-		panic("Both src and dst must be slices")
-	}
-
-	if !dst.CanSet() {
-		panic("Destination slice cannot be set")
-	}
-
-	if dst.IsNil() {
-		dst.Set(reflect.MakeSlice(dst.Type(), src.Len(), src.Cap()))
-	}
-
-	if src.IsNil() {
-		return
-	}
-
-	if src.Len() != dst.Len() {
-		dst.Set(reflect.MakeSlice(dst.Type(), src.Len(), src.Cap()))
+// LoadDotEnv reads the .env file in the working directory into the process environment.
+// Variables that already hold a value are kept.
+func LoadDotEnv() yaerrors.Error {
+	values, err := readDotEnvFile(DotEnvFile)
+	if err != nil {
+		return err.Wrap("load dot env")
 	}
 
-	for i := range src.Len() {
-		val := src.Index(i)
-
-		if val.IsValid() {
-			converted, err := valueparser.ConvertValue(val, dst.Type().Elem())
-			if err != nil {
-				panic("Cannot convert value: " + err.Error())
-			}
-
-			dst.Index(i).Set(converted)
-		}
+	if err = setEnvIfAbsent(values); err != nil {
+		return err.Wrap("load dot env")
 	}
-}
-
-// getMapType determines the type of a map based on its key and value types.
-// It returns a mapType constant that represents the specific type of the map.
-// The function checks the key and value types of the map using reflection.
-// It supports various combinations of key and value types, including string, int, uint, float, and bool.
-// If the key or value type is not supported, it returns invalidMap.
-// The function also handles special cases, such as byte slices and invalid types
-// to ensure that the map type is correctly identified.
-func getMapType(v reflect.Value) mapType {
-	switch v.Type().Key().Kind() {
-	case reflect.String:
-		switch v.Type().Elem().Kind() {
-		case reflect.String:
-			return stringStringMap
-
-		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-			return stringIntMap
-
-		case reflect.Uint,
-			reflect.Uint8,
-			reflect.Uint16,
-			reflect.Uint32,
-			reflect.Uint64,
-			reflect.Uintptr:
-			return stringUintMap
-
-		case reflect.Float32, reflect.Float64:
-			return stringFloatMap
-
-		case reflect.Bool:
-			return stringBoolMap
-
-		case reflect.Invalid,
-			reflect.Chan,
-			reflect.Func,
-			reflect.Interface,
-			reflect.Map,
-			reflect.Pointer,
-			reflect.Slice,
-			reflect.Struct,
-			reflect.Complex64,
-			reflect.Complex128,
-			reflect.Array,
-			reflect.UnsafePointer:
-			return invalidMap
-
-		default:
-			return invalidMap
-		}
-	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		switch v.Type().Elem().Kind() {
-		case reflect.String:
-			return intStringMap
-
-		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-			return intIntMap
-
-		case reflect.Uint,
-			reflect.Uint8,
-			reflect.Uint16,
-			reflect.Uint32,
-			reflect.Uint64,
-			reflect.Uintptr:
-			return intUintMap
-
-		case reflect.Float32, reflect.Float64:
-			return intFloatMap
-
-		case reflect.Bool:
-			return intBoolMap
-
-		case reflect.Invalid,
-			reflect.Chan,
-			reflect.Func,
-			reflect.Interface,
-			reflect.Map,
-			reflect.Pointer,
-			reflect.Slice,
-			reflect.Struct,
-			reflect.Complex64,
-			reflect.Complex128,
-			reflect.Array,
-			reflect.UnsafePointer:
-			return invalidMap
-
-		default:
-			return invalidMap
-		}
-	case reflect.Uint,
-		reflect.Uint8,
-		reflect.Uint16,
-		reflect.Uint32,
-		reflect.Uint64,
-		reflect.Uintptr:
-		switch v.Type().Elem().Kind() {
-		case reflect.String:
-			return uintStringMap
 
-		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-			return uintIntMap
-
-		case reflect.Uint,
-			reflect.Uint8,
-			reflect.Uint16,
-			reflect.Uint32,
-			reflect.Uint64,
-			reflect.Uintptr:
-			return uintUintMap
-
-		case reflect.Float32, reflect.Float64:
-			return uintFloatMap
-
-		case reflect.Bool:
-			return uintBoolMap
-
-		case reflect.Invalid,
-			reflect.Chan,
-			reflect.Func,
-			reflect.Interface,
-			reflect.Map,
-			reflect.Pointer,
-			reflect.Slice,
-			reflect.Struct,
-			reflect.Complex64,
-			reflect.Complex128,
-			reflect.Array,
-			reflect.UnsafePointer:
-			return invalidMap
-
-		default:
-			return invalidMap
-		}
-	case reflect.Float32, reflect.Float64:
-		switch v.Type().Elem().Kind() {
-		case reflect.String:
-			return floatStringMap
-
-		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-			return floatIntMap
-
-		case reflect.Uint,
-			reflect.Uint8,
-			reflect.Uint16,
-			reflect.Uint32,
-			reflect.Uint64,
-			reflect.Uintptr:
-			return floatUintMap
-
-		case reflect.Float32, reflect.Float64:
-			return floatFloatMap
-
-		case reflect.Bool:
-			return floatBoolMap
-
-		case reflect.Invalid,
-			reflect.Chan,
-			reflect.Func,
-			reflect.Interface,
-			reflect.Map,
-			reflect.Pointer,
-			reflect.Slice,
-			reflect.Struct,
-			reflect.Complex64,
-			reflect.Complex128,
-			reflect.Array,
-			reflect.UnsafePointer:
-			return invalidMap
-
-		default:
-			return invalidMap
-		}
-	case reflect.Bool:
-		switch v.Type().Elem().Kind() {
-		case reflect.String:
-			return boolStringMap
-
-		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-			return boolIntMap
-
-		case reflect.Uint,
-			reflect.Uint8,
-			reflect.Uint16,
-			reflect.Uint32,
-			reflect.Uint64,
-			reflect.Uintptr:
-			return boolUintMap
-
-		case reflect.Float32, reflect.Float64:
-			return boolFloatMap
-
-		case reflect.Bool:
-			return boolBoolMap
-
-		case reflect.Invalid,
-			reflect.Chan,
-			reflect.Func,
-			reflect.Interface,
-			reflect.Map,
-			reflect.Pointer,
-			reflect.Slice,
-			reflect.Struct,
-			reflect.Complex64,
-			reflect.Complex128,
-			reflect.Array,
-			reflect.UnsafePointer:
-			return invalidMap
-
-		default:
-			return invalidMap
-		}
-
-	case reflect.Invalid,
-		reflect.Chan,
-		reflect.Func,
-		reflect.Interface,
-		reflect.Map,
-		reflect.Pointer,
-		reflect.Slice,
-		reflect.Struct,
-		reflect.Complex64,
-		reflect.Complex128,
-		reflect.Array,
-		reflect.UnsafePointer:
-		return invalidMap
-
-	default:
-		return invalidMap
-	}
+	return nil
 }
 
-func LoadDotEnv() yaerrors.Error {
-	file, err := os.Open(DotEnvFile)
+// readDotEnvFile parses the KEY=value lines of the file at path. Blank lines and lines
+// starting with # are skipped, and matching single or double quotes around a value are
+// removed. When a key repeats, its first value wins.
+func readDotEnvFile(path string) (map[string]string, yaerrors.Error) {
+	file, err := os.Open(path)
 	if err != nil {
-		return yaerrors.FromError(
+		return nil, yaerrors.FromError(
 			http.StatusInternalServerError,
 			err,
-			"load dot env: cannot open .env file",
+			"read dot env: cannot open "+path,
 		)
 	}
 	defer file.Close()
 
+	values := map[string]string{}
 	scanner := bufio.NewScanner(file)
 
 	for scanner.Scan() {
-		if err := scanner.Err(); err != nil {
-			return yaerrors.FromError(
-				http.StatusInternalServerError,
-				err,
-				"load dot env: error reading .env file",
-			)
-		}
-
 		scannedText := strings.TrimSpace(scanner.Text())
 		if scannedText == "" || strings.HasPrefix(scannedText, "#") {
 			continue
@@ -373,10 +73,10 @@ func LoadDotEnv() yaerrors.Error {
 
 		keyValue := strings.SplitN(scannedText, "=", DotEnvKVParts)
 		if len(keyValue) != DotEnvKVParts {
-			return yaerrors.FromError(
+			return nil, yaerrors.FromError(
 				http.StatusInternalServerError,
 				ErrInvalidDotEnvFileFormat,
-				"load dot env: invalid .env file format for line: "+scannedText,
+				"read dot env: invalid .env file format for line: "+scannedText,
 			)
 		}
 
@@ -389,16 +89,50 @@ func LoadDotEnv() yaerrors.Error {
 			value = strings.Trim(value, "'")
 		}
 
-		if os.Getenv(key) == "" {
-			if err := os.Setenv(key, value); err != nil {
-				return yaerrors.FromError(
-					http.StatusInternalServerError,
-					err,
-					"load dot env: cannot set environment variable "+key,
-				)
-			}
+		if _, exists := values[key]; !exists {
+			values[key] = value
 		}
 	}
 
-	return nil
+	if err := scanner.Err(); err != nil {
+		return nil, yaerrors.FromError(
+			http.StatusInternalServerError,
+			err,
+			"read dot env: error reading "+path,
+		)
+	}
+
+	return values, nil
+}
+
+// isNestedStruct reports whether vType is a struct loaded field by field rather than
+// parsed from a single value.
+func isNestedStruct(vType reflect.Type) bool {
+	return vType != nil && vType.Kind() == reflect.Struct && !isParsableType(vType)
+}
+
+// elemType returns the element type of a slice, looking through pointer elements, or
+// nil when vType is not a slice.
+func elemType(vType reflect.Type) reflect.Type {
+	if vType.Kind() != reflect.Slice {
+		return nil
+	}
+
+	elem := vType.Elem()
+	if elem.Kind() == reflect.Pointer {
+		elem = elem.Elem()
+	}
+
+	return elem
+}
+
+// hasEnvPrefix reports whether any environment variable starts with prefix.
+func hasEnvPrefix(prefix string) bool {
+	for _, entry := range os.Environ() {
+		if strings.HasPrefix(entry, prefix) {
+			return true
+		}
+	}
+
+	return false
 }
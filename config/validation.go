@@ -0,0 +1,257 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yaerrors"
+)
+
+// validateField checks the loaded fieldVal, read from source, against the min, max,
+// oneof, regex, url and email tags of field.
+//
+// min and max bound numbers (durations accept "1s"-style bounds) and the length of
+// strings, slices and maps. oneof, regex, url and email apply to a scalar or to every
+// element of a slice and skip empty strings, so they compose with optional fields. A
+// zero field whose variable is unset is not validated at all.
+func validateField(
+	field *reflect.StructField,
+	fieldVal reflect.Value,
+	source envSource,
+) yaerrors.Error {
+	if fieldVal.IsZero() {
+		if _, exists, err := source.lookup(); err == nil && !exists {
+			return nil
+		}
+	}
+
+	reason, err := checkRange(field, fieldVal)
+	if err == nil {
+		reason, err = checkElements(field, fieldVal, source)
+	}
+
+	if err == nil {
+		return nil
+	}
+
+	var yaErr yaerrors.Error
+	if errors.As(err, &yaErr) {
+		return yaErr
+	}
+
+	return yaerrors.FromError(
+		http.StatusInternalServerError,
+		&FieldError{
+			Key:    source.key,
+			Value:  fmt.Sprint(source.display(fieldVal.Interface())),
+			Reason: reason,
+			Err:    err,
+		},
+		"config loader: field "+field.Name,
+	)
+}
+
+// checkRange applies the min and max tags of field to fieldVal.
+func checkRange(field *reflect.StructField, fieldVal reflect.Value) (string, error) {
+	for _, tag := range []string{MinTagName, MaxTagName} {
+		raw, ok := field.Tag.Lookup(tag)
+		if !ok {
+			continue
+		}
+
+		measure, unit, supported := measureValue(fieldVal)
+		if !supported {
+			return fmt.Sprintf("%s tag is not supported on %s", tag, fieldVal.Type()),
+				ErrInvalidFieldTag
+		}
+
+		bound, err := parseBound(fieldVal.Type(), raw)
+		if err != nil {
+			return fmt.Sprintf("%s tag %q is not a valid bound", tag, raw), err
+		}
+
+		switch {
+		case tag == MinTagName && measure < bound:
+			return fmt.Sprintf("must be at least %s%s", raw, unit), ErrValueOutOfRange
+		case tag == MaxTagName && measure > bound:
+			return fmt.Sprintf("must be at most %s%s", raw, unit), ErrValueOutOfRange
+		}
+	}
+
+	return "", nil
+}
+
+// checkElements applies the oneof, regex, url and email tags of field to fieldVal.
+func checkElements(
+	field *reflect.StructField,
+	fieldVal reflect.Value,
+	source envSource,
+) (string, error) {
+	isURL, _, err := parseFlagTag(field, source.key, URLTagName)
+	if err != nil {
+		return "", err
+	}
+
+	isEmail, _, err := parseFlagTag(field, source.key, EmailTagName)
+	if err != nil {
+		return "", err
+	}
+
+	oneOf, hasOneOf := field.Tag.Lookup(OneOfTagName)
+	allowed := strings.Fields(oneOf)
+
+	var pattern *regexp.Regexp
+
+	if raw, ok := field.Tag.Lookup(RegexTagName); ok {
+		var compileErr error
+
+		pattern, compileErr = regexp.Compile(raw)
+		if compileErr != nil {
+			return fmt.Sprintf("regex tag %q does not compile", raw),
+				errors.Join(compileErr, ErrInvalidFieldTag)
+		}
+	}
+
+	for _, value := range elementStrings(fieldVal) {
+		if value == "" {
+			continue
+		}
+
+		switch {
+		case hasOneOf && !slices.Contains(allowed, value):
+			return "must be one of " + strings.Join(allowed, ", "), ErrValueNotAllowed
+		case pattern != nil && !pattern.MatchString(value):
+			return "must match " + pattern.String(), ErrValueMismatch
+		case isURL && !isAbsoluteURL(value):
+			return "must be an absolute URL", ErrInvalidURL
+		case isEmail && !isEmailAddress(value):
+			return "must be an email address", ErrInvalidEmail
+		}
+	}
+
+	return "", nil
+}
+
+// measureValue returns what min and max compare against: the number itself, or the
+// length of a string, slice or map together with its unit.
+func measureValue(value reflect.Value) (float64, string, bool) {
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int()), "", true
+	case reflect.Uint,
+		reflect.Uint8,
+		reflect.Uint16,
+		reflect.Uint32,
+		reflect.Uint64,
+		reflect.Uintptr:
+		return float64(value.Uint()), "", true
+	case reflect.Float32, reflect.Float64:
+		return value.Float(), "", true
+	case reflect.String:
+		return float64(utf8.RuneCountInString(value.String())), " characters", true
+	case reflect.Slice, reflect.Map:
+		return float64(value.Len()), " items", true
+	case reflect.Invalid,
+		reflect.Bool,
+		reflect.Complex64,
+		reflect.Complex128,
+		reflect.Array,
+		reflect.Chan,
+		reflect.Func,
+		reflect.Interface,
+		reflect.Pointer,
+		reflect.Struct,
+		reflect.UnsafePointer:
+		return 0, "", false
+	default:
+		return 0, "", false
+	}
+}
+
+// parseBound parses a min or max tag for a field of type vType.
+func parseBound(vType reflect.Type, raw string) (float64, error) {
+	if vType == durationType {
+		duration, err := time.ParseDuration(raw)
+		if err != nil {
+			return 0, errors.Join(err, ErrInvalidFieldTag)
+		}
+
+		return float64(duration), nil
+	}
+
+	bound, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return 0, errors.Join(err, ErrInvalidFieldTag)
+	}
+
+	return bound, nil
+}
+
+// elementStrings formats a scalar, or every element of a slice, for the string checks.
+// Maps are only bounded by min and max.
+func elementStrings(value reflect.Value) []string {
+	switch value.Kind() {
+	case reflect.Slice:
+		elements := make([]string, 0, value.Len())
+
+		for i := range value.Len() {
+			elements = append(elements, fmt.Sprint(value.Index(i).Interface()))
+		}
+
+		return elements
+	case reflect.Invalid,
+		reflect.Array,
+		reflect.Chan,
+		reflect.Func,
+		reflect.Interface,
+		reflect.Map,
+		reflect.Pointer,
+		reflect.Struct,
+		reflect.UnsafePointer:
+		return nil
+	case reflect.Bool,
+		reflect.Int,
+		reflect.Int8,
+		reflect.Int16,
+		reflect.Int32,
+		reflect.Int64,
+		reflect.Uint,
+		reflect.Uint8,
+		reflect.Uint16,
+		reflect.Uint32,
+		reflect.Uint64,
+		reflect.Uintptr,
+		reflect.Float32,
+		reflect.Float64,
+		reflect.Complex64,
+		reflect.Complex128,
+		reflect.String:
+		return []string{fmt.Sprint(value.Interface())}
+	default:
+		return nil
+	}
+}
+
+// isAbsoluteURL reports whether value is a URL with a scheme and a host.
+func isAbsoluteURL(value string) bool {
+	parsed, err := url.Parse(value)
+
+	return err == nil && parsed.Scheme != "" && parsed.Host != ""
+}
+
+// isEmailAddress reports whether value is a bare email address.
+func isEmailAddress(value string) bool {
+	address, err := mail.ParseAddress(value)
+
+	return err == nil && address.Address == value
+}
@@ -0,0 +1,327 @@
+package config
+
+import (
+	"maps"
+	"os"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yaerrors"
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yalogger"
+)
+
+// WatcherConfig configures a Watcher. Zero fields fall back to the package defaults.
+type WatcherConfig[T any] struct {
+	// Sources are read on every load, lowest precedence first, exactly as in
+	// LoadConfigStructFromSources. The files behind YAML, TOML, JSON and .env sources are
+	// polled for changes.
+	Sources []Source
+	// PollInterval is how often the files are checked. Zero means
+	// DefaultWatcherPollInterval; a negative value disables polling, leaving reloads to
+	// Watcher.Reload.
+	PollInterval time.Duration
+	// Validate, when set, runs on every freshly loaded struct after the tag validation.
+	// A rejected struct is dropped and the previous config stays active.
+	Validate func(*T) yaerrors.Error
+}
+
+// Watcher keeps a configuration struct loaded from sources up to date while the process
+// runs. When a watched file changes it loads a fresh struct, validates it and atomically
+// swaps it in, then notifies subscribers with the old and new values. A load or
+// validation failure keeps the previous config. The zero value is not valid; use
+// NewWatcher.
+//
+// Structs returned by Get are shared snapshots and must not be modified.
+//
+// Example:
+//
+//	watcher, err := config.NewWatcher(config.WatcherConfig[Config]{
+//		Sources: append(config.YaToolsSources("tool"), config.NewEnvSource()),
+//	}, log)
+//	if err != nil {
+//		// handle error
+//	}
+//	defer watcher.Close()
+//
+//	watcher.Subscribe(func(previous, current *Config) {
+//		if previous.LogLevel != current.LogLevel {
+//			log.SetLevel(current.LogLevel)
+//		}
+//	})
+//
+//	limit := watcher.Get().RateLimit
+type Watcher[T any] struct {
+	config WatcherConfig[T]
+	log    yalogger.Logger
+
+	current atomic.Pointer[watcherSnapshot[T]]
+
+	reloadMutex sync.Mutex
+
+	subscribersMutex sync.Mutex
+	subscribers      map[uint64]func(previous, current *T)
+	nextSubscriber   uint64
+
+	stamps    map[string]fileStamp
+	done      chan struct{}
+	stopped   chan struct{}
+	closeOnce sync.Once
+}
+
+// watcherSnapshot is one loaded config together with the origins of its fields.
+type watcherSnapshot[T any] struct {
+	value   *T
+	origins Origins
+}
+
+// fileStamp is what a watched file looked like at the last poll.
+type fileStamp struct {
+	exists  bool
+	size    int64
+	modTime time.Time
+}
+
+// fileBackedSource is a Source read from a file that can be watched.
+type fileBackedSource interface {
+	filePath() string
+}
+
+// NewWatcher loads the config from config.Sources and starts polling the files behind
+// them. It fails when the first load fails, so a broken deployment does not start.
+//
+// Example:
+//
+//	watcher, err := config.NewWatcher(config.WatcherConfig[Config]{
+//		Sources:      config.DefaultSources("config", os.Args[1:]),
+//		PollInterval: 5 * time.Second,
+//	}, log)
+func NewWatcher[T any](config WatcherConfig[T], log yalogger.Logger) (*Watcher[T], yaerrors.Error) {
+	safetyCheck(&log)
+
+	if config.PollInterval == 0 {
+		config.PollInterval = DefaultWatcherPollInterval
+	}
+
+	watcher := &Watcher[T]{
+		config:      config,
+		log:         log,
+		subscribers: map[uint64]func(previous, current *T){},
+		done:        make(chan struct{}),
+		stopped:     make(chan struct{}),
+	}
+
+	watcher.stamps = watcher.stampFiles()
+
+	snapshot, err := watcher.load()
+	if err != nil {
+		return nil, err.WrapWithLog("config watcher: initial load", log)
+	}
+
+	watcher.current.Store(snapshot)
+
+	if config.PollInterval < 0 {
+		close(watcher.stopped)
+
+		return watcher, nil
+	}
+
+	go watcher.watch(config.PollInterval)
+
+	return watcher, nil
+}
+
+// YaToolsSources returns the .yatools/<name>.json files as sources with the precedence of
+// LoadConfigStructFromEnvWithYaTools: the per-user ~/.yatools file first, then the
+// project file in the current working directory.
+//
+// Example:
+//
+//	sources := append(config.YaToolsSources("tool"), config.NewEnvSource())
+func YaToolsSources(name string) []Source {
+	sources := make([]Source, 0, yaToolsSourceCount)
+
+	if homePath := yaToolsHomePath(name); homePath != "" {
+		sources = append(sources, NewJSONFileSource(homePath))
+	}
+
+	return append(sources, NewJSONFileSource(yaToolsPath(".", name)))
+}
+
+// Get returns the active config.
+//
+// Example:
+//
+//	level := watcher.Get().LogLevel
+func (w *Watcher[T]) Get() *T {
+	return w.current.Load().value
+}
+
+// Origins returns the origins of the fields of the active config.
+//
+// Example:
+//
+//	fmt.Println(watcher.Origins()["LOG_LEVEL"])
+func (w *Watcher[T]) Origins() Origins {
+	return w.current.Load().origins
+}
+
+// Subscribe registers fn to be called with the previous and the new config after every
+// reload that changes it. Subscribers run one after another on the reloading goroutine,
+// so fn should return quickly. The returned function removes the subscription.
+//
+// Example:
+//
+//	unsubscribe := watcher.Subscribe(func(previous, current *Config) {
+//		limiter.SetLimit(current.RateLimit)
+//	})
+//	defer unsubscribe()
+func (w *Watcher[T]) Subscribe(fn func(previous, current *T)) func() {
+	w.subscribersMutex.Lock()
+	defer w.subscribersMutex.Unlock()
+
+	id := w.nextSubscriber
+	w.nextSubscriber++
+	w.subscribers[id] = fn
+
+	return func() {
+		w.subscribersMutex.Lock()
+		defer w.subscribersMutex.Unlock()
+
+		delete(w.subscribers, id)
+	}
+}
+
+// Reload loads the config from the sources right away, whether or not a file changed,
+// which suits a SIGHUP handler. It reports whether a new config was swapped in: an
+// unchanged config is kept without notifying anyone, and on error the previous config
+// stays active.
+//
+// Example:
+//
+//	if _, err := watcher.Reload(); err != nil {
+//		log.Errorf("config stays unchanged: %v", err)
+//	}
+func (w *Watcher[T]) Reload() (bool, yaerrors.Error) {
+	w.reloadMutex.Lock()
+	defer w.reloadMutex.Unlock()
+
+	snapshot, err := w.load()
+	if err != nil {
+		return false, err.Wrap("config watcher: reload")
+	}
+
+	previous := w.current.Load()
+	if reflect.DeepEqual(previous.value, snapshot.value) {
+		w.current.Store(&watcherSnapshot[T]{value: previous.value, origins: snapshot.origins})
+
+		return false, nil
+	}
+
+	w.current.Store(snapshot)
+
+	w.notify(previous.value, snapshot.value)
+
+	return true, nil
+}
+
+// Close stops polling and waits for a reload started by a poll to finish, so polling
+// calls no subscriber after it returns. The last config stays readable.
+//
+// Example:
+//
+//	defer watcher.Close()
+func (w *Watcher[T]) Close() yaerrors.Error {
+	w.closeOnce.Do(func() {
+		close(w.done)
+	})
+
+	<-w.stopped
+
+	return nil
+}
+
+// load reads the sources into a fresh struct and validates it.
+func (w *Watcher[T]) load() (*watcherSnapshot[T], yaerrors.Error) {
+	value := new(T)
+
+	origins, err := LoadConfigStructFromSources(value, w.log, w.config.Sources...)
+	if err != nil {
+		return nil, err
+	}
+
+	if w.config.Validate != nil {
+		if err = w.config.Validate(value); err != nil {
+			return nil, err.Wrap("validate config")
+		}
+	}
+
+	return &watcherSnapshot[T]{value: value, origins: origins}, nil
+}
+
+// notify calls every subscriber with the previous and current config.
+func (w *Watcher[T]) notify(previous, current *T) {
+	w.subscribersMutex.Lock()
+	subscribers := make([]func(previous, current *T), 0, len(w.subscribers))
+
+	for _, fn := range w.subscribers {
+		subscribers = append(subscribers, fn)
+	}
+	w.subscribersMutex.Unlock()
+
+	for _, fn := range subscribers {
+		fn(previous, current)
+	}
+}
+
+// watch reloads the config every interval in which a watched file changed, until Close.
+func (w *Watcher[T]) watch(interval time.Duration) {
+	defer close(w.stopped)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-ticker.C:
+			stamps := w.stampFiles()
+			if maps.Equal(stamps, w.stamps) {
+				continue
+			}
+
+			w.stamps = stamps
+
+			if _, err := w.Reload(); err != nil {
+				w.log.Errorf("Keeping the previous config: %v", err)
+			}
+		}
+	}
+}
+
+// stampFiles stats every file behind the sources.
+func (w *Watcher[T]) stampFiles() map[string]fileStamp {
+	stamps := map[string]fileStamp{}
+
+	for _, source := range w.config.Sources {
+		watched, ok := source.(fileBackedSource)
+		if !ok {
+			continue
+		}
+
+		path := watched.filePath()
+
+		info, err := os.Stat(path)
+		if err != nil {
+			stamps[path] = fileStamp{}
+
+			continue
+		}
+
+		stamps[path] = fileStamp{exists: true, size: info.Size(), modTime: info.ModTime()}
+	}
+
+	return stamps
+}
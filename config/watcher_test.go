@@ -0,0 +1,128 @@
+package config_test
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/YaCodeDev/GoYaCodeDevUtils/config"
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yaerrors"
+)
+
+var errTestRejected = errors.New("rejected by test")
+
+type watcherConfig struct {
+	Level string `default:"info"`
+	Limit int    `default:"10" min:"1"`
+}
+
+func TestWatcherReloadsChangedFile(t *testing.T) {
+	path := writeSourceFile(t, t.TempDir(), "config.yaml", "limit: 20\n")
+
+	watcher, err := config.NewWatcher(config.WatcherConfig[watcherConfig]{
+		Sources:      []config.Source{config.NewYAMLFileSource(path)},
+		PollInterval: 10 * time.Millisecond,
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer watcher.Close()
+
+	if got := watcher.Get().Limit; got != 20 {
+		t.Fatalf("expected the initial limit 20, got %d", got)
+	}
+
+	changes := make(chan [2]watcherConfig, 1)
+
+	watcher.Subscribe(func(previous, current *watcherConfig) {
+		changes <- [2]watcherConfig{*previous, *current}
+	})
+
+	writeSourceFile(t, filepath.Dir(path), "config.yaml", "limit: 30\nlevel: debug\n")
+
+	future := time.Now().Add(time.Second)
+	if chtimesErr := os.Chtimes(path, future, future); chtimesErr != nil {
+		t.Fatal(chtimesErr)
+	}
+
+	select {
+	case change := <-changes:
+		if change[0].Limit != 20 || change[1].Limit != 30 || change[1].Level != "debug" {
+			t.Errorf("unexpected change %+v", change)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("subscriber was not notified")
+	}
+
+	if got := watcher.Get(); got.Limit != 30 || got.Level != "debug" {
+		t.Errorf("expected the reloaded config, got %+v", got)
+	}
+
+	if origin := watcher.Origins()["LEVEL"]; origin != "yaml:"+path {
+		t.Errorf("expected LEVEL to come from the yaml file, got %q", origin)
+	}
+}
+
+func TestWatcherKeepsPreviousConfigOnFailure(t *testing.T) {
+	dir := t.TempDir()
+	path := writeSourceFile(t, dir, "config.json", `{"limit": 5}`)
+
+	watcher, err := config.NewWatcher(config.WatcherConfig[watcherConfig]{
+		Sources:      []config.Source{config.NewJSONFileSource(path)},
+		PollInterval: -1,
+		Validate: func(value *watcherConfig) yaerrors.Error {
+			if value.Level == "trace" {
+				return yaerrors.FromError(http.StatusInternalServerError, errTestRejected, "test")
+			}
+
+			return nil
+		},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer watcher.Close()
+
+	notified := false
+
+	watcher.Subscribe(func(_, _ *watcherConfig) {
+		notified = true
+	})
+
+	writeSourceFile(t, dir, "config.json", `{"limit": 0}`)
+
+	if _, err = watcher.Reload(); !errors.Is(err, config.ErrValueOutOfRange) {
+		t.Fatalf("expected ErrValueOutOfRange, got %v", err)
+	}
+
+	writeSourceFile(t, dir, "config.json", `{"limit": 7, "level": "trace"}`)
+
+	if _, err = watcher.Reload(); !errors.Is(err, errTestRejected) {
+		t.Fatalf("expected the validation error, got %v", err)
+	}
+
+	if got := watcher.Get().Limit; got != 5 || notified {
+		t.Errorf("expected the previous config without notification, got limit %d", got)
+	}
+
+	writeSourceFile(t, dir, "config.json", `{"limit": 5}`)
+
+	swapped, err := watcher.Reload()
+	if err != nil || swapped || notified {
+		t.Errorf("expected an unchanged config to be kept, got swapped=%t err=%v", swapped, err)
+	}
+}
+
+func TestNewWatcherFailsOnInvalidConfig(t *testing.T) {
+	path := writeSourceFile(t, t.TempDir(), "config.toml", "limit = -1\n")
+
+	_, err := config.NewWatcher(config.WatcherConfig[watcherConfig]{
+		Sources: []config.Source{config.NewTOMLFileSource(path)},
+	}, nil)
+	if !errors.Is(err, config.ErrInvalidConfig) {
+		t.Fatalf("expected ErrInvalidConfig, got %v", err)
+	}
+}
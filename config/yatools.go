@@ -3,11 +3,9 @@ package config
 import (
 	"bytes"
 	"encoding/json"
-	"fmt"
 	"net/http"
 	"os"
 	"path/filepath"
-	"strconv"
 	"strings"
 
 	"github.com/YaCodeDev/GoYaCodeDevUtils/yaerrors"
@@ -191,7 +189,9 @@ func seedEnvFromYaToolsFile(path string) yaerrors.Error {
 	}
 
 	flattened := map[string]string{}
-	if flattenErr := flattenYaToolsEnv("", raw, flattened); flattenErr != nil {
+	flattener := sourceFlattener{normalize: strings.ToUpper}
+
+	if flattenErr := flattener.flatten("", raw, flattened); flattenErr != nil {
 		return flattenErr.Wrap("flatten yatools config " + path)
 	}
 
@@ -219,76 +219,6 @@ func readYaToolsFile(path string) ([]byte, bool, yaerrors.Error) {
 	return content, true, nil
 }
 
-func flattenYaToolsEnv(
-	prefix string,
-	raw map[string]any,
-	out map[string]string,
-) yaerrors.Error {
-	for key, value := range raw {
-		envKey := strings.ToUpper(key)
-		if prefix != "" {
-			envKey = prefix + YaToolsKeySeparator + envKey
-		}
-
-		switch typed := value.(type) {
-		case nil:
-			continue
-		case map[string]any:
-			if err := flattenYaToolsEnv(envKey, typed, out); err != nil {
-				return err
-			}
-		case []any:
-			joined, joinErr := joinYaToolsArray(typed)
-			if joinErr != nil {
-				return joinErr.Wrap("flatten array " + envKey)
-			}
-
-			out[envKey] = joined
-		default:
-			scalar, scalarErr := yaToolsScalar(typed)
-			if scalarErr != nil {
-				return scalarErr.Wrap("flatten value " + envKey)
-			}
-
-			out[envKey] = scalar
-		}
-	}
-
-	return nil
-}
-
-func joinYaToolsArray(values []any) (string, yaerrors.Error) {
-	parts := make([]string, 0, len(values))
-
-	for _, value := range values {
-		scalar, scalarErr := yaToolsScalar(value)
-		if scalarErr != nil {
-			return "", scalarErr
-		}
-
-		parts = append(parts, scalar)
-	}
-
-	return strings.Join(parts, YaToolsArraySeparator), nil
-}
-
-func yaToolsScalar(value any) (string, yaerrors.Error) {
-	switch typed := value.(type) {
-	case string:
-		return typed, nil
-	case bool:
-		return strconv.FormatBool(typed), nil
-	case json.Number:
-		return typed.String(), nil
-	default:
-		return "", yaerrors.FromError(
-			http.StatusInternalServerError,
-			ErrUnsupportedYaToolsValue,
-			fmt.Sprintf("yatools config value of type %T", value),
-		)
-	}
-}
-
 func setEnvIfAbsent(values map[string]string) yaerrors.Error {
 	for key, value := range values {
 		if os.Getenv(key) != "" {
@@ -0,0 +1,1257 @@
+// ===================== Bounded in‑memory implementation ===================== //
+
+// BoundedMemory is a size‑capped, sharded alternative to [Memory] for
+// production processes that cannot afford an unbounded map.
+
+package yacache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"maps"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+	"weak"
+
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yaerrors"
+)
+
+// boundedMemoryBackend names the bounded in‑memory backend in error
+// tracebacks.
+const boundedMemoryBackend = "BOUNDED MEMORY"
+
+// scoreSize is what a sorted-set score adds to an entry's size.
+const scoreSize = 8
+
+const (
+	// DefaultBoundedMemoryShards is the shard count used when
+	// BoundedMemoryConfig.Shards is zero.
+	DefaultBoundedMemoryShards = 16
+	// DefaultBoundedMemorySweepInterval is the expiry sweep interval used when
+	// BoundedMemoryConfig.SweepInterval is zero.
+	DefaultBoundedMemorySweepInterval = time.Second
+)
+
+// BoundedMemory is a threadsafe, TTL‑aware in‑memory cache with an entry
+// and/or byte budget. It implements Cache[MemoryContainer], so it can replace
+// [Memory] wherever one is expected.
+//
+// Unlike [Memory]:
+//
+//   - Keys are spread over independently locked shards instead of one global
+//     RWMutex.
+//   - Expired values are invisible immediately and are purged from a per‑shard
+//     min‑heap ordered by deadline, so a sweep costs O(expired·log n) instead
+//     of a full scan.
+//   - Once a budget is exceeded the least valuable entry according to Policy
+//     is evicted. A hash counts as one entry and is evicted as a whole, like a
+//     Redis key under maxmemory. A write never evicts the entry it wrote, so
+//     a single value larger than MaxBytes stays until the next write.
+//   - A zero TTL means "no expiry" for hash fields too.
+//
+// Example:
+//
+//	memory := yacache.NewBoundedMemory(yacache.BoundedMemoryConfig{
+//	    MaxEntries: 10_000,
+//	    MaxBytes:   64 << 20,
+//	    Policy:     yacache.EvictLFU,
+//	})
+//	_ = memory.Set(ctx, "k", "v", time.Minute)
+//	fmt.Println(memory.Stats().Entries) // 1
+type BoundedMemory struct {
+	shards []*boundedShard
+	mask   uint64
+	config BoundedMemoryConfig
+
+	clock       atomic.Uint64
+	entries     atomic.Int64
+	bytes       atomic.Int64
+	hits        atomic.Uint64
+	misses      atomic.Uint64
+	evictions   atomic.Uint64
+	expirations atomic.Uint64
+
+	evictMutex sync.Mutex
+	done       chan struct{}
+	closeOnce  sync.Once
+}
+
+// NewBoundedMemory builds a [BoundedMemory] and starts its background expiry
+// sweeper. The sweeper stops on Close or once the cache is garbage collected.
+//
+// Example:
+//
+//	memory := yacache.NewBoundedMemory(yacache.BoundedMemoryConfig{MaxEntries: 1000})
+func NewBoundedMemory(config BoundedMemoryConfig) *BoundedMemory {
+	shards := DefaultBoundedMemoryShards
+	if config.Shards > 0 {
+		shards = 1
+		for shards < config.Shards {
+			shards <<= 1
+		}
+	}
+
+	if config.SweepInterval <= 0 {
+		config.SweepInterval = DefaultBoundedMemorySweepInterval
+	}
+
+	config.Shards = shards
+
+	memory := &BoundedMemory{
+		shards: make([]*boundedShard, shards),
+		mask:   uint64(shards - 1),
+		config: config,
+		done:   make(chan struct{}),
+	}
+
+	for i := range memory.shards {
+		memory.shards[i] = &boundedShard{
+			values:     make(map[string]*boundedEntry),
+			hashes:     make(map[string]*boundedEntry),
+			lists:      make(map[string]*boundedEntry),
+			sortedSets: make(map[string]*boundedEntry),
+			eviction:   evictionHeap{lfu: config.Policy == EvictLFU},
+		}
+	}
+
+	go sweepBounded(weak.Make(memory), config.SweepInterval, memory.done)
+
+	return memory
+}
+
+// sweepBounded periodically drains the expired prefix of every shard's
+// expiry heap.
+func sweepBounded(
+	pointer weak.Pointer[BoundedMemory],
+	interval time.Duration,
+	done <-chan struct{},
+) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			memory := pointer.Value()
+			if memory == nil {
+				return
+			}
+
+			memory.sweep(time.Now())
+		case <-done:
+			return
+		}
+	}
+}
+
+// Stats returns a snapshot of the hit, miss, eviction and expiration counters
+// together with the current usage.
+//
+// Example:
+//
+//	stats := memory.Stats()
+//	fmt.Println(stats.Hits, stats.Misses, stats.Evictions)
+func (b *BoundedMemory) Stats() BoundedMemoryStats {
+	return BoundedMemoryStats{
+		Hits:        b.hits.Load(),
+		Misses:      b.misses.Load(),
+		Evictions:   b.evictions.Load(),
+		Expirations: b.expirations.Load(),
+		Entries:     b.entries.Load(),
+		Bytes:       b.bytes.Load(),
+	}
+}
+
+// Raw returns a snapshot of the live (unexpired) contents as a
+// MemoryContainer. Mutating the snapshot does not affect the cache.
+//
+// Example:
+//
+//	snapshot := memory.Raw()
+func (b *BoundedMemory) Raw() MemoryContainer {
+	container := NewMemoryContainer()
+	now := time.Now()
+
+	for _, shard := range b.shards {
+		shard.mutex.Lock()
+
+		for key, entry := range shard.values {
+			if !entry.value.expired(now) {
+				container.Map[key] = entry.value.item()
+			}
+		}
+
+		for key, entry := range shard.hashes {
+			child := make(childMemoryContainer, len(entry.fields)+1)
+
+			for field, value := range entry.fields {
+				if !value.expired(now) {
+					child[field] = value.item()
+				}
+			}
+
+			child[yaMapLen] = newMemoryCacheItem(strconv.Itoa(len(child)))
+			container.HMap[key] = child
+		}
+
+		for key, entry := range shard.lists {
+			if !entry.value.expired(now) {
+				container.Lists[key] = &memoryListItem{
+					Values:    slices.Clone(entry.list),
+					ExpiresAt: entry.value.expiresAt,
+					Endless:   entry.value.expiresAt.IsZero(),
+				}
+			}
+		}
+
+		for key, entry := range shard.sortedSets {
+			if !entry.value.expired(now) {
+				container.SortedSets[key] = &memorySortedSetItem{
+					Scores:    maps.Clone(entry.scores),
+					ExpiresAt: entry.value.expiresAt,
+					Endless:   entry.value.expiresAt.IsZero(),
+				}
+			}
+		}
+
+		shard.mutex.Unlock()
+	}
+
+	return container
+}
+
+// HSetEX implementation for BoundedMemory. A non‑positive ttl stores the
+// field without expiry.
+//
+// Example:
+//
+//	_ = memory.HSetEX(ctx, "main", "field", "val", time.Minute)
+func (b *BoundedMemory) HSetEX(
+	_ context.Context,
+	mainKey string,
+	childKey string,
+	value string,
+	ttl time.Duration,
+) yaerrors.Error {
+	shard := b.shard(mainKey)
+	now := time.Now()
+
+	shard.mutex.Lock()
+
+	entry := b.lookupHash(shard, mainKey, now)
+	if entry == nil {
+		entry = &boundedEntry{
+			key:    mainKey,
+			kind:   memoryKindHash,
+			fields: make(map[string]*boundedValue),
+			size:   int64(len(mainKey)),
+		}
+
+		b.insert(shard, entry)
+	}
+
+	stored, ok := entry.fields[childKey]
+	if !ok {
+		stored = &boundedValue{entry: entry, field: childKey, index: -1}
+		entry.fields[childKey] = stored
+
+		b.resize(entry, int64(len(childKey)))
+	}
+
+	b.resize(entry, int64(len(value)-len(stored.value)))
+	stored.value = value
+	stored.expiresAt = deadline(now, ttl)
+
+	shard.schedule(stored)
+	b.touch(shard, entry)
+
+	shard.mutex.Unlock()
+
+	b.enforce(entry)
+
+	return nil
+}
+
+// HGet implementation for BoundedMemory.
+//
+// Example:
+//
+//	value, _ := memory.HGet(ctx, "main", "field")
+func (b *BoundedMemory) HGet(
+	_ context.Context,
+	mainKey string,
+	childKey string,
+) (string, yaerrors.Error) {
+	shard := b.shard(mainKey)
+	now := time.Now()
+
+	shard.mutex.Lock()
+	defer shard.mutex.Unlock()
+
+	entry := b.lookupHash(shard, mainKey, now)
+	if entry == nil {
+		b.misses.Add(1)
+
+		return "", yaerrors.FromError(
+			http.StatusInternalServerError,
+			errors.Join(ErrNotFoundValue, ErrFailedToGetValue),
+			fmt.Sprintf("[BOUNDED MEMORY] failed to get child map by `%s`", mainKey),
+		)
+	}
+
+	stored := b.lookupField(shard, entry, childKey, now)
+	if stored == nil {
+		b.misses.Add(1)
+
+		return "", yaerrors.FromError(
+			http.StatusInternalServerError,
+			ErrNotFoundValue,
+			fmt.Sprintf("[BOUNDED MEMORY] failed to get value by `%s:%s`", mainKey, childKey),
+		)
+	}
+
+	b.hits.Add(1)
+	b.touch(shard, entry)
+
+	return stored.value, nil
+}
+
+// HGetAll implementation for BoundedMemory.
+//
+// Example:
+//
+//	main, _ := memory.HGetAll(ctx, "main")
+func (b *BoundedMemory) HGetAll(
+	_ context.Context,
+	mainKey string,
+) (map[string]string, yaerrors.Error) {
+	shard := b.shard(mainKey)
+	now := time.Now()
+
+	shard.mutex.Lock()
+	defer shard.mutex.Unlock()
+
+	entry := b.lookupHash(shard, mainKey, now)
+	if entry == nil {
+		b.misses.Add(1)
+
+		return nil, yaerrors.FromError(
+			http.StatusInternalServerError,
+			ErrFailedToGetValues,
+			fmt.Sprintf("[BOUNDED MEMORY] failed to get child map by `%s`", mainKey),
+		)
+	}
+
+	b.hits.Add(1)
+	b.touch(shard, entry)
+
+	result := make(map[string]string, len(entry.fields))
+
+	for field, stored := range entry.fields {
+		result[field] = stored.value
+	}
+
+	return result, nil
+}
+
+// HGetDelSingle implementation for BoundedMemory.
+//
+// Example:
+//
+//	value, _ := memory.HGetDelSingle(ctx, "jobs", "id‑1")
+func (b *BoundedMemory) HGetDelSingle(
+	_ context.Context,
+	mainKey string,
+	childKey string,
+) (string, yaerrors.Error) {
+	shard := b.shard(mainKey)
+	now := time.Now()
+
+	shard.mutex.Lock()
+	defer shard.mutex.Unlock()
+
+	entry := b.lookupHash(shard, mainKey, now)
+	if entry == nil {
+		b.misses.Add(1)
+
+		return "", yaerrors.FromError(
+			http.StatusInternalServerError,
+			ErrFailedToGetDeleteSingle,
+			fmt.Sprintf("[BOUNDED MEMORY] failed to get child map by `%s`", mainKey),
+		)
+	}
+
+	stored := b.lookupField(shard, entry, childKey, now)
+	if stored == nil {
+		b.misses.Add(1)
+
+		return "", yaerrors.FromError(
+			http.StatusInternalServerError,
+			ErrNotFoundValue,
+			fmt.Sprintf("[BOUNDED MEMORY] failed `HGETDEL` by %s:%s", mainKey, childKey),
+		)
+	}
+
+	b.hits.Add(1)
+	b.removeField(shard, stored)
+
+	return stored.value, nil
+}
+
+// HLen implements [Cache.HLen] for the bounded in‑memory back‑end.
+func (b *BoundedMemory) HLen(
+	_ context.Context,
+	mainKey string,
+) (int64, yaerrors.Error) {
+	shard := b.shard(mainKey)
+
+	shard.mutex.Lock()
+	defer shard.mutex.Unlock()
+
+	entry := b.lookupHash(shard, mainKey, time.Now())
+	if entry == nil {
+		return 0, nil
+	}
+
+	return int64(len(entry.fields)), nil
+}
+
+// HExist reports whether the childKey exists. Like [Memory.HExist], a missing
+// hash is an error.
+//
+// Example:
+//
+//	ok, _ := memory.HExist(ctx, "k", "f")
+func (b *BoundedMemory) HExist(
+	_ context.Context,
+	mainKey string,
+	childKey string,
+) (bool, yaerrors.Error) {
+	shard := b.shard(mainKey)
+	now := time.Now()
+
+	shard.mutex.Lock()
+	defer shard.mutex.Unlock()
+
+	entry := b.lookupHash(shard, mainKey, now)
+	if entry == nil {
+		return false, yaerrors.FromError(
+			http.StatusInternalServerError,
+			ErrFailedToHExist,
+			fmt.Sprintf("[BOUNDED MEMORY] failed to get child map by `%s`", mainKey),
+		)
+	}
+
+	return b.lookupField(shard, entry, childKey, now) != nil, nil
+}
+
+// HDelSingle deletes a single field. Like [Memory.HDelSingle], a missing hash
+// is an error.
+//
+// Example:
+//
+//	_ = memory.HDelSingle(ctx, "jobs", "id-1")
+func (b *BoundedMemory) HDelSingle(
+	_ context.Context,
+	mainKey string,
+	childKey string,
+) yaerrors.Error {
+	shard := b.shard(mainKey)
+
+	shard.mutex.Lock()
+	defer shard.mutex.Unlock()
+
+	entry := b.lookupHash(shard, mainKey, time.Now())
+	if entry == nil {
+		return yaerrors.FromError(
+			http.StatusInternalServerError,
+			ErrFailedToDeleteSingle,
+			fmt.Sprintf("[BOUNDED MEMORY] failed to get child map by `%s`", mainKey),
+		)
+	}
+
+	if stored, ok := entry.fields[childKey]; ok {
+		b.removeField(shard, stored)
+	}
+
+	return nil
+}
+
+// Set stores key → value and applies a TTL. A zero ttl means “store
+// indefinitely”. Storing may evict other entries to stay within budget.
+//
+// Example:
+//
+//	_ = memory.Set(ctx, "access-token", "abcdef", 15*time.Minute)
+func (b *BoundedMemory) Set(
+	_ context.Context,
+	key string,
+	value string,
+	ttl time.Duration,
+) yaerrors.Error {
+	shard := b.shard(key)
+
+	shard.mutex.Lock()
+
+	entry := b.store(shard, key, value, deadline(time.Now(), ttl))
+
+	shard.mutex.Unlock()
+
+	b.enforce(entry)
+
+	return nil
+}
+
+// Get retrieves the value stored under key. A missing or expired key returns
+// an error matching ErrNotFoundValue.
+//
+// Example:
+//
+//	token, _ := memory.Get(ctx, "access-token")
+func (b *BoundedMemory) Get(
+	_ context.Context,
+	key string,
+) (string, yaerrors.Error) {
+	shard := b.shard(key)
+
+	shard.mutex.Lock()
+	defer shard.mutex.Unlock()
+
+	entry := b.lookupValue(shard, key, time.Now())
+	if entry == nil {
+		b.misses.Add(1)
+
+		return "", yaerrors.FromError(
+			http.StatusInternalServerError,
+			errors.Join(ErrNotFoundValue, ErrFailedToGetValue),
+			"[BOUNDED MEMORY] failed to get value in key: "+key,
+		)
+	}
+
+	b.hits.Add(1)
+	b.touch(shard, entry)
+
+	return entry.value.value, nil
+}
+
+// MGet fetches the values for the specified keys. Missing keys are silently
+// skipped, exactly like [Memory.MGet].
+//
+// Example:
+//
+//	values, _ := memory.MGet(ctx, "k1", "k2")
+func (b *BoundedMemory) MGet(
+	_ context.Context,
+	keys ...string,
+) (map[string]string, yaerrors.Error) {
+	result := make(map[string]string)
+	now := time.Now()
+
+	for _, key := range keys {
+		shard := b.shard(key)
+
+		shard.mutex.Lock()
+
+		if entry := b.lookupValue(shard, key, now); entry != nil {
+			b.hits.Add(1)
+			b.touch(shard, entry)
+
+			result[key] = entry.value.value
+		} else {
+			b.misses.Add(1)
+		}
+
+		shard.mutex.Unlock()
+	}
+
+	return result, nil
+}
+
+// GetDel atomically reads and deletes the key.
+//
+// Example:
+//
+//	token, _ := memory.GetDel(ctx, "one-shot-token")
+func (b *BoundedMemory) GetDel(
+	_ context.Context,
+	key string,
+) (string, yaerrors.Error) {
+	shard := b.shard(key)
+
+	shard.mutex.Lock()
+	defer shard.mutex.Unlock()
+
+	entry := b.lookupValue(shard, key, time.Now())
+	if entry == nil {
+		b.misses.Add(1)
+
+		return "", yaerrors.FromError(
+			http.StatusInternalServerError,
+			ErrFailedToGetDelValue,
+			"[BOUNDED MEMORY] failed to get and delete value in key: "+key,
+		)
+	}
+
+	b.hits.Add(1)
+	b.remove(shard, entry)
+
+	return entry.value.value, nil
+}
+
+// Exists reports whether all specified keys are present as plain values,
+// lists or sorted sets. Expired keys are never reported.
+//
+// Example:
+//
+//	ok, _ := memory.Exists(ctx, "access-token", "refresh-token")
+func (b *BoundedMemory) Exists(
+	_ context.Context,
+	keys ...string,
+) (bool, yaerrors.Error) {
+	now := time.Now()
+
+	for _, key := range keys {
+		shard := b.shard(key)
+
+		shard.mutex.Lock()
+		found := b.lookupValue(shard, key, now) != nil ||
+			b.lookupEntry(shard, memoryKindList, key, now) != nil ||
+			b.lookupEntry(shard, memoryKindSortedSet, key, now) != nil
+		shard.mutex.Unlock()
+
+		if !found {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// CompareAndSwap replaces the value under key only if it currently equals
+// expected; a missing or expired key compares equal to "". The comparison
+// and the write happen under the key's shard lock.
+//
+// Example:
+//
+//	ok, _ := memory.CompareAndSwap(ctx, "counter", "1", "2", 0)
+func (b *BoundedMemory) CompareAndSwap(
+	_ context.Context,
+	key string,
+	expected string,
+	value string,
+	ttl time.Duration,
+) (bool, yaerrors.Error) {
+	shard := b.shard(key)
+	now := time.Now()
+
+	shard.mutex.Lock()
+
+	var current string
+
+	if entry := b.lookupValue(shard, key, now); entry != nil {
+		current = entry.value.value
+	}
+
+	if current != expected {
+		shard.mutex.Unlock()
+
+		return false, nil
+	}
+
+	entry := b.store(shard, key, value, deadline(now, ttl))
+
+	shard.mutex.Unlock()
+
+	b.enforce(entry)
+
+	return true, nil
+}
+
+// Del unconditionally removes the plain value, list or sorted set stored under
+// key. Deleting a missing key is not an error.
+//
+// Example:
+//
+//	_ = memory.Del(ctx, "access-token")
+func (b *BoundedMemory) Del(
+	_ context.Context,
+	key string,
+) yaerrors.Error {
+	shard := b.shard(key)
+
+	shard.mutex.Lock()
+	defer shard.mutex.Unlock()
+
+	for _, index := range []map[string]*boundedEntry{shard.values, shard.lists, shard.sortedSets} {
+		if entry, ok := index[key]; ok {
+			b.remove(shard, entry)
+		}
+	}
+
+	return nil
+}
+
+// IncrBy adds delta to the integer stored under key, keeping its deadline.
+//
+// Example:
+//
+//	views, _ := memory.IncrBy(ctx, "views:42", 10)
+func (b *BoundedMemory) IncrBy(
+	_ context.Context,
+	key string,
+	delta int64,
+) (int64, yaerrors.Error) {
+	shard := b.shard(key)
+	now := time.Now()
+
+	shard.mutex.Lock()
+
+	if b.holdsOtherKind(shard, key, memoryKindString, now) {
+		shard.mutex.Unlock()
+
+		return 0, wrongTypeError(boundedMemoryBackend, "INCRBY", key, ErrFailedToIncr)
+	}
+
+	value := delta
+
+	var expiresAt time.Time
+
+	if entry := b.lookupValue(shard, key, now); entry != nil {
+		result, err := incrementString(boundedMemoryBackend, key, entry.value.value, delta)
+		if err != nil {
+			shard.mutex.Unlock()
+
+			return 0, err
+		}
+
+		value, expiresAt = result, entry.value.expiresAt
+	}
+
+	entry := b.store(shard, key, strconv.FormatInt(value, 10), expiresAt)
+
+	shard.mutex.Unlock()
+
+	b.enforce(entry)
+
+	return value, nil
+}
+
+// Incr adds one to the integer stored under key.
+//
+// Example:
+//
+//	views, _ := memory.Incr(ctx, "views:42")
+func (b *BoundedMemory) Incr(
+	ctx context.Context,
+	key string,
+) (int64, yaerrors.Error) {
+	return b.IncrBy(ctx, key, 1)
+}
+
+// LPush inserts values at the head of the list under key. The list counts as
+// one entry for eviction.
+//
+// Example:
+//
+//	length, _ := memory.LPush(ctx, "jobs", "a", "b") // jobs = [b a]
+func (b *BoundedMemory) LPush(
+	_ context.Context,
+	key string,
+	values ...string,
+) (int64, yaerrors.Error) {
+	return b.push(key, true, values)
+}
+
+// RPush appends values to the tail of the list under key. The list counts as
+// one entry for eviction.
+//
+// Example:
+//
+//	length, _ := memory.RPush(ctx, "jobs", "a", "b") // jobs = [a b]
+func (b *BoundedMemory) RPush(
+	_ context.Context,
+	key string,
+	values ...string,
+) (int64, yaerrors.Error) {
+	return b.push(key, false, values)
+}
+
+// push implements LPush (head) and RPush.
+func (b *BoundedMemory) push(key string, head bool, values []string) (int64, yaerrors.Error) {
+	shard := b.shard(key)
+	now := time.Now()
+
+	shard.mutex.Lock()
+
+	if b.holdsOtherKind(shard, key, memoryKindList, now) {
+		shard.mutex.Unlock()
+
+		return 0, wrongTypeError(boundedMemoryBackend, "PUSH", key, ErrFailedToPush)
+	}
+
+	entry := b.lookupEntry(shard, memoryKindList, key, now)
+	if entry == nil {
+		if len(values) == 0 {
+			shard.mutex.Unlock()
+
+			return 0, nil
+		}
+
+		entry = b.collection(shard, memoryKindList, key)
+	}
+
+	for _, value := range values {
+		b.resize(entry, int64(len(value)))
+	}
+
+	entry.list = pushValues(entry.list, head, values)
+	length := int64(len(entry.list))
+
+	b.touch(shard, entry)
+
+	shard.mutex.Unlock()
+
+	b.enforce(entry)
+
+	return length, nil
+}
+
+// LPop removes and returns the first element of the list under key.
+//
+// Example:
+//
+//	job, _ := memory.LPop(ctx, "jobs")
+func (b *BoundedMemory) LPop(
+	_ context.Context,
+	key string,
+) (string, yaerrors.Error) {
+	return b.pop(key, true)
+}
+
+// RPop removes and returns the last element of the list under key.
+//
+// Example:
+//
+//	job, _ := memory.RPop(ctx, "jobs")
+func (b *BoundedMemory) RPop(
+	_ context.Context,
+	key string,
+) (string, yaerrors.Error) {
+	return b.pop(key, false)
+}
+
+// pop implements LPop (head) and RPop.
+func (b *BoundedMemory) pop(key string, head bool) (string, yaerrors.Error) {
+	shard := b.shard(key)
+	now := time.Now()
+
+	shard.mutex.Lock()
+	defer shard.mutex.Unlock()
+
+	if b.holdsOtherKind(shard, key, memoryKindList, now) {
+		return "", wrongTypeError(boundedMemoryBackend, "POP", key, ErrFailedToPop)
+	}
+
+	entry := b.lookupEntry(shard, memoryKindList, key, now)
+	if entry == nil {
+		return "", notFoundError(boundedMemoryBackend, "POP", key, ErrFailedToPop)
+	}
+
+	var value string
+
+	if head {
+		value, entry.list = entry.list[0], entry.list[1:]
+	} else {
+		last := len(entry.list) - 1
+		value, entry.list = entry.list[last], entry.list[:last]
+	}
+
+	b.resize(entry, -int64(len(value)))
+
+	if len(entry.list) == 0 {
+		b.remove(shard, entry)
+	} else {
+		b.touch(shard, entry)
+	}
+
+	return value, nil
+}
+
+// LRange returns a copy of the elements between start and stop, inclusive.
+//
+// Example:
+//
+//	all, _ := memory.LRange(ctx, "jobs", 0, -1)
+func (b *BoundedMemory) LRange(
+	_ context.Context,
+	key string,
+	start int64,
+	stop int64,
+) ([]string, yaerrors.Error) {
+	shard := b.shard(key)
+	now := time.Now()
+
+	shard.mutex.Lock()
+	defer shard.mutex.Unlock()
+
+	if b.holdsOtherKind(shard, key, memoryKindList, now) {
+		return nil, wrongTypeError(boundedMemoryBackend, "LRANGE", key, ErrFailedToRange)
+	}
+
+	entry := b.lookupEntry(shard, memoryKindList, key, now)
+	if entry == nil {
+		return []string{}, nil
+	}
+
+	b.touch(shard, entry)
+
+	from, to, ok := listBounds(len(entry.list), start, stop)
+	if !ok {
+		return []string{}, nil
+	}
+
+	return slices.Clone(entry.list[from:to]), nil
+}
+
+// ZAdd adds or updates sorted-set members. A NaN score is rejected. The
+// sorted set counts as one entry for eviction.
+//
+// Example:
+//
+//	_, _ = memory.ZAdd(ctx, "leaderboard", yacache.ZMember{Member: "alice", Score: 42})
+func (b *BoundedMemory) ZAdd(
+	_ context.Context,
+	key string,
+	members ...ZMember,
+) (int64, yaerrors.Error) {
+	if len(members) == 0 {
+		return 0, nil
+	}
+
+	for _, member := range members {
+		if math.IsNaN(member.Score) {
+			return 0, yaerrors.FromError(
+				http.StatusInternalServerError,
+				ErrFailedToZAdd,
+				fmt.Sprintf("[BOUNDED MEMORY] NaN score for `%s` by `%s`", member.Member, key),
+			)
+		}
+	}
+
+	shard := b.shard(key)
+	now := time.Now()
+
+	shard.mutex.Lock()
+
+	if b.holdsOtherKind(shard, key, memoryKindSortedSet, now) {
+		shard.mutex.Unlock()
+
+		return 0, wrongTypeError(boundedMemoryBackend, "ZADD", key, ErrFailedToZAdd)
+	}
+
+	entry := b.lookupEntry(shard, memoryKindSortedSet, key, now)
+	if entry == nil {
+		entry = b.collection(shard, memoryKindSortedSet, key)
+		entry.scores = make(map[string]float64, len(members))
+	}
+
+	var added int64
+
+	for _, member := range members {
+		if _, exists := entry.scores[member.Member]; !exists {
+			b.resize(entry, int64(len(member.Member))+scoreSize)
+
+			added++
+		}
+
+		entry.scores[member.Member] = member.Score
+	}
+
+	b.touch(shard, entry)
+
+	shard.mutex.Unlock()
+
+	b.enforce(entry)
+
+	return added, nil
+}
+
+// ZRangeByScore returns the members scored within [minScore, maxScore].
+//
+// Example:
+//
+//	top, _ := memory.ZRangeByScore(ctx, "leaderboard", 100, math.Inf(1))
+func (b *BoundedMemory) ZRangeByScore(
+	_ context.Context,
+	key string,
+	minScore float64,
+	maxScore float64,
+) ([]ZMember, yaerrors.Error) {
+	shard := b.shard(key)
+	now := time.Now()
+
+	shard.mutex.Lock()
+	defer shard.mutex.Unlock()
+
+	if b.holdsOtherKind(shard, key, memoryKindSortedSet, now) {
+		return nil, wrongTypeError(boundedMemoryBackend, "ZRANGEBYSCORE", key, ErrFailedToZRange)
+	}
+
+	entry := b.lookupEntry(shard, memoryKindSortedSet, key, now)
+	if entry == nil {
+		return []ZMember{}, nil
+	}
+
+	b.touch(shard, entry)
+
+	return rangeByScore(entry.scores, minScore, maxScore), nil
+}
+
+// ZRem removes sorted-set members.
+//
+// Example:
+//
+//	_, _ = memory.ZRem(ctx, "leaderboard", "alice")
+func (b *BoundedMemory) ZRem(
+	_ context.Context,
+	key string,
+	members ...string,
+) (int64, yaerrors.Error) {
+	if len(members) == 0 {
+		return 0, nil
+	}
+
+	shard := b.shard(key)
+	now := time.Now()
+
+	shard.mutex.Lock()
+	defer shard.mutex.Unlock()
+
+	if b.holdsOtherKind(shard, key, memoryKindSortedSet, now) {
+		return 0, wrongTypeError(boundedMemoryBackend, "ZREM", key, ErrFailedToZRem)
+	}
+
+	entry := b.lookupEntry(shard, memoryKindSortedSet, key, now)
+	if entry == nil {
+		return 0, nil
+	}
+
+	var removed int64
+
+	for _, member := range members {
+		if _, exists := entry.scores[member]; exists {
+			delete(entry.scores, member)
+			b.resize(entry, -(int64(len(member)) + scoreSize))
+
+			removed++
+		}
+	}
+
+	if len(entry.scores) == 0 {
+		b.remove(shard, entry)
+	}
+
+	return removed, nil
+}
+
+// Scan yields the live keys starting with prefix in lexical order. The keys
+// are collected shard by shard and yielded without holding any lock, so the
+// loop body may write to the cache.
+//
+// Example:
+//
+//	for key := range memory.Scan(ctx, "user:") { … }
+func (b *BoundedMemory) Scan(
+	_ context.Context,
+	prefix string,
+) iter.Seq2[string, yaerrors.Error] {
+	return func(yield func(string, yaerrors.Error) bool) {
+		var keys []string
+
+		now := time.Now()
+
+		for _, shard := range b.shards {
+			shard.mutex.Lock()
+
+			for _, entry := range b.matching(shard, prefix, now) {
+				keys = append(keys, entry.key)
+			}
+
+			shard.mutex.Unlock()
+		}
+
+		slices.Sort(keys)
+
+		for _, key := range slices.Compact(keys) {
+			if !yield(key, nil) {
+				return
+			}
+		}
+	}
+}
+
+// DelPrefix deletes every key starting with prefix and returns how many live
+// keys it deleted.
+//
+// Example:
+//
+//	deleted, _ := memory.DelPrefix(ctx, "user:42:")
+func (b *BoundedMemory) DelPrefix(
+	_ context.Context,
+	prefix string,
+) (int64, yaerrors.Error) {
+	if prefix == "" {
+		return 0, emptyPrefixError(boundedMemoryBackend)
+	}
+
+	var deleted int64
+
+	now := time.Now()
+
+	for _, shard := range b.shards {
+		shard.mutex.Lock()
+
+		for _, entry := range b.matching(shard, prefix, now) {
+			b.remove(shard, entry)
+
+			deleted++
+		}
+
+		shard.mutex.Unlock()
+	}
+
+	return deleted, nil
+}
+
+// TTL returns the time key has left to live, 0 for a key without TTL.
+//
+// Example:
+//
+//	ttl, _ := memory.TTL(ctx, "access-token")
+func (b *BoundedMemory) TTL(
+	_ context.Context,
+	key string,
+) (time.Duration, yaerrors.Error) {
+	shard := b.shard(key)
+	now := time.Now()
+
+	shard.mutex.Lock()
+	defer shard.mutex.Unlock()
+
+	entry := b.lookupAny(shard, key, now)
+	if entry == nil {
+		return 0, notFoundError(boundedMemoryBackend, "PTTL", key, ErrFailedToGetTTL)
+	}
+
+	var longest time.Duration
+
+	for _, stored := range entry.deadlines() {
+		if stored.expiresAt.IsZero() {
+			return 0, nil
+		}
+
+		ttl, _ := remaining(stored.expiresAt, now)
+		longest = max(longest, ttl)
+	}
+
+	return longest, nil
+}
+
+// Expire sets the TTL of key, or deletes it for a non‑positive ttl.
+//
+// Example:
+//
+//	ok, _ := memory.Expire(ctx, "access-token", time.Hour)
+func (b *BoundedMemory) Expire(
+	_ context.Context,
+	key string,
+	ttl time.Duration,
+) (bool, yaerrors.Error) {
+	shard := b.shard(key)
+	now := time.Now()
+
+	shard.mutex.Lock()
+	defer shard.mutex.Unlock()
+
+	entry := b.lookupAny(shard, key, now)
+	if entry == nil {
+		return false, nil
+	}
+
+	if ttl <= 0 {
+		b.remove(shard, entry)
+
+		return true, nil
+	}
+
+	for _, stored := range entry.deadlines() {
+		stored.expiresAt = now.Add(ttl)
+
+		shard.schedule(stored)
+	}
+
+	return true, nil
+}
+
+// Persist removes the TTL of key.
+//
+// Example:
+//
+//	ok, _ := memory.Persist(ctx, "access-token")
+func (b *BoundedMemory) Persist(
+	_ context.Context,
+	key string,
+) (bool, yaerrors.Error) {
+	shard := b.shard(key)
+
+	shard.mutex.Lock()
+	defer shard.mutex.Unlock()
+
+	entry := b.lookupAny(shard, key, time.Now())
+	if entry == nil {
+		return false, nil
+	}
+
+	persisted := false
+
+	for _, stored := range entry.deadlines() {
+		if !stored.expiresAt.IsZero() {
+			stored.expiresAt, persisted = time.Time{}, true
+
+			shard.schedule(stored)
+		}
+	}
+
+	return persisted, nil
+}
+
+// Ping always succeeds for the bounded in‑memory backend.
+//
+// Example:
+//
+//	_ = memory.Ping(ctx)
+func (b *BoundedMemory) Ping(_ context.Context) yaerrors.Error {
+	return nil
+}
+
+// Close stops the sweeper and drops every entry. Counters are kept.
+//
+// Example:
+//
+//	_ = memory.Close()
+func (b *BoundedMemory) Close() yaerrors.Error {
+	b.closeOnce.Do(func() {
+		close(b.done)
+	})
+
+	b.purge()
+
+	return nil
+}
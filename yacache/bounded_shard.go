@@ -0,0 +1,424 @@
+package yacache
+
+import (
+	"container/heap"
+	"hash/fnv"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
+// purge drops every entry while keeping the cache usable.
+func (b *BoundedMemory) purge() {
+	for _, shard := range b.shards {
+		shard.mutex.Lock()
+
+		for _, entry := range shard.values {
+			b.remove(shard, entry)
+		}
+
+		for _, entry := range shard.hashes {
+			b.remove(shard, entry)
+		}
+
+		for _, entry := range shard.lists {
+			b.remove(shard, entry)
+		}
+
+		for _, entry := range shard.sortedSets {
+			b.remove(shard, entry)
+		}
+
+		shard.mutex.Unlock()
+	}
+}
+
+// shard picks the partition owning key.
+func (b *BoundedMemory) shard(key string) *boundedShard {
+	hash := fnv.New64a()
+
+	_, _ = hash.Write([]byte(key)) //nolint:errcheck // hash.Hash.Write never fails
+
+	return b.shards[hash.Sum64()&b.mask]
+}
+
+// store writes a plain value under key, creating or updating its entry and
+// replacing a list or sorted set held by the same key. The caller holds the
+// shard lock and calls enforce after releasing it.
+func (b *BoundedMemory) store(
+	shard *boundedShard,
+	key string,
+	value string,
+	expiresAt time.Time,
+) *boundedEntry {
+	for _, index := range []map[string]*boundedEntry{shard.lists, shard.sortedSets} {
+		if other, found := index[key]; found {
+			b.remove(shard, other)
+		}
+	}
+
+	entry, ok := shard.values[key]
+	if !ok {
+		entry = &boundedEntry{key: key, kind: memoryKindString, size: int64(len(key))}
+		entry.value = &boundedValue{entry: entry, index: -1}
+
+		b.insert(shard, entry)
+	}
+
+	b.resize(entry, int64(len(value)-len(entry.value.value)))
+	entry.value.value = value
+	entry.value.expiresAt = expiresAt
+
+	shard.schedule(entry.value)
+	b.touch(shard, entry)
+
+	return entry
+}
+
+// collection creates an empty list or sorted set entry under key without
+// expiry. The caller holds the shard lock and calls enforce after releasing it.
+func (b *BoundedMemory) collection(shard *boundedShard, kind memoryKind, key string) *boundedEntry {
+	entry := &boundedEntry{key: key, kind: kind, size: int64(len(key))}
+	entry.value = &boundedValue{entry: entry, index: -1}
+
+	b.insert(shard, entry)
+
+	return entry
+}
+
+// lookupValue returns the live plain entry under key, dropping it if expired.
+func (b *BoundedMemory) lookupValue(shard *boundedShard, key string, now time.Time) *boundedEntry {
+	return b.lookupEntry(shard, memoryKindString, key, now)
+}
+
+// lookupEntry returns the live entry of kind (anything but a hash) under key,
+// dropping it if expired.
+func (b *BoundedMemory) lookupEntry(
+	shard *boundedShard,
+	kind memoryKind,
+	key string,
+	now time.Time,
+) *boundedEntry {
+	entry, ok := shard.index(kind)[key]
+	if !ok {
+		return nil
+	}
+
+	if entry.value.expired(now) {
+		b.expirations.Add(1)
+		b.remove(shard, entry)
+
+		return nil
+	}
+
+	return entry
+}
+
+// lookupHash returns the live hash under key after dropping its expired
+// fields, or nil when none are left.
+func (b *BoundedMemory) lookupHash(shard *boundedShard, key string, now time.Time) *boundedEntry {
+	entry, ok := shard.hashes[key]
+	if !ok {
+		return nil
+	}
+
+	for _, stored := range entry.fields {
+		if stored.expired(now) {
+			b.expirations.Add(1)
+			b.removeField(shard, stored)
+		}
+	}
+
+	if _, ok = shard.hashes[key]; !ok {
+		return nil
+	}
+
+	return entry
+}
+
+// holdsOtherKind reports whether key holds a live value of another kind than
+// the one a command operates on.
+func (b *BoundedMemory) holdsOtherKind(
+	shard *boundedShard,
+	key string,
+	kind memoryKind,
+	now time.Time,
+) bool {
+	for _, other := range []memoryKind{
+		memoryKindString,
+		memoryKindHash,
+		memoryKindList,
+		memoryKindSortedSet,
+	} {
+		switch {
+		case other == kind:
+			continue
+		case other == memoryKindHash:
+			if b.lookupHash(shard, key, now) != nil {
+				return true
+			}
+		default:
+			if b.lookupEntry(shard, other, key, now) != nil {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// lookupAny returns the live entry of whatever kind key holds.
+func (b *BoundedMemory) lookupAny(shard *boundedShard, key string, now time.Time) *boundedEntry {
+	for _, kind := range []memoryKind{memoryKindString, memoryKindList, memoryKindSortedSet} {
+		if entry := b.lookupEntry(shard, kind, key, now); entry != nil {
+			return entry
+		}
+	}
+
+	return b.lookupHash(shard, key, now)
+}
+
+// matching returns the live entries of a shard whose key starts with prefix,
+// dropping the expired ones it meets. The caller holds the shard lock.
+func (b *BoundedMemory) matching(
+	shard *boundedShard,
+	prefix string,
+	now time.Time,
+) []*boundedEntry {
+	var entries []*boundedEntry
+
+	for _, kind := range []memoryKind{
+		memoryKindString,
+		memoryKindHash,
+		memoryKindList,
+		memoryKindSortedSet,
+	} {
+		for key := range shard.index(kind) {
+			if !strings.HasPrefix(key, prefix) {
+				continue
+			}
+
+			var entry *boundedEntry
+
+			if kind == memoryKindHash {
+				entry = b.lookupHash(shard, key, now)
+			} else {
+				entry = b.lookupEntry(shard, kind, key, now)
+			}
+
+			if entry != nil {
+				entries = append(entries, entry)
+			}
+		}
+	}
+
+	return entries
+}
+
+// lookupField returns the live field of a hash entry, dropping it if expired.
+func (b *BoundedMemory) lookupField(
+	shard *boundedShard,
+	entry *boundedEntry,
+	field string,
+	now time.Time,
+) *boundedValue {
+	stored, ok := entry.fields[field]
+	if !ok {
+		return nil
+	}
+
+	if stored.expired(now) {
+		b.expirations.Add(1)
+		b.removeField(shard, stored)
+
+		return nil
+	}
+
+	return stored
+}
+
+// insert registers a new entry with its shard and the global usage.
+func (b *BoundedMemory) insert(shard *boundedShard, entry *boundedEntry) {
+	shard.index(entry.kind)[entry.key] = entry
+
+	heap.Push(&shard.eviction, entry)
+
+	b.entries.Add(1)
+	b.bytes.Add(entry.size)
+}
+
+// remove drops an entry with all of its values.
+func (b *BoundedMemory) remove(shard *boundedShard, entry *boundedEntry) {
+	if entry.kind == memoryKindHash {
+		for _, stored := range entry.fields {
+			shard.unschedule(stored)
+		}
+	} else {
+		shard.unschedule(entry.value)
+	}
+
+	delete(shard.index(entry.kind), entry.key)
+
+	heap.Remove(&shard.eviction, entry.index)
+
+	b.entries.Add(-1)
+	b.bytes.Add(-entry.size)
+}
+
+// removeField drops a single hash field and the hash itself once empty.
+func (b *BoundedMemory) removeField(shard *boundedShard, stored *boundedValue) {
+	entry := stored.entry
+
+	shard.unschedule(stored)
+	delete(entry.fields, stored.field)
+	b.resize(entry, -int64(len(stored.field)+len(stored.value)))
+
+	if len(entry.fields) == 0 {
+		b.remove(shard, entry)
+	}
+}
+
+// resize adjusts the size of entry and the global byte usage by delta.
+func (b *BoundedMemory) resize(entry *boundedEntry, delta int64) {
+	entry.size += delta
+
+	b.bytes.Add(delta)
+}
+
+// touch records an access for the eviction policy.
+func (b *BoundedMemory) touch(shard *boundedShard, entry *boundedEntry) {
+	entry.tick = b.clock.Add(1)
+	entry.frequency++
+
+	heap.Fix(&shard.eviction, entry.index)
+}
+
+// overBudget reports whether the current usage exceeds any configured budget.
+func (b *BoundedMemory) overBudget() bool {
+	return (b.config.MaxEntries > 0 && b.entries.Load() > b.config.MaxEntries) ||
+		(b.config.MaxBytes > 0 && b.bytes.Load() > b.config.MaxBytes)
+}
+
+// enforce evicts entries until usage fits the budget. Each round compares
+// the best victim of every shard, so eviction order is global even though
+// every shard keeps its own heap. The entry that was just written is
+// protected, otherwise LFU would always evict the newcomer. Callers must not
+// hold any shard lock.
+func (b *BoundedMemory) enforce(protected *boundedEntry) {
+	if !b.overBudget() {
+		return
+	}
+
+	b.evictMutex.Lock()
+	defer b.evictMutex.Unlock()
+
+	for b.overBudget() {
+		var (
+			victim    *boundedShard
+			candidate *boundedEntry
+		)
+
+		for _, shard := range b.shards {
+			shard.mutex.Lock()
+
+			if head := shard.eviction.victim(protected); head != nil &&
+				(candidate == nil || shard.eviction.before(head, candidate)) {
+				victim, candidate = shard, head
+			}
+
+			shard.mutex.Unlock()
+		}
+
+		if victim == nil {
+			return
+		}
+
+		victim.mutex.Lock()
+
+		if head := victim.eviction.victim(protected); head != nil {
+			b.remove(victim, head)
+			b.evictions.Add(1)
+		}
+
+		victim.mutex.Unlock()
+	}
+}
+
+// sweep purges every value whose deadline is not after now.
+func (b *BoundedMemory) sweep(now time.Time) {
+	for _, shard := range b.shards {
+		shard.mutex.Lock()
+
+		for len(shard.expiry) > 0 && shard.expiry[0].expired(now) {
+			stored := shard.expiry[0]
+
+			b.expirations.Add(1)
+
+			if stored.entry.kind == memoryKindHash {
+				b.removeField(shard, stored)
+			} else {
+				b.remove(shard, stored.entry)
+			}
+		}
+
+		shard.mutex.Unlock()
+	}
+}
+
+// index returns the map holding the entries of kind.
+func (s *boundedShard) index(kind memoryKind) map[string]*boundedEntry {
+	switch kind {
+	case memoryKindHash:
+		return s.hashes
+	case memoryKindList:
+		return s.lists
+	case memoryKindSortedSet:
+		return s.sortedSets
+	default:
+		return s.values
+	}
+}
+
+// schedule (re)places value in the expiry heap according to its deadline.
+func (s *boundedShard) schedule(value *boundedValue) {
+	switch {
+	case value.expiresAt.IsZero():
+		s.unschedule(value)
+	case value.index >= 0:
+		heap.Fix(&s.expiry, value.index)
+	default:
+		heap.Push(&s.expiry, value)
+	}
+}
+
+// unschedule takes value out of the expiry heap if it is there.
+func (s *boundedShard) unschedule(value *boundedValue) {
+	if value.index >= 0 {
+		heap.Remove(&s.expiry, value.index)
+	}
+}
+
+// deadlines returns the values carrying the entry's TTL: every field of a
+// hash, the single value of any other kind.
+func (e *boundedEntry) deadlines() []*boundedValue {
+	if e.kind != memoryKindHash {
+		return []*boundedValue{e.value}
+	}
+
+	return slices.Collect(maps.Values(e.fields))
+}
+
+// expired reports whether the value's deadline has passed at now.
+func (v *boundedValue) expired(now time.Time) bool {
+	return !v.expiresAt.IsZero() && now.After(v.expiresAt)
+}
+
+// item converts the value into the MemoryContainer representation.
+func (v *boundedValue) item() *memoryCacheItem {
+	if v.expiresAt.IsZero() {
+		return newMemoryCacheItem(v.value)
+	}
+
+	return newMemoryCacheItemEX(v.value, v.expiresAt)
+}
@@ -0,0 +1,252 @@
+package yacache_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yacache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var _ yacache.Cache[yacache.MemoryContainer] = (*yacache.BoundedMemory)(nil)
+
+func TestBoundedMemory_Operations_Works(t *testing.T) {
+	ctx := context.Background()
+
+	memory := yacache.NewBoundedMemory(yacache.BoundedMemoryConfig{})
+	defer memory.Close()
+
+	t.Run("[Set/Get] round trip", func(t *testing.T) {
+		require.Nil(t, memory.Set(ctx, yamainKey, yavalue, yattl))
+
+		value, err := memory.Get(ctx, yamainKey)
+		require.Nil(t, err)
+		assert.Equal(t, yavalue, value)
+	})
+
+	t.Run("[Get] missing key matches ErrNotFoundValue", func(t *testing.T) {
+		_, err := memory.Get(ctx, "missing")
+		assert.True(t, errors.Is(err, yacache.ErrNotFoundValue))
+	})
+
+	t.Run("[MGet/Exists] skip missing keys", func(t *testing.T) {
+		values, err := memory.MGet(ctx, yamainKey, "missing")
+		require.Nil(t, err)
+		assert.Equal(t, map[string]string{yamainKey: yavalue}, values)
+
+		exists, _ := memory.Exists(ctx, yamainKey)
+		assert.True(t, exists)
+
+		exists, _ = memory.Exists(ctx, yamainKey, "missing")
+		assert.False(t, exists)
+	})
+
+	t.Run("[CompareAndSwap] swaps only on match", func(t *testing.T) {
+		swapped, _ := memory.CompareAndSwap(ctx, yamainKey, "other", yavalue2, 0)
+		assert.False(t, swapped)
+
+		swapped, _ = memory.CompareAndSwap(ctx, yamainKey, yavalue, yavalue2, 0)
+		assert.True(t, swapped)
+
+		swapped, _ = memory.CompareAndSwap(ctx, yamainKey2, "", yavalue, 0)
+		assert.True(t, swapped)
+	})
+
+	t.Run("[GetDel/Del] remove keys", func(t *testing.T) {
+		value, err := memory.GetDel(ctx, yamainKey)
+		require.Nil(t, err)
+		assert.Equal(t, yavalue2, value)
+
+		require.Nil(t, memory.Del(ctx, yamainKey2))
+
+		exists, _ := memory.Exists(ctx, yamainKey2)
+		assert.False(t, exists)
+	})
+
+	t.Run("[Hash] field operations", func(t *testing.T) {
+		require.Nil(t, memory.HSetEX(ctx, yamainKey, yachildKey, yavalue, yattl))
+		require.Nil(t, memory.HSetEX(ctx, yamainKey, yachildKey2, yavalue2, 0))
+
+		value, err := memory.HGet(ctx, yamainKey, yachildKey)
+		require.Nil(t, err)
+		assert.Equal(t, yavalue, value)
+
+		all, err := memory.HGetAll(ctx, yamainKey)
+		require.Nil(t, err)
+		assert.Equal(t, map[string]string{yachildKey: yavalue, yachildKey2: yavalue2}, all)
+
+		hlen, _ := memory.HLen(ctx, yamainKey)
+		assert.Equal(t, int64(2), hlen)
+
+		value, err = memory.HGetDelSingle(ctx, yamainKey, yachildKey)
+		require.Nil(t, err)
+		assert.Equal(t, yavalue, value)
+
+		require.Nil(t, memory.HDelSingle(ctx, yamainKey, yachildKey2))
+
+		hlen, _ = memory.HLen(ctx, yamainKey)
+		assert.Zero(t, hlen)
+		assert.Zero(t, memory.Stats().Entries)
+		assert.Zero(t, memory.Stats().Bytes)
+	})
+}
+
+func TestBoundedMemory_Eviction_Works(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("[LRU] evicts the least recently used entry", func(t *testing.T) {
+		memory := yacache.NewBoundedMemory(yacache.BoundedMemoryConfig{
+			MaxEntries: 2,
+			Policy:     yacache.EvictLRU,
+		})
+		defer memory.Close()
+
+		_ = memory.Set(ctx, "a", "1", 0)
+		_ = memory.Set(ctx, "b", "2", 0)
+		_, _ = memory.Get(ctx, "a")
+		_ = memory.Set(ctx, "c", "3", 0)
+
+		values, _ := memory.MGet(ctx, "a", "b", "c")
+		assert.Equal(t, map[string]string{"a": "1", "c": "3"}, values)
+		assert.Equal(t, uint64(1), memory.Stats().Evictions)
+	})
+
+	t.Run("[LFU] evicts the least frequently used entry, never the newcomer", func(t *testing.T) {
+		memory := yacache.NewBoundedMemory(yacache.BoundedMemoryConfig{
+			MaxEntries: 2,
+			Policy:     yacache.EvictLFU,
+		})
+		defer memory.Close()
+
+		_ = memory.Set(ctx, "a", "1", 0)
+		_ = memory.Set(ctx, "b", "2", 0)
+
+		for range 3 {
+			_, _ = memory.Get(ctx, "a")
+		}
+
+		_, _ = memory.Get(ctx, "b")
+		_ = memory.Set(ctx, "a", "1", 0) // most recent write, still most frequent
+		_ = memory.Set(ctx, "c", "3", 0)
+
+		values, _ := memory.MGet(ctx, "a", "b", "c")
+		assert.Equal(t, map[string]string{"a": "1", "c": "3"}, values)
+	})
+
+	t.Run("[Bytes] keeps the payload within MaxBytes", func(t *testing.T) {
+		memory := yacache.NewBoundedMemory(yacache.BoundedMemoryConfig{MaxBytes: 10})
+		defer memory.Close()
+
+		_ = memory.Set(ctx, "k1", "12345", 0)
+		_ = memory.Set(ctx, "k2", "12345", 0)
+
+		stats := memory.Stats()
+		assert.Equal(t, int64(1), stats.Entries)
+		assert.Equal(t, int64(7), stats.Bytes)
+
+		_, err := memory.Get(ctx, "k2")
+		assert.Nil(t, err)
+	})
+
+	t.Run("[Hash] a hash is evicted as a whole", func(t *testing.T) {
+		memory := yacache.NewBoundedMemory(yacache.BoundedMemoryConfig{MaxEntries: 1})
+		defer memory.Close()
+
+		_ = memory.HSetEX(ctx, "h", "f1", "v", 0)
+		_ = memory.HSetEX(ctx, "h", "f2", "v", 0)
+		_ = memory.Set(ctx, "k", "v", 0)
+
+		hlen, _ := memory.HLen(ctx, "h")
+		assert.Zero(t, hlen)
+		assert.Equal(t, int64(1), memory.Stats().Entries)
+	})
+
+	t.Run("[Concurrent] usage never settles above the budget", func(t *testing.T) {
+		memory := yacache.NewBoundedMemory(yacache.BoundedMemoryConfig{MaxEntries: 64, Shards: 4})
+		defer memory.Close()
+
+		var wg sync.WaitGroup
+
+		for worker := range 8 {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				for i := range 500 {
+					key := strconv.Itoa(worker) + ":" + strconv.Itoa(i)
+
+					_ = memory.Set(ctx, key, "v", 0)
+					_, _ = memory.Get(ctx, key)
+				}
+			}()
+		}
+
+		wg.Wait()
+
+		assert.Equal(t, int64(64), memory.Stats().Entries)
+		assert.Len(t, memory.Raw().Map, 64)
+	})
+}
+
+func TestBoundedMemory_Expiry_Works(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("[Lazy] expired values are never returned", func(t *testing.T) {
+		memory := yacache.NewBoundedMemory(yacache.BoundedMemoryConfig{SweepInterval: time.Hour})
+		defer memory.Close()
+
+		_ = memory.Set(ctx, yamainKey, yavalue, time.Millisecond)
+		_ = memory.HSetEX(ctx, yamainKey, yachildKey, yavalue, time.Millisecond)
+		_ = memory.HSetEX(ctx, yamainKey, yachildKey2, yavalue2, 0)
+
+		time.Sleep(5 * time.Millisecond)
+
+		_, err := memory.Get(ctx, yamainKey)
+		assert.NotNil(t, err)
+
+		all, err := memory.HGetAll(ctx, yamainKey)
+		require.Nil(t, err)
+		assert.Equal(t, map[string]string{yachildKey2: yavalue2}, all)
+		assert.Equal(t, uint64(2), memory.Stats().Expirations)
+	})
+
+	t.Run("[Sweeper] expired values are purged without access", func(t *testing.T) {
+		memory := yacache.NewBoundedMemory(yacache.BoundedMemoryConfig{
+			SweepInterval: 5 * time.Millisecond,
+		})
+		defer memory.Close()
+
+		_ = memory.Set(ctx, yamainKey, yavalue, time.Millisecond)
+		_ = memory.HSetEX(ctx, yamainKey, yachildKey, yavalue, time.Millisecond)
+		_ = memory.Set(ctx, yamainKey2, yavalue2, 0)
+
+		assert.Eventually(t, func() bool {
+			return memory.Stats().Entries == 1
+		}, time.Second, 5*time.Millisecond)
+
+		assert.Equal(t, int64(len(yamainKey2)+len(yavalue2)), memory.Stats().Bytes)
+	})
+}
+
+func TestBoundedMemory_Stats_Works(t *testing.T) {
+	ctx := context.Background()
+
+	memory := yacache.NewBoundedMemory(yacache.BoundedMemoryConfig{})
+	defer memory.Close()
+
+	_ = memory.Set(ctx, yamainKey, yavalue, 0)
+	_, _ = memory.Get(ctx, yamainKey)
+	_, _ = memory.Get(ctx, yamainKey)
+	_, _ = memory.Get(ctx, "missing")
+
+	stats := memory.Stats()
+	assert.Equal(t, uint64(2), stats.Hits)
+	assert.Equal(t, uint64(1), stats.Misses)
+	assert.Equal(t, int64(1), stats.Entries)
+}
@@ -0,0 +1,402 @@
+package yacache_test
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yacache"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCache_Conformance runs the same behavioural suite against every
+// backend, so they stay interchangeable.
+func TestCache_Conformance(t *testing.T) {
+	t.Run("Memory", func(t *testing.T) {
+		runConformance(t, func(t *testing.T) (yacache.Cache[yacache.MemoryContainer], func(time.Duration)) {
+			t.Helper()
+
+			cache := yacache.NewCache(yacache.NewMemoryContainer())
+			t.Cleanup(func() { _ = cache.Close() })
+
+			return cache, time.Sleep
+		})
+	})
+
+	t.Run("BoundedMemory", func(t *testing.T) {
+		runConformance(t, func(t *testing.T) (yacache.Cache[yacache.MemoryContainer], func(time.Duration)) {
+			t.Helper()
+
+			cache := yacache.NewBoundedMemory(yacache.BoundedMemoryConfig{MaxEntries: 1000})
+			t.Cleanup(func() { _ = cache.Close() })
+
+			return cache, time.Sleep
+		})
+	})
+
+	t.Run("Redis", func(t *testing.T) {
+		runConformance(t, func(t *testing.T) (yacache.Cache[*redis.Client], func(time.Duration)) {
+			t.Helper()
+
+			server := miniredis.RunT(t)
+			client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+			t.Cleanup(func() { _ = client.Close() })
+
+			return yacache.NewCache(client), elapseOn(server)
+		})
+	})
+
+	t.Run("NearCache", func(t *testing.T) {
+		runConformance(t, func(t *testing.T) (yacache.Cache[*redis.Client], func(time.Duration)) {
+			t.Helper()
+
+			server := miniredis.RunT(t)
+
+			return newTestNearCache(t, server, yacache.NearCacheConfig{}), elapseOn(server)
+		})
+	})
+}
+
+// elapseOn lets d pass for a cache over server: miniredis expires keys only
+// when fast-forwarded, while a near cache's local copies follow the clock.
+func elapseOn(server *miniredis.Miniredis) func(time.Duration) {
+	return func(d time.Duration) {
+		time.Sleep(d)
+		server.FastForward(d)
+	}
+}
+
+// runConformance checks the portable semantics of the Cache interface on a
+// fresh cache per subtest. newCache also returns how to let time pass for
+// that cache.
+func runConformance[T yacache.Container](
+	t *testing.T,
+	newCache func(t *testing.T) (yacache.Cache[T], func(time.Duration)),
+) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	t.Run("[Incr/IncrBy] counts from zero", func(t *testing.T) {
+		cache, _ := newCache(t)
+
+		value, err := cache.Incr(ctx, yamainKey)
+		require.Nil(t, err)
+		assert.Equal(t, int64(1), value)
+
+		value, err = cache.IncrBy(ctx, yamainKey, 10)
+		require.Nil(t, err)
+		assert.Equal(t, int64(11), value)
+
+		value, err = cache.IncrBy(ctx, yamainKey, -20)
+		require.Nil(t, err)
+		assert.Equal(t, int64(-9), value)
+
+		stored, err := cache.Get(ctx, yamainKey)
+		require.Nil(t, err)
+		assert.Equal(t, "-9", stored)
+	})
+
+	t.Run("[Incr/IncrBy] rejects non-integers and overflow", func(t *testing.T) {
+		cache, _ := newCache(t)
+
+		require.Nil(t, cache.Set(ctx, yamainKey, yavalue, 0))
+
+		_, err := cache.Incr(ctx, yamainKey)
+		assert.True(t, errors.Is(err, yacache.ErrValueNotInteger))
+
+		require.Nil(t, cache.Set(ctx, yamainKey, strconv.FormatInt(math.MaxInt64, 10), 0))
+
+		_, err = cache.Incr(ctx, yamainKey)
+		assert.True(t, errors.Is(err, yacache.ErrValueNotInteger))
+	})
+
+	t.Run("[List] push, range and pop", func(t *testing.T) {
+		cache, _ := newCache(t)
+
+		length, err := cache.LPush(ctx, yamainKey, "a", "b")
+		require.Nil(t, err)
+		assert.Equal(t, int64(2), length)
+
+		length, err = cache.RPush(ctx, yamainKey, "c")
+		require.Nil(t, err)
+		assert.Equal(t, int64(3), length)
+
+		for _, testCase := range []struct {
+			start, stop int64
+			want        []string
+		}{
+			{0, -1, []string{"b", "a", "c"}},
+			{-2, -1, []string{"a", "c"}},
+			{1, 100, []string{"a", "c"}},
+			{-100, 0, []string{"b"}},
+			{2, 1, nil},
+			{5, 10, nil},
+		} {
+			values, err := cache.LRange(ctx, yamainKey, testCase.start, testCase.stop)
+			require.Nil(t, err)
+
+			if testCase.want == nil {
+				assert.Empty(t, values, "LRange(%d, %d)", testCase.start, testCase.stop)
+			} else {
+				assert.Equal(
+					t,
+					testCase.want,
+					values,
+					"LRange(%d, %d)",
+					testCase.start,
+					testCase.stop,
+				)
+			}
+		}
+
+		value, err := cache.LPop(ctx, yamainKey)
+		require.Nil(t, err)
+		assert.Equal(t, "b", value)
+
+		value, err = cache.RPop(ctx, yamainKey)
+		require.Nil(t, err)
+		assert.Equal(t, "c", value)
+
+		value, err = cache.RPop(ctx, yamainKey)
+		require.Nil(t, err)
+		assert.Equal(t, "a", value)
+
+		_, err = cache.LPop(ctx, yamainKey)
+		assert.True(t, errors.Is(err, yacache.ErrNotFoundValue))
+
+		exists, _ := cache.Exists(ctx, yamainKey)
+		assert.False(t, exists, "an emptied list is deleted")
+
+		values, err := cache.LRange(ctx, yamainKey, 0, -1)
+		require.Nil(t, err)
+		assert.Empty(t, values)
+	})
+
+	t.Run("[SortedSet] add, range by score and remove", func(t *testing.T) {
+		cache, _ := newCache(t)
+
+		added, err := cache.ZAdd(
+			ctx,
+			yamainKey,
+			yacache.ZMember{Member: "a", Score: 3},
+			yacache.ZMember{Member: "b", Score: 1},
+			yacache.ZMember{Member: "d", Score: 2},
+			yacache.ZMember{Member: "c", Score: 2},
+		)
+		require.Nil(t, err)
+		assert.Equal(t, int64(4), added)
+
+		added, err = cache.ZAdd(ctx, yamainKey, yacache.ZMember{Member: "a", Score: -1.5})
+		require.Nil(t, err)
+		assert.Zero(t, added, "updating a score adds nothing")
+
+		members, err := cache.ZRangeByScore(ctx, yamainKey, math.Inf(-1), math.Inf(1))
+		require.Nil(t, err)
+		assert.Equal(t, []yacache.ZMember{
+			{Member: "a", Score: -1.5},
+			{Member: "b", Score: 1},
+			{Member: "c", Score: 2},
+			{Member: "d", Score: 2},
+		}, members)
+
+		members, err = cache.ZRangeByScore(ctx, yamainKey, 1, 2)
+		require.Nil(t, err)
+		assert.Len(t, members, 3)
+
+		removed, err := cache.ZRem(ctx, yamainKey, "a", "missing")
+		require.Nil(t, err)
+		assert.Equal(t, int64(1), removed)
+
+		removed, err = cache.ZRem(ctx, yamainKey, "b", "c", "d")
+		require.Nil(t, err)
+		assert.Equal(t, int64(3), removed)
+
+		exists, _ := cache.Exists(ctx, yamainKey)
+		assert.False(t, exists, "an emptied sorted set is deleted")
+
+		members, err = cache.ZRangeByScore(ctx, yamainKey, math.Inf(-1), math.Inf(1))
+		require.Nil(t, err)
+		assert.Empty(t, members)
+	})
+
+	t.Run("[Kinds] commands on another kind fail with ErrWrongType", func(t *testing.T) {
+		cache, _ := newCache(t)
+
+		require.Nil(t, cache.Set(ctx, "string", yavalue, 0))
+
+		_, err := cache.RPush(ctx, "list", "x")
+		require.Nil(t, err)
+
+		_, err = cache.ZAdd(ctx, "zset", yacache.ZMember{Member: "x", Score: 1})
+		require.Nil(t, err)
+
+		_, err = cache.Incr(ctx, "list")
+		assert.True(t, errors.Is(err, yacache.ErrWrongType))
+
+		_, err = cache.LPush(ctx, "string", "x")
+		assert.True(t, errors.Is(err, yacache.ErrWrongType))
+
+		_, err = cache.LPop(ctx, "zset")
+		assert.True(t, errors.Is(err, yacache.ErrWrongType))
+
+		_, err = cache.LRange(ctx, "zset", 0, -1)
+		assert.True(t, errors.Is(err, yacache.ErrWrongType))
+
+		_, err = cache.ZAdd(ctx, "list", yacache.ZMember{Member: "x", Score: 1})
+		assert.True(t, errors.Is(err, yacache.ErrWrongType))
+
+		_, err = cache.ZRangeByScore(ctx, "string", 0, 1)
+		assert.True(t, errors.Is(err, yacache.ErrWrongType))
+	})
+
+	t.Run("[Kinds] Set replaces and Del removes any kind", func(t *testing.T) {
+		cache, _ := newCache(t)
+
+		_, err := cache.RPush(ctx, yamainKey, "x")
+		require.Nil(t, err)
+
+		require.Nil(t, cache.Set(ctx, yamainKey, yavalue, 0))
+
+		value, err := cache.Get(ctx, yamainKey)
+		require.Nil(t, err)
+		assert.Equal(t, yavalue, value)
+
+		_, err = cache.ZAdd(ctx, yamainKey2, yacache.ZMember{Member: "x", Score: 1})
+		require.Nil(t, err)
+
+		exists, _ := cache.Exists(ctx, yamainKey, yamainKey2)
+		assert.True(t, exists)
+
+		require.Nil(t, cache.Del(ctx, yamainKey2))
+
+		exists, _ = cache.Exists(ctx, yamainKey2)
+		assert.False(t, exists)
+	})
+
+	t.Run("[Scan/DelPrefix] match keys of every kind by prefix", func(t *testing.T) {
+		cache, _ := newCache(t)
+
+		require.Nil(t, cache.Set(ctx, "scan:a", yavalue, 0))
+		require.Nil(t, cache.Set(ctx, "scan*", yavalue, 0))
+		require.Nil(t, cache.Set(ctx, "other", yavalue, 0))
+
+		_, err := cache.RPush(ctx, "scan:b", "x")
+		require.Nil(t, err)
+
+		_, err = cache.ZAdd(ctx, "scan:c", yacache.ZMember{Member: "x", Score: 1})
+		require.Nil(t, err)
+
+		scan := func(prefix string) []string {
+			keys := map[string]struct{}{}
+
+			for key, err := range cache.Scan(ctx, prefix) {
+				require.Nil(t, err)
+
+				keys[key] = struct{}{}
+			}
+
+			return slices.Sorted(maps.Keys(keys))
+		}
+
+		assert.Equal(t, []string{"scan:a", "scan:b", "scan:c"}, scan("scan:"))
+		assert.Equal(t, []string{"scan*"}, scan("scan*"), "glob characters are literal")
+		assert.Len(t, scan(""), 5)
+
+		for range cache.Scan(ctx, "") {
+			break
+		}
+
+		_, err = cache.DelPrefix(ctx, "")
+		assert.True(t, errors.Is(err, yacache.ErrEmptyPrefix))
+
+		deleted, err := cache.DelPrefix(ctx, "scan:")
+		require.Nil(t, err)
+		assert.Equal(t, int64(3), deleted)
+		assert.Equal(t, []string{"other", "scan*"}, scan(""))
+	})
+
+	t.Run("[TTL/Expire/Persist] inspect and change expiry", func(t *testing.T) {
+		cache, _ := newCache(t)
+
+		require.Nil(t, cache.Set(ctx, yamainKey, yavalue, 0))
+
+		ttl, err := cache.TTL(ctx, yamainKey)
+		require.Nil(t, err)
+		assert.Zero(t, ttl, "no TTL")
+
+		_, err = cache.TTL(ctx, "missing")
+		assert.True(t, errors.Is(err, yacache.ErrNotFoundValue))
+
+		ok, err := cache.Expire(ctx, yamainKey, yattl)
+		require.Nil(t, err)
+		assert.True(t, ok)
+
+		ttl, err = cache.TTL(ctx, yamainKey)
+		require.Nil(t, err)
+		assert.InDelta(t, yattl, ttl, float64(time.Second))
+
+		ok, _ = cache.Persist(ctx, yamainKey)
+		assert.True(t, ok)
+
+		ok, _ = cache.Persist(ctx, yamainKey)
+		assert.False(t, ok, "nothing left to persist")
+
+		ttl, _ = cache.TTL(ctx, yamainKey)
+		assert.Zero(t, ttl)
+
+		ok, _ = cache.Expire(ctx, "missing", yattl)
+		assert.False(t, ok)
+
+		_, err = cache.RPush(ctx, yamainKey2, "x")
+		require.Nil(t, err)
+
+		ok, _ = cache.Expire(ctx, yamainKey2, yattl)
+		assert.True(t, ok)
+
+		ttl, _ = cache.TTL(ctx, yamainKey2)
+		assert.Positive(t, ttl)
+
+		ok, _ = cache.Expire(ctx, yamainKey2, 0)
+		assert.True(t, ok)
+
+		exists, _ := cache.Exists(ctx, yamainKey2)
+		assert.False(t, exists, "a non-positive ttl deletes the key")
+	})
+	t.Run("[Expiry] an expired key is gone for every command", func(t *testing.T) {
+		cache, elapse := newCache(t)
+
+		const shortTTL = 10 * time.Millisecond
+
+		require.Nil(t, cache.Set(ctx, yamainKey, yavalue, shortTTL))
+		require.Nil(t, cache.Set(ctx, yamainKey2, "5", shortTTL))
+
+		elapse(3 * shortTTL)
+
+		_, err := cache.TTL(ctx, yamainKey)
+		assert.True(t, errors.Is(err, yacache.ErrNotFoundValue), "TTL: %v", err)
+
+		_, err = cache.Get(ctx, yamainKey)
+		assert.True(t, errors.Is(err, yacache.ErrNotFoundValue), "Get: %v", err)
+
+		swapped, err := cache.CompareAndSwap(ctx, yamainKey, "", yavalue2, 0)
+		require.Nil(t, err)
+		assert.True(t, swapped, "an expired key compares as missing")
+
+		value, err := cache.Get(ctx, yamainKey)
+		require.Nil(t, err)
+		assert.Equal(t, yavalue2, value)
+
+		counter, err := cache.IncrBy(ctx, yamainKey2, 1)
+		require.Nil(t, err)
+		assert.Equal(t, int64(1), counter, "an expired counter counts from zero")
+	})
+}
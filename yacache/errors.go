@@ -3,19 +3,38 @@ package yacache
 import "errors"
 
 var (
-	ErrFailedToSet             = errors.New("[CACHE] failed to set new value with ttl")
-	ErrFailedToHSetEx          = errors.New("[CACHE] failed to hash set new value with ttl")
-	ErrFailedToGetValue        = errors.New("[CACHE] failed to get a value")
-	ErrFailedToMGetValues      = errors.New("[CACHE] failed to get multi values")
-	ErrFailedToDelValue        = errors.New("[CACHE] failed to delete a value")
-	ErrFailedToGetValues       = errors.New("[CACHE] failed to get values")
-	ErrFailedToGetDelValue     = errors.New("[CACHE] failed to get and delete value")
-	ErrFailedToGetDeleteSingle = errors.New("[CACHE] failed to get and delete single value")
-	ErrNotFoundValue           = errors.New("[CACHE] not found a value")
-	ErrFailedToGetLen          = errors.New("[CACHE] failed to get len")
-	ErrFailedToExists          = errors.New("[CACHE] failed to get exists a value")
-	ErrFailedToHExist          = errors.New("[CACHE] failed to get hash exists a value")
-	ErrFailedToDeleteSingle    = errors.New("[CACHE] failed to delete value")
-	ErrFailedPing              = errors.New("[CACHE] failed to get `PONG` from ping")
-	ErrFailedToCloseBackend    = errors.New("[CACHE] failed to close backend")
+	ErrFailedToSet                 = errors.New("[CACHE] failed to set new value with ttl")
+	ErrFailedToHSetEx              = errors.New("[CACHE] failed to hash set new value with ttl")
+	ErrFailedToGetValue            = errors.New("[CACHE] failed to get a value")
+	ErrFailedToMGetValues          = errors.New("[CACHE] failed to get multi values")
+	ErrFailedToDelValue            = errors.New("[CACHE] failed to delete a value")
+	ErrFailedToGetValues           = errors.New("[CACHE] failed to get values")
+	ErrFailedToGetDelValue         = errors.New("[CACHE] failed to get and delete value")
+	ErrFailedToGetDeleteSingle     = errors.New("[CACHE] failed to get and delete single value")
+	ErrNotFoundValue               = errors.New("[CACHE] not found a value")
+	ErrFailedToGetLen              = errors.New("[CACHE] failed to get len")
+	ErrFailedToExists              = errors.New("[CACHE] failed to get exists a value")
+	ErrFailedToHExist              = errors.New("[CACHE] failed to get hash exists a value")
+	ErrFailedToDeleteSingle        = errors.New("[CACHE] failed to delete value")
+	ErrFailedToCompareAndSwap      = errors.New("[CACHE] failed to compare and swap value")
+	ErrFailedToEncodeValue         = errors.New("[CACHE] failed to encode value")
+	ErrFailedToDecodeValue         = errors.New("[CACHE] failed to decode value")
+	ErrFailedToIncr                = errors.New("[CACHE] failed to increment value")
+	ErrValueNotInteger             = errors.New("[CACHE] value is not an integer or out of range")
+	ErrWrongType                   = errors.New("[CACHE] key holds another kind of value")
+	ErrFailedToPush                = errors.New("[CACHE] failed to push to list")
+	ErrFailedToPop                 = errors.New("[CACHE] failed to pop from list")
+	ErrFailedToRange               = errors.New("[CACHE] failed to get list range")
+	ErrFailedToZAdd                = errors.New("[CACHE] failed to add sorted set members")
+	ErrFailedToZRange              = errors.New("[CACHE] failed to get sorted set range")
+	ErrFailedToZRem                = errors.New("[CACHE] failed to remove sorted set members")
+	ErrFailedToScan                = errors.New("[CACHE] failed to scan keys")
+	ErrFailedToDelPrefix           = errors.New("[CACHE] failed to delete keys by prefix")
+	ErrEmptyPrefix                 = errors.New("[CACHE] empty prefix would match every key")
+	ErrFailedToGetTTL              = errors.New("[CACHE] failed to get ttl")
+	ErrFailedToExpire              = errors.New("[CACHE] failed to set ttl")
+	ErrFailedToPersist             = errors.New("[CACHE] failed to remove ttl")
+	ErrFailedPing                  = errors.New("[CACHE] failed to get `PONG` from ping")
+	ErrFailedToCloseBackend        = errors.New("[CACHE] failed to close backend")
+	ErrFailedToPublishInvalidation = errors.New("[CACHE] failed to publish invalidation")
 )
@@ -29,6 +29,25 @@ var MemoryModule = fx.Module(
 	fx.Provide(fx.Annotate(newMemoryCache, fx.As(new(Cache[MemoryContainer])))),
 )
 
+// BoundedMemoryModuleName is the fx module name for the bounded in-memory
+// yacache backend.
+const BoundedMemoryModuleName = "yacache-bounded-memory"
+
+// BoundedMemoryModule provides a Cache[MemoryContainer] backed by
+// BoundedMemory, configured by a BoundedMemoryConfig supplied to the graph.
+// Use it instead of MemoryModule, not alongside it.
+//
+// Example usage:
+//
+//	fx.New(
+//		fx.Supply(yacache.BoundedMemoryConfig{MaxEntries: 10_000, Policy: yacache.EvictLRU}),
+//		yacache.BoundedMemoryModule,
+//	)
+var BoundedMemoryModule = fx.Module(
+	BoundedMemoryModuleName,
+	fx.Provide(fx.Annotate(NewBoundedMemory, fx.As(new(Cache[MemoryContainer])))),
+)
+
 // RedisParams configures the Redis client provided by RedisModule.
 type RedisParams struct {
 	Host     string
@@ -61,3 +80,24 @@ var RedisModule = fx.Module(
 	fx.Provide(newRedisClientFromParams),
 	fx.Provide(fx.Annotate(NewRedis, fx.As(new(Cache[*redis.Client])))),
 )
+
+// NearCacheModuleName is the fx module name for the two-tier yacache backend.
+const NearCacheModuleName = "yacache-near"
+
+// NearCacheModule provides a Cache[*redis.Client] backed by NearCache, dialing
+// Redis like RedisModule and configured by a NearCacheConfig supplied to the
+// graph. Use it instead of RedisModule, not alongside it.
+//
+// Example usage:
+//
+//	fx.New(
+//		fx.Supply(yacache.RedisParams{Host: "localhost", Port: 6379}),
+//		fx.Supply(yacache.NearCacheConfig{LocalTTL: 30 * time.Second}),
+//		yalogger.LoggerModule,
+//		yacache.NearCacheModule,
+//	)
+var NearCacheModule = fx.Module(
+	NearCacheModuleName,
+	fx.Provide(newRedisClientFromParams),
+	fx.Provide(fx.Annotate(NewNearCache, fx.As(new(Cache[*redis.Client])))),
+)
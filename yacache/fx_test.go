@@ -24,10 +24,18 @@ func TestCacheModules(t *testing.T) {
 			name: "when MemoryModule is wired / then it resolves a usable Cache[MemoryContainer]",
 			run:  testMemoryModuleResolvesUsableCache,
 		},
+		{
+			name: "when BoundedMemoryModule is wired with a config / then it resolves a usable Cache[MemoryContainer]",
+			run:  testBoundedMemoryModuleResolvesUsableCache,
+		},
 		{
 			name: "when RedisModule is wired against a miniredis instance / then it resolves a usable Cache[*redis.Client]",
 			run:  testRedisModuleResolvesUsableCache,
 		},
+		{
+			name: "when NearCacheModule is wired against a miniredis instance / then it resolves a usable Cache[*redis.Client]",
+			run:  testNearCacheModuleResolvesUsableCache,
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -71,6 +79,43 @@ func testMemoryModuleResolvesUsableCache(t *testing.T) {
 	}
 }
 
+func testBoundedMemoryModuleResolvesUsableCache(t *testing.T) {
+	t.Parallel()
+
+	const (
+		key   = "fx-bounded-memory-key"
+		value = "fx-bounded-memory-value"
+	)
+
+	var cache yacache.Cache[yacache.MemoryContainer]
+
+	fxtest.New(
+		t,
+		fx.Supply(yacache.BoundedMemoryConfig{MaxEntries: 1}),
+		yacache.BoundedMemoryModule,
+		fx.Populate(&cache),
+	)
+
+	if _, ok := cache.(*yacache.BoundedMemory); !ok {
+		t.Fatalf("expected BoundedMemoryModule to populate a *BoundedMemory, got %T", cache)
+	}
+
+	ctx := context.Background()
+
+	if err := cache.Set(ctx, key, value, 0); err != nil {
+		t.Fatalf("expected Set to succeed, got error: %v", err)
+	}
+
+	got, err := cache.Get(ctx, key)
+	if err != nil {
+		t.Fatalf("expected Get to succeed, got error: %v", err)
+	}
+
+	if got != value {
+		t.Errorf("expected stored value %q, got %q", value, got)
+	}
+}
+
 func testRedisModuleResolvesUsableCache(t *testing.T) {
 	t.Parallel()
 
@@ -124,3 +169,61 @@ func testRedisModuleResolvesUsableCache(t *testing.T) {
 		t.Errorf("expected stored value %q, got %q", value, got)
 	}
 }
+
+func testNearCacheModuleResolvesUsableCache(t *testing.T) {
+	t.Parallel()
+
+	const (
+		key   = "fx-near-key"
+		value = "fx-near-value"
+	)
+
+	server, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("expected miniredis to start, got error: %v", err)
+	}
+
+	defer server.Close()
+
+	port, err := strconv.ParseUint(server.Port(), 10, 16)
+	if err != nil {
+		t.Fatalf("expected miniredis port to parse as uint16, got error: %v", err)
+	}
+
+	var cache yacache.Cache[*redis.Client]
+
+	fxtest.New(
+		t,
+		yacache.NearCacheModule,
+		yalogger.LoggerModule,
+		fx.Supply((*yalogger.Config)(nil)),
+		fx.Supply(yacache.RedisParams{
+			Host: server.Host(),
+			Port: uint16(port),
+		}),
+		fx.Supply(yacache.NearCacheConfig{}),
+		fx.Populate(&cache),
+	)
+
+	near, ok := cache.(*yacache.NearCache)
+	if !ok {
+		t.Fatalf("expected NearCacheModule to populate a *NearCache, got %T", cache)
+	}
+
+	defer near.Close()
+
+	ctx := context.Background()
+
+	if err := cache.Set(ctx, key, value, 0); err != nil {
+		t.Fatalf("expected Set to succeed, got error: %v", err)
+	}
+
+	got, err := cache.Get(ctx, key)
+	if err != nil {
+		t.Fatalf("expected Get to succeed, got error: %v", err)
+	}
+
+	if got != value {
+		t.Errorf("expected stored value %q, got %q", value, got)
+	}
+}
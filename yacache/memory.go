@@ -8,9 +8,15 @@ package yacache
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"iter"
+	"maps"
+	"math"
 	"net/http"
+	"slices"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 	"weak"
@@ -20,6 +26,9 @@ import (
 
 const yaMapLen = `[_____YaMapLen_____YA_/\_CODE_/\_DEV]`
 
+// memoryBackend names the in‑memory backend in error tracebacks.
+const memoryBackend = "MEMORY"
+
 // Memory is a threadsafe, TTL‑aware map‑backed cache.
 //
 // Example (create + basic operations):
@@ -99,6 +108,18 @@ func cleanup(
 				}
 			}
 
+			for key, value := range memory.inner.Lists {
+				if value.isExpired() {
+					delete(memory.inner.Lists, key)
+				}
+			}
+
+			for key, value := range memory.inner.SortedSets {
+				if value.isExpired() {
+					delete(memory.inner.SortedSets, key)
+				}
+			}
+
 			memory.mutex.Unlock()
 		case <-done:
 			return
@@ -313,7 +334,8 @@ func (m *Memory) HDelSingle(
 }
 
 // Set stores a key→value pair in Memory.Map and (optionally) applies
-// a TTL.  A zero ttl means “store indefinitely”.
+// a TTL.  A zero ttl means “store indefinitely”.  Like Redis SET, it replaces
+// a list or sorted set stored under the same key.
 //
 // Example:
 //
@@ -331,6 +353,9 @@ func (m *Memory) Set(
 
 	defer m.mutex.Unlock()
 
+	delete(m.inner.Lists, key)
+	delete(m.inner.SortedSets, key)
+
 	if ttl <= 0 {
 		m.inner.Map[key] = newMemoryCacheItem(value)
 
@@ -342,8 +367,9 @@ func (m *Memory) Set(
 	return nil
 }
 
-// Get retrieves the value stored under key.  If the key is missing,
-// it returns a yaerrors.Error with http.StatusInternalServerError semantics.
+// Get retrieves the value stored under key.  If the key is missing or its
+// TTL has elapsed, it returns a yaerrors.Error with
+// http.StatusInternalServerError semantics.
 //
 // Example:
 //
@@ -359,10 +385,10 @@ func (m *Memory) Get(
 	defer m.mutex.RUnlock()
 
 	value, ok := m.inner.Map[key]
-	if !ok {
+	if !ok || value.isExpired() {
 		return "", yaerrors.FromError(
 			http.StatusInternalServerError,
-			ErrFailedToGetValue,
+			errors.Join(ErrNotFoundValue, ErrFailedToGetValue),
 			"[MEMORY] failed to get value in key: "+key,
 		)
 	}
@@ -373,7 +399,7 @@ func (m *Memory) Get(
 // MGet fetches the values for the specified keys from the in-memory cache.
 //
 // It returns a map where each found key is mapped to its corresponding string value.
-// Keys that are not found are silently skipped — no error is returned.
+// Keys that are not found or expired are silently skipped — no error is returned.
 // As a result, the returned map may contain fewer entries than requested.
 //
 // Example:
@@ -404,7 +430,7 @@ func (m *Memory) MGet(
 
 	for _, key := range keys {
 		value, ok := m.inner.Map[key]
-		if !ok {
+		if !ok || value.isExpired() {
 			continue
 		}
 
@@ -415,7 +441,8 @@ func (m *Memory) MGet(
 }
 
 // GetDel atomically reads and deletes the key.  Used for one-shot
-// tokens or queues where an item should disappear right after read.
+// tokens or queues where an item should disappear right after read. An
+// expired key is deleted and reported missing.
 //
 // Example:
 //
@@ -431,7 +458,9 @@ func (m *Memory) GetDel(
 	defer m.mutex.Unlock()
 
 	value, ok := m.inner.Map[key]
-	if !ok {
+	if !ok || value.isExpired() {
+		delete(m.inner.Map, key)
+
 		return "", yaerrors.FromError(
 			http.StatusInternalServerError,
 			ErrFailedToGetDelValue,
@@ -444,7 +473,8 @@ func (m *Memory) GetDel(
 	return value.Value, nil
 }
 
-// Exists reports whether all specified keys are currently present in Memory.Map.
+// Exists reports whether all specified keys are currently present as plain
+// values, lists or sorted sets.
 //
 // An entry is considered “present” until the background sweeper removes it,
 // even if its TTL has already expired. Therefore, expired entries may still
@@ -478,8 +508,11 @@ func (m *Memory) Exists(
 	defer m.mutex.RUnlock()
 
 	for _, key := range keys {
-		_, ok := m.inner.Map[key]
-		if !ok {
+		_, isString := m.inner.Map[key]
+		_, isList := m.inner.Lists[key]
+		_, isSortedSet := m.inner.SortedSets[key]
+
+		if !isString && !isList && !isSortedSet {
 			return false, nil
 		}
 	}
@@ -487,7 +520,51 @@ func (m *Memory) Exists(
 	return true, nil
 }
 
-// Del unconditionally removes key from Memory.Map.  The operation is
+// CompareAndSwap replaces the value under key only if it currently equals
+// expected; a missing key compares equal to "". The comparison and the write
+// happen under the same write lock, so concurrent callers never both win.
+// An expired entry that has not been swept yet compares as missing, as it
+// reads through Get.
+//
+// Example:
+//
+//	ok, _ := memory.CompareAndSwap(ctx, "counter", "1", "2", 0)
+func (m *Memory) CompareAndSwap(
+	_ context.Context,
+	key string,
+	expected string,
+	value string,
+	ttl time.Duration,
+) (bool, yaerrors.Error) {
+	m.safetyCheck()
+
+	m.mutex.Lock()
+
+	defer m.mutex.Unlock()
+
+	var current string
+
+	if item, ok := m.inner.Map[key]; ok && !item.isExpired() {
+		current = item.Value
+	}
+
+	if current != expected {
+		return false, nil
+	}
+
+	if ttl <= 0 {
+		m.inner.Map[key] = newMemoryCacheItem(value)
+
+		return true, nil
+	}
+
+	m.inner.Map[key] = newMemoryCacheItemEX(value, time.Now().Add(ttl))
+
+	return true, nil
+}
+
+// Del unconditionally removes the plain value, list or sorted set stored
+// under key.  The operation is
 // idempotent: deleting a non-existent key is not an error.
 //
 // Example:
@@ -504,10 +581,491 @@ func (m *Memory) Del(
 	defer m.mutex.Unlock()
 
 	delete(m.inner.Map, key)
+	delete(m.inner.Lists, key)
+	delete(m.inner.SortedSets, key)
 
 	return nil
 }
 
+// IncrBy adds delta to the integer stored under key. An expired value that
+// has not been swept yet counts as missing, like in Redis.
+//
+// Example:
+//
+//	views, _ := memory.IncrBy(ctx, "views:42", 10)
+func (m *Memory) IncrBy(
+	_ context.Context,
+	key string,
+	delta int64,
+) (int64, yaerrors.Error) {
+	m.safetyCheck()
+
+	m.mutex.Lock()
+
+	defer m.mutex.Unlock()
+
+	if m.inner.holdsOtherKind(key, memoryKindString) {
+		return 0, wrongTypeError(memoryBackend, "INCRBY", key, ErrFailedToIncr)
+	}
+
+	item, ok := m.inner.Map[key]
+	if !ok || item.isExpired() {
+		m.inner.Map[key] = newMemoryCacheItem(strconv.FormatInt(delta, 10))
+
+		return delta, nil
+	}
+
+	value, err := incrementString(memoryBackend, key, item.Value, delta)
+	if err != nil {
+		return 0, err
+	}
+
+	item.Value = strconv.FormatInt(value, 10)
+
+	return value, nil
+}
+
+// Incr adds one to the integer stored under key.
+//
+// Example:
+//
+//	views, _ := memory.Incr(ctx, "views:42")
+func (m *Memory) Incr(
+	ctx context.Context,
+	key string,
+) (int64, yaerrors.Error) {
+	return m.IncrBy(ctx, key, 1)
+}
+
+// LPush inserts values at the head of the list under key.
+//
+// Example:
+//
+//	length, _ := memory.LPush(ctx, "jobs", "a", "b") // jobs = [b a]
+func (m *Memory) LPush(
+	_ context.Context,
+	key string,
+	values ...string,
+) (int64, yaerrors.Error) {
+	return m.push(key, true, values)
+}
+
+// RPush appends values to the tail of the list under key.
+//
+// Example:
+//
+//	length, _ := memory.RPush(ctx, "jobs", "a", "b") // jobs = [a b]
+func (m *Memory) RPush(
+	_ context.Context,
+	key string,
+	values ...string,
+) (int64, yaerrors.Error) {
+	return m.push(key, false, values)
+}
+
+// push implements LPush (head) and RPush.
+func (m *Memory) push(key string, head bool, values []string) (int64, yaerrors.Error) {
+	m.safetyCheck()
+
+	m.mutex.Lock()
+
+	defer m.mutex.Unlock()
+
+	if m.inner.holdsOtherKind(key, memoryKindList) {
+		return 0, wrongTypeError(memoryBackend, "PUSH", key, ErrFailedToPush)
+	}
+
+	list := m.inner.liveList(key)
+	if list == nil {
+		if len(values) == 0 {
+			return 0, nil
+		}
+
+		list = &memoryListItem{Endless: true}
+		m.inner.Lists[key] = list
+	}
+
+	list.Values = pushValues(list.Values, head, values)
+
+	return int64(len(list.Values)), nil
+}
+
+// LPop removes and returns the first element of the list under key.
+//
+// Example:
+//
+//	job, _ := memory.LPop(ctx, "jobs")
+func (m *Memory) LPop(
+	_ context.Context,
+	key string,
+) (string, yaerrors.Error) {
+	return m.pop(key, true)
+}
+
+// RPop removes and returns the last element of the list under key.
+//
+// Example:
+//
+//	job, _ := memory.RPop(ctx, "jobs")
+func (m *Memory) RPop(
+	_ context.Context,
+	key string,
+) (string, yaerrors.Error) {
+	return m.pop(key, false)
+}
+
+// pop implements LPop (head) and RPop.
+func (m *Memory) pop(key string, head bool) (string, yaerrors.Error) {
+	m.safetyCheck()
+
+	m.mutex.Lock()
+
+	defer m.mutex.Unlock()
+
+	if m.inner.holdsOtherKind(key, memoryKindList) {
+		return "", wrongTypeError(memoryBackend, "POP", key, ErrFailedToPop)
+	}
+
+	list := m.inner.liveList(key)
+	if list == nil {
+		return "", notFoundError(memoryBackend, "POP", key, ErrFailedToPop)
+	}
+
+	var value string
+
+	if head {
+		value, list.Values = list.Values[0], list.Values[1:]
+	} else {
+		last := len(list.Values) - 1
+		value, list.Values = list.Values[last], list.Values[:last]
+	}
+
+	if len(list.Values) == 0 {
+		delete(m.inner.Lists, key)
+	}
+
+	return value, nil
+}
+
+// LRange returns a copy of the elements between start and stop, inclusive.
+//
+// Example:
+//
+//	all, _ := memory.LRange(ctx, "jobs", 0, -1)
+func (m *Memory) LRange(
+	_ context.Context,
+	key string,
+	start int64,
+	stop int64,
+) ([]string, yaerrors.Error) {
+	m.safetyCheck()
+
+	m.mutex.RLock()
+
+	defer m.mutex.RUnlock()
+
+	if m.inner.holdsOtherKind(key, memoryKindList) {
+		return nil, wrongTypeError(memoryBackend, "LRANGE", key, ErrFailedToRange)
+	}
+
+	list, ok := m.inner.Lists[key]
+	if !ok || list.isExpired() {
+		return []string{}, nil
+	}
+
+	from, to, ok := listBounds(len(list.Values), start, stop)
+	if !ok {
+		return []string{}, nil
+	}
+
+	return slices.Clone(list.Values[from:to]), nil
+}
+
+// ZAdd adds or updates sorted-set members. A NaN score is rejected.
+//
+// Example:
+//
+//	_, _ = memory.ZAdd(ctx, "leaderboard", yacache.ZMember{Member: "alice", Score: 42})
+func (m *Memory) ZAdd(
+	_ context.Context,
+	key string,
+	members ...ZMember,
+) (int64, yaerrors.Error) {
+	if len(members) == 0 {
+		return 0, nil
+	}
+
+	for _, member := range members {
+		if math.IsNaN(member.Score) {
+			return 0, yaerrors.FromError(
+				http.StatusInternalServerError,
+				ErrFailedToZAdd,
+				fmt.Sprintf("[MEMORY] NaN score for `%s` by `%s`", member.Member, key),
+			)
+		}
+	}
+
+	m.safetyCheck()
+
+	m.mutex.Lock()
+
+	defer m.mutex.Unlock()
+
+	if m.inner.holdsOtherKind(key, memoryKindSortedSet) {
+		return 0, wrongTypeError(memoryBackend, "ZADD", key, ErrFailedToZAdd)
+	}
+
+	set, ok := m.inner.SortedSets[key]
+	if !ok || set.isExpired() {
+		set = &memorySortedSetItem{Scores: make(map[string]float64), Endless: true}
+		m.inner.SortedSets[key] = set
+	}
+
+	var added int64
+
+	for _, member := range members {
+		if _, exists := set.Scores[member.Member]; !exists {
+			added++
+		}
+
+		set.Scores[member.Member] = member.Score
+	}
+
+	return added, nil
+}
+
+// ZRangeByScore returns the members scored within [minScore, maxScore].
+//
+// Example:
+//
+//	top, _ := memory.ZRangeByScore(ctx, "leaderboard", 100, math.Inf(1))
+func (m *Memory) ZRangeByScore(
+	_ context.Context,
+	key string,
+	minScore float64,
+	maxScore float64,
+) ([]ZMember, yaerrors.Error) {
+	m.safetyCheck()
+
+	m.mutex.RLock()
+
+	defer m.mutex.RUnlock()
+
+	if m.inner.holdsOtherKind(key, memoryKindSortedSet) {
+		return nil, wrongTypeError(memoryBackend, "ZRANGEBYSCORE", key, ErrFailedToZRange)
+	}
+
+	set, ok := m.inner.SortedSets[key]
+	if !ok || set.isExpired() {
+		return []ZMember{}, nil
+	}
+
+	return rangeByScore(set.Scores, minScore, maxScore), nil
+}
+
+// ZRem removes sorted-set members.
+//
+// Example:
+//
+//	_, _ = memory.ZRem(ctx, "leaderboard", "alice")
+func (m *Memory) ZRem(
+	_ context.Context,
+	key string,
+	members ...string,
+) (int64, yaerrors.Error) {
+	if len(members) == 0 {
+		return 0, nil
+	}
+
+	m.safetyCheck()
+
+	m.mutex.Lock()
+
+	defer m.mutex.Unlock()
+
+	if m.inner.holdsOtherKind(key, memoryKindSortedSet) {
+		return 0, wrongTypeError(memoryBackend, "ZREM", key, ErrFailedToZRem)
+	}
+
+	set, ok := m.inner.SortedSets[key]
+	if !ok || set.isExpired() {
+		return 0, nil
+	}
+
+	var removed int64
+
+	for _, member := range members {
+		if _, exists := set.Scores[member]; exists {
+			delete(set.Scores, member)
+
+			removed++
+		}
+	}
+
+	if len(set.Scores) == 0 {
+		delete(m.inner.SortedSets, key)
+	}
+
+	return removed, nil
+}
+
+// Scan yields the live keys starting with prefix in lexical order. The keys
+// are collected under the read lock and yielded after releasing it, so the
+// loop body may write to the cache.
+//
+// Example:
+//
+//	for key := range memory.Scan(ctx, "user:") { … }
+func (m *Memory) Scan(
+	_ context.Context,
+	prefix string,
+) iter.Seq2[string, yaerrors.Error] {
+	return func(yield func(string, yaerrors.Error) bool) {
+		m.safetyCheck()
+
+		m.mutex.RLock()
+		keys := m.inner.liveKeys(prefix)
+		m.mutex.RUnlock()
+
+		for _, key := range keys {
+			if !yield(key, nil) {
+				return
+			}
+		}
+	}
+}
+
+// DelPrefix deletes every key starting with prefix, expired ones included,
+// and returns how many live keys it deleted.
+//
+// Example:
+//
+//	deleted, _ := memory.DelPrefix(ctx, "user:42:")
+func (m *Memory) DelPrefix(
+	_ context.Context,
+	prefix string,
+) (int64, yaerrors.Error) {
+	if prefix == "" {
+		return 0, emptyPrefixError(memoryBackend)
+	}
+
+	m.safetyCheck()
+
+	m.mutex.Lock()
+
+	defer m.mutex.Unlock()
+
+	deleted := int64(len(m.inner.liveKeys(prefix)))
+
+	deletePrefix(m.inner.Map, prefix)
+	deletePrefix(m.inner.HMap, prefix)
+	deletePrefix(m.inner.Lists, prefix)
+	deletePrefix(m.inner.SortedSets, prefix)
+
+	return deleted, nil
+}
+
+// TTL returns the time key has left to live, 0 for a key without TTL.
+//
+// Example:
+//
+//	ttl, _ := memory.TTL(ctx, "access-token")
+func (m *Memory) TTL(
+	_ context.Context,
+	key string,
+) (time.Duration, yaerrors.Error) {
+	m.safetyCheck()
+
+	m.mutex.RLock()
+
+	defer m.mutex.RUnlock()
+
+	deadlines := m.inner.deadlines(key)
+	if len(deadlines) == 0 {
+		return 0, notFoundError(memoryBackend, "PTTL", key, ErrFailedToGetTTL)
+	}
+
+	var longest time.Duration
+
+	now := time.Now()
+
+	for _, deadline := range deadlines {
+		if *deadline.endless {
+			return 0, nil
+		}
+
+		ttl, _ := remaining(*deadline.expiresAt, now)
+		longest = max(longest, ttl)
+	}
+
+	return longest, nil
+}
+
+// Expire sets the TTL of key, or deletes it for a non‑positive ttl.
+//
+// Example:
+//
+//	ok, _ := memory.Expire(ctx, "access-token", time.Hour)
+func (m *Memory) Expire(
+	_ context.Context,
+	key string,
+	ttl time.Duration,
+) (bool, yaerrors.Error) {
+	m.safetyCheck()
+
+	m.mutex.Lock()
+
+	defer m.mutex.Unlock()
+
+	deadlines := m.inner.deadlines(key)
+	if len(deadlines) == 0 {
+		return false, nil
+	}
+
+	if ttl <= 0 {
+		delete(m.inner.Map, key)
+		delete(m.inner.HMap, key)
+		delete(m.inner.Lists, key)
+		delete(m.inner.SortedSets, key)
+
+		return true, nil
+	}
+
+	expiresAt := time.Now().Add(ttl)
+
+	for _, deadline := range deadlines {
+		*deadline.expiresAt, *deadline.endless = expiresAt, false
+	}
+
+	return true, nil
+}
+
+// Persist removes the TTL of key.
+//
+// Example:
+//
+//	ok, _ := memory.Persist(ctx, "access-token")
+func (m *Memory) Persist(
+	_ context.Context,
+	key string,
+) (bool, yaerrors.Error) {
+	m.safetyCheck()
+
+	m.mutex.Lock()
+
+	defer m.mutex.Unlock()
+
+	persisted := false
+
+	for _, deadline := range m.inner.deadlines(key) {
+		if !*deadline.endless {
+			*deadline.endless, persisted = true, true
+		}
+	}
+
+	return persisted, nil
+}
+
 // Ping always succeeds for the in‑memory backend.
 //
 // Example:
@@ -537,6 +1095,14 @@ func (m *Memory) Close() yaerrors.Error {
 		delete(m.inner.Map, k)
 	}
 
+	for k := range m.inner.Lists {
+		delete(m.inner.Lists, k)
+	}
+
+	for k := range m.inner.SortedSets {
+		delete(m.inner.SortedSets, k)
+	}
+
 	m.done <- struct{}{}
 
 	return nil
@@ -610,6 +1176,42 @@ func (m *memoryCacheItem) isExpired() bool {
 	return time.Now().After(m.ExpiresAt) && !m.Endless
 }
 
+// memoryListItem is a list stored in MemoryContainer.Lists. Its TTL covers the
+// whole list, like a Redis key.
+type memoryListItem struct {
+	Values    []string  // elements, head first
+	ExpiresAt time.Time // TTL deadline (ignored when Endless)
+	Endless   bool      // true → infinite lifetime
+}
+
+// isExpired reports whether the list's TTL has elapsed.
+func (m *memoryListItem) isExpired() bool {
+	return time.Now().After(m.ExpiresAt) && !m.Endless
+}
+
+// memorySortedSetItem is a sorted set stored in MemoryContainer.SortedSets.
+// Members are ordered on read, which keeps writes O(1).
+type memorySortedSetItem struct {
+	Scores    map[string]float64 // member → score
+	ExpiresAt time.Time          // TTL deadline (ignored when Endless)
+	Endless   bool               // true → infinite lifetime
+}
+
+// isExpired reports whether the sorted set's TTL has elapsed.
+func (m *memorySortedSetItem) isExpired() bool {
+	return time.Now().After(m.ExpiresAt) && !m.Endless
+}
+
+// memoryKind tells apart the kinds of value a MemoryContainer key can hold.
+type memoryKind uint8
+
+const (
+	memoryKindString memoryKind = iota
+	memoryKindHash
+	memoryKindList
+	memoryKindSortedSet
+)
+
 // MemoryContainer is the concrete map-backed store used by the
 // in-memory cache backend.  It maintains **two** separate collections
 // behind a single struct so the higher-level [Memory] wrapper can serve
@@ -626,6 +1228,9 @@ func (m *memoryCacheItem) isExpired() bool {
 //
 //  2. Map – a flat key/value store for commands such as Set / Get / Del.
 //
+//  3. Lists and SortedSets – Redis-style lists and sorted sets.  A key
+//     holds at most one of a plain value, a list or a sorted set.
+//
 // Both maps are protected by the outer [Memory] mutex; they are *not*
 // thread-safe on their own.
 //
@@ -646,6 +1251,10 @@ type MemoryContainer struct {
 	HMap map[string]childMemoryContainer
 	// Map stores “simple” key/value pairs.
 	Map map[string]*memoryCacheItem
+	// Lists stores lists pushed with LPush / RPush.
+	Lists map[string]*memoryListItem
+	// SortedSets stores sorted sets built with ZAdd.
+	SortedSets map[string]*memorySortedSetItem
 }
 
 // childMemoryContainer is the inner map type held inside HMap.
@@ -660,8 +1269,130 @@ type childMemoryContainer map[string]*memoryCacheItem
 //	fmt.Println(len(container)) // 0
 func NewMemoryContainer() MemoryContainer {
 	return MemoryContainer{
-		HMap: make(map[string]childMemoryContainer),
-		Map:  make(map[string]*memoryCacheItem),
+		HMap:       make(map[string]childMemoryContainer),
+		Map:        make(map[string]*memoryCacheItem),
+		Lists:      make(map[string]*memoryListItem),
+		SortedSets: make(map[string]*memorySortedSetItem),
+	}
+}
+
+// holdsOtherKind reports whether key currently holds a value of another kind
+// than the one a command operates on.
+//
+// Example:
+//
+//	if container.holdsOtherKind("jobs", memoryKindList) { … } // WRONGTYPE
+func (m MemoryContainer) holdsOtherKind(key string, kind memoryKind) bool {
+	_, isString := m.Map[key]
+	_, isHash := m.HMap[key]
+	_, isList := m.Lists[key]
+	_, isSortedSet := m.SortedSets[key]
+
+	return (isString && kind != memoryKindString) ||
+		(isHash && kind != memoryKindHash) ||
+		(isList && kind != memoryKindList) ||
+		(isSortedSet && kind != memoryKindSortedSet)
+}
+
+// liveList returns the list under key, dropping it first if it expired.
+//
+// Example:
+//
+//	list := container.liveList("jobs")
+//	if list == nil { … } // missing
+func (m MemoryContainer) liveList(key string) *memoryListItem {
+	list, ok := m.Lists[key]
+	if !ok {
+		return nil
+	}
+
+	if list.isExpired() {
+		delete(m.Lists, key)
+
+		return nil
+	}
+
+	return list
+}
+
+// memoryDeadline points at the TTL metadata of one live item, so TTL,
+// Expire and Persist treat every kind alike.
+type memoryDeadline struct {
+	expiresAt *time.Time
+	endless   *bool
+}
+
+// deadlines returns the TTL metadata of the live value under key: one for a
+// plain value, list or sorted set, one per live field for a hash, none for a
+// missing key.
+//
+// Example:
+//
+//	if len(container.deadlines("jobs")) == 0 { … } // missing
+func (m MemoryContainer) deadlines(key string) []memoryDeadline {
+	if item, ok := m.Map[key]; ok && !item.isExpired() {
+		return []memoryDeadline{{&item.ExpiresAt, &item.Endless}}
+	}
+
+	if list, ok := m.Lists[key]; ok && !list.isExpired() {
+		return []memoryDeadline{{&list.ExpiresAt, &list.Endless}}
+	}
+
+	if set, ok := m.SortedSets[key]; ok && !set.isExpired() {
+		return []memoryDeadline{{&set.ExpiresAt, &set.Endless}}
+	}
+
+	var deadlines []memoryDeadline
+
+	for field, item := range m.HMap[key] {
+		if field != yaMapLen && !item.isExpired() {
+			deadlines = append(deadlines, memoryDeadline{&item.ExpiresAt, &item.Endless})
+		}
+	}
+
+	return deadlines
+}
+
+// liveKeys returns the sorted, distinct keys starting with prefix that hold
+// a live value of any kind.
+//
+// Example:
+//
+//	keys := container.liveKeys("user:")
+func (m MemoryContainer) liveKeys(prefix string) []string {
+	seen := make(map[string]struct{})
+
+	collect := func(key string) {
+		if strings.HasPrefix(key, prefix) && len(m.deadlines(key)) > 0 {
+			seen[key] = struct{}{}
+		}
+	}
+
+	for key := range m.Map {
+		collect(key)
+	}
+
+	for key := range m.HMap {
+		collect(key)
+	}
+
+	for key := range m.Lists {
+		collect(key)
+	}
+
+	for key := range m.SortedSets {
+		collect(key)
+	}
+
+	return slices.Sorted(maps.Keys(seen))
+}
+
+// deletePrefix removes every key starting with prefix from index.
+func deletePrefix[V any](index map[string]V, prefix string) {
+	for key := range index {
+		if strings.HasPrefix(key, prefix) {
+			delete(index, key)
+		}
 	}
 }
 
@@ -367,3 +367,81 @@ func TestMemory_DeleteWorkflow_Works(t *testing.T) {
 
 	assert.Equal(t, expected, exist)
 }
+
+func TestMemory_CompareAndSwap_Works(t *testing.T) {
+	ctx := context.Background()
+
+	memory := yacache.NewMemory(yacache.NewMemoryContainer(), time.Hour)
+
+	t.Run("[CompareAndSwap] missing key matches empty expected", func(t *testing.T) {
+		swapped, err := memory.CompareAndSwap(ctx, yamainKey, "", yavalue, yattl)
+
+		assert.Nil(t, err)
+		assert.True(t, swapped)
+	})
+
+	t.Run("[CompareAndSwap] mismatch keeps value", func(t *testing.T) {
+		swapped, _ := memory.CompareAndSwap(ctx, yamainKey, yavalue2, "other", yattl)
+
+		assert.False(t, swapped)
+
+		value, _ := memory.Get(ctx, yamainKey)
+
+		assert.Equal(t, yavalue, value)
+	})
+
+	t.Run("[CompareAndSwap] match replaces value", func(t *testing.T) {
+		swapped, _ := memory.CompareAndSwap(ctx, yamainKey, yavalue, yavalue2, yattl)
+
+		assert.True(t, swapped)
+
+		value, _ := memory.Get(ctx, yamainKey)
+
+		assert.Equal(t, yavalue2, value)
+	})
+}
+
+func TestMemory_HashTTL_Works(t *testing.T) {
+	ctx := context.Background()
+
+	for name, memory := range map[string]yacache.Cache[yacache.MemoryContainer]{
+		"Memory":        yacache.NewMemory(yacache.NewMemoryContainer(), time.Hour),
+		"BoundedMemory": yacache.NewBoundedMemory(yacache.BoundedMemoryConfig{}),
+	} {
+		t.Run(name, func(t *testing.T) {
+			defer memory.Close()
+
+			_ = memory.HSetEX(ctx, yamainKey, yachildKey, yavalue, yattl)
+			_ = memory.HSetEX(ctx, yamainKey, yachildKey2, yavalue2, 2*yattl)
+
+			ttl, err := memory.TTL(ctx, yamainKey)
+
+			assert.Nil(t, err)
+			assert.InDelta(t, 2*yattl, ttl, float64(time.Second), "longest-lived field")
+
+			ok, _ := memory.Expire(ctx, yamainKey, yattl/2)
+
+			assert.True(t, ok)
+
+			ttl, _ = memory.TTL(ctx, yamainKey)
+
+			assert.InDelta(t, yattl/2, ttl, float64(time.Second), "every field expires together")
+
+			ok, _ = memory.Persist(ctx, yamainKey)
+
+			assert.True(t, ok)
+
+			ttl, _ = memory.TTL(ctx, yamainKey)
+
+			assert.Zero(t, ttl)
+
+			keys := []string{}
+
+			for key := range memory.Scan(ctx, "") {
+				keys = append(keys, key)
+			}
+
+			assert.Equal(t, []string{yamainKey}, keys)
+		})
+	}
+}
@@ -0,0 +1,742 @@
+// ===================== Two‑tier (near‑cache) implementation ===================== //
+
+// NearCache keeps a bounded local copy of hot Redis keys and relies on Redis
+// pub/sub to tell every replica when a copy goes stale.
+
+package yacache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yaerrors"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// DefaultNearCacheChannel is the pub/sub channel used when
+	// NearCacheConfig.Channel is empty.
+	DefaultNearCacheChannel = "yacache:invalidate"
+	// DefaultNearCacheLocalTTL is the local TTL cap used when
+	// NearCacheConfig.LocalTTL is zero.
+	DefaultNearCacheLocalTTL = time.Minute
+	// DefaultNearCacheMaxEntries is the local entry budget used when
+	// NearCacheConfig.Local sets neither MaxEntries nor MaxBytes.
+	DefaultNearCacheMaxEntries = 10_000
+)
+
+// nearCacheResubscribeDelay is how long the listener waits before reading
+// again after the subscription connection failed.
+const nearCacheResubscribeDelay = 100 * time.Millisecond
+
+// NearCacheConfig configures [NewNearCache]. Zero fields fall back to the
+// package defaults.
+type NearCacheConfig struct {
+	// Channel is the pub/sub channel replicas exchange invalidations on. Every
+	// replica sharing a Redis database must use the same channel.
+	Channel string
+	// LocalTTL caps how long a value is served locally. It bounds staleness
+	// should an invalidation ever be lost; a shorter Redis TTL always wins.
+	LocalTTL time.Duration
+	// Local configures the bounded L1.
+	Local BoundedMemoryConfig
+}
+
+// NearCache is a two‑tier cache: a [BoundedMemory] L1 in front of a [Redis]
+// L2. It implements Cache[*redis.Client], so it can replace [Redis] wherever
+// one is expected (yafsm, yaratelimit, …).
+//
+//   - Get is served from L1; a miss reads L2 and keeps the value locally for
+//     min(LocalTTL, remaining Redis TTL).
+//   - Every write and delete goes to L2 first, then drops the local copy and
+//     publishes the key on Channel so the other replicas drop theirs.
+//   - Hash, list and sorted-set commands, MGet and Exists misses always go to
+//     L2: only plain keys are cached locally.
+//   - Values are only kept locally while the invalidation subscription is
+//     live. When it breaks, L1 is flushed and refilled after resubscribing, so
+//     a missed invalidation never outlives the outage.
+//
+// Callers writing through Raw bypass all of this and must call Invalidate for
+// the keys they touched.
+//
+// Example:
+//
+//	client := yacache.NewRedisClient("localhost", uint16(6379), "", 1, log)
+//	near := yacache.NewNearCache(client, yacache.NearCacheConfig{LocalTTL: 30 * time.Second})
+//	defer near.Close()
+//	locale, _ := near.Get(ctx, "locale:42") // Redis once, then local
+type NearCache struct {
+	remote *Redis
+	local  *BoundedMemory
+	client *redis.Client
+	config NearCacheConfig
+
+	pubsub *redis.PubSub
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	// mutex orders local populates against invalidations; generation tells a
+	// populate whether an invalidation ran while its L2 read was in flight.
+	mutex      sync.Mutex
+	generation uint64
+	subscribed atomic.Bool
+}
+
+// NewNearCache builds a [NearCache] over client and starts listening for
+// invalidations. Close stops the listener and closes client.
+//
+// Example:
+//
+//	near := yacache.NewNearCache(client, yacache.NearCacheConfig{})
+func NewNearCache(client *redis.Client, config NearCacheConfig) *NearCache {
+	if config.Channel == "" {
+		config.Channel = DefaultNearCacheChannel
+	}
+
+	if config.LocalTTL <= 0 {
+		config.LocalTTL = DefaultNearCacheLocalTTL
+	}
+
+	if config.Local.MaxEntries <= 0 && config.Local.MaxBytes <= 0 {
+		config.Local.MaxEntries = DefaultNearCacheMaxEntries
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	near := &NearCache{
+		remote: NewRedis(client),
+		local:  NewBoundedMemory(config.Local),
+		client: client,
+		config: config,
+		pubsub: client.Subscribe(ctx, config.Channel),
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+
+	go near.listen(ctx)
+
+	return near
+}
+
+// listen applies invalidations until Close. Every (re)subscription flushes
+// L1, since messages published while it was down are lost.
+func (n *NearCache) listen(ctx context.Context) {
+	defer close(n.done)
+
+	for {
+		message, err := n.pubsub.Receive(ctx)
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, redis.ErrClosed) {
+				return
+			}
+
+			n.subscribed.Store(false)
+			n.drop()
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(nearCacheResubscribeDelay):
+			}
+
+			continue
+		}
+
+		switch message := message.(type) {
+		case *redis.Subscription:
+			if message.Kind == "subscribe" {
+				n.drop()
+				n.subscribed.Store(true)
+			}
+		case *redis.Message:
+			n.drop(message.Payload)
+		}
+	}
+}
+
+// drop removes keys from L1, or everything when no key is given, and
+// invalidates populates that are still in flight.
+func (n *NearCache) drop(keys ...string) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	n.generation++
+
+	if len(keys) == 0 {
+		n.local.purge()
+
+		return
+	}
+
+	for _, key := range keys {
+		_ = n.local.Del(context.Background(), key)
+	}
+}
+
+// populate stores a value read from L2 at generation, unless an invalidation
+// ran since then or invalidations are currently not being received.
+func (n *NearCache) populate(key, value string, ttl time.Duration, generation uint64) {
+	if ttl <= 0 || ttl > n.config.LocalTTL {
+		ttl = n.config.LocalTTL
+	}
+
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	if n.generation != generation || !n.subscribed.Load() {
+		return
+	}
+
+	_ = n.local.Set(context.Background(), key, value, ttl)
+}
+
+// Invalidate drops keys from the local cache of every replica, this one
+// included. Call it after changing keys through Raw.
+//
+// Example:
+//
+//	_ = near.Raw().Incr(ctx, "counter").Err()
+//	_ = near.Invalidate(ctx, "counter")
+func (n *NearCache) Invalidate(ctx context.Context, keys ...string) yaerrors.Error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	n.drop(keys...)
+
+	pipe := n.client.Pipeline()
+
+	for _, key := range keys {
+		pipe.Publish(ctx, n.config.Channel, key)
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return yaerrors.FromError(
+			http.StatusInternalServerError,
+			errors.Join(err, ErrFailedToPublishInvalidation),
+			fmt.Sprintf("[NEAR] failed `PUBLISH` to `%s`", n.config.Channel),
+		)
+	}
+
+	return nil
+}
+
+// LocalStats reports the counters of the L1 cache.
+//
+// Example:
+//
+//	fmt.Println(near.LocalStats().Hits)
+func (n *NearCache) LocalStats() BoundedMemoryStats {
+	return n.local.Stats()
+}
+
+// Raw returns the underlying *redis.Client. Writes made through it are not
+// seen by any L1 until Invalidate is called.
+//
+// Example:
+//
+//	client := near.Raw()
+func (n *NearCache) Raw() *redis.Client {
+	return n.client
+}
+
+// HSetEX sets a hash field in L2; hashes are never cached locally.
+//
+// Example:
+//
+//	_ = near.HSetEX(ctx, "sessions", "u42", "token", time.Hour)
+func (n *NearCache) HSetEX(
+	ctx context.Context,
+	mainKey string,
+	childKey string,
+	value string,
+	ttl time.Duration,
+) yaerrors.Error {
+	return n.remote.HSetEX(ctx, mainKey, childKey, value, ttl)
+}
+
+// HGet reads a hash field from L2.
+//
+// Example:
+//
+//	token, _ := near.HGet(ctx, "sessions", "u42")
+func (n *NearCache) HGet(
+	ctx context.Context,
+	mainKey string,
+	childKey string,
+) (string, yaerrors.Error) {
+	return n.remote.HGet(ctx, mainKey, childKey)
+}
+
+// HGetAll reads a whole hash from L2.
+//
+// Example:
+//
+//	sessions, _ := near.HGetAll(ctx, "sessions")
+func (n *NearCache) HGetAll(
+	ctx context.Context,
+	mainKey string,
+) (map[string]string, yaerrors.Error) {
+	return n.remote.HGetAll(ctx, mainKey)
+}
+
+// HGetDelSingle reads and deletes a hash field in L2.
+//
+// Example:
+//
+//	token, _ := near.HGetDelSingle(ctx, "sessions", "u42")
+func (n *NearCache) HGetDelSingle(
+	ctx context.Context,
+	mainKey string,
+	childKey string,
+) (string, yaerrors.Error) {
+	return n.remote.HGetDelSingle(ctx, mainKey, childKey)
+}
+
+// HLen counts the fields of a hash in L2.
+//
+// Example:
+//
+//	count, _ := near.HLen(ctx, "sessions")
+func (n *NearCache) HLen(
+	ctx context.Context,
+	mainKey string,
+) (int64, yaerrors.Error) {
+	return n.remote.HLen(ctx, mainKey)
+}
+
+// HExist checks a hash field in L2.
+//
+// Example:
+//
+//	ok, _ := near.HExist(ctx, "sessions", "u42")
+func (n *NearCache) HExist(
+	ctx context.Context,
+	mainKey string,
+	childKey string,
+) (bool, yaerrors.Error) {
+	return n.remote.HExist(ctx, mainKey, childKey)
+}
+
+// HDelSingle deletes a hash field in L2.
+//
+// Example:
+//
+//	_ = near.HDelSingle(ctx, "sessions", "u42")
+func (n *NearCache) HDelSingle(
+	ctx context.Context,
+	mainKey string,
+	childKey string,
+) yaerrors.Error {
+	return n.remote.HDelSingle(ctx, mainKey, childKey)
+}
+
+// Set writes key to L2 and invalidates every local copy.
+//
+// Example:
+//
+//	_ = near.Set(ctx, "locale:42", "en", 0)
+func (n *NearCache) Set(
+	ctx context.Context,
+	key string,
+	value string,
+	ttl time.Duration,
+) yaerrors.Error {
+	if err := n.remote.Set(ctx, key, value, ttl); err != nil {
+		return err
+	}
+
+	return n.Invalidate(ctx, key)
+}
+
+// Get serves key from L1, falling back to L2 on a miss. A key missing from
+// both returns an error matching ErrNotFoundValue.
+//
+// Example:
+//
+//	locale, _ := near.Get(ctx, "locale:42")
+func (n *NearCache) Get(
+	ctx context.Context,
+	key string,
+) (string, yaerrors.Error) {
+	if value, err := n.local.Get(ctx, key); err == nil {
+		return value, nil
+	}
+
+	n.mutex.Lock()
+	generation := n.generation
+	n.mutex.Unlock()
+
+	pipe := n.client.Pipeline()
+	get := pipe.Get(ctx, key)
+	pttl := pipe.PTTL(ctx, key)
+
+	_, err := pipe.Exec(ctx)
+	if errors.Is(get.Err(), redis.Nil) {
+		return "", yaerrors.FromError(
+			http.StatusInternalServerError,
+			errors.Join(get.Err(), ErrNotFoundValue, ErrFailedToGetValue),
+			fmt.Sprintf("[NEAR] not found value by `%s`", key),
+		)
+	}
+
+	if err != nil {
+		return "", yaerrors.FromError(
+			http.StatusInternalServerError,
+			errors.Join(err, ErrFailedToGetValue),
+			fmt.Sprintf("[NEAR] failed `GET` by `%s`", key),
+		)
+	}
+
+	value := get.Val()
+
+	n.populate(key, value, pttl.Val(), generation)
+
+	return value, nil
+}
+
+// MGet serves the keys found in L1 and reads the rest from L2 without
+// caching them. Missing keys are skipped.
+//
+// Example:
+//
+//	values, _ := near.MGet(ctx, "locale:1", "locale:2")
+func (n *NearCache) MGet(
+	ctx context.Context,
+	keys ...string,
+) (map[string]string, yaerrors.Error) {
+	result, err := n.local.MGet(ctx, keys...)
+	if err != nil {
+		result = make(map[string]string)
+	}
+
+	missing := make([]string, 0, len(keys))
+
+	for _, key := range keys {
+		if _, ok := result[key]; !ok {
+			missing = append(missing, key)
+		}
+	}
+
+	if len(missing) == 0 {
+		return result, nil
+	}
+
+	remote, err := n.remote.MGet(ctx, missing...)
+	if err != nil {
+		return nil, err
+	}
+
+	for key, value := range remote {
+		result[key] = value
+	}
+
+	return result, nil
+}
+
+// Exists reports whether all keys exist, asking L2 unless every key is
+// cached locally.
+//
+// Example:
+//
+//	ok, _ := near.Exists(ctx, "locale:42")
+func (n *NearCache) Exists(
+	ctx context.Context,
+	keys ...string,
+) (bool, yaerrors.Error) {
+	if exists, err := n.local.Exists(ctx, keys...); err == nil && exists {
+		return true, nil
+	}
+
+	return n.remote.Exists(ctx, keys...)
+}
+
+// CompareAndSwap swaps key in L2 and, if it swapped, invalidates every local
+// copy.
+//
+// Example:
+//
+//	swapped, _ := near.CompareAndSwap(ctx, "state:42", "idle", "busy", 0)
+func (n *NearCache) CompareAndSwap(
+	ctx context.Context,
+	key string,
+	expected string,
+	value string,
+	ttl time.Duration,
+) (bool, yaerrors.Error) {
+	swapped, err := n.remote.CompareAndSwap(ctx, key, expected, value, ttl)
+	if err != nil || !swapped {
+		return swapped, err
+	}
+
+	return true, n.Invalidate(ctx, key)
+}
+
+// Del deletes key from L2 and invalidates every local copy.
+//
+// Example:
+//
+//	_ = near.Del(ctx, "locale:42")
+func (n *NearCache) Del(
+	ctx context.Context,
+	key string,
+) yaerrors.Error {
+	if err := n.remote.Del(ctx, key); err != nil {
+		return err
+	}
+
+	return n.Invalidate(ctx, key)
+}
+
+// GetDel reads and deletes key in L2 and invalidates every local copy.
+//
+// Example:
+//
+//	code, _ := near.GetDel(ctx, "otp:42")
+func (n *NearCache) GetDel(
+	ctx context.Context,
+	key string,
+) (string, yaerrors.Error) {
+	value, err := n.remote.GetDel(ctx, key)
+	if err != nil {
+		return "", err
+	}
+
+	return value, n.Invalidate(ctx, key)
+}
+
+// IncrBy increments key in L2 and invalidates every local copy.
+//
+// Example:
+//
+//	views, _ := near.IncrBy(ctx, "views:42", 10)
+func (n *NearCache) IncrBy(
+	ctx context.Context,
+	key string,
+	delta int64,
+) (int64, yaerrors.Error) {
+	value, err := n.remote.IncrBy(ctx, key, delta)
+	if err != nil {
+		return 0, err
+	}
+
+	return value, n.Invalidate(ctx, key)
+}
+
+// Incr increments key by one in L2 and invalidates every local copy.
+//
+// Example:
+//
+//	views, _ := near.Incr(ctx, "views:42")
+func (n *NearCache) Incr(
+	ctx context.Context,
+	key string,
+) (int64, yaerrors.Error) {
+	return n.IncrBy(ctx, key, 1)
+}
+
+// LPush pushes to a list in L2; lists are never cached locally.
+//
+// Example:
+//
+//	length, _ := near.LPush(ctx, "jobs", "a")
+func (n *NearCache) LPush(
+	ctx context.Context,
+	key string,
+	values ...string,
+) (int64, yaerrors.Error) {
+	return n.remote.LPush(ctx, key, values...)
+}
+
+// RPush appends to a list in L2.
+//
+// Example:
+//
+//	length, _ := near.RPush(ctx, "jobs", "a")
+func (n *NearCache) RPush(
+	ctx context.Context,
+	key string,
+	values ...string,
+) (int64, yaerrors.Error) {
+	return n.remote.RPush(ctx, key, values...)
+}
+
+// LPop pops the head of a list in L2.
+//
+// Example:
+//
+//	job, _ := near.LPop(ctx, "jobs")
+func (n *NearCache) LPop(
+	ctx context.Context,
+	key string,
+) (string, yaerrors.Error) {
+	return n.remote.LPop(ctx, key)
+}
+
+// RPop pops the tail of a list in L2.
+//
+// Example:
+//
+//	job, _ := near.RPop(ctx, "jobs")
+func (n *NearCache) RPop(
+	ctx context.Context,
+	key string,
+) (string, yaerrors.Error) {
+	return n.remote.RPop(ctx, key)
+}
+
+// LRange reads a list range from L2.
+//
+// Example:
+//
+//	all, _ := near.LRange(ctx, "jobs", 0, -1)
+func (n *NearCache) LRange(
+	ctx context.Context,
+	key string,
+	start int64,
+	stop int64,
+) ([]string, yaerrors.Error) {
+	return n.remote.LRange(ctx, key, start, stop)
+}
+
+// ZAdd adds sorted-set members in L2; sorted sets are never cached locally.
+//
+// Example:
+//
+//	_, _ = near.ZAdd(ctx, "leaderboard", yacache.ZMember{Member: "alice", Score: 42})
+func (n *NearCache) ZAdd(
+	ctx context.Context,
+	key string,
+	members ...ZMember,
+) (int64, yaerrors.Error) {
+	return n.remote.ZAdd(ctx, key, members...)
+}
+
+// ZRangeByScore reads a score range from L2.
+//
+// Example:
+//
+//	top, _ := near.ZRangeByScore(ctx, "leaderboard", 100, math.Inf(1))
+func (n *NearCache) ZRangeByScore(
+	ctx context.Context,
+	key string,
+	minScore float64,
+	maxScore float64,
+) ([]ZMember, yaerrors.Error) {
+	return n.remote.ZRangeByScore(ctx, key, minScore, maxScore)
+}
+
+// ZRem removes sorted-set members in L2.
+//
+// Example:
+//
+//	_, _ = near.ZRem(ctx, "leaderboard", "alice")
+func (n *NearCache) ZRem(
+	ctx context.Context,
+	key string,
+	members ...string,
+) (int64, yaerrors.Error) {
+	return n.remote.ZRem(ctx, key, members...)
+}
+
+// Scan walks the keys of L2, which holds every key L1 does.
+//
+// Example:
+//
+//	for key, err := range near.Scan(ctx, "locale:") { … }
+func (n *NearCache) Scan(
+	ctx context.Context,
+	prefix string,
+) iter.Seq2[string, yaerrors.Error] {
+	return n.remote.Scan(ctx, prefix)
+}
+
+// DelPrefix deletes the matching keys from L2 and invalidates every local
+// copy of them, batch by batch.
+//
+// Example:
+//
+//	deleted, _ := near.DelPrefix(ctx, "locale:")
+func (n *NearCache) DelPrefix(
+	ctx context.Context,
+	prefix string,
+) (int64, yaerrors.Error) {
+	return n.remote.delPrefix(ctx, prefix, func(keys []string) yaerrors.Error {
+		return n.Invalidate(ctx, keys...)
+	})
+}
+
+// TTL reads the TTL of key from L2.
+//
+// Example:
+//
+//	ttl, _ := near.TTL(ctx, "locale:42")
+func (n *NearCache) TTL(
+	ctx context.Context,
+	key string,
+) (time.Duration, yaerrors.Error) {
+	return n.remote.TTL(ctx, key)
+}
+
+// Expire sets the TTL of key in L2 and invalidates every local copy, which
+// may otherwise outlive a shortened TTL.
+//
+// Example:
+//
+//	ok, _ := near.Expire(ctx, "locale:42", time.Minute)
+func (n *NearCache) Expire(
+	ctx context.Context,
+	key string,
+	ttl time.Duration,
+) (bool, yaerrors.Error) {
+	ok, err := n.remote.Expire(ctx, key, ttl)
+	if err != nil || !ok {
+		return ok, err
+	}
+
+	return true, n.Invalidate(ctx, key)
+}
+
+// Persist removes the TTL of key in L2. Local copies keep their own, shorter
+// TTL, so nothing needs invalidating.
+//
+// Example:
+//
+//	ok, _ := near.Persist(ctx, "locale:42")
+func (n *NearCache) Persist(
+	ctx context.Context,
+	key string,
+) (bool, yaerrors.Error) {
+	return n.remote.Persist(ctx, key)
+}
+
+// Ping pings L2.
+//
+// Example:
+//
+//	if err := near.Ping(ctx); err != nil { … }
+func (n *NearCache) Ping(ctx context.Context) yaerrors.Error {
+	return n.remote.Ping(ctx)
+}
+
+// Close stops the invalidation listener, drops L1 and closes the client.
+//
+// Example:
+//
+//	near := yacache.NewNearCache(client, yacache.NearCacheConfig{})
+//	defer near.Close()
+func (n *NearCache) Close() yaerrors.Error {
+	n.cancel()
+	_ = n.pubsub.Close()
+	<-n.done
+
+	_ = n.local.Close()
+
+	return n.remote.Close()
+}
@@ -0,0 +1,171 @@
+package yacache_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yacache"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var _ yacache.Cache[*redis.Client] = (*yacache.NearCache)(nil)
+
+// newTestNearCache builds a replica on server and waits until it serves reads
+// locally, i.e. until its invalidation subscription is live.
+func newTestNearCache(
+	t *testing.T,
+	server *miniredis.Miniredis,
+	config yacache.NearCacheConfig,
+) *yacache.NearCache {
+	t.Helper()
+
+	ctx := context.Background()
+	near := yacache.NewNearCache(redis.NewClient(&redis.Options{Addr: server.Addr()}), config)
+
+	t.Cleanup(func() { _ = near.Close() })
+
+	const probe = "near:probe"
+
+	require.Nil(t, near.Set(ctx, probe, "1", 0))
+	require.Eventually(t, func() bool {
+		_, _ = near.Get(ctx, probe)
+		hits := near.LocalStats().Hits
+		_, _ = near.Get(ctx, probe)
+
+		return near.LocalStats().Hits > hits
+	}, time.Second, 5*time.Millisecond)
+	require.Nil(t, near.Del(ctx, probe))
+
+	return near
+}
+
+func TestNearCache_Reads_ServedLocally(t *testing.T) {
+	ctx := context.Background()
+	server := miniredis.RunT(t)
+	near := newTestNearCache(t, server, yacache.NearCacheConfig{})
+
+	// Seeded behind the cache, so no invalidation echo can race the populate.
+	require.NoError(t, server.Set(yamainKey, yavalue))
+
+	value, err := near.Get(ctx, yamainKey)
+	require.Nil(t, err)
+	assert.Equal(t, yavalue, value)
+
+	t.Run("[Get] a cached value survives an unannounced Redis change", func(t *testing.T) {
+		require.NoError(t, server.Set(yamainKey, "changed behind our back"))
+
+		value, err := near.Get(ctx, yamainKey)
+		require.Nil(t, err)
+		assert.Equal(t, yavalue, value)
+	})
+
+	t.Run("[Invalidate] drops the local copy", func(t *testing.T) {
+		require.Nil(t, near.Invalidate(ctx, yamainKey))
+
+		value, err := near.Get(ctx, yamainKey)
+		require.Nil(t, err)
+		assert.Equal(t, "changed behind our back", value)
+	})
+
+	t.Run("[Get] missing key matches ErrNotFoundValue", func(t *testing.T) {
+		_, err := near.Get(ctx, "missing")
+		assert.True(t, errors.Is(err, yacache.ErrNotFoundValue))
+	})
+
+	t.Run("[MGet/Exists] mix local and remote keys", func(t *testing.T) {
+		require.NoError(t, server.Set(yamainKey2, yavalue2))
+
+		values, err := near.MGet(ctx, yamainKey, yamainKey2, "missing")
+		require.Nil(t, err)
+		assert.Equal(
+			t,
+			map[string]string{yamainKey: "changed behind our back", yamainKey2: yavalue2},
+			values,
+		)
+
+		exists, _ := near.Exists(ctx, yamainKey, yamainKey2)
+		assert.True(t, exists)
+
+		exists, _ = near.Exists(ctx, yamainKey, "missing")
+		assert.False(t, exists)
+	})
+
+	t.Run("[Hash] commands go straight to Redis", func(t *testing.T) {
+		server.HSet(yamainKey+":h", yachildKey, yavalue)
+
+		value, err := near.HGet(ctx, yamainKey+":h", yachildKey)
+		require.Nil(t, err)
+		assert.Equal(t, yavalue, value)
+
+		server.HSet(yamainKey+":h", yachildKey, yavalue2)
+
+		value, err = near.HGet(ctx, yamainKey+":h", yachildKey)
+		require.Nil(t, err)
+		assert.Equal(t, yavalue2, value)
+	})
+}
+
+func TestNearCache_Writes_InvalidateReplicas(t *testing.T) {
+	ctx := context.Background()
+	server := miniredis.RunT(t)
+	first := newTestNearCache(t, server, yacache.NearCacheConfig{})
+	second := newTestNearCache(t, server, yacache.NearCacheConfig{})
+
+	eventuallyReads := func(t *testing.T, near *yacache.NearCache, want string) {
+		t.Helper()
+
+		assert.Eventually(t, func() bool {
+			value, _ := near.Get(ctx, yamainKey)
+
+			return value == want
+		}, time.Second, 5*time.Millisecond)
+	}
+
+	require.Nil(t, first.Set(ctx, yamainKey, yavalue, 0))
+	eventuallyReads(t, first, yavalue)
+	eventuallyReads(t, second, yavalue)
+
+	t.Run("[Set] on one replica refreshes the other", func(t *testing.T) {
+		require.Nil(t, second.Set(ctx, yamainKey, yavalue2, 0))
+		eventuallyReads(t, first, yavalue2)
+	})
+
+	t.Run("[CompareAndSwap] on one replica refreshes the other", func(t *testing.T) {
+		swapped, err := first.CompareAndSwap(ctx, yamainKey, yavalue2, yavalue, 0)
+		require.Nil(t, err)
+		assert.True(t, swapped)
+		eventuallyReads(t, second, yavalue)
+	})
+
+	t.Run("[Del] on one replica evicts the other", func(t *testing.T) {
+		require.Nil(t, second.Del(ctx, yamainKey))
+
+		assert.Eventually(t, func() bool {
+			_, err := first.Get(ctx, yamainKey)
+
+			return errors.Is(err, yacache.ErrNotFoundValue)
+		}, time.Second, 5*time.Millisecond)
+	})
+}
+
+func TestNearCache_LocalTTL_BoundsStaleness(t *testing.T) {
+	ctx := context.Background()
+	server := miniredis.RunT(t)
+	near := newTestNearCache(t, server, yacache.NearCacheConfig{LocalTTL: 20 * time.Millisecond})
+
+	require.Nil(t, near.Set(ctx, yamainKey, yavalue, 0))
+	_, _ = near.Get(ctx, yamainKey)
+
+	require.NoError(t, server.Set(yamainKey, yavalue2))
+
+	assert.Eventually(t, func() bool {
+		value, _ := near.Get(ctx, yamainKey)
+
+		return value == yavalue2
+	}, time.Second, 5*time.Millisecond)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"iter"
 	"net/http"
 	"strconv"
 	"strings"
@@ -14,6 +15,9 @@ import (
 	"github.com/redis/go-redis/v9"
 )
 
+// scanCount is the COUNT hint passed to every SCAN round trip.
+const scanCount = 100
+
 // Redis wraps a *redis.Client and implements the Cache interface.
 //
 // It intentionally exposes only the subset of commands used by the
@@ -359,6 +363,14 @@ func (r *Redis) Get(
 	key string,
 ) (string, yaerrors.Error) {
 	value, err := r.client.Get(ctx, key).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", yaerrors.FromError(
+			http.StatusInternalServerError,
+			errors.Join(err, ErrNotFoundValue, ErrFailedToGetValue),
+			fmt.Sprintf("[%s] not found value by `%s`", r.backendName, key),
+		)
+	}
+
 	if err != nil {
 		return "", yaerrors.FromError(
 			http.StatusInternalServerError,
@@ -461,6 +473,45 @@ func (r *Redis) Exists(
 	return count == int64(len(keys)), nil
 }
 
+// CompareAndSwap runs a small Lua script that compares the current value of
+// key (a missing key reads as "") with expected and, on a match, SETs value
+// with the given TTL – all in one atomic server-side step.
+//
+// Example:
+//
+//	ok, _ := redis.CompareAndSwap(ctx, "counter", "1", "2", time.Minute)
+func (r *Redis) CompareAndSwap(
+	ctx context.Context,
+	key string,
+	expected string,
+	value string,
+	ttl time.Duration,
+) (bool, yaerrors.Error) {
+	var milliseconds int64
+
+	if ttl > 0 {
+		milliseconds = max(ttl.Milliseconds(), 1)
+	}
+
+	swapped, err := compareAndSwapScript.Run(
+		ctx,
+		r.client,
+		[]string{key},
+		expected,
+		value,
+		milliseconds,
+	).Int()
+	if err != nil {
+		return false, yaerrors.FromError(
+			http.StatusInternalServerError,
+			errors.Join(err, ErrFailedToCompareAndSwap),
+			fmt.Sprintf("[%s] failed `CAS` by `%s`", r.backendName, key),
+		)
+	}
+
+	return swapped == 1, nil
+}
+
 // Del removes key through DEL.  The call is safe to repeat: deleting a
 // missing key is not considered an error.
 //
@@ -504,6 +555,451 @@ func (r *Redis) GetDel(
 	return value, nil
 }
 
+// IncrBy executes INCRBY, which keeps the key's TTL.
+//
+// Example:
+//
+//	views, _ := redis.IncrBy(ctx, "views:42", 10)
+func (r *Redis) IncrBy(
+	ctx context.Context,
+	key string,
+	delta int64,
+) (int64, yaerrors.Error) {
+	value, err := r.client.IncrBy(ctx, key, delta).Result()
+	if err != nil {
+		return 0, yaerrors.FromError(
+			http.StatusInternalServerError,
+			errors.Join(replyError(err), ErrFailedToIncr),
+			fmt.Sprintf("[%s] failed `INCRBY` by `%s`", r.backendName, key),
+		)
+	}
+
+	return value, nil
+}
+
+// Incr executes INCR.
+//
+// Example:
+//
+//	views, _ := redis.Incr(ctx, "views:42")
+func (r *Redis) Incr(
+	ctx context.Context,
+	key string,
+) (int64, yaerrors.Error) {
+	return r.IncrBy(ctx, key, 1)
+}
+
+// LPush executes LPUSH. Pushing no values only reports the length (LLEN).
+//
+// Example:
+//
+//	length, _ := redis.LPush(ctx, "jobs", "a", "b")
+func (r *Redis) LPush(
+	ctx context.Context,
+	key string,
+	values ...string,
+) (int64, yaerrors.Error) {
+	return r.push(ctx, "LPUSH", key, values)
+}
+
+// RPush executes RPUSH. Pushing no values only reports the length (LLEN).
+//
+// Example:
+//
+//	length, _ := redis.RPush(ctx, "jobs", "a", "b")
+func (r *Redis) RPush(
+	ctx context.Context,
+	key string,
+	values ...string,
+) (int64, yaerrors.Error) {
+	return r.push(ctx, "RPUSH", key, values)
+}
+
+// push runs LPUSH or RPUSH; Redis rejects both without values, so an empty
+// push falls back to LLEN.
+func (r *Redis) push(
+	ctx context.Context,
+	command string,
+	key string,
+	values []string,
+) (int64, yaerrors.Error) {
+	var cmd *redis.IntCmd
+
+	switch {
+	case len(values) == 0:
+		cmd = r.client.LLen(ctx, key)
+	case command == "LPUSH":
+		cmd = r.client.LPush(ctx, key, toArgs(values)...)
+	default:
+		cmd = r.client.RPush(ctx, key, toArgs(values)...)
+	}
+
+	length, err := cmd.Result()
+	if err != nil {
+		return 0, yaerrors.FromError(
+			http.StatusInternalServerError,
+			errors.Join(replyError(err), ErrFailedToPush),
+			fmt.Sprintf("[%s] failed `%s` by `%s`", r.backendName, command, key),
+		)
+	}
+
+	return length, nil
+}
+
+// LPop executes LPOP.
+//
+// Example:
+//
+//	job, _ := redis.LPop(ctx, "jobs")
+func (r *Redis) LPop(
+	ctx context.Context,
+	key string,
+) (string, yaerrors.Error) {
+	return r.pop(r.client.LPop(ctx, key), "LPOP", key)
+}
+
+// RPop executes RPOP.
+//
+// Example:
+//
+//	job, _ := redis.RPop(ctx, "jobs")
+func (r *Redis) RPop(
+	ctx context.Context,
+	key string,
+) (string, yaerrors.Error) {
+	return r.pop(r.client.RPop(ctx, key), "RPOP", key)
+}
+
+// pop converts the reply of LPOP or RPOP.
+func (r *Redis) pop(cmd *redis.StringCmd, command string, key string) (string, yaerrors.Error) {
+	value, err := cmd.Result()
+	if errors.Is(err, redis.Nil) {
+		return "", notFoundError(r.backendName, command, key, ErrFailedToPop)
+	}
+
+	if err != nil {
+		return "", yaerrors.FromError(
+			http.StatusInternalServerError,
+			errors.Join(replyError(err), ErrFailedToPop),
+			fmt.Sprintf("[%s] failed `%s` by `%s`", r.backendName, command, key),
+		)
+	}
+
+	return value, nil
+}
+
+// LRange executes LRANGE.
+//
+// Example:
+//
+//	latest, _ := redis.LRange(ctx, "events", 0, 9)
+func (r *Redis) LRange(
+	ctx context.Context,
+	key string,
+	start int64,
+	stop int64,
+) ([]string, yaerrors.Error) {
+	values, err := r.client.LRange(ctx, key, start, stop).Result()
+	if err != nil {
+		return nil, yaerrors.FromError(
+			http.StatusInternalServerError,
+			errors.Join(replyError(err), ErrFailedToRange),
+			fmt.Sprintf("[%s] failed `LRANGE` by `%s`", r.backendName, key),
+		)
+	}
+
+	return values, nil
+}
+
+// ZAdd executes ZADD. Adding no members is a no‑op.
+//
+// Example:
+//
+//	_, _ = redis.ZAdd(ctx, "leaderboard", yacache.ZMember{Member: "alice", Score: 42})
+func (r *Redis) ZAdd(
+	ctx context.Context,
+	key string,
+	members ...ZMember,
+) (int64, yaerrors.Error) {
+	if len(members) == 0 {
+		return 0, nil
+	}
+
+	entries := make([]redis.Z, len(members))
+
+	for i, member := range members {
+		entries[i] = redis.Z{Score: member.Score, Member: member.Member}
+	}
+
+	added, err := r.client.ZAdd(ctx, key, entries...).Result()
+	if err != nil {
+		return 0, yaerrors.FromError(
+			http.StatusInternalServerError,
+			errors.Join(replyError(err), ErrFailedToZAdd),
+			fmt.Sprintf("[%s] failed `ZADD` by `%s`", r.backendName, key),
+		)
+	}
+
+	return added, nil
+}
+
+// ZRangeByScore executes ZRANGEBYSCORE … WITHSCORES.
+//
+// Example:
+//
+//	top, _ := redis.ZRangeByScore(ctx, "leaderboard", 100, math.Inf(1))
+func (r *Redis) ZRangeByScore(
+	ctx context.Context,
+	key string,
+	minScore float64,
+	maxScore float64,
+) ([]ZMember, yaerrors.Error) {
+	entries, err := r.client.ZRangeByScoreWithScores(ctx, key, &redis.ZRangeBy{
+		Min: formatScore(minScore),
+		Max: formatScore(maxScore),
+	}).Result()
+	if err != nil {
+		return nil, yaerrors.FromError(
+			http.StatusInternalServerError,
+			errors.Join(replyError(err), ErrFailedToZRange),
+			fmt.Sprintf("[%s] failed `ZRANGEBYSCORE` by `%s`", r.backendName, key),
+		)
+	}
+
+	result := make([]ZMember, len(entries))
+
+	for i, entry := range entries {
+		result[i] = ZMember{Member: fmt.Sprint(entry.Member), Score: entry.Score}
+	}
+
+	return result, nil
+}
+
+// ZRem executes ZREM. Removing no members is a no‑op.
+//
+// Example:
+//
+//	_, _ = redis.ZRem(ctx, "leaderboard", "alice")
+func (r *Redis) ZRem(
+	ctx context.Context,
+	key string,
+	members ...string,
+) (int64, yaerrors.Error) {
+	if len(members) == 0 {
+		return 0, nil
+	}
+
+	removed, err := r.client.ZRem(ctx, key, toArgs(members)...).Result()
+	if err != nil {
+		return 0, yaerrors.FromError(
+			http.StatusInternalServerError,
+			errors.Join(replyError(err), ErrFailedToZRem),
+			fmt.Sprintf("[%s] failed `ZREM` by `%s`", r.backendName, key),
+		)
+	}
+
+	return removed, nil
+}
+
+// Scan walks the keys matching `prefix*` with SCAN, scanCount keys per
+// round trip. Glob metacharacters in prefix are escaped.
+//
+// Example:
+//
+//	for key, err := range redis.Scan(ctx, "fsm:") { … }
+func (r *Redis) Scan(
+	ctx context.Context,
+	prefix string,
+) iter.Seq2[string, yaerrors.Error] {
+	return func(yield func(string, yaerrors.Error) bool) {
+		_ = r.scanBatches(ctx, prefix, func(keys []string) bool {
+			for _, key := range keys {
+				if !yield(key, nil) {
+					return false
+				}
+			}
+
+			return true
+		}, func(err yaerrors.Error) {
+			yield("", err)
+		})
+	}
+}
+
+// DelPrefix deletes the keys matching `prefix*` batch by batch with UNLINK,
+// so large key sets are freed off the main Redis thread.
+//
+// Example:
+//
+//	deleted, _ := redis.DelPrefix(ctx, "user:42:")
+func (r *Redis) DelPrefix(
+	ctx context.Context,
+	prefix string,
+) (int64, yaerrors.Error) {
+	return r.delPrefix(ctx, prefix, nil)
+}
+
+// delPrefix implements DelPrefix, calling deleted (when set) with every batch
+// of keys it removed.
+func (r *Redis) delPrefix(
+	ctx context.Context,
+	prefix string,
+	deleted func(keys []string) yaerrors.Error,
+) (int64, yaerrors.Error) {
+	if prefix == "" {
+		return 0, emptyPrefixError(r.backendName)
+	}
+
+	var (
+		total    int64
+		batchErr yaerrors.Error
+	)
+
+	scanErr := r.scanBatches(ctx, prefix, func(keys []string) bool {
+		if len(keys) == 0 {
+			return true
+		}
+
+		count, err := r.client.Unlink(ctx, keys...).Result()
+		if err != nil {
+			batchErr = yaerrors.FromError(
+				http.StatusInternalServerError,
+				errors.Join(err, ErrFailedToDelPrefix),
+				fmt.Sprintf("[%s] failed `UNLINK` by prefix `%s`", r.backendName, prefix),
+			)
+
+			return false
+		}
+
+		total += count
+
+		if deleted != nil {
+			batchErr = deleted(keys)
+		}
+
+		return batchErr == nil
+	}, nil)
+
+	if scanErr != nil {
+		return total, scanErr.Wrap("failed to delete keys by prefix")
+	}
+
+	return total, batchErr
+}
+
+// scanBatches feeds each SCAN batch of keys matching `prefix*` to batch until
+// the cursor wraps around or batch returns false. A SCAN failure is passed to
+// failed (when set) and returned.
+func (r *Redis) scanBatches(
+	ctx context.Context,
+	prefix string,
+	batch func(keys []string) bool,
+	failed func(err yaerrors.Error),
+) yaerrors.Error {
+	var cursor uint64
+
+	match := escapeGlob(prefix) + "*"
+
+	for {
+		keys, next, err := r.client.Scan(ctx, cursor, match, scanCount).Result()
+		if err != nil {
+			scanErr := yaerrors.FromError(
+				http.StatusInternalServerError,
+				errors.Join(err, ErrFailedToScan),
+				fmt.Sprintf("[%s] failed `SCAN` by prefix `%s`", r.backendName, prefix),
+			)
+
+			if failed != nil {
+				failed(scanErr)
+			}
+
+			return scanErr
+		}
+
+		if !batch(keys) || next == 0 {
+			return nil
+		}
+
+		cursor = next
+	}
+}
+
+// TTL executes PTTL.
+//
+// Example:
+//
+//	ttl, _ := redis.TTL(ctx, "session:42")
+func (r *Redis) TTL(
+	ctx context.Context,
+	key string,
+) (time.Duration, yaerrors.Error) {
+	ttl, err := r.client.PTTL(ctx, key).Result()
+	if err != nil {
+		return 0, yaerrors.FromError(
+			http.StatusInternalServerError,
+			errors.Join(err, ErrFailedToGetTTL),
+			fmt.Sprintf("[%s] failed `PTTL` by `%s`", r.backendName, key),
+		)
+	}
+
+	const (
+		missing    = -2
+		persistent = -1
+	)
+
+	switch ttl {
+	case missing:
+		return 0, notFoundError(r.backendName, "PTTL", key, ErrFailedToGetTTL)
+	case persistent:
+		return 0, nil
+	default:
+		return ttl, nil
+	}
+}
+
+// Expire executes PEXPIRE.
+//
+// Example:
+//
+//	ok, _ := redis.Expire(ctx, "session:42", time.Hour)
+func (r *Redis) Expire(
+	ctx context.Context,
+	key string,
+	ttl time.Duration,
+) (bool, yaerrors.Error) {
+	ok, err := r.client.PExpire(ctx, key, max(ttl, 0)).Result()
+	if err != nil {
+		return false, yaerrors.FromError(
+			http.StatusInternalServerError,
+			errors.Join(err, ErrFailedToExpire),
+			fmt.Sprintf("[%s] failed `PEXPIRE` by `%s`", r.backendName, key),
+		)
+	}
+
+	return ok, nil
+}
+
+// Persist executes PERSIST.
+//
+// Example:
+//
+//	ok, _ := redis.Persist(ctx, "session:42")
+func (r *Redis) Persist(
+	ctx context.Context,
+	key string,
+) (bool, yaerrors.Error) {
+	ok, err := r.client.Persist(ctx, key).Result()
+	if err != nil {
+		return false, yaerrors.FromError(
+			http.StatusInternalServerError,
+			errors.Join(err, ErrFailedToPersist),
+			fmt.Sprintf("[%s] failed `PERSIST` by `%s`", r.backendName, key),
+		)
+	}
+
+	return ok, nil
+}
+
 // Ping sends the Redis PING command.
 //
 // It is called by unit tests to guarantee that NewCache(client)
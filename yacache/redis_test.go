@@ -196,4 +196,28 @@ func TestRedisCacheService(t *testing.T) {
 			assert.Equal(t, expected, hlen)
 		})
 	})
+
+	t.Run("[CompareAndSwap] - swap works", func(t *testing.T) {
+		key := yamainKey2 + "CASTEST"
+
+		swapped, _ := redis.CompareAndSwap(ctx, key, "", yavalue, yattl)
+
+		assert.True(t, swapped)
+
+		swapped, _ = redis.CompareAndSwap(ctx, key, yavalue2, "other", yattl)
+
+		assert.False(t, swapped)
+
+		swapped, _ = redis.CompareAndSwap(ctx, key, yavalue, yavalue2, yattl)
+
+		assert.True(t, swapped)
+
+		value, _ := redis.Raw().Get(ctx, key).Result()
+
+		assert.Equal(t, yavalue2, value)
+
+		ttl, _ := redis.Raw().TTL(ctx, key).Result()
+
+		assert.Equal(t, yattl, ttl)
+	})
 }
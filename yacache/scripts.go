@@ -0,0 +1,22 @@
+package yacache
+
+import "github.com/redis/go-redis/v9"
+
+// compareAndSwapScript backs Redis.CompareAndSwap.
+//
+//	KEYS[1] – key
+//	ARGV[1] – expected value ("" matches a missing key)
+//	ARGV[2] – new value
+//	ARGV[3] – TTL in milliseconds, 0 for none
+var compareAndSwapScript = redis.NewScript(`
+local current = redis.call('GET', KEYS[1]) or ''
+if current ~= ARGV[1] then
+	return 0
+end
+if tonumber(ARGV[3]) > 0 then
+	redis.call('SET', KEYS[1], ARGV[2], 'PX', ARGV[3])
+else
+	redis.call('SET', KEYS[1], ARGV[2])
+end
+return 1
+`)
@@ -0,0 +1,335 @@
+package yacache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yaencoding"
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yaerrors"
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yagzip"
+	"golang.org/x/sync/singleflight"
+)
+
+// Codec converts typed values to and from the string form a [Cache] stores.
+//
+// The built-in codecs are [JSONCodec], [MessagePackCodec] and
+// [GzipMessagePackCodec]; any other format only needs these two methods.
+type Codec[V any] interface {
+	// Encode serializes value into its cached string form.
+	Encode(value V) (string, yaerrors.Error)
+
+	// Decode parses a string produced by Encode back into a value.
+	Decode(data string) (V, yaerrors.Error)
+}
+
+// JSONCodec stores values as JSON. It is the most portable choice: the cached
+// strings stay human-readable and can be shared with non-Go consumers.
+//
+// Example:
+//
+//	users := yacache.NewTypedCache(cache, yacache.NewJSONCodec[User]())
+type JSONCodec[V any] struct{}
+
+// NewJSONCodec returns a JSONCodec for V.
+func NewJSONCodec[V any]() *JSONCodec[V] {
+	return &JSONCodec[V]{}
+}
+
+// Encode marshals value as JSON.
+func (JSONCodec[V]) Encode(value V) (string, yaerrors.Error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", yaerrors.FromError(
+			http.StatusInternalServerError,
+			errors.Join(err, ErrFailedToEncodeValue),
+			fmt.Sprintf("[CODEC] failed to marshal %T as json", value),
+		)
+	}
+
+	return string(data), nil
+}
+
+// Decode unmarshals a JSON document into V.
+func (JSONCodec[V]) Decode(data string) (V, yaerrors.Error) {
+	var value V
+
+	if err := json.Unmarshal([]byte(data), &value); err != nil {
+		return value, yaerrors.FromError(
+			http.StatusInternalServerError,
+			errors.Join(err, ErrFailedToDecodeValue),
+			fmt.Sprintf("[CODEC] failed to unmarshal json as %T", value),
+		)
+	}
+
+	return value, nil
+}
+
+// MessagePackCodec stores values as raw MessagePack (via yaencoding), which is
+// both smaller and faster than JSON. Cached strings are binary.
+//
+// Example:
+//
+//	sessions := yacache.NewTypedCache(cache, yacache.NewMessagePackCodec[Session]())
+type MessagePackCodec[V any] struct{}
+
+// NewMessagePackCodec returns a MessagePackCodec for V.
+func NewMessagePackCodec[V any]() *MessagePackCodec[V] {
+	return &MessagePackCodec[V]{}
+}
+
+// Encode marshals value as MessagePack.
+func (MessagePackCodec[V]) Encode(value V) (string, yaerrors.Error) {
+	data, err := yaencoding.EncodeMessagePack(value)
+	if err != nil {
+		return "", yaerrors.FromError(
+			err.Code(),
+			errors.Join(err, ErrFailedToEncodeValue),
+			"[CODEC] failed to encode message pack",
+		)
+	}
+
+	return string(data), nil
+}
+
+// Decode unmarshals MessagePack data into V.
+func (MessagePackCodec[V]) Decode(data string) (V, yaerrors.Error) {
+	value, err := yaencoding.DecodeMessagePack[V]([]byte(data))
+	if err != nil {
+		var zero V
+
+		return zero, yaerrors.FromError(
+			err.Code(),
+			errors.Join(err, ErrFailedToDecodeValue),
+			"[CODEC] failed to decode message pack",
+		)
+	}
+
+	return *value, nil
+}
+
+// GzipMessagePackCodec stores values as gzip-compressed MessagePack (via
+// yagzip), trading CPU for memory on large, repetitive values such as lists or
+// rendered documents.
+//
+// Example:
+//
+//	feeds := yacache.NewTypedCache(cache, yacache.NewGzipMessagePackCodec[Feed](nil))
+type GzipMessagePackCodec[V any] struct {
+	gzip *yagzip.Gzip
+}
+
+// NewGzipMessagePackCodec returns a GzipMessagePackCodec compressing with
+// gzip. A nil gzip falls back to yagzip.NewGzip().
+func NewGzipMessagePackCodec[V any](gzip *yagzip.Gzip) *GzipMessagePackCodec[V] {
+	if gzip == nil {
+		gzip = yagzip.NewGzip()
+	}
+
+	return &GzipMessagePackCodec[V]{gzip: gzip}
+}
+
+// Encode marshals value as MessagePack and gzips the result.
+func (c *GzipMessagePackCodec[V]) Encode(value V) (string, yaerrors.Error) {
+	data, err := yaencoding.EncodeMessagePack(value)
+	if err != nil {
+		return "", yaerrors.FromError(
+			err.Code(),
+			errors.Join(err, ErrFailedToEncodeValue),
+			"[CODEC] failed to encode message pack",
+		)
+	}
+
+	compressed, err := c.gzip.Zip(data)
+	if err != nil {
+		return "", yaerrors.FromError(
+			err.Code(),
+			errors.Join(err, ErrFailedToEncodeValue),
+			"[CODEC] failed to gzip message pack",
+		)
+	}
+
+	return string(compressed), nil
+}
+
+// Decode gunzips data and unmarshals the MessagePack payload into V.
+func (c *GzipMessagePackCodec[V]) Decode(data string) (V, yaerrors.Error) {
+	var zero V
+
+	decompressed, err := c.gzip.Unzip([]byte(data))
+	if err != nil {
+		return zero, yaerrors.FromError(
+			err.Code(),
+			errors.Join(err, ErrFailedToDecodeValue),
+			"[CODEC] failed to gunzip message pack",
+		)
+	}
+
+	value, err := yaencoding.DecodeMessagePack[V](decompressed)
+	if err != nil {
+		return zero, yaerrors.FromError(
+			err.Code(),
+			errors.Join(err, ErrFailedToDecodeValue),
+			"[CODEC] failed to decode message pack",
+		)
+	}
+
+	return *value, nil
+}
+
+// Loader produces the value GetOrLoad caches on a miss.
+type Loader[V any] func(ctx context.Context) (V, yaerrors.Error)
+
+// TypedCache stores values of type V in a [Cache] through a [Codec], so callers
+// never marshal by hand.
+//
+// GetOrLoad adds stampede protection: concurrent misses of the same key within
+// one TypedCache share a single loader call.
+//
+// Example:
+//
+//	users := yacache.NewTypedCache(cache, yacache.NewMessagePackCodec[User]())
+//	user, err := users.GetOrLoad(ctx, "user:42", time.Minute, func(ctx context.Context) (User, yaerrors.Error) {
+//	    return repo.FindUser(ctx, 42)
+//	})
+type TypedCache[T Container, V any] struct {
+	cache  Cache[T]
+	codec  Codec[V]
+	flight singleflight.Group
+}
+
+// loadResult carries a Loader outcome through singleflight, which only
+// propagates plain errors.
+type loadResult[V any] struct {
+	value V
+	err   yaerrors.Error
+}
+
+// NewTypedCache wraps cache so that values of type V are stored via codec.
+//
+// Example:
+//
+//	users := yacache.NewTypedCache(cache, yacache.NewJSONCodec[User]())
+func NewTypedCache[T Container, V any](cache Cache[T], codec Codec[V]) *TypedCache[T, V] {
+	return &TypedCache[T, V]{
+		cache: cache,
+		codec: codec,
+	}
+}
+
+// Cache returns the wrapped untyped cache.
+func (c *TypedCache[T, V]) Cache() Cache[T] {
+	return c.cache
+}
+
+// Get fetches and decodes the value stored under key. A missing key returns
+// an error matching ErrNotFoundValue.
+//
+// Example:
+//
+//	user, err := users.Get(ctx, "user:42")
+func (c *TypedCache[T, V]) Get(ctx context.Context, key string) (V, yaerrors.Error) {
+	var zero V
+
+	data, err := c.cache.Get(ctx, key)
+	if err != nil {
+		return zero, err.Wrap("[TYPED] failed to get value")
+	}
+
+	value, err := c.codec.Decode(data)
+	if err != nil {
+		return zero, err.Wrap(fmt.Sprintf("[TYPED] failed to decode value by `%s`", key))
+	}
+
+	return value, nil
+}
+
+// Set encodes value and stores it under key with ttl (zero means no expiry).
+//
+// Example:
+//
+//	_ = users.Set(ctx, "user:42", user, time.Minute)
+func (c *TypedCache[T, V]) Set(
+	ctx context.Context,
+	key string,
+	value V,
+	ttl time.Duration,
+) yaerrors.Error {
+	data, err := c.codec.Encode(value)
+	if err != nil {
+		return err.Wrap(fmt.Sprintf("[TYPED] failed to encode value by `%s`", key))
+	}
+
+	if setErr := c.cache.Set(ctx, key, data, ttl); setErr != nil {
+		return setErr.Wrap("[TYPED] failed to set value")
+	}
+
+	return nil
+}
+
+// Del removes key from the cache.
+//
+// Example:
+//
+//	_ = users.Del(ctx, "user:42")
+func (c *TypedCache[T, V]) Del(ctx context.Context, key string) yaerrors.Error {
+	if err := c.cache.Del(ctx, key); err != nil {
+		return err.Wrap("[TYPED] failed to delete value")
+	}
+
+	return nil
+}
+
+// GetOrLoad returns the value cached under key or, on a miss, calls load,
+// stores its result for ttl and returns it.
+//
+// Concurrent misses of the same key share one load call (and the context of
+// the caller that started it); the others wait for its result. Loader errors
+// are returned as-is and nothing is cached. A cached value that no longer
+// decodes is treated as a miss and overwritten. Errors other than a miss, and
+// a failure to store the loaded value, are returned; in the latter case the
+// loaded value is returned alongside the error.
+//
+// Example:
+//
+//	user, err := users.GetOrLoad(ctx, "user:42", time.Minute, func(ctx context.Context) (User, yaerrors.Error) {
+//	    return repo.FindUser(ctx, 42)
+//	})
+func (c *TypedCache[T, V]) GetOrLoad(
+	ctx context.Context,
+	key string,
+	ttl time.Duration,
+	load Loader[V],
+) (V, yaerrors.Error) {
+	value, err := c.Get(ctx, key)
+	if err == nil {
+		return value, nil
+	}
+
+	if !errors.Is(err, ErrNotFoundValue) && !errors.Is(err, ErrFailedToDecodeValue) {
+		return value, err.Wrap("[TYPED] failed to look up value before loading")
+	}
+
+	result, _, _ := c.flight.Do(key, func() (any, error) {
+		loaded, loadErr := load(ctx)
+		if loadErr != nil {
+			return loadResult[V]{value: loaded, err: loadErr}, nil
+		}
+
+		if setErr := c.Set(ctx, key, loaded, ttl); setErr != nil {
+			return loadResult[V]{
+				value: loaded,
+				err:   setErr.Wrap("[TYPED] failed to store loaded value"),
+			}, nil
+		}
+
+		return loadResult[V]{value: loaded}, nil
+	})
+
+	loaded, _ := result.(loadResult[V]) //nolint:errcheck // the function above only returns loadResult[V]
+
+	return loaded.value, loaded.err
+}
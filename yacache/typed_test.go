@@ -0,0 +1,187 @@
+package yacache_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yacache"
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yaerrors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type typedUser struct {
+	ID    uint64   `json:"id"    msgpack:"id"`
+	Name  string   `json:"name"  msgpack:"name"`
+	Roles []string `json:"roles" msgpack:"roles"`
+}
+
+var typedCodecs = map[string]yacache.Codec[typedUser]{
+	"JSON":            yacache.NewJSONCodec[typedUser](),
+	"MessagePack":     yacache.NewMessagePackCodec[typedUser](),
+	"GzipMessagePack": yacache.NewGzipMessagePackCodec[typedUser](nil),
+}
+
+func TestTypedCache_Codecs_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	user := typedUser{ID: 42, Name: "yacodder", Roles: []string{"admin", "dev"}}
+
+	for name, codec := range typedCodecs {
+		t.Run(name+"/Memory", func(t *testing.T) {
+			typed := yacache.NewTypedCache(yacache.NewCache(yacache.NewMemoryContainer()), codec)
+
+			require.Nil(t, typed.Set(ctx, yamainKey, user, yattl))
+
+			got, err := typed.Get(ctx, yamainKey)
+			require.Nil(t, err)
+			assert.Equal(t, user, got)
+		})
+
+		t.Run(name+"/Redis", func(t *testing.T) {
+			client, cleanup := setupTestRedis(t)
+			defer cleanup()
+
+			typed := yacache.NewTypedCache(yacache.NewCache(client), codec)
+
+			require.Nil(t, typed.Set(ctx, yamainKey, user, yattl))
+
+			got, err := typed.Get(ctx, yamainKey)
+			require.Nil(t, err)
+			assert.Equal(t, user, got)
+		})
+	}
+}
+
+func TestTypedCache_Get_Missing(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("[Memory] missing key matches ErrNotFoundValue", func(t *testing.T) {
+		typed := yacache.NewTypedCache(
+			yacache.NewCache(yacache.NewMemoryContainer()),
+			yacache.NewJSONCodec[typedUser](),
+		)
+
+		_, err := typed.Get(ctx, yamainKey)
+		assert.True(t, errors.Is(err, yacache.ErrNotFoundValue))
+	})
+
+	t.Run("[Redis] missing key matches ErrNotFoundValue", func(t *testing.T) {
+		client, cleanup := setupTestRedis(t)
+		defer cleanup()
+
+		typed := yacache.NewTypedCache(yacache.NewCache(client), yacache.NewJSONCodec[typedUser]())
+
+		_, err := typed.Get(ctx, yamainKey)
+		assert.True(t, errors.Is(err, yacache.ErrNotFoundValue))
+	})
+}
+
+func TestTypedCache_GetOrLoad_Works(t *testing.T) {
+	ctx := context.Background()
+	user := typedUser{ID: 7, Name: "loaded"}
+
+	t.Run("[Miss] loads and caches the value", func(t *testing.T) {
+		typed := yacache.NewTypedCache(
+			yacache.NewCache(yacache.NewMemoryContainer()),
+			yacache.NewMessagePackCodec[typedUser](),
+		)
+
+		var calls atomic.Int32
+
+		load := func(context.Context) (typedUser, yaerrors.Error) {
+			calls.Add(1)
+
+			return user, nil
+		}
+
+		for range 3 {
+			got, err := typed.GetOrLoad(ctx, yamainKey, yattl, load)
+			require.Nil(t, err)
+			assert.Equal(t, user, got)
+		}
+
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("[Stampede] concurrent misses share one load", func(t *testing.T) {
+		typed := yacache.NewTypedCache(
+			yacache.NewCache(yacache.NewMemoryContainer()),
+			yacache.NewJSONCodec[typedUser](),
+		)
+
+		var (
+			calls   atomic.Int32
+			wg      sync.WaitGroup
+			release = make(chan struct{})
+		)
+
+		load := func(context.Context) (typedUser, yaerrors.Error) {
+			calls.Add(1)
+			<-release
+
+			return user, nil
+		}
+
+		const callers = 32
+
+		results := make([]typedUser, callers)
+
+		for i := range callers {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				results[i], _ = typed.GetOrLoad(ctx, yamainKey, yattl, load)
+			}()
+		}
+
+		time.Sleep(50 * time.Millisecond)
+		close(release)
+		wg.Wait()
+
+		assert.Equal(t, int32(1), calls.Load())
+
+		for _, result := range results {
+			assert.Equal(t, user, result)
+		}
+	})
+
+	t.Run("[Error] loader errors are returned and not cached", func(t *testing.T) {
+		typed := yacache.NewTypedCache(
+			yacache.NewCache(yacache.NewMemoryContainer()),
+			yacache.NewJSONCodec[typedUser](),
+		)
+
+		_, err := typed.GetOrLoad(ctx, yamainKey, yattl, func(context.Context) (typedUser, yaerrors.Error) {
+			return typedUser{}, yaerrors.FromString(http.StatusNotFound, "no such user")
+		})
+		require.NotNil(t, err)
+		assert.Equal(t, http.StatusNotFound, err.Code())
+
+		exists, _ := typed.Cache().Exists(ctx, yamainKey)
+		assert.False(t, exists)
+	})
+
+	t.Run("[Corrupted] an undecodable value is reloaded", func(t *testing.T) {
+		cache := yacache.NewCache(yacache.NewMemoryContainer())
+		typed := yacache.NewTypedCache(cache, yacache.NewJSONCodec[typedUser]())
+
+		require.Nil(t, cache.Set(ctx, yamainKey, "{not json", yattl))
+
+		got, err := typed.GetOrLoad(ctx, yamainKey, yattl, func(context.Context) (typedUser, yaerrors.Error) {
+			return user, nil
+		})
+		require.Nil(t, err)
+		assert.Equal(t, user, got)
+
+		cached, err := typed.Get(ctx, yamainKey)
+		require.Nil(t, err)
+		assert.Equal(t, user, cached)
+	})
+}
@@ -0,0 +1,103 @@
+package yacache
+
+import (
+	"sync"
+	"time"
+)
+
+// ZMember is a sorted-set member together with its score.
+//
+// Example:
+//
+//	_, _ = cache.ZAdd(ctx, "leaderboard", yacache.ZMember{Member: "alice", Score: 42})
+type ZMember struct {
+	Member string
+	Score  float64
+}
+
+// EvictionPolicy selects which entry a [BoundedMemory] drops once it is over
+// budget.
+type EvictionPolicy uint8
+
+const (
+	// EvictLRU drops the least recently used entry.
+	EvictLRU EvictionPolicy = iota
+	// EvictLFU drops the least frequently used entry, the least recently used
+	// one among equally frequent entries.
+	EvictLFU
+)
+
+// BoundedMemoryConfig configures [NewBoundedMemory]. Zero fields fall back to
+// "unlimited" for budgets and to the package defaults otherwise.
+type BoundedMemoryConfig struct {
+	// MaxEntries caps the number of entries (plain keys plus whole hashes).
+	MaxEntries int64
+	// MaxBytes caps the payload size: the sum of key, field, value and member
+	// lengths, plus eight bytes per sorted-set score.
+	MaxBytes int64
+	// Policy picks the victim once a budget is exceeded.
+	Policy EvictionPolicy
+	// Shards is the number of independently locked partitions.
+	Shards int
+	// SweepInterval is how often expired entries are purged in the background.
+	SweepInterval time.Duration
+}
+
+// BoundedMemoryStats is a point‑in‑time snapshot of [BoundedMemory] counters.
+type BoundedMemoryStats struct {
+	// Hits and Misses count value lookups (Get, MGet per key, GetDel, HGet,
+	// HGetAll, HGetDelSingle).
+	Hits   uint64
+	Misses uint64
+	// Evictions counts entries dropped to stay within budget.
+	Evictions uint64
+	// Expirations counts values dropped because their TTL elapsed.
+	Expirations uint64
+	// Entries and Bytes report the current usage measured against the budget.
+	Entries int64
+	Bytes   int64
+}
+
+// boundedShard is one independently locked partition of a BoundedMemory.
+type boundedShard struct {
+	mutex      sync.Mutex
+	values     map[string]*boundedEntry
+	hashes     map[string]*boundedEntry
+	lists      map[string]*boundedEntry
+	sortedSets map[string]*boundedEntry
+	eviction   evictionHeap
+	expiry     expiryHeap
+}
+
+// boundedEntry is the unit of eviction: a plain value, a whole hash, a whole
+// list or a whole sorted set.
+type boundedEntry struct {
+	key       string
+	kind      memoryKind
+	value     *boundedValue            // every kind but hashes; carries the deadline
+	fields    map[string]*boundedValue // hash entries only
+	list      []string                 // list entries only
+	scores    map[string]float64       // sorted set entries only
+	size      int64
+	frequency uint64
+	tick      uint64
+	index     int // position in the shard's eviction heap
+}
+
+// boundedValue is a single stored string with its own deadline.
+type boundedValue struct {
+	entry     *boundedEntry
+	field     string
+	value     string
+	expiresAt time.Time // zero → no expiry
+	index     int       // position in the shard's expiry heap, -1 when unscheduled
+}
+
+// evictionHeap orders a shard's entries so that the next victim is on top.
+type evictionHeap struct {
+	entries []*boundedEntry
+	lfu     bool
+}
+
+// expiryHeap orders a shard's expiring values by deadline, earliest on top.
+type expiryHeap []*boundedValue
@@ -1,9 +1,24 @@
 package yacache
 
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yaerrors"
+)
+
 // safetyCheck ensures that the internal maps are initialized before any operation touches them.
 func (m *Memory) safetyCheck() {
 	m.mutex.RLock()
-	initialized := m.inner.HMap != nil && m.inner.Map != nil
+	initialized := m.inner.HMap != nil && m.inner.Map != nil &&
+		m.inner.Lists != nil && m.inner.SortedSets != nil
 	m.mutex.RUnlock()
 
 	if initialized {
@@ -20,4 +35,296 @@ func (m *Memory) safetyCheck() {
 	if m.inner.Map == nil {
 		m.inner.Map = make(map[string]*memoryCacheItem)
 	}
+
+	if m.inner.Lists == nil {
+		m.inner.Lists = make(map[string]*memoryListItem)
+	}
+
+	if m.inner.SortedSets == nil {
+		m.inner.SortedSets = make(map[string]*memorySortedSetItem)
+	}
+}
+
+// addInt64 returns value+delta, or false when the sum would overflow.
+func addInt64(value, delta int64) (int64, bool) {
+	if (delta > 0 && value > math.MaxInt64-delta) || (delta < 0 && value < math.MinInt64-delta) {
+		return 0, false
+	}
+
+	return value + delta, true
+}
+
+// incrementString applies IncrBy semantics to a stored string value.
+func incrementString(backend, key, value string, delta int64) (int64, yaerrors.Error) {
+	current, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, yaerrors.FromError(
+			http.StatusInternalServerError,
+			errors.Join(err, ErrValueNotInteger, ErrFailedToIncr),
+			fmt.Sprintf("[%s] failed `INCRBY` by `%s`", backend, key),
+		)
+	}
+
+	result, ok := addInt64(current, delta)
+	if !ok {
+		return 0, yaerrors.FromError(
+			http.StatusInternalServerError,
+			errors.Join(ErrValueNotInteger, ErrFailedToIncr),
+			fmt.Sprintf("[%s] `INCRBY` by `%s` would overflow", backend, key),
+		)
+	}
+
+	return result, nil
+}
+
+// wrongTypeError reports a command issued against a key holding another kind
+// of value, joined with the command's own sentinel.
+func wrongTypeError(backend, command, key string, sentinel error) yaerrors.Error {
+	return yaerrors.FromError(
+		http.StatusInternalServerError,
+		errors.Join(ErrWrongType, sentinel),
+		fmt.Sprintf(
+			"[%s] `%s` against a key holding another kind of value by `%s`",
+			backend,
+			command,
+			key,
+		),
+	)
+}
+
+// notFoundError reports a command issued against a missing key.
+func notFoundError(backend, command, key string, sentinel error) yaerrors.Error {
+	return yaerrors.FromError(
+		http.StatusInternalServerError,
+		errors.Join(ErrNotFoundValue, sentinel),
+		fmt.Sprintf("[%s] `%s` found no value by `%s`", backend, command, key),
+	)
+}
+
+// replyError joins a Redis error reply with the portable sentinel it maps
+// to, so callers can match WRONGTYPE and integer errors on every backend.
+func replyError(err error) error {
+	message := err.Error()
+
+	switch {
+	case strings.HasPrefix(message, "WRONGTYPE"):
+		return errors.Join(err, ErrWrongType)
+	case strings.Contains(message, "not an integer"), strings.Contains(message, "overflow"):
+		return errors.Join(err, ErrValueNotInteger)
+	default:
+		return err
+	}
+}
+
+// formatScore renders a sorted-set bound the way ZRANGEBYSCORE expects it.
+func formatScore(score float64) string {
+	switch {
+	case math.IsInf(score, 1):
+		return "+inf"
+	case math.IsInf(score, -1):
+		return "-inf"
+	default:
+		return strconv.FormatFloat(score, 'g', -1, 64)
+	}
+}
+
+// listBounds converts LRANGE‑style inclusive indexes, which may be negative,
+// into a half‑open slice range over a list of length. ok is false when the
+// range is empty.
+func listBounds(length int, start, stop int64) (from, to int, ok bool) {
+	size := int64(length)
+
+	if start < 0 {
+		start += size
+	}
+
+	if stop < 0 {
+		stop += size
+	}
+
+	start = max(start, 0)
+	stop = min(stop, size-1)
+
+	if start > stop {
+		return 0, 0, false
+	}
+
+	return int(start), int(stop) + 1, true
+}
+
+// rangeByScore returns the members of scores within [minScore, maxScore],
+// ordered by score and then by member like a Redis sorted set.
+func rangeByScore(scores map[string]float64, minScore, maxScore float64) []ZMember {
+	result := make([]ZMember, 0)
+
+	for member, score := range scores {
+		if score >= minScore && score <= maxScore {
+			result = append(result, ZMember{Member: member, Score: score})
+		}
+	}
+
+	slices.SortFunc(result, func(left, right ZMember) int {
+		if order := cmp.Compare(left.Score, right.Score); order != 0 {
+			return order
+		}
+
+		return strings.Compare(left.Member, right.Member)
+	})
+
+	return result
+}
+
+// pushValues returns list with values pushed at the head (one after another,
+// like LPUSH) or appended at the tail.
+func pushValues(list []string, head bool, values []string) []string {
+	if !head {
+		return append(list, values...)
+	}
+
+	reversed := slices.Clone(values)
+	slices.Reverse(reversed)
+
+	return append(reversed, list...)
+}
+
+// toArgs converts strings into the variadic arguments go-redis expects.
+func toArgs(values []string) []any {
+	args := make([]any, len(values))
+
+	for i, value := range values {
+		args[i] = value
+	}
+
+	return args
+}
+
+// emptyPrefixError rejects a DelPrefix that would match every key.
+func emptyPrefixError(backend string) yaerrors.Error {
+	return yaerrors.FromError(
+		http.StatusBadRequest,
+		errors.Join(ErrEmptyPrefix, ErrFailedToDelPrefix),
+		fmt.Sprintf("[%s] refused to delete keys by an empty prefix", backend),
+	)
+}
+
+// escapeGlob escapes the Redis glob metacharacters in literal.
+func escapeGlob(literal string) string {
+	var builder strings.Builder
+
+	for _, char := range literal {
+		switch char {
+		case '*', '?', '[', ']', '\\':
+			builder.WriteRune('\\')
+		}
+
+		builder.WriteRune(char)
+	}
+
+	return builder.String()
+}
+
+// remaining converts an absolute deadline into the TTL left at now; ok is
+// false once the deadline has passed. A zero deadline means no expiry.
+func remaining(expiresAt time.Time, now time.Time) (ttl time.Duration, ok bool) {
+	if expiresAt.IsZero() {
+		return 0, true
+	}
+
+	ttl = expiresAt.Sub(now)
+
+	return ttl, ttl > 0
+}
+
+// deadline converts a relative ttl into an absolute deadline; a non‑positive
+// ttl means no deadline.
+func deadline(now time.Time, ttl time.Duration) time.Time {
+	if ttl <= 0 {
+		return time.Time{}
+	}
+
+	return now.Add(ttl)
+}
+
+// victim returns the entry to evict next, skipping protected.
+func (h *evictionHeap) victim(protected *boundedEntry) *boundedEntry {
+	if len(h.entries) == 0 {
+		return nil
+	}
+
+	if h.entries[0] != protected {
+		return h.entries[0]
+	}
+
+	// The runner-up of a binary heap is one of the root's children.
+	var runnerUp *boundedEntry
+
+	for _, child := range h.entries[1:min(len(h.entries), 3)] {
+		if runnerUp == nil || h.before(child, runnerUp) {
+			runnerUp = child
+		}
+	}
+
+	return runnerUp
+}
+
+// before reports whether left should be evicted before right.
+func (h *evictionHeap) before(left, right *boundedEntry) bool {
+	if h.lfu && left.frequency != right.frequency {
+		return left.frequency < right.frequency
+	}
+
+	return left.tick < right.tick
+}
+
+func (h *evictionHeap) Len() int { return len(h.entries) }
+
+func (h *evictionHeap) Less(i, j int) bool { return h.before(h.entries[i], h.entries[j]) }
+
+func (h *evictionHeap) Swap(i, j int) {
+	h.entries[i], h.entries[j] = h.entries[j], h.entries[i]
+	h.entries[i].index = i
+	h.entries[j].index = j
+}
+
+func (h *evictionHeap) Push(value any) {
+	entry, _ := value.(*boundedEntry) //nolint:errcheck // only *boundedEntry is ever pushed
+	entry.index = len(h.entries)
+	h.entries = append(h.entries, entry)
+}
+
+func (h *evictionHeap) Pop() any {
+	last := len(h.entries) - 1
+	entry := h.entries[last]
+	h.entries[last] = nil
+	h.entries = h.entries[:last]
+	entry.index = -1
+
+	return entry
+}
+
+func (h *expiryHeap) Len() int { return len(*h) }
+
+func (h *expiryHeap) Less(i, j int) bool { return (*h)[i].expiresAt.Before((*h)[j].expiresAt) }
+
+func (h *expiryHeap) Swap(i, j int) {
+	(*h)[i], (*h)[j] = (*h)[j], (*h)[i]
+	(*h)[i].index = i
+	(*h)[j].index = j
+}
+
+func (h *expiryHeap) Push(value any) {
+	stored, _ := value.(*boundedValue) //nolint:errcheck // only *boundedValue is ever pushed
+	stored.index = len(*h)
+	*h = append(*h, stored)
+}
+
+func (h *expiryHeap) Pop() any {
+	old := *h
+	last := len(old) - 1
+	stored := old[last]
+	old[last] = nil
+	*h = old[:last]
+	stored.index = -1
+
+	return stored
 }
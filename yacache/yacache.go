@@ -60,6 +60,7 @@ package yacache
 
 import (
 	"context"
+	"iter"
 	"time"
 
 	"github.com/YaCodeDev/GoYaCodeDevUtils/yaerrors"
@@ -191,7 +192,8 @@ type Cache[T Container] interface {
 	) yaerrors.Error
 
 	// Get retrieves the value previously saved under key.
-	// If the key is missing, an implementation-specific yaerrors.Error is returned.
+	// If the key is missing, the returned yaerrors.Error matches ErrNotFoundValue
+	// (as well as ErrFailedToGetValue) under errors.Is.
 	//
 	// Example:
 	//
@@ -267,6 +269,27 @@ type Cache[T Container] interface {
 		key ...string,
 	) (bool, yaerrors.Error)
 
+	// CompareAndSwap atomically replaces the value stored under key with value
+	// (applying ttl exactly like Set) only if the current value equals
+	// expected. A missing key compares equal to the empty string, so an empty
+	// expected value turns the call into "set if absent" for keys that never
+	// hold an empty string. It reports whether the swap happened.
+	//
+	// Example:
+	//
+	//	ctx := context.Background()
+	//	ok, _ := c.CompareAndSwap(ctx, "counter", "1", "2", time.Minute)
+	//	if !ok {
+	//	    // somebody else changed "counter" first – reload and retry
+	//	}
+	CompareAndSwap(
+		ctx context.Context,
+		key string,
+		expected string,
+		value string,
+		ttl time.Duration,
+	) (bool, yaerrors.Error)
+
 	// Del unconditionally removes key from the cache.
 	// The operation is idempotent: deleting a non-existent key is not an error.
 	//
@@ -279,6 +302,206 @@ type Cache[T Container] interface {
 		key string,
 	) yaerrors.Error
 
+	// IncrBy atomically adds delta to the base‑10 integer stored under key and
+	// returns the result. A missing key counts as 0 and is created without
+	// expiry; an existing key keeps its TTL. A value that is not an int64, or a
+	// result that would overflow, fails with an error matching
+	// ErrValueNotInteger; a key holding a list or sorted set fails with
+	// ErrWrongType.
+	//
+	// Example:
+	//
+	//	views, _ := c.IncrBy(ctx, "views:42", 10)
+	IncrBy(
+		ctx context.Context,
+		key string,
+		delta int64,
+	) (int64, yaerrors.Error)
+
+	// Incr is IncrBy with a delta of 1.
+	//
+	// Example:
+	//
+	//	views, _ := c.Incr(ctx, "views:42")
+	Incr(
+		ctx context.Context,
+		key string,
+	) (int64, yaerrors.Error)
+
+	// LPush inserts values at the head of the list under key one after
+	// another, so the last value ends up first, and returns the new length.
+	// A missing key becomes a list without expiry; a key holding another kind
+	// of value fails with ErrWrongType.
+	//
+	// Example:
+	//
+	//	length, _ := c.LPush(ctx, "jobs", "a", "b") // jobs = [b a]
+	LPush(
+		ctx context.Context,
+		key string,
+		values ...string,
+	) (int64, yaerrors.Error)
+
+	// RPush appends values to the tail of the list under key and returns the
+	// new length. Otherwise it behaves like LPush.
+	//
+	// Example:
+	//
+	//	length, _ := c.RPush(ctx, "jobs", "a", "b") // jobs = [a b]
+	RPush(
+		ctx context.Context,
+		key string,
+		values ...string,
+	) (int64, yaerrors.Error)
+
+	// LPop removes and returns the first element of the list under key. A
+	// missing key fails with an error matching ErrNotFoundValue. A list
+	// emptied by a pop is deleted.
+	//
+	// Example:
+	//
+	//	job, _ := c.LPop(ctx, "jobs")
+	LPop(
+		ctx context.Context,
+		key string,
+	) (string, yaerrors.Error)
+
+	// RPop removes and returns the last element of the list under key.
+	// Otherwise it behaves like LPop.
+	//
+	// Example:
+	//
+	//	job, _ := c.RPop(ctx, "jobs")
+	RPop(
+		ctx context.Context,
+		key string,
+	) (string, yaerrors.Error)
+
+	// LRange returns the elements between the start and stop indexes, both
+	// inclusive. Negative indexes count from the tail (-1 is the last
+	// element) and out‑of‑range indexes are clamped, so LRange(ctx, key, 0, -1)
+	// returns the whole list. A missing key yields an empty slice.
+	//
+	// Example:
+	//
+	//	latest, _ := c.LRange(ctx, "events", 0, 9)
+	LRange(
+		ctx context.Context,
+		key string,
+		start int64,
+		stop int64,
+	) ([]string, yaerrors.Error)
+
+	// ZAdd adds members to the sorted set under key, updating the score of
+	// members already present, and returns how many members were new. A
+	// missing key becomes a sorted set without expiry; a key holding another
+	// kind of value fails with ErrWrongType.
+	//
+	// Example:
+	//
+	//	_, _ = c.ZAdd(ctx, "leaderboard", yacache.ZMember{Member: "alice", Score: 42})
+	ZAdd(
+		ctx context.Context,
+		key string,
+		members ...ZMember,
+	) (int64, yaerrors.Error)
+
+	// ZRangeByScore returns the members whose score lies within
+	// [minScore, maxScore], ordered by score and then by member. Pass
+	// math.Inf(-1) / math.Inf(1) for an open bound. A missing key yields an
+	// empty slice.
+	//
+	// Example:
+	//
+	//	top, _ := c.ZRangeByScore(ctx, "leaderboard", 100, math.Inf(1))
+	ZRangeByScore(
+		ctx context.Context,
+		key string,
+		minScore float64,
+		maxScore float64,
+	) ([]ZMember, yaerrors.Error)
+
+	// ZRem removes members from the sorted set under key and returns how many
+	// were present. A sorted set emptied this way is deleted.
+	//
+	// Example:
+	//
+	//	_, _ = c.ZRem(ctx, "leaderboard", "alice")
+	ZRem(
+		ctx context.Context,
+		key string,
+		members ...string,
+	) (int64, yaerrors.Error)
+
+	// Scan iterates over the keys starting with prefix, of every kind (plain
+	// values, hashes, lists and sorted sets); an empty prefix matches every
+	// key. Iteration is lazy, so keys may be deleted while scanning. On Redis
+	// it walks the keyspace with a cursor‑based SCAN (never KEYS), which may
+	// yield a key more than once if the keyspace is rehashed meanwhile. A
+	// failure is yielded once as the error of the last pair.
+	//
+	// Example:
+	//
+	//	for key, err := range c.Scan(ctx, "fsm:42:") {
+	//	    if err != nil {
+	//	        return err
+	//	    }
+	//	    fmt.Println(key)
+	//	}
+	Scan(
+		ctx context.Context,
+		prefix string,
+	) iter.Seq2[string, yaerrors.Error]
+
+	// DelPrefix deletes every key starting with prefix, whatever its kind,
+	// and returns how many were deleted. An empty prefix is rejected with
+	// ErrEmptyPrefix rather than wiping the whole cache.
+	//
+	// Example:
+	//
+	//	deleted, _ := c.DelPrefix(ctx, "user:42:")
+	DelPrefix(
+		ctx context.Context,
+		prefix string,
+	) (int64, yaerrors.Error)
+
+	// TTL returns the time key has left to live, or 0 when it never expires.
+	// A missing key fails with an error matching ErrNotFoundValue. On memory
+	// backends the TTL of a hash is that of its longest‑lived field, and 0 as
+	// soon as one field never expires.
+	//
+	// Example:
+	//
+	//	ttl, _ := c.TTL(ctx, "session:42")
+	TTL(
+		ctx context.Context,
+		key string,
+	) (time.Duration, yaerrors.Error)
+
+	// Expire sets the TTL of an existing key and reports whether the key
+	// exists. A non‑positive ttl deletes the key, as in Redis. On memory
+	// backends expiring a hash applies ttl to each of its fields.
+	//
+	// Example:
+	//
+	//	ok, _ := c.Expire(ctx, "session:42", time.Hour)
+	Expire(
+		ctx context.Context,
+		key string,
+		ttl time.Duration,
+	) (bool, yaerrors.Error)
+
+	// Persist removes the TTL of key and reports whether there was one to
+	// remove (false for a missing or already persistent key).
+	//
+	// Example:
+	//
+	//	ok, _ := c.Persist(ctx, "session:42")
+	Persist(
+		ctx context.Context,
+		key string,
+	) (bool, yaerrors.Error)
+
 	// Ping verifies that the cache service is reachable and healthy.
 	//
 	// Example:
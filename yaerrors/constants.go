@@ -0,0 +1,20 @@
+package yaerrors
+
+// Log field keys written by LogFields.
+const (
+	KeyErrorCode  = "error_code"
+	KeyErrorStack = "error_stack"
+)
+
+// RFC 7807 problem details defaults.
+const (
+	ProblemContentType = "application/problem+json"
+	DefaultProblemType = "about:blank"
+)
+
+// maxStackDepth is the maximum number of frames captured per error.
+const maxStackDepth = 32
+
+// stackCaptureSkip skips runtime.Callers, captureStack and the constructor, so the
+// captured stack starts at the constructor's caller.
+const stackCaptureSkip = 3
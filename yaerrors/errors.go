@@ -6,3 +6,14 @@ import "errors"
 // they are dereferencing a nil error.
 // This error is used as a safety measure to prevent nil pointer dereference.
 var ErrTeapot = errors.New("backend developer is a teapot")
+
+// ErrEmptyErrorCode is returned when registering a Definition without an error code.
+var ErrEmptyErrorCode = errors.New("error code is empty")
+
+// ErrInvalidStatus is returned when registering a Definition whose status is not a
+// valid HTTP status code.
+var ErrInvalidStatus = errors.New("status is not a valid HTTP status code")
+
+// ErrDuplicateErrorCode is returned when registering an error code that is already
+// registered.
+var ErrDuplicateErrorCode = errors.New("error code is already registered")
@@ -0,0 +1,56 @@
+package yaerrors
+
+import "net/http"
+
+// Problem is an RFC 7807 problem details object. ErrorCode is an extension member
+// carrying the stable error code, so clients can branch on it without parsing Type.
+// Error fields are not part of it: they are meant for logs and may hold internals.
+type Problem struct {
+	Type      string `json:"type"`
+	Title     string `json:"title"`
+	Status    int    `json:"status"`
+	Detail    string `json:"detail,omitempty"`
+	Instance  string `json:"instance,omitempty"`
+	ErrorCode string `json:"code,omitempty"`
+}
+
+// NewProblem describes err as an RFC 7807 problem. Type and Title come from the
+// registered Definition of err's error code; errors without one are reported as
+// DefaultProblemType with the standard text of their status.
+//
+// Parameters:
+//
+//   - err: the error to describe. Its Code is the status and its UnwrapLastError the detail.
+//   - instance: a URI reference identifying this occurrence, usually the request path.
+//     Empty omits it.
+//
+// Example usage:
+//
+//	ctx.Header("Content-Type", yaerrors.ProblemContentType)
+//	ctx.JSON(err.Code(), yaerrors.NewProblem(err, ctx.Request.URL.Path))
+func NewProblem(err Error, instance string) Problem {
+	if err == nil {
+		err = FromString(http.StatusTeapot, ErrTeapot.Error())
+	}
+
+	problem := Problem{
+		Type:      DefaultProblemType,
+		Title:     http.StatusText(err.Code()),
+		Status:    err.Code(),
+		Detail:    err.UnwrapLastError(),
+		Instance:  instance,
+		ErrorCode: err.ErrorCode(),
+	}
+
+	if definition, ok := Lookup(problem.ErrorCode); ok {
+		if definition.Type != "" {
+			problem.Type = definition.Type
+		}
+
+		if definition.Title != "" {
+			problem.Title = definition.Title
+		}
+	}
+
+	return problem
+}
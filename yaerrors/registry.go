@@ -0,0 +1,153 @@
+package yaerrors
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+)
+
+// Definition describes a well-known error: a stable error code with the HTTP status
+// and the RFC 7807 problem type and title it is reported with. Declare definitions
+// once per service with MustRegister and create errors from them with New or FromError.
+//
+// Example usage:
+//
+//	var ErrTokenExpired = yaerrors.MustRegister(yaerrors.Definition{
+//	    ErrorCode: "auth.token_expired",
+//	    Status:    http.StatusUnauthorized,
+//	    Title:     "Access token expired",
+//	})
+//
+//	return ErrTokenExpired.New("token expired 5 minutes ago")
+type Definition struct {
+	// ErrorCode is the stable machine-readable code, such as "auth.token_expired".
+	ErrorCode string
+	// Status is the HTTP status code errors of this definition carry.
+	Status int
+	// Title is a short, human-readable summary that does not change between
+	// occurrences. Empty means the standard text of Status.
+	Title string
+	// Type is a URI reference identifying the problem type. Empty means
+	// DefaultProblemType.
+	Type string
+}
+
+// definitions is the process-wide registry of well-known errors.
+var definitions = struct {
+	mu     sync.RWMutex
+	byCode map[string]Definition
+}{
+	byCode: make(map[string]Definition),
+}
+
+// Register adds definition to the registry of well-known errors, so NewProblem can
+// describe errors carrying its error code.
+//
+// Parameters:
+//
+//   - definition: the error to register. ErrorCode must be unique and non-empty and
+//     Status must be a 4xx or 5xx HTTP status code.
+//
+// Returns:
+//
+//   - Error: ErrEmptyErrorCode, ErrInvalidStatus or ErrDuplicateErrorCode, or nil on success.
+func Register(definition Definition) Error {
+	if definition.ErrorCode == "" {
+		return FromError(http.StatusBadRequest, ErrEmptyErrorCode, "register error definition")
+	}
+
+	if definition.Status < http.StatusBadRequest ||
+		definition.Status > http.StatusNetworkAuthenticationRequired {
+		return FromError(
+			http.StatusBadRequest,
+			ErrInvalidStatus,
+			fmt.Sprintf(
+				"register error definition %q with status %d",
+				definition.ErrorCode,
+				definition.Status,
+			),
+		)
+	}
+
+	definitions.mu.Lock()
+	defer definitions.mu.Unlock()
+
+	if _, exists := definitions.byCode[definition.ErrorCode]; exists {
+		return FromError(
+			http.StatusConflict,
+			ErrDuplicateErrorCode,
+			fmt.Sprintf("register error definition %q", definition.ErrorCode),
+		)
+	}
+
+	definitions.byCode[definition.ErrorCode] = definition
+
+	return nil
+}
+
+// MustRegister behaves like Register but panics when definition cannot be registered.
+// It is meant for package-level variable declarations, where a broken definition is a
+// programming error.
+func MustRegister(definition Definition) Definition {
+	if err := Register(definition); err != nil {
+		panic(err.Error())
+	}
+
+	return definition
+}
+
+// Lookup returns the registered definition of errorCode.
+func Lookup(errorCode string) (Definition, bool) {
+	definitions.mu.RLock()
+	defer definitions.mu.RUnlock()
+
+	definition, ok := definitions.byCode[errorCode]
+
+	return definition, ok
+}
+
+// Definitions returns every registered definition, sorted by error code.
+func Definitions() []Definition {
+	definitions.mu.RLock()
+	defer definitions.mu.RUnlock()
+
+	registered := make([]Definition, 0, len(definitions.byCode))
+	for _, definition := range definitions.byCode {
+		registered = append(registered, definition)
+	}
+
+	slices.SortFunc(registered, func(a, b Definition) int {
+		return cmp.Compare(a.ErrorCode, b.ErrorCode)
+	})
+
+	return registered
+}
+
+// New creates an Error of this definition, carrying its status and error code.
+// detail describes this occurrence and becomes the error message.
+func (d Definition) New(detail string) Error {
+	return &yaError{
+		code:      d.Status,
+		errorCode: d.ErrorCode,
+		stack:     captureStack(),
+		cause: errors.New( //nolint:err113 // This is error constructor, error is not from library, no constants here
+			detail,
+		),
+		traceback: detail,
+	}
+}
+
+// FromError creates an Error of this definition wrapping cause, like the package-level
+// FromError. The error code of the definition replaces the one of cause.
+func (d Definition) FromError(cause error, wrap string) Error {
+	return inherit(&yaError{
+		code:      d.Status,
+		errorCode: d.ErrorCode,
+		stack:     captureStack(),
+		cause:     cause,
+		traceback: fmt.Sprintf("%s: %v", wrap, cause),
+	})
+}
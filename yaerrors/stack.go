@@ -0,0 +1,106 @@
+package yaerrors
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"runtime"
+	"sync/atomic"
+)
+
+// stackCapture reports whether constructors capture the call stack.
+var stackCapture atomic.Bool
+
+// SetStackCapture enables or disables call stack capture for errors created from
+// now on. Capture is disabled by default because it costs a runtime.Callers call
+// per error; enable it in development or while chasing a specific failure.
+//
+// Example usage:
+//
+//	yaerrors.SetStackCapture(cfg.Debug)
+func SetStackCapture(enabled bool) {
+	stackCapture.Store(enabled)
+}
+
+// StackCaptureEnabled reports whether new errors capture the call stack.
+func StackCaptureEnabled() bool {
+	return stackCapture.Load()
+}
+
+// captureStack returns the program counters of the constructor's caller and up,
+// or nil when stack capture is disabled.
+func captureStack() []uintptr {
+	if !stackCapture.Load() {
+		return nil
+	}
+
+	pcs := make([]uintptr, maxStackDepth)
+
+	return pcs[:runtime.Callers(stackCaptureSkip, pcs)]
+}
+
+// inherit copies the error code, fields and stack of a yaerrors.Error cause into e,
+// so wrapping an error with FromError keeps its details. Fields already set on e win.
+func inherit(e *yaError) *yaError {
+	var inner *yaError
+	if !errors.As(e.cause, &inner) || inner == nil {
+		return e
+	}
+
+	if e.errorCode == "" {
+		e.errorCode = inner.errorCode
+	}
+
+	if len(inner.stack) > 0 {
+		e.stack = inner.stack
+	}
+
+	if len(inner.fields) > 0 {
+		fields := maps.Clone(inner.fields)
+		maps.Copy(fields, e.fields)
+		e.fields = fields
+	}
+
+	return e
+}
+
+// LogFields returns the structured log fields describing err: its attached fields,
+// its error code under KeyErrorCode and its captured stack under KeyErrorStack as
+// "function file:line" lines. It returns nil when err has none of them.
+//
+// Example usage:
+//
+//	log.WithFields(yaerrors.LogFields(err)).Warn(err.UnwrapLastError())
+func LogFields(err Error) map[string]any {
+	if err == nil {
+		return nil
+	}
+
+	fields := err.Fields()
+
+	errorCode := err.ErrorCode()
+	stack := err.Stack()
+
+	if errorCode == "" && len(stack) == 0 {
+		return fields
+	}
+
+	if fields == nil {
+		fields = make(map[string]any)
+	}
+
+	if errorCode != "" {
+		fields[KeyErrorCode] = errorCode
+	}
+
+	if len(stack) > 0 {
+		lines := make([]string, 0, len(stack))
+		for _, frame := range stack {
+			lines = append(lines, fmt.Sprintf("%s %s:%d", frame.Function, frame.File, frame.Line))
+		}
+
+		fields[KeyErrorStack] = lines
+	}
+
+	return fields
+}
@@ -3,7 +3,9 @@ package yaerrors
 import (
 	"errors"
 	"fmt"
+	"maps"
 	"net/http"
+	"runtime"
 	"strings"
 
 	"github.com/YaCodeDev/GoYaCodeDevUtils/yalogger"
@@ -21,6 +23,12 @@ type Error interface {
 	Wrap(msg string) Error
 	WrapWithLog(msg string, log yalogger.Logger) Error
 	Code() int
+	ErrorCode() string
+	WithErrorCode(errorCode string) Error
+	WithField(key string, value any) Error
+	WithFields(fields map[string]any) Error
+	Fields() map[string]any
+	Stack() []runtime.Frame
 	Error() string
 	Unwrap() error
 	UnwrapLastError() string
@@ -36,18 +44,23 @@ const (
 // Minimal error implementation for Error interface.
 type yaError struct {
 	code      int
+	errorCode string
+	fields    map[string]any
+	stack     []uintptr
 	cause     error
 	traceback string
 }
 
 // Generates a new Error from an existing error with a custom code and message.
 // It wraps the original error with additional context and returns a new Error instance.
+// When cause is itself a yaerrors.Error, its error code, fields and stack are kept.
 func FromError(code int, cause error, wrap string) Error {
-	return &yaError{
+	return inherit(&yaError{
 		code:      code,
+		stack:     captureStack(),
 		cause:     cause,
 		traceback: fmt.Sprintf("%s: %v", wrap, cause),
-	}
+	})
 }
 
 // Generates a new Error from an existing error with a custom code and message.
@@ -57,18 +70,20 @@ func FromErrorWithLog(code int, cause error, wrap string, log yalogger.Logger) E
 	msg := fmt.Sprintf("%s: %v", wrap, cause)
 	log.Error(msg)
 
-	return &yaError{
+	return inherit(&yaError{
 		code:      code,
+		stack:     captureStack(),
 		cause:     cause,
 		traceback: msg,
-	}
+	})
 }
 
 // Generates a new Error from a string message with a custom code.
 // It creates a new Error instance with the provided code and message.
 func FromString(code int, msg string) Error {
 	return &yaError{
-		code: code,
+		code:  code,
+		stack: captureStack(),
 		cause: errors.New( //nolint:err113 // This is error constructor, error is not from library, no constants here
 			msg,
 		),
@@ -83,7 +98,8 @@ func FromStringWithLog(code int, msg string, log yalogger.Logger) Error {
 	log.Error(msg)
 
 	return &yaError{
-		code: code,
+		code:  code,
+		stack: captureStack(),
 		cause: errors.New( //nolint:err113 // This is error constructor, error is not from library, no constants here
 			msg,
 		),
@@ -132,8 +148,15 @@ func (e *yaError) Wrap(msg string) Error {
 // Wrap adds a message to the error traceback, providing additional context.
 // It is highly recommended to use this method each time you return the error
 // to a higher level in the call stack.
-// It also logs the error message using the provided logger.
+// It also logs the error message using the provided logger, with the fields
+// returned by LogFields attached as structured log fields.
 func (e *yaError) WrapWithLog(msg string, log yalogger.Logger) Error {
+	safetyCheck(&e)
+
+	if fields := LogFields(e); len(fields) > 0 {
+		log = log.WithFields(fields)
+	}
+
 	log.Error(msg)
 
 	return e.Wrap(msg)
@@ -146,12 +169,91 @@ func (e *yaError) Code() int {
 	return e.code
 }
 
+// ErrorCode returns the stable machine-readable error code, such as
+// "auth.token_expired", or an empty string when none was set.
+func (e *yaError) ErrorCode() string {
+	safetyCheck(&e)
+
+	return e.errorCode
+}
+
+// WithErrorCode sets the stable machine-readable error code. Clients and log
+// queries match on it instead of the message, so keep it unchanged once published.
+func (e *yaError) WithErrorCode(errorCode string) Error {
+	safetyCheck(&e)
+	e.errorCode = errorCode
+
+	return e
+}
+
+// WithField attaches a key-value detail to the error. Fields are written as
+// structured log fields by WrapWithLog and are never sent to clients.
+func (e *yaError) WithField(key string, value any) Error {
+	safetyCheck(&e)
+
+	if e.fields == nil {
+		e.fields = make(map[string]any, 1)
+	}
+
+	e.fields[key] = value
+
+	return e
+}
+
+// WithFields attaches several key-value details to the error, overwriting
+// fields with the same keys.
+func (e *yaError) WithFields(fields map[string]any) Error {
+	safetyCheck(&e)
+
+	if len(fields) == 0 {
+		return e
+	}
+
+	if e.fields == nil {
+		e.fields = make(map[string]any, len(fields))
+	}
+
+	maps.Copy(e.fields, fields)
+
+	return e
+}
+
+// Fields returns a copy of the fields attached to the error, or nil when none are.
+func (e *yaError) Fields() map[string]any {
+	safetyCheck(&e)
+
+	return maps.Clone(e.fields)
+}
+
+// Stack returns the call stack captured when the error was created, innermost
+// frame first, or nil when stack capture was disabled at that time.
+// See SetStackCapture.
+func (e *yaError) Stack() []runtime.Frame {
+	safetyCheck(&e)
+
+	if len(e.stack) == 0 {
+		return nil
+	}
+
+	frames := runtime.CallersFrames(e.stack)
+	stack := make([]runtime.Frame, 0, len(e.stack))
+
+	for {
+		frame, more := frames.Next()
+		stack = append(stack, frame)
+
+		if !more {
+			return stack
+		}
+	}
+}
+
 // Is reports whether target represents the same logical error as e.
 //
 // If target is itself a yaerrors.Error, e and target are considered equal
-// when they carry the same Code and their causes satisfy errors.Is against
-// each other. Otherwise Is falls back to IsError, matching target against
-// e's own cause chain.
+// when both carry the same non-empty ErrorCode, or else when they carry the
+// same Code and their causes satisfy errors.Is against each other. Otherwise
+// Is falls back to IsError, matching target against e's own cause chain.
 //
 // Is satisfies the standard library's errors.Is contract
 // (interface{ Is(error) bool }), so calling errors.Is(e, target) from any
@@ -164,6 +266,10 @@ func (e *yaError) Is(target error) bool {
 	}
 
 	if yaTarget, ok := target.(Error); ok {
+		if e.errorCode != "" && yaTarget.ErrorCode() != "" {
+			return e.errorCode == yaTarget.ErrorCode()
+		}
+
 		return e.code == yaTarget.Code() && errors.Is(e.cause, yaTarget.Unwrap())
 	}
 
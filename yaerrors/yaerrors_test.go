@@ -1,11 +1,15 @@
 package yaerrors_test
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/YaCodeDev/GoYaCodeDevUtils/yaerrors"
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yalogger"
 )
 
 func TestYaErrorFromString(t *testing.T) {
@@ -148,3 +152,97 @@ func TestYaError_IsError_NoMatch(t *testing.T) {
 		t.Fatalf("err.IsError(other) = true, want false")
 	}
 }
+
+func TestYaError_WrapWithLog_EmitsFieldsAndErrorCode(t *testing.T) {
+	buffer := bytes.NewBuffer(nil)
+	log := yalogger.NewBaseLogger(&yalogger.Config{
+		BaseLoggerType:   yalogger.Slog,
+		Level:            yalogger.InfoLevel,
+		DisableTimestamp: true,
+		Output:           buffer,
+	}).NewLogger()
+
+	err := yaerrors.FromString(401, "token expired").
+		WithErrorCode("auth.token_expired").
+		WithField("user_id", 42).
+		WrapWithLog("authenticate", log)
+
+	if err.ErrorCode() != "auth.token_expired" || err.Fields()["user_id"] != 42 {
+		t.Fatalf("unexpected error details %q %v", err.ErrorCode(), err.Fields())
+	}
+
+	output := buffer.String()
+	for _, want := range []string{"user_id=42", "error_code=auth.token_expired", "msg=authenticate"} {
+		if !strings.Contains(output, want) {
+			t.Fatalf("expected %q in %q", want, output)
+		}
+	}
+
+	wrapped := yaerrors.FromError(500, err, "handle request")
+	if wrapped.ErrorCode() != "auth.token_expired" || wrapped.Fields()["user_id"] != 42 {
+		t.Fatalf("expected FromError to keep the details, got %q %v", wrapped.ErrorCode(), wrapped.Fields())
+	}
+}
+
+func TestYaError_StackCapture(t *testing.T) {
+	if err := yaerrors.FromString(500, "no stack"); err.Stack() != nil {
+		t.Fatalf("expected no stack by default, got %v", err.Stack())
+	}
+
+	yaerrors.SetStackCapture(true)
+	t.Cleanup(func() { yaerrors.SetStackCapture(false) })
+
+	stack := yaerrors.FromString(500, "with stack").Stack()
+	if len(stack) == 0 || !strings.HasSuffix(stack[0].Function, "TestYaError_StackCapture") {
+		t.Fatalf("expected the stack to start at the caller, got %v", stack)
+	}
+
+	fields := yaerrors.LogFields(yaerrors.FromString(500, "with stack"))
+	if lines, ok := fields[yaerrors.KeyErrorStack].([]string); !ok || len(lines) == 0 {
+		t.Fatalf("expected the stack in the log fields, got %v", fields)
+	}
+}
+
+func TestRegistry_DefinitionsAndProblem(t *testing.T) {
+	definition := yaerrors.MustRegister(yaerrors.Definition{
+		ErrorCode: "test.quota_exceeded",
+		Status:    http.StatusTooManyRequests,
+		Title:     "Quota exceeded",
+		Type:      "https://errors.example.com/quota",
+	})
+
+	if err := yaerrors.Register(definition); !errors.Is(err, yaerrors.ErrDuplicateErrorCode) {
+		t.Fatalf("expected a duplicate registration to fail, got %v", err)
+	}
+
+	if err := yaerrors.Register(yaerrors.Definition{ErrorCode: "test.ok", Status: 200}); !errors.Is(
+		err,
+		yaerrors.ErrInvalidStatus,
+	) {
+		t.Fatalf("expected a non-error status to fail, got %v", err)
+	}
+
+	err := definition.New("100 of 100 requests used").Wrap("send message")
+	if !err.Is(definition.New("other detail")) || err.Code() != http.StatusTooManyRequests {
+		t.Fatalf("expected errors of one definition to match, got %v", err)
+	}
+
+	problem := yaerrors.NewProblem(err, "/messages")
+	expected := yaerrors.Problem{
+		Type:      "https://errors.example.com/quota",
+		Title:     "Quota exceeded",
+		Status:    http.StatusTooManyRequests,
+		Detail:    "send message",
+		Instance:  "/messages",
+		ErrorCode: "test.quota_exceeded",
+	}
+
+	if problem != expected {
+		t.Fatalf("expected %+v, got %+v", expected, problem)
+	}
+
+	unregistered := yaerrors.NewProblem(yaerrors.FromString(http.StatusNotFound, "no user"), "")
+	if unregistered.Type != yaerrors.DefaultProblemType || unregistered.Title != "Not Found" {
+		t.Fatalf("unexpected problem for an unregistered error %+v", unregistered)
+	}
+}
@@ -1,6 +1,6 @@
 package yafsm
 
-const (
-	stateKey     = "state"
-	stateDataKey = "stateData"
-)
+// stateFormatVersion is the version of the stored state record. Version 1
+// (unversioned) stored stateData as a JSON string holding the marshalled
+// state; version 2 embeds it as a JSON value and adds TTL and history.
+const stateFormatVersion = 2
@@ -75,3 +75,43 @@ func (b *EntityFSMStorage) GetStateData(
 ) yaerrors.Error {
 	return b.storage.GetStateData(stateData, emptyState)
 }
+
+// Transition moves the entity to the state to only if it currently is in the
+// state named from, and reports whether it did.
+//
+// Example usage:
+//
+// moved, err := userFSMStorage.Transition(ctx, (AwaitingName{}).StateName(), &AwaitingAge{})
+func (b *EntityFSMStorage) Transition(
+	ctx context.Context,
+	from string,
+	to State,
+) (bool, yaerrors.Error) {
+	return b.storage.Transition(ctx, b.uid, from, to)
+}
+
+// History returns the states the entity has left, newest first.
+//
+// Example usage:
+//
+// entries, err := userFSMStorage.History(ctx)
+func (b *EntityFSMStorage) History(
+	ctx context.Context,
+) ([]HistoryEntry, yaerrors.Error) {
+	return b.storage.History(ctx, b.uid)
+}
+
+// Back moves the entity back to the state it left most recently.
+//
+// Example usage:
+//
+// stateName, stateData, err := userFSMStorage.Back(ctx)
+//
+//	if errors.Is(err, yafsm.ErrEmptyHistory) {
+//	    // already at the first step
+//	}
+func (b *EntityFSMStorage) Back(
+	ctx context.Context,
+) (string, stateDataMarshalled, yaerrors.Error) { //nolint:revive,lll // Unexported type used for safe encapsulation of marshalled state data
+	return b.storage.Back(ctx, b.uid)
+}
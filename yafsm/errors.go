@@ -0,0 +1,14 @@
+package yafsm
+
+import "errors"
+
+var (
+	ErrFailedToMarshalState   = errors.New("[FSM] failed to marshal state data")
+	ErrFailedToUnmarshalState = errors.New("[FSM] failed to unmarshal state data")
+	ErrMissingState           = errors.New("[FSM] stored record has no state")
+	ErrEmptyHistory           = errors.New("[FSM] no previous state to go back to")
+)
+
+// errStateMismatch stops a Transition whose entity is in another state. It
+// never reaches callers, which get false instead.
+var errStateMismatch = errors.New("[FSM] current state does not match")
@@ -3,8 +3,11 @@ package yafsm
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"reflect"
+	"time"
 
 	"github.com/YaCodeDev/GoYaCodeDevUtils/yacache"
 	"github.com/YaCodeDev/GoYaCodeDevUtils/yaerrors"
@@ -35,29 +38,79 @@ type EmptyState struct {
 	BaseState[EmptyState]
 }
 
+// ExpiringState is a State that only lasts for a while: once StateTTL has
+// elapsed since the entity entered it, the entity is back in the default
+// state.
+//
+// Example:
+//
+//	type AwaitingCode struct {
+//	    yafsm.BaseState[AwaitingCode]
+//	}
+//
+//	func (AwaitingCode) StateTTL() time.Duration { return 5 * time.Minute }
+type ExpiringState interface {
+	State
+	StateTTL() time.Duration
+}
+
 // stateDataMarshalled is a type alias for marshalled state data.
 type stateDataMarshalled string
 
-// StateAndData is a struct that holds the state name and its marshalled data.
+// StateAndData is the version 1 layout of a stored state, where StateData is
+// the state marshalled to a JSON string. Version 1 records are still read
+// and are rewritten in the current format on the next transition.
 type StateAndData struct {
 	State     string `json:"state"`
 	StateData string `json:"stateData"`
 }
 
-// FSM is an interface for finite state machine storage.\
+// HistoryEntry is a state an entity has left.
+type HistoryEntry struct {
+	// State is the state name.
+	State string
+	// StateData holds the state data; decode it with GetStateData.
+	StateData stateDataMarshalled
+	// EnteredAt and LeftAt bound the time the entity spent in the state.
+	EnteredAt time.Time
+	LeftAt    time.Time
+}
+
+// Config tunes a DefaultFSMStorage.
+type Config struct {
+	// HistoryLimit is how many previous states are kept per entity for
+	// History and Back; zero keeps none.
+	HistoryLimit int
+}
+
+// FSM is an interface for finite state machine storage.
 type FSM interface {
 	SetState(ctx context.Context, uid string, state State) yaerrors.Error
+	Transition(
+		ctx context.Context,
+		uid string,
+		from string,
+		to State,
+	) (bool, yaerrors.Error)
 	GetState(ctx context.Context, uid string) (string, stateDataMarshalled, yaerrors.Error)
 	GetStateData(stateData stateDataMarshalled, emptyState State) yaerrors.Error
+	History(ctx context.Context, uid string) ([]HistoryEntry, yaerrors.Error)
+	Back(ctx context.Context, uid string) (string, stateDataMarshalled, yaerrors.Error)
 }
 
 // DefaultFSMStorage is a default implementation of the FSM interface using yacache.
+//
+// Every write is a read followed by Cache.CompareAndSwap of the entity's
+// whole record (current state, TTL and history), retried on conflict, so
+// concurrent transitions never lose an update on any backend.
 type DefaultFSMStorage[T yacache.Container] struct {
 	storage      yacache.Cache[T]
 	defaultState State
+	config       Config
 }
 
-// NewDefaultFSMStorage creates a new instance of DefaultFSMStorage.
+// NewDefaultFSMStorage creates a new instance of DefaultFSMStorage that keeps
+// no history.
 //
 // Example usage:
 //
@@ -67,15 +120,33 @@ type DefaultFSMStorage[T yacache.Container] struct {
 func NewDefaultFSMStorage[T yacache.Container](
 	storage yacache.Cache[T],
 	defaultState State,
+) *DefaultFSMStorage[T] {
+	return NewDefaultFSMStorageWithConfig(storage, defaultState, Config{})
+}
+
+// NewDefaultFSMStorageWithConfig creates a new instance of DefaultFSMStorage
+// tuned by config.
+//
+// Example usage:
+//
+// fsmStorage := fsm.NewDefaultFSMStorageWithConfig(cache, fsm.EmptyState{}, fsm.Config{HistoryLimit: 10})
+func NewDefaultFSMStorageWithConfig[T yacache.Container](
+	storage yacache.Cache[T],
+	defaultState State,
+	config Config,
 ) *DefaultFSMStorage[T] {
 	return &DefaultFSMStorage[T]{
 		storage:      storage,
 		defaultState: defaultState,
+		config:       config,
 	}
 }
 
-// SetState sets the state for a given user ID.
-// The state data is marshalled to JSON before being stored.
+// SetState sets the state for a given user ID, pushing the state it replaces
+// onto the history. The state data is marshalled to JSON before being stored;
+// an ExpiringState falls back to the default state after its TTL. A stored
+// record that cannot be decoded is overwritten as if there were none, so
+// SetState recovers an entity whose record is corrupted or foreign.
 //
 // Example usage:
 //
@@ -85,32 +156,63 @@ func (b *DefaultFSMStorage[T]) SetState(
 	uid string,
 	stateData State,
 ) yaerrors.Error {
-	val, err := json.Marshal(stateData)
+	record, err := newRecord(stateData)
 	if err != nil {
-		return yaerrors.FromError(
-			http.StatusInternalServerError,
-			err,
-			"failed to marshal state data",
-		)
+		return err
 	}
 
-	val, err = json.Marshal(StateAndData{
-		State:     stateData.StateName(),
-		StateData: string(val),
-	})
+	_, _, err = b.update(
+		ctx,
+		uid,
+		true,
+		func(current storedState, now time.Time) (storedState, yaerrors.Error) {
+			return b.move(current, record, now), nil
+		},
+	)
+
+	return err
+}
+
+// Transition moves the entity from the state named from to the state to,
+// only if it currently is in from, and reports whether it did. Use the
+// default state's name to match an entity without a state.
+//
+// Example usage:
+//
+// moved, err := fsmStorage.Transition(ctx, "123", (AwaitingName{}).StateName(), &AwaitingAge{})
+func (b *DefaultFSMStorage[T]) Transition(
+	ctx context.Context,
+	uid string,
+	from string,
+	to State,
+) (bool, yaerrors.Error) {
+	record, err := newRecord(to)
 	if err != nil {
-		return yaerrors.FromError(
-			http.StatusInternalServerError,
-			err,
-			"failed to marshal state data",
-		)
+		return false, err
 	}
 
-	return b.storage.Set(ctx, uid, string(val), 0)
+	_, moved, err := b.update(
+		ctx,
+		uid,
+		false,
+		func(current storedState, now time.Time) (storedState, yaerrors.Error) {
+			if name := b.stateName(current); name != from {
+				return storedState{}, yaerrors.FromError(
+					http.StatusConflict,
+					errStateMismatch,
+					fmt.Sprintf("state of `%s` is `%s`, not `%s`", uid, name, from),
+				)
+			}
+
+			return b.move(current, record, now), nil
+		},
+	)
+
+	return moved, err
 }
 
 // GetState retrieves the current state and its marshalled data for a given user ID.
-// If no state is found, it returns the default state.
+// If no state is found, or the stored state has expired, it returns the default state.
 //
 // Example usage:
 //
@@ -124,29 +226,20 @@ func (b *DefaultFSMStorage[T]) GetState(
 		return b.defaultState.StateName(), "", nil
 	}
 
-	var stateAndData map[string]string
-
-	if err := json.Unmarshal([]byte(data), &stateAndData); err != nil {
-		return "", "", yaerrors.FromError(
-			http.StatusInternalServerError,
-			err,
-			"failed to unmarshal state data map",
-		)
+	stored, err := decodeState(data)
+	if err != nil {
+		return "", "", err
 	}
 
-	state, ok := stateAndData[stateKey]
-
-	if !ok {
-		return "", "", yaerrors.FromString(
-			http.StatusNotFound,
-			"failed to get state",
-		)
+	if stored.expired(time.Now()) {
+		return b.defaultState.StateName(), "", nil
 	}
 
-	return state, stateDataMarshalled(data), nil
+	return stored.State, stateDataMarshalled(data), nil
 }
 
 // GetStateData unmarshals the state data into the provided empty state struct.
+// It accepts data returned by GetState, Back and History in any stored format.
 //
 // Example usage:
 //
@@ -165,32 +258,231 @@ func (b *DefaultFSMStorage[T]) GetStateData(
 		return nil
 	}
 
-	var stateAndData map[string]string
-
-	if err := json.Unmarshal([]byte(stateData), &stateAndData); err != nil {
-		return yaerrors.FromError(
-			http.StatusInternalServerError,
-			err,
-			"failed to unmarshal state data map",
-		)
+	stored, err := decodeState(string(stateData))
+	if err != nil {
+		return err
 	}
 
-	stateDataMarshalled, ok := stateAndData[stateDataKey]
-
-	if !ok {
-		return yaerrors.FromString(
+	if len(stored.StateData) == 0 {
+		return yaerrors.FromError(
 			http.StatusNotFound,
+			ErrMissingState,
 			"failed to get state data",
 		)
 	}
 
-	if err := json.Unmarshal([]byte(stateDataMarshalled), emptyState); err != nil {
+	if err := json.Unmarshal(stored.StateData, emptyState); err != nil {
 		return yaerrors.FromError(
 			http.StatusInternalServerError,
-			err,
+			errors.Join(err, ErrFailedToUnmarshalState),
 			"failed to unmarshal state data",
 		)
 	}
 
 	return nil
 }
+
+// History returns the states the entity has left, newest first, up to
+// Config.HistoryLimit of them.
+//
+// Example usage:
+//
+// entries, err := fsmStorage.History(ctx, "123")
+func (b *DefaultFSMStorage[T]) History(
+	ctx context.Context,
+	uid string,
+) ([]HistoryEntry, yaerrors.Error) {
+	_, stored, err := b.load(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]HistoryEntry, 0, len(stored.History))
+
+	for _, record := range stored.History {
+		data, err := encodeState(storedState{stateRecord: record})
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, HistoryEntry{
+			State:     record.State,
+			StateData: stateDataMarshalled(data),
+			EnteredAt: time.UnixMilli(record.EnteredAt),
+			LeftAt:    time.UnixMilli(record.LeftAt),
+		})
+	}
+
+	return entries, nil
+}
+
+// Back moves the entity back to the state it left most recently, dropping
+// that state from the history, and returns it like GetState. The restored
+// state's TTL starts over. It fails with ErrEmptyHistory when there is no
+// previous state.
+//
+// Example usage:
+//
+// stateName, stateData, err := fsmStorage.Back(ctx, "123")
+func (b *DefaultFSMStorage[T]) Back(
+	ctx context.Context,
+	uid string,
+) (string, stateDataMarshalled, yaerrors.Error) { //nolint:revive,lll // Unexported type used for safe encapsulation of marshalled state data
+	data, _, err := b.update(
+		ctx,
+		uid,
+		false,
+		func(current storedState, now time.Time) (storedState, yaerrors.Error) {
+			if len(current.History) == 0 {
+				return storedState{}, yaerrors.FromError(
+					http.StatusNotFound,
+					ErrEmptyHistory,
+					fmt.Sprintf("failed to go back from state `%s`", b.stateName(current)),
+				)
+			}
+
+			return storedState{
+				stateRecord: current.History[0].enter(now),
+				History:     current.History[1:],
+			}, nil
+		},
+	)
+	if err != nil {
+		return "", "", err
+	}
+
+	stored, err := decodeState(data)
+	if err != nil {
+		return "", "", err
+	}
+
+	return stored.State, stateDataMarshalled(data), nil
+}
+
+// update applies change to the record stored for uid with an optimistic
+// Cache.CompareAndSwap loop and returns the encoded record it wrote.
+// A change failing with errStateMismatch stops the loop without an error and
+// without writing. With overwrite set, a record that fails to decode is
+// handed to change as empty and replaced, still under CompareAndSwap;
+// otherwise the decode error is returned.
+func (b *DefaultFSMStorage[T]) update(
+	ctx context.Context,
+	uid string,
+	overwrite bool,
+	change func(current storedState, now time.Time) (storedState, yaerrors.Error),
+) (string, bool, yaerrors.Error) {
+	for {
+		if err := ctx.Err(); err != nil {
+			return "", false, yaerrors.FromError(
+				http.StatusInternalServerError,
+				err,
+				fmt.Sprintf("gave up updating state of `%s`", uid),
+			)
+		}
+
+		value, current, err := b.load(ctx, uid)
+		if err != nil && (!overwrite || value == "") {
+			return "", false, err
+		}
+
+		now := time.Now()
+
+		next, err := change(current, now)
+		if errors.Is(err, errStateMismatch) {
+			return "", false, nil
+		}
+
+		if err != nil {
+			return "", false, err
+		}
+
+		encoded, err := encodeState(next)
+		if err != nil {
+			return "", false, err
+		}
+
+		swapped, err := b.storage.CompareAndSwap(ctx, uid, value, encoded, b.keyTTL(next))
+		if err != nil {
+			return "", false, err.Wrap("failed to store state")
+		}
+
+		if swapped {
+			return encoded, true, nil
+		}
+	}
+}
+
+// load reads the raw and decoded record stored for uid. A missing record
+// reads as "" and an expired current state as the default one, keeping the
+// history. A record that fails to decode is returned raw with the error.
+func (b *DefaultFSMStorage[T]) load(
+	ctx context.Context,
+	uid string,
+) (string, storedState, yaerrors.Error) {
+	value, err := b.storage.Get(ctx, uid)
+	if err != nil {
+		if !errors.Is(err, yacache.ErrNotFoundValue) {
+			return "", storedState{}, err.Wrap("failed to read state")
+		}
+
+		return "", storedState{}, nil
+	}
+
+	stored, err := decodeState(value)
+	if err != nil {
+		return value, storedState{}, err
+	}
+
+	if stored.expired(time.Now()) {
+		stored.stateRecord = stateRecord{}
+	}
+
+	return value, stored, nil
+}
+
+// move makes record the current state, pushing the current one onto the
+// history when the entity has one; an entity without a stored or unexpired
+// state pushes nothing.
+func (b *DefaultFSMStorage[T]) move(
+	current storedState,
+	record stateRecord,
+	now time.Time,
+) storedState {
+	next := storedState{stateRecord: record.enter(now)}
+
+	if b.config.HistoryLimit <= 0 {
+		return next
+	}
+
+	history := current.History
+
+	if current.State != "" {
+		left := current.stateRecord
+		left.LeftAt = now.UnixMilli()
+
+		history = append([]stateRecord{left}, history...)
+	}
+
+	next.History = history[:min(len(history), b.config.HistoryLimit)]
+
+	return next
+}
+
+// keyTTL is the cache TTL of a record: the state's own TTL when there is no
+// history to keep, none otherwise.
+func (b *DefaultFSMStorage[T]) keyTTL(stored storedState) time.Duration {
+	if len(stored.History) > 0 {
+		return 0
+	}
+
+	return time.Duration(stored.TTL) * time.Millisecond
+}
+
+// stateName returns the name of the current state, the default one if none.
+func (b *DefaultFSMStorage[T]) stateName(stored storedState) string {
+	if stored.State == "" {
+		return b.defaultState.StateName()
+	}
+
+	return stored.State
+}
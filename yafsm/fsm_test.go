@@ -4,7 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/YaCodeDev/GoYaCodeDevUtils/yacache"
 	"github.com/YaCodeDev/GoYaCodeDevUtils/yafsm"
@@ -16,6 +20,20 @@ type ExampleState struct {
 	Param string `json:"param"`
 }
 
+type OtherState struct {
+	yafsm.BaseState[OtherState]
+
+	Step int `json:"step"`
+}
+
+type ShortLivedState struct {
+	yafsm.BaseState[ShortLivedState]
+}
+
+func (ShortLivedState) StateTTL() time.Duration {
+	return 20 * time.Millisecond
+}
+
 func TestFSMStorage_SetGetRoundTrip(t *testing.T) {
 	ctx := context.Background()
 
@@ -99,3 +117,226 @@ func TestFSMStorage_CorruptedPayload(t *testing.T) {
 		t.Fatalf("expected json.SyntaxError, got %v", err)
 	}
 }
+
+func TestFSMStorage_SetState_OverwritesUndecodableRecord(t *testing.T) {
+	ctx := context.Background()
+
+	cache := yacache.NewCache(yacache.NewMemoryContainer())
+	fsm := yafsm.NewDefaultFSMStorageWithConfig(
+		cache,
+		yafsm.EmptyState{},
+		yafsm.Config{HistoryLimit: 2},
+	)
+
+	for uid, garbage := range map[string]string{
+		"garbage":   "{not:a:json}",
+		"stateless": `{"version":2,"stateData":"{}"}`,
+	} {
+		if err := cache.Set(ctx, uid, garbage, 0); err != nil {
+			t.Fatalf("failed to set %s record: %v", uid, err)
+		}
+
+		if _, err := fsm.Transition(ctx, uid, "", OtherState{}); err == nil {
+			t.Fatalf("Transition over a %s record must fail", uid)
+		}
+
+		if err := fsm.SetState(ctx, uid, ExampleState{Param: "recovered"}); err != nil {
+			t.Fatalf("SetState over a %s record failed: %v", uid, err)
+		}
+
+		name, _, err := fsm.GetState(ctx, uid)
+		if err != nil || name != (ExampleState{}).StateName() {
+			t.Fatalf("unexpected state after recovering %s: %q %v", uid, name, err)
+		}
+
+		history, err := fsm.History(ctx, uid)
+		if err != nil || len(history) != 0 {
+			t.Fatalf("an overwritten record must leave no history: %v %v", history, err)
+		}
+	}
+}
+
+func TestFSMStorage_ReadsVersion1Records(t *testing.T) {
+	ctx := context.Background()
+
+	cache := yacache.NewCache(yacache.NewMemoryContainer())
+	fsm := yafsm.NewDefaultFSMStorage(cache, yafsm.EmptyState{})
+
+	uid := "legacy"
+
+	legacy, _ := json.Marshal(yafsm.StateAndData{
+		State:     (ExampleState{}).StateName(),
+		StateData: `{"param":"old"}`,
+	})
+
+	if err := cache.Set(ctx, uid, string(legacy), 0); err != nil {
+		t.Fatalf("failed to set legacy record: %v", err)
+	}
+
+	name, raw, err := fsm.GetState(ctx, uid)
+	if err != nil {
+		t.Fatalf("GetState failed: %v", err)
+	}
+
+	var got ExampleState
+	if err := fsm.GetStateData(raw, &got); err != nil {
+		t.Fatalf("GetStateData failed: %v", err)
+	}
+
+	if name != (ExampleState{}).StateName() || got.Param != "old" {
+		t.Fatalf("unexpected legacy state: %q %+v", name, got)
+	}
+
+	moved, err := fsm.Transition(ctx, uid, name, OtherState{Step: 1})
+	if err != nil || !moved {
+		t.Fatalf("Transition from a legacy record failed: %v %v", moved, err)
+	}
+
+	stored, _ := cache.Get(ctx, uid)
+	if !strings.Contains(stored, `"version":2`) {
+		t.Fatalf("record was not rewritten in the current format: %s", stored)
+	}
+}
+
+func TestFSMStorage_Transition_ComparesState(t *testing.T) {
+	ctx := context.Background()
+
+	cache := yacache.NewCache(yacache.NewMemoryContainer())
+	fsm := yafsm.NewDefaultFSMStorage(cache, yafsm.EmptyState{})
+
+	uid := "cas"
+	initial := (yafsm.EmptyState{}).StateName()
+
+	const workers = 16
+
+	var (
+		wg    sync.WaitGroup
+		moved atomic.Int32
+	)
+
+	for range workers {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			ok, err := fsm.Transition(ctx, uid, initial, ExampleState{Param: "won"})
+			if err != nil {
+				t.Errorf("Transition failed: %v", err)
+			}
+
+			if ok {
+				moved.Add(1)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if moved.Load() != 1 {
+		t.Fatalf("expected exactly one transition out of %q, got %d", initial, moved.Load())
+	}
+
+	ok, err := fsm.Transition(ctx, uid, (OtherState{}).StateName(), OtherState{})
+	if err != nil || ok {
+		t.Fatalf("transition from the wrong state must not apply: %v %v", ok, err)
+	}
+
+	ok, err = fsm.Transition(ctx, uid, (ExampleState{}).StateName(), OtherState{Step: 2})
+	if err != nil || !ok {
+		t.Fatalf("transition from the current state must apply: %v %v", ok, err)
+	}
+}
+
+func TestFSMStorage_ExpiringState_FallsBackToDefault(t *testing.T) {
+	ctx := context.Background()
+
+	cache := yacache.NewCache(yacache.NewMemoryContainer())
+	fsm := yafsm.NewDefaultFSMStorageWithConfig(
+		cache,
+		yafsm.EmptyState{},
+		yafsm.Config{HistoryLimit: 5},
+	)
+
+	uid := "ttl"
+
+	if err := fsm.SetState(ctx, uid, ExampleState{Param: "first"}); err != nil {
+		t.Fatalf("SetState failed: %v", err)
+	}
+
+	if err := fsm.SetState(ctx, uid, ShortLivedState{}); err != nil {
+		t.Fatalf("SetState failed: %v", err)
+	}
+
+	name, _, _ := fsm.GetState(ctx, uid)
+	if name != (ShortLivedState{}).StateName() {
+		t.Fatalf("expected the expiring state before its TTL, got %q", name)
+	}
+
+	time.Sleep(40 * time.Millisecond)
+
+	name, raw, _ := fsm.GetState(ctx, uid)
+	if name != (yafsm.EmptyState{}).StateName() || raw != "" {
+		t.Fatalf("expected the default state after the TTL, got %q", name)
+	}
+
+	name, _, err := fsm.Back(ctx, uid)
+	if err != nil || name != (ExampleState{}).StateName() {
+		t.Fatalf("history must survive an expired state: %q %v", name, err)
+	}
+}
+
+func TestFSMStorage_History_IsBounded(t *testing.T) {
+	ctx := context.Background()
+
+	cache := yacache.NewCache(yacache.NewMemoryContainer())
+	fsm := yafsm.NewDefaultFSMStorageWithConfig(
+		cache,
+		yafsm.EmptyState{},
+		yafsm.Config{HistoryLimit: 2},
+	)
+
+	uid := "history"
+
+	for step := 1; step <= 4; step++ {
+		if err := fsm.SetState(ctx, uid, OtherState{Step: step}); err != nil {
+			t.Fatalf("SetState failed: %v", err)
+		}
+	}
+
+	entries, err := fsm.History(ctx, uid)
+	if err != nil {
+		t.Fatalf("History failed: %v", err)
+	}
+
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 history entries, got %d", len(entries))
+	}
+
+	for i, want := range []int{3, 2} {
+		var got OtherState
+		if err := fsm.GetStateData(entries[i].StateData, &got); err != nil {
+			t.Fatalf("GetStateData failed: %v", err)
+		}
+
+		if got.Step != want || entries[i].LeftAt.Before(entries[i].EnteredAt) {
+			t.Fatalf("entry %d: expected step %d, got %+v", i, want, entries[i])
+		}
+	}
+
+	for _, want := range []int{3, 2} {
+		_, raw, err := fsm.Back(ctx, uid)
+		if err != nil {
+			t.Fatalf("Back failed: %v", err)
+		}
+
+		var got OtherState
+		if err := fsm.GetStateData(raw, &got); err != nil || got.Step != want {
+			t.Fatalf("expected to go back to step %d, got %+v (%v)", want, got, err)
+		}
+	}
+
+	if _, _, err := fsm.Back(ctx, uid); !errors.Is(err, yafsm.ErrEmptyHistory) {
+		t.Fatalf("expected ErrEmptyHistory, got %v", err)
+	}
+}
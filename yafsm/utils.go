@@ -0,0 +1,137 @@
+package yafsm
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yaerrors"
+)
+
+// stateRecord is one state of an entity: its current state or a history
+// entry.
+type stateRecord struct {
+	State     string          `json:"state"`
+	StateData json.RawMessage `json:"stateData"`
+	TTL       int64           `json:"ttl,omitempty"`       // milliseconds, 0 for none
+	ExpiresAt int64           `json:"expiresAt,omitempty"` // unix milliseconds, 0 for never
+	EnteredAt int64           `json:"enteredAt,omitempty"` // unix milliseconds
+	LeftAt    int64           `json:"leftAt,omitempty"`    // unix milliseconds, history only
+}
+
+// storedState is the value stored per entity: the current state followed by
+// the states it left, newest first. An empty State means the default state.
+type storedState struct {
+	Version int `json:"version"`
+	stateRecord
+	History []stateRecord `json:"history,omitempty"`
+}
+
+// expired reports whether the record's TTL has elapsed at now.
+func (r stateRecord) expired(now time.Time) bool {
+	return r.ExpiresAt != 0 && now.UnixMilli() >= r.ExpiresAt
+}
+
+// enter returns the record as the current state from now on, with its TTL
+// counted from now.
+func (r stateRecord) enter(now time.Time) stateRecord {
+	r.EnteredAt, r.LeftAt, r.ExpiresAt = now.UnixMilli(), 0, 0
+
+	if r.TTL > 0 {
+		r.ExpiresAt = now.Add(time.Duration(r.TTL) * time.Millisecond).UnixMilli()
+	}
+
+	return r
+}
+
+// newRecord marshals state into a record; an ExpiringState carries its TTL.
+func newRecord(state State) (stateRecord, yaerrors.Error) {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return stateRecord{}, yaerrors.FromError(
+			http.StatusInternalServerError,
+			errors.Join(err, ErrFailedToMarshalState),
+			"failed to marshal state data",
+		)
+	}
+
+	record := stateRecord{State: state.StateName(), StateData: data}
+
+	if expiring, ok := state.(ExpiringState); ok {
+		record.TTL = expiring.StateTTL().Milliseconds()
+	}
+
+	return record, nil
+}
+
+// encodeState marshals a stored state in the current format.
+func encodeState(stored storedState) (string, yaerrors.Error) {
+	stored.Version = stateFormatVersion
+
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return "", yaerrors.FromError(
+			http.StatusInternalServerError,
+			errors.Join(err, ErrFailedToMarshalState),
+			"failed to marshal state record",
+		)
+	}
+
+	return string(data), nil
+}
+
+// decodeState parses a stored state in any format version. Version 1 stored
+// stateData as a string of JSON, which is unwrapped here.
+func decodeState(value string) (storedState, yaerrors.Error) {
+	var stored storedState
+
+	if err := json.Unmarshal([]byte(value), &stored); err != nil {
+		return storedState{}, yaerrors.FromError(
+			http.StatusInternalServerError,
+			errors.Join(err, ErrFailedToUnmarshalState),
+			"failed to unmarshal state data map",
+		)
+	}
+
+	if stored.State == "" {
+		return storedState{}, yaerrors.FromError(
+			http.StatusNotFound,
+			ErrMissingState,
+			"failed to get state",
+		)
+	}
+
+	if stored.Version >= stateFormatVersion {
+		return stored, nil
+	}
+
+	data, err := unwrapStateData(stored.StateData)
+	if err != nil {
+		return storedState{}, err
+	}
+
+	stored.StateData = data
+
+	return stored, nil
+}
+
+// unwrapStateData turns version 1 stateData, a JSON string holding JSON, into
+// the JSON it holds.
+func unwrapStateData(data json.RawMessage) (json.RawMessage, yaerrors.Error) {
+	if len(data) == 0 {
+		return data, nil
+	}
+
+	var inner string
+
+	if err := json.Unmarshal(data, &inner); err != nil {
+		return nil, yaerrors.FromError(
+			http.StatusInternalServerError,
+			errors.Join(err, ErrFailedToUnmarshalState),
+			"failed to unmarshal version 1 state data",
+		)
+	}
+
+	return json.RawMessage(inner), nil
+}
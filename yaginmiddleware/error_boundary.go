@@ -9,6 +9,9 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// teapotMessage answers the "this should not happen" cases, see ErrorBoundary.
+const teapotMessage = "Backend developer is a teapot"
+
 // ErrorResponseFunc builds the JSON body written for a resolved request error. status
 // is the HTTP status code the response will be written with; message is the
 // human-readable error text.
@@ -42,6 +45,11 @@ func defaultErrorResponse(_ int, message string) any {
 //     already used across this org's Gin services for the "this should not happen"
 //     case, not a joke unique to this package.
 //
+// Fields and the error code attached to a yaerrors.Error are logged as structured
+// fields (see yaerrors.LogFields). An ErrorBoundary built with NewProblemErrorBoundary
+// answers every case with an RFC 7807 application/problem+json body instead, taking
+// the type and title of registered errors from the yaerrors registry.
+//
 // Middlewares that abort a request on failure (StaticBearerAuth, JWTBearerAuth, or a
 // service's own) must be registered *after* ErrorBoundary in the chain: they only
 // record the error via ctx.Error and call ctx.Abort, relying on ErrorBoundary to turn
@@ -58,6 +66,7 @@ func defaultErrorResponse(_ int, message string) any {
 type ErrorBoundary struct {
 	log      yalogger.Logger
 	response ErrorResponseFunc
+	problem  bool
 }
 
 // NewErrorBoundary constructs an ErrorBoundary middleware using the default
@@ -80,6 +89,26 @@ func NewErrorBoundaryWithResponse(log yalogger.Logger, response ErrorResponseFun
 	return &ErrorBoundary{log: log, response: response}
 }
 
+// NewProblemErrorBoundary behaves like NewErrorBoundary but writes every response as
+// an RFC 7807 problem details object (yaerrors.Problem) with the
+// application/problem+json content type. The request path becomes its instance.
+//
+// Example:
+//
+//	var errTokenExpired = yaerrors.MustRegister(yaerrors.Definition{
+//	    ErrorCode: "auth.token_expired",
+//	    Status:    http.StatusUnauthorized,
+//	    Title:     "Access token expired",
+//	})
+//
+//	router.Use(yaginmiddleware.NewProblemErrorBoundary(log).Handle)
+//	router.GET("/me", func(ctx *gin.Context) {
+//	    _ = ctx.Error(errTokenExpired.New("token expired"))
+//	})
+func NewProblemErrorBoundary(log yalogger.Logger) *ErrorBoundary {
+	return &ErrorBoundary{log: log, response: defaultErrorResponse, problem: true}
+}
+
 // Handle implements the Middleware interface, mapping any error(s) recorded via
 // ctx.Error during the request into a JSON response and a log line.
 func (e *ErrorBoundary) Handle(ctx *gin.Context) {
@@ -100,21 +129,21 @@ func (e *ErrorBoundary) handleSingle(ctx *gin.Context, err error) {
 
 	if !errors.As(err, &yaerr) {
 		e.log.Errorf("unclassified error: %v", err)
-		ctx.JSON(
-			http.StatusInternalServerError,
-			e.response(http.StatusInternalServerError, "Internal server error"),
+		e.respond(
+			ctx,
+			yaerrors.FromString(http.StatusInternalServerError, "Internal server error"),
 		)
 
 		return
 	}
 
 	if yaerr == nil {
-		yaerr = yaerrors.FromString(http.StatusTeapot, "Backend developer is a teapot")
+		yaerr = yaerrors.FromString(http.StatusTeapot, teapotMessage)
 	}
 
 	logAtLevel(e.log, yaerr)
 
-	ctx.JSON(yaerr.Code(), e.response(yaerr.Code(), yaerr.UnwrapLastError()))
+	e.respond(ctx, yaerr)
 }
 
 func (e *ErrorBoundary) handleMultiple(ctx *gin.Context) {
@@ -122,12 +151,26 @@ func (e *ErrorBoundary) handleMultiple(ctx *gin.Context) {
 		e.log.Errorf("error: %v", ginErr.Err)
 	}
 
-	const teapotMessage = "Backend developer is a teapot"
+	e.respond(ctx, yaerrors.FromString(http.StatusTeapot, teapotMessage))
+}
+
+// respond writes err either as a problem details object or through e.response.
+func (e *ErrorBoundary) respond(ctx *gin.Context, err yaerrors.Error) {
+	if !e.problem {
+		ctx.JSON(err.Code(), e.response(err.Code(), err.UnwrapLastError()))
+
+		return
+	}
 
-	ctx.JSON(http.StatusTeapot, e.response(http.StatusTeapot, teapotMessage))
+	ctx.Header("Content-Type", yaerrors.ProblemContentType)
+	ctx.JSON(err.Code(), yaerrors.NewProblem(err, ctx.Request.URL.Path))
 }
 
 func logAtLevel(log yalogger.Logger, err yaerrors.Error) {
+	if fields := yaerrors.LogFields(err); len(fields) > 0 {
+		log = log.WithFields(fields)
+	}
+
 	if isWarnLevel(err.Code()) {
 		log.Warn(err.UnwrapLastError())
 
@@ -119,4 +119,51 @@ func TestErrorBoundary_Handle(t *testing.T) {
 		assert.Equal(t, http.StatusTeapot, rec.Code)
 		assert.JSONEq(t, `{"error":"Backend developer is a teapot"}`, rec.Body.String())
 	})
+	t.Run("[Problem] RendersRegisteredDefinition", func(t *testing.T) {
+		t.Parallel()
+
+		definition := yaerrors.MustRegister(yaerrors.Definition{
+			ErrorCode: "test.token_expired",
+			Status:    http.StatusUnauthorized,
+			Title:     "Access token expired",
+		})
+
+		engine := gin.New()
+		engine.Use(yaginmiddleware.NewProblemErrorBoundary(newTestLogger()).Handle)
+		engine.GET("/me", func(ctx *gin.Context) {
+			_ = ctx.Error(definition.New("token expired").WithField("user_id", 42))
+		})
+		engine.GET("/boom", func(ctx *gin.Context) {
+			_ = ctx.Error(
+				errors.New("boom"),
+			) //nolint:err113 // intentional plain error for the test
+		})
+
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/me", nil)
+
+		engine.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.Equal(t, yaerrors.ProblemContentType, rec.Header().Get("Content-Type"))
+		assert.JSONEq(
+			t,
+			`{"type":"about:blank","title":"Access token expired","status":401,`+
+				`"detail":"token expired","instance":"/me","code":"test.token_expired"}`,
+			rec.Body.String(),
+		)
+
+		rec = httptest.NewRecorder()
+		req = httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/boom", nil)
+
+		engine.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.JSONEq(
+			t,
+			`{"type":"about:blank","title":"Internal Server Error","status":500,`+
+				`"detail":"Internal server error","instance":"/boom"}`,
+			rec.Body.String(),
+		)
+	})
 }
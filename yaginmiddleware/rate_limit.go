@@ -0,0 +1,150 @@
+package yaginmiddleware
+
+import (
+	"hash/fnv"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yaerrors"
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yaratelimit"
+	"github.com/gin-gonic/gin"
+)
+
+// Response headers RateLimit sets on every request it lets through or rejects.
+const (
+	RateLimitLimitHeader     = "RateLimit-Limit"
+	RateLimitRemainingHeader = "RateLimit-Remaining"
+	RateLimitResetHeader     = "RateLimit-Reset"
+	RetryAfterHeader         = "Retry-After"
+)
+
+// RateLimitKeyFunc derives the rate-limit subject id of a request. A returned
+// error aborts the request through the ErrorBoundary with the error's status.
+type RateLimitKeyFunc func(ctx *gin.Context) (uint64, yaerrors.Error)
+
+// RateLimitByClientIP keys requests by an FNV-1a hash of gin.Context.ClientIP,
+// so it honours the engine's trusted-proxy configuration.
+func RateLimitByClientIP(ctx *gin.Context) (uint64, yaerrors.Error) {
+	hash := fnv.New64a()
+
+	_, _ = hash.Write([]byte(ctx.ClientIP())) //nolint:errcheck // hash.Hash.Write never fails
+
+	return hash.Sum64(), nil
+}
+
+// RateLimitByJWTSubject keys requests by the Sub of the JWTClaims a
+// JWTBearerAuth stored under contextKey (DefaultJWTContextKey when empty), so
+// it must be registered after that JWTBearerAuth. A request without claims is
+// rejected with 500, since it means the chain is misconfigured.
+func RateLimitByJWTSubject(contextKey string) RateLimitKeyFunc {
+	if contextKey == "" {
+		contextKey = DefaultJWTContextKey
+	}
+
+	return func(ctx *gin.Context) (uint64, yaerrors.Error) {
+		value, _ := ctx.Get(contextKey)
+
+		claims, ok := value.(JWTClaims)
+		if !ok {
+			return 0, yaerrors.FromString(
+				http.StatusInternalServerError,
+				"jwt claims are missing from the request context",
+			)
+		}
+
+		return claims.Sub, nil
+	}
+}
+
+// RateLimit is a Gin middleware that records one hit per request on a
+// yaratelimit.RateLimiter, keyed by a RateLimitKeyFunc within a fixed group.
+//
+// Every response carries RateLimit-Limit, RateLimit-Remaining and
+// RateLimit-Reset (seconds until the quota is fully restored). A request over
+// the limit additionally gets Retry-After (whole seconds) and aborts the chain
+// with 429; a failing key function or limiter aborts with the status it
+// reports.
+//
+// Must be registered after an ErrorBoundary in the middleware chain; see
+// ErrorBoundary's doc comment.
+//
+// Example:
+//
+//	limiter := yaratelimit.NewRateLimiter(cache, yaratelimit.SlidingWindowCounter, 100, time.Minute)
+//	router.Use(yaginmiddleware.NewErrorBoundary(log).Handle)
+//	router.Use(yaginmiddleware.NewRateLimit(limiter, "api", yaginmiddleware.RateLimitByClientIP).Handle)
+type RateLimit struct {
+	limiter yaratelimit.RateLimiter
+	group   string
+	key     RateLimitKeyFunc
+}
+
+// NewRateLimit constructs a RateLimit counting hits of key's subject under
+// group. A nil key falls back to RateLimitByClientIP.
+func NewRateLimit(
+	limiter yaratelimit.RateLimiter,
+	group string,
+	key RateLimitKeyFunc,
+) *RateLimit {
+	if key == nil {
+		key = RateLimitByClientIP
+	}
+
+	return &RateLimit{limiter: limiter, group: group, key: key}
+}
+
+// Handle implements the Middleware interface.
+func (r *RateLimit) Handle(ctx *gin.Context) {
+	if r.limiter == nil {
+		abortWithError(
+			ctx,
+			yaerrors.FromString(http.StatusInternalServerError, "rate limiter is not configured"),
+		)
+
+		return
+	}
+
+	id, err := r.key(ctx)
+	if err != nil {
+		abortWithError(ctx, err.Wrap("failed to derive rate limit key"))
+
+		return
+	}
+
+	decision, err := r.limiter.Allow(ctx.Request.Context(), id, r.group)
+	if err != nil {
+		abortWithError(ctx, err.Wrap("failed to apply rate limit"))
+
+		return
+	}
+
+	ctx.Header(RateLimitLimitHeader, strconv.Itoa(int(decision.Limit)))
+	ctx.Header(RateLimitRemainingHeader, strconv.Itoa(int(decision.Remaining)))
+	ctx.Header(
+		RateLimitResetHeader,
+		strconv.FormatInt(ceilSeconds(time.Until(decision.ResetAt)), 10),
+	)
+
+	if !decision.Allowed {
+		ctx.Header(
+			RetryAfterHeader,
+			strconv.FormatInt(max(ceilSeconds(decision.RetryAfter), 1), 10),
+		)
+
+		abortWithError(
+			ctx,
+			yaerrors.FromString(http.StatusTooManyRequests, "rate limit exceeded"),
+		)
+
+		return
+	}
+
+	ctx.Next()
+}
+
+// ceilSeconds rounds duration up to whole seconds, clamping negatives to zero.
+func ceilSeconds(duration time.Duration) int64 {
+	return int64(math.Ceil(max(duration, 0).Seconds()))
+}
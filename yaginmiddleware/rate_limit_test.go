@@ -0,0 +1,171 @@
+package yaginmiddleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yacache"
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yaerrors"
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yaginmiddleware"
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yaratelimit"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func newRateLimitEngine(middlewares ...gin.HandlerFunc) *gin.Engine {
+	engine := gin.New()
+	engine.Use(yaginmiddleware.NewErrorBoundary(newTestLogger()).Handle)
+	engine.Use(middlewares...)
+	engine.GET("/ping", func(ctx *gin.Context) {
+		ctx.JSON(http.StatusOK, gin.H{"message": "pong"})
+	})
+
+	return engine
+}
+
+func newTestRateLimiter(limit uint8) yaratelimit.RateLimiter {
+	cache := yacache.NewCache(yacache.NewMemoryContainer())
+
+	return yaratelimit.NewRateLimiter(cache, yaratelimit.SlidingLog, limit, time.Minute)
+}
+
+func doRateLimitRequest(
+	t *testing.T,
+	engine *gin.Engine,
+	remoteAddr, token string,
+) *httptest.ResponseRecorder {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/ping", nil)
+	req.RemoteAddr = remoteAddr
+
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	engine.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestRateLimit_ByClientIP(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+
+	engine := newRateLimitEngine(
+		yaginmiddleware.NewRateLimit(newTestRateLimiter(2), "ping", nil).Handle,
+	)
+
+	t.Run("[Allowed] requests under the limit carry quota headers", func(t *testing.T) {
+		for remaining := 1; remaining >= 0; remaining-- {
+			rec := doRateLimitRequest(t, engine, "10.0.0.1:1234", "")
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, "2", rec.Header().Get(yaginmiddleware.RateLimitLimitHeader))
+			assert.Equal(
+				t,
+				strconv.Itoa(remaining),
+				rec.Header().Get(yaginmiddleware.RateLimitRemainingHeader),
+			)
+			assert.Equal(t, "60", rec.Header().Get(yaginmiddleware.RateLimitResetHeader))
+			assert.Empty(t, rec.Header().Get(yaginmiddleware.RetryAfterHeader))
+		}
+	})
+
+	t.Run("[Rejected] the request over the limit gets 429 and Retry-After", func(t *testing.T) {
+		rec := doRateLimitRequest(t, engine, "10.0.0.1:1234", "")
+
+		assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+		assert.Equal(t, "0", rec.Header().Get(yaginmiddleware.RateLimitRemainingHeader))
+		assert.Equal(t, "60", rec.Header().Get(yaginmiddleware.RetryAfterHeader))
+		assert.Contains(t, rec.Body.String(), "rate limit exceeded")
+	})
+
+	t.Run("[Allowed] another client IP has its own quota", func(t *testing.T) {
+		rec := doRateLimitRequest(t, engine, "10.0.0.2:1234", "")
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+}
+
+func TestRateLimit_ByJWTSubject(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+
+	secret := []byte("s3cret")
+
+	engine := newRateLimitEngine(
+		yaginmiddleware.NewJWTBearerAuth(secret, 0).Handle,
+		yaginmiddleware.NewRateLimit(
+			newTestRateLimiter(1),
+			"ping",
+			yaginmiddleware.RateLimitByJWTSubject(""),
+		).Handle,
+	)
+
+	first, err := yaginmiddleware.GenerateJWT(1, 0, 0, time.Hour, secret)
+	assert.Nil(t, err)
+
+	second, err := yaginmiddleware.GenerateJWT(2, 0, 0, time.Hour, secret)
+	assert.Nil(t, err)
+
+	assert.Equal(t, http.StatusOK, doRateLimitRequest(t, engine, "10.0.0.1:1", first).Code)
+	assert.Equal(
+		t,
+		http.StatusTooManyRequests,
+		doRateLimitRequest(t, engine, "10.0.0.2:1", first).Code,
+		"the same subject is limited regardless of its IP",
+	)
+	assert.Equal(t, http.StatusOK, doRateLimitRequest(t, engine, "10.0.0.1:1", second).Code)
+}
+
+func TestRateLimit_Failures(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+
+	t.Run("[JWT] missing claims abort with 500", func(t *testing.T) {
+		engine := newRateLimitEngine(
+			yaginmiddleware.NewRateLimit(
+				newTestRateLimiter(1),
+				"ping",
+				yaginmiddleware.RateLimitByJWTSubject(""),
+			).Handle,
+		)
+
+		rec := doRateLimitRequest(t, engine, "10.0.0.1:1", "")
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+
+	t.Run("[Custom] key errors keep their status", func(t *testing.T) {
+		engine := newRateLimitEngine(
+			yaginmiddleware.NewRateLimit(
+				newTestRateLimiter(1),
+				"ping",
+				func(_ *gin.Context) (uint64, yaerrors.Error) {
+					return 0, yaerrors.FromString(http.StatusBadRequest, "no api key")
+				},
+			).Handle,
+		)
+
+		rec := doRateLimitRequest(t, engine, "10.0.0.1:1", "")
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("[Config] nil limiter aborts with 500", func(t *testing.T) {
+		engine := newRateLimitEngine(yaginmiddleware.NewRateLimit(nil, "ping", nil).Handle)
+
+		rec := doRateLimitRequest(t, engine, "10.0.0.1:1", "")
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+}
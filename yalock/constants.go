@@ -0,0 +1,22 @@
+package yalock
+
+import "time"
+
+const (
+	// DefaultTTL is the lease used when Config.TTL is zero.
+	DefaultTTL = 30 * time.Second
+	// DefaultRetryInterval is how often a blocked Acquire retries when
+	// Config.RetryInterval is zero.
+	DefaultRetryInterval = 100 * time.Millisecond
+)
+
+const (
+	keyPrefix = "lock-"
+
+	valueSeparator = ","
+	recordFields   = 3
+
+	// renewalsPerTTL is how many renewals fit in one lease by default, so a
+	// holder survives a couple of failed renewals before its lease runs out.
+	renewalsPerTTL = 3
+)
@@ -0,0 +1,10 @@
+package yalock
+
+import "errors"
+
+var (
+	ErrLockHeld             = errors.New("[LOCK] lock is held by another owner")
+	ErrLockNotHeld          = errors.New("[LOCK] lock is no longer held")
+	ErrFailedToAcquire      = errors.New("[LOCK] failed to acquire lock")
+	ErrInvalidStorageFormat = errors.New("[LOCK] invalid lock record")
+)
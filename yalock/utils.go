@@ -0,0 +1,106 @@
+package yalock
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yacache"
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yaerrors"
+)
+
+// record is the parsed lock value "<fence>,<deadline_unix_ms>,<owner>". A
+// free lock has an empty owner and keeps the last fence handed out.
+type record struct {
+	fence    uint64
+	deadline int64
+	owner    string
+}
+
+// heldAt reports whether the record holds a live lease at now.
+func (r record) heldAt(now time.Time) bool {
+	return r.owner != "" && r.deadline > now.UnixMilli()
+}
+
+// String formats the record as it is stored in the cache.
+func (r record) String() string {
+	return strings.Join([]string{
+		strconv.FormatUint(r.fence, 10),
+		strconv.FormatInt(r.deadline, 10),
+		r.owner,
+	}, valueSeparator)
+}
+
+// parseRecord parses a stored lock value; "" is a lock that was never taken.
+func parseRecord(value string) (record, yaerrors.Error) {
+	if value == "" {
+		return record{}, nil
+	}
+
+	fields := strings.SplitN(value, valueSeparator, recordFields)
+	if len(fields) != recordFields {
+		return record{}, yaerrors.FromError(
+			http.StatusInternalServerError,
+			ErrInvalidStorageFormat,
+			"couldn't split lock record",
+		)
+	}
+
+	fence, err := strconv.ParseUint(fields[0], 10, 64)
+	if err != nil {
+		return record{}, yaerrors.FromError(
+			http.StatusInternalServerError,
+			errors.Join(err, ErrInvalidStorageFormat),
+			"couldn't parse lock fence",
+		)
+	}
+
+	deadline, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil {
+		return record{}, yaerrors.FromError(
+			http.StatusInternalServerError,
+			errors.Join(err, ErrInvalidStorageFormat),
+			"couldn't parse lock deadline",
+		)
+	}
+
+	return record{fence: fence, deadline: deadline, owner: fields[2]}, nil
+}
+
+// load reads the value and the parsed record stored under key; a missing key
+// reads as "".
+func load[Cache yacache.Container](
+	ctx context.Context,
+	cache yacache.Cache[Cache],
+	key string,
+) (string, record, yaerrors.Error) {
+	value, err := cache.Get(ctx, key)
+	if err != nil {
+		if !errors.Is(err, yacache.ErrNotFoundValue) {
+			return "", record{}, err.Wrap("failed to read lock record")
+		}
+
+		value = ""
+	}
+
+	current, err := parseRecord(value)
+	if err != nil {
+		return "", record{}, err
+	}
+
+	return value, current, nil
+}
+
+// newOwner returns a random owner token, unique per acquisition.
+func newOwner() string {
+	return rand.Text()
+}
+
+// formatKey builds the cache key of the lock called name.
+func formatKey(name string) string {
+	return keyPrefix + name
+}
@@ -0,0 +1,489 @@
+// Package yalock implements a distributed lock backed by a yacache.Cache, for
+// leader election and at-most-one-worker sections (cron-style jobs, migration
+// runners) across replicas sharing a Redis, or across goroutines sharing a
+// memory cache.
+//
+// # Storage layout
+//
+// Each lock is a single key:
+//
+//	lock-<name>
+//
+// The cache value is a compact CSV tuple:
+//
+//	"<fence>,<deadline_unix_ms>,<owner>"
+//
+// For example: "7,1726860030000,5UJKS…" means the lock is held by owner
+// 5UJKS… with fencing token 7 until unix time 1726860030 (milliseconds). A
+// released lock keeps its fence with an empty owner ("7,0,"), so the key has
+// no TTL and fencing tokens keep increasing across releases and lost leases.
+//
+// # Model
+//
+// Every transition is a read followed by a Cache.CompareAndSwap of the whole
+// record, so it is atomic on every backend:
+//
+//   - Acquire / TryAcquire succeed when the record has no owner or its
+//     deadline has passed, writing fence+1, now+TTL and a fresh random owner
+//     token.
+//   - Renew moves the deadline to now+TTL, only if the record still carries
+//     the holder's owner token.
+//   - Release clears the owner, only if the record still carries the
+//     holder's owner token (compare-and-delete of the lease).
+//
+// Deadlines come from the clock of the process taking or renewing the lock,
+// so replicas need loosely synchronised clocks, exactly like yaratelimit.
+// Pick a TTL well above the expected clock skew.
+//
+// # Fencing
+//
+// A lease can run out while its holder is paused (GC, network partition), so
+// a holder can never be sure it is still the only one. Lock.Fence returns a
+// token that strictly increases with every acquisition of the same lock:
+// pass it along with every write to the protected resource and have the
+// resource reject tokens lower than the highest it has seen.
+package yalock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yacache"
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yaerrors"
+)
+
+// Config configures a [Locker]. Zero fields fall back to the package
+// defaults.
+type Config struct {
+	// TTL is the lease: how long a lock stays held without being renewed.
+	TTL time.Duration
+	// RenewInterval is how often a held lock renews its lease in the
+	// background. Zero means TTL/3; a negative value disables automatic
+	// renewal, leaving it to Lock.Renew.
+	RenewInterval time.Duration
+	// RetryInterval is how often a blocked Acquire tries again.
+	RetryInterval time.Duration
+}
+
+// Locker hands out the locks stored in one cache.
+// The zero value is not valid; use NewLocker.
+//
+// Example:
+//
+//	cache := yacache.NewCache(redisClient)
+//	locker := yalock.NewLocker(cache, yalock.Config{TTL: 10 * time.Second})
+//	lock, err := locker.Acquire(ctx, "migrations")
+type Locker[Cache yacache.Container] struct {
+	cache  yacache.Cache[Cache]
+	config Config
+}
+
+// NewLocker wires a cache and returns a ready-to-use locker.
+//
+//   - cache : any yacache implementation (memory, redis, near-cache)
+//   - config: lease and retry timings; the zero value uses the defaults
+//
+// Example:
+//
+//	locker := yalock.NewLocker(cache, yalock.Config{})
+func NewLocker[Cache yacache.Container](
+	cache yacache.Cache[Cache],
+	config Config,
+) *Locker[Cache] {
+	if config.TTL <= 0 {
+		config.TTL = DefaultTTL
+	}
+
+	if config.RenewInterval == 0 {
+		config.RenewInterval = config.TTL / renewalsPerTTL
+	}
+
+	if config.RetryInterval <= 0 {
+		config.RetryInterval = DefaultRetryInterval
+	}
+
+	return &Locker[Cache]{
+		cache:  cache,
+		config: config,
+	}
+}
+
+// TryAcquire takes the lock called name if it is free and returns
+// immediately otherwise, with an error matching ErrLockHeld.
+//
+// Example:
+//
+//	lock, err := locker.TryAcquire(ctx, "report")
+//	if errors.Is(err, yalock.ErrLockHeld) {
+//	    return nil // another replica is on it
+//	}
+func (l *Locker[Cache]) TryAcquire(
+	ctx context.Context,
+	name string,
+) (*Lock[Cache], yaerrors.Error) {
+	key := formatKey(name)
+	owner := newOwner()
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, yaerrors.FromError(
+				http.StatusInternalServerError,
+				errors.Join(err, ErrFailedToAcquire),
+				fmt.Sprintf("gave up acquiring lock `%s`", name),
+			)
+		}
+
+		value, current, err := load(ctx, l.cache, key)
+		if err != nil {
+			return nil, err.Wrap("failed to acquire lock")
+		}
+
+		now := time.Now()
+
+		if current.heldAt(now) {
+			return nil, yaerrors.FromError(
+				http.StatusConflict,
+				ErrLockHeld,
+				fmt.Sprintf(
+					"lock `%s` is held until %s",
+					name,
+					time.UnixMilli(current.deadline).Format(time.RFC3339Nano),
+				),
+			)
+		}
+
+		next := record{
+			fence:    current.fence + 1,
+			deadline: now.Add(l.config.TTL).UnixMilli(),
+			owner:    owner,
+		}
+
+		swapped, err := l.cache.CompareAndSwap(ctx, key, value, next.String(), 0)
+		if err != nil {
+			return nil, err.Wrap("failed to acquire lock")
+		}
+
+		if swapped {
+			return l.newLock(ctx, name, next), nil
+		}
+	}
+}
+
+// Acquire blocks until it takes the lock called name, retrying every
+// RetryInterval, or until ctx is done. ctx only bounds the wait: the lock
+// stays held, and renewed, after ctx is cancelled.
+//
+// Example:
+//
+//	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+//	defer cancel()
+//
+//	lock, err := locker.Acquire(ctx, "migrations")
+//	if err != nil {
+//	    return err
+//	}
+//	defer lock.Release(context.Background())
+func (l *Locker[Cache]) Acquire(
+	ctx context.Context,
+	name string,
+) (*Lock[Cache], yaerrors.Error) {
+	for {
+		lock, err := l.TryAcquire(ctx, name)
+		if err == nil || !errors.Is(err, ErrLockHeld) {
+			return lock, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, yaerrors.FromError(
+				http.StatusConflict,
+				errors.Join(ctx.Err(), ErrLockHeld, ErrFailedToAcquire),
+				fmt.Sprintf("gave up waiting for lock `%s`", name),
+			)
+		case <-time.After(l.config.RetryInterval):
+		}
+	}
+}
+
+// Do runs fn while holding the lock called name: it blocks in Acquire, runs
+// fn with a context cancelled as soon as the lock is lost, then releases the
+// lock. An error from fn takes precedence over a failed release.
+//
+// Example:
+//
+//	err := locker.Do(ctx, "migrations", func(ctx context.Context, fence uint64) yaerrors.Error {
+//	    return migrate(ctx, fence)
+//	})
+func (l *Locker[Cache]) Do(
+	ctx context.Context,
+	name string,
+	fn func(ctx context.Context, fence uint64) yaerrors.Error,
+) yaerrors.Error {
+	lock, err := l.Acquire(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	held, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go func() {
+		select {
+		case <-lock.Done():
+			cancel()
+		case <-held.Done():
+		}
+	}()
+
+	fnErr := fn(held, lock.Fence())
+
+	releaseErr := lock.Release(context.WithoutCancel(ctx))
+
+	if fnErr != nil {
+		return fnErr
+	}
+
+	return releaseErr
+}
+
+// newLock wraps a freshly written record and starts its renewal.
+func (l *Locker[Cache]) newLock(ctx context.Context, name string, held record) *Lock[Cache] {
+	renewal, cancel := context.WithCancel(context.WithoutCancel(ctx))
+
+	lock := &Lock[Cache]{
+		locker:  l,
+		name:    name,
+		key:     formatKey(name),
+		owner:   held.owner,
+		fence:   held.fence,
+		held:    held,
+		done:    make(chan struct{}),
+		cancel:  cancel,
+		stopped: make(chan struct{}),
+	}
+
+	if l.config.RenewInterval > 0 {
+		go lock.keepAlive(renewal, l.config.RenewInterval)
+	} else {
+		close(lock.stopped)
+	}
+
+	return lock
+}
+
+// Lock is one acquisition of a named lock. It is safe for concurrent use.
+type Lock[Cache yacache.Container] struct {
+	locker *Locker[Cache]
+	name   string
+	key    string
+	owner  string
+	fence  uint64
+
+	mutex    sync.Mutex
+	held     record
+	done     chan struct{}
+	doneOnce sync.Once
+
+	cancel  context.CancelFunc
+	stopped chan struct{}
+}
+
+// Name returns the name the lock was acquired by.
+func (l *Lock[Cache]) Name() string {
+	return l.name
+}
+
+// Owner returns the random token identifying this acquisition.
+func (l *Lock[Cache]) Owner() string {
+	return l.owner
+}
+
+// Fence returns the fencing token of this acquisition. It is higher than the
+// token of every earlier acquisition of the same lock.
+//
+// Example:
+//
+//	_, err = db.ExecContext(ctx,
+//	    "UPDATE jobs SET state = ?, fence = ? WHERE id = ? AND fence < ?",
+//	    state, lock.Fence(), id, lock.Fence())
+func (l *Lock[Cache]) Fence() uint64 {
+	return l.fence
+}
+
+// Done returns a channel closed once the lock is no longer held: after
+// Release, or when another owner took it over or renewal kept failing until
+// the lease ran out.
+//
+// Example:
+//
+//	select {
+//	case <-lock.Done():
+//	    return errLeadershipLost
+//	case job := <-jobs:
+//	    run(job)
+//	}
+func (l *Lock[Cache]) Done() <-chan struct{} {
+	return l.done
+}
+
+// Renew extends the lease to TTL from now. It fails with an error matching
+// ErrLockNotHeld once another owner has taken the lock; a lease that ran out
+// without anyone taking the lock is simply extended.
+//
+// Example:
+//
+//	if err := lock.Renew(ctx); errors.Is(err, yalock.ErrLockNotHeld) { … }
+func (l *Lock[Cache]) Renew(ctx context.Context) yaerrors.Error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.released() {
+		return l.notHeldError()
+	}
+
+	next := l.held
+	next.deadline = time.Now().Add(l.locker.config.TTL).UnixMilli()
+
+	if err := l.swap(ctx, next); err != nil {
+		return err.Wrap("failed to renew lock")
+	}
+
+	return nil
+}
+
+// Release stops the renewal and frees the lock if this acquisition still
+// holds it. It fails with an error matching ErrLockNotHeld if the lock had
+// been lost, i.e. the protected section may have overlapped with another
+// owner's.
+//
+// Example:
+//
+//	defer lock.Release(context.Background())
+func (l *Lock[Cache]) Release(ctx context.Context) yaerrors.Error {
+	l.cancel()
+	<-l.stopped
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.released() {
+		return l.notHeldError()
+	}
+
+	if err := l.swap(ctx, record{fence: l.fence}); err != nil {
+		return err.Wrap("failed to release lock")
+	}
+
+	l.finish()
+
+	return nil
+}
+
+// keepAlive renews the lease every interval until ctx is cancelled or the
+// lock is lost. A renewal that fails for any other reason is retried on the
+// next tick, and the lock is given up once its lease has run out.
+func (l *Lock[Cache]) keepAlive(ctx context.Context, interval time.Duration) {
+	defer close(l.stopped)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := l.Renew(ctx)
+
+			switch {
+			case err == nil:
+			case ctx.Err() != nil, errors.Is(err, ErrLockNotHeld):
+				return
+			case l.expired(time.Now()):
+				l.finish()
+
+				return
+			}
+		}
+	}
+}
+
+// swap replaces the held record with next. A failed compare is re-read: the
+// record may still be ours, written by a call whose reply was lost, in which
+// case the swap is retried from it; otherwise the lock is lost. The caller
+// holds the mutex.
+func (l *Lock[Cache]) swap(ctx context.Context, next record) yaerrors.Error {
+	expected := l.held.String()
+
+	for {
+		swapped, err := l.locker.cache.CompareAndSwap(ctx, l.key, expected, next.String(), 0)
+		if err != nil {
+			return err
+		}
+
+		if swapped {
+			l.held = next
+
+			return nil
+		}
+
+		value, current, err := load(ctx, l.locker.cache, l.key)
+		if err != nil {
+			return err
+		}
+
+		if current.owner != l.owner || current.fence != l.fence {
+			l.finish()
+
+			return l.notHeldError()
+		}
+
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return yaerrors.FromError(
+				http.StatusInternalServerError,
+				ctxErr,
+				fmt.Sprintf("gave up updating lock `%s`", l.name),
+			)
+		}
+
+		expected = value
+	}
+}
+
+// expired reports whether the held lease has run out at now.
+func (l *Lock[Cache]) expired(now time.Time) bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	return !l.held.heldAt(now)
+}
+
+// released reports whether Done is closed.
+func (l *Lock[Cache]) released() bool {
+	select {
+	case <-l.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// finish closes Done.
+func (l *Lock[Cache]) finish() {
+	l.doneOnce.Do(func() {
+		close(l.done)
+	})
+}
+
+// notHeldError reports that this acquisition no longer holds the lock.
+func (l *Lock[Cache]) notHeldError() yaerrors.Error {
+	return yaerrors.FromError(
+		http.StatusConflict,
+		ErrLockNotHeld,
+		fmt.Sprintf("lock `%s` with fence %d is no longer held", l.name, l.fence),
+	)
+}
@@ -0,0 +1,257 @@
+package yalock_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yacache"
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yaerrors"
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yalock"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const lockName = "migrations"
+
+// locker builds a fresh Locker over one backend.
+type locker interface {
+	TryAcquire(ctx context.Context, name string) (lock, yaerrors.Error)
+	Acquire(ctx context.Context, name string) (lock, yaerrors.Error)
+	Do(
+		ctx context.Context,
+		name string,
+		fn func(ctx context.Context, fence uint64) yaerrors.Error,
+	) yaerrors.Error
+}
+
+type lock interface {
+	Fence() uint64
+	Owner() string
+	Done() <-chan struct{}
+	Renew(ctx context.Context) yaerrors.Error
+	Release(ctx context.Context) yaerrors.Error
+}
+
+// genericLocker adapts *yalock.Locker[Cache] to locker.
+type genericLocker[Cache yacache.Container] struct {
+	*yalock.Locker[Cache]
+}
+
+func (g genericLocker[Cache]) TryAcquire(ctx context.Context, name string) (lock, yaerrors.Error) {
+	acquired, err := g.Locker.TryAcquire(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return acquired, nil
+}
+
+func (g genericLocker[Cache]) Acquire(ctx context.Context, name string) (lock, yaerrors.Error) {
+	acquired, err := g.Locker.Acquire(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return acquired, nil
+}
+
+// forEachBackend runs fn with a factory of lockers sharing one memory, Redis
+// or near-cache backend.
+func forEachBackend(t *testing.T, fn func(t *testing.T, newLocker func(yalock.Config) locker)) {
+	t.Helper()
+
+	t.Run("Memory", func(t *testing.T) {
+		cache := yacache.NewCache(yacache.NewMemoryContainer())
+
+		fn(t, func(config yalock.Config) locker {
+			return genericLocker[yacache.MemoryContainer]{yalock.NewLocker(cache, config)}
+		})
+	})
+
+	t.Run("Redis", func(t *testing.T) {
+		server := miniredis.RunT(t)
+		client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+
+		t.Cleanup(func() { _ = client.Close() })
+
+		cache := yacache.NewCache(client)
+
+		fn(t, func(config yalock.Config) locker {
+			return genericLocker[*redis.Client]{yalock.NewLocker(cache, config)}
+		})
+	})
+
+	t.Run("NearCache", func(t *testing.T) {
+		server := miniredis.RunT(t)
+		near := yacache.NewNearCache(
+			redis.NewClient(&redis.Options{Addr: server.Addr()}),
+			yacache.NearCacheConfig{},
+		)
+
+		t.Cleanup(func() { _ = near.Close() })
+
+		fn(t, func(config yalock.Config) locker {
+			return genericLocker[*redis.Client]{yalock.NewLocker(near, config)}
+		})
+	})
+}
+
+func TestLocker_TryAcquire_Works(t *testing.T) {
+	ctx := context.Background()
+
+	forEachBackend(t, func(t *testing.T, newLocker func(yalock.Config) locker) {
+		first, second := newLocker(yalock.Config{}), newLocker(yalock.Config{})
+
+		held, err := first.TryAcquire(ctx, lockName)
+		require.Nil(t, err)
+		assert.Equal(t, uint64(1), held.Fence())
+		assert.NotEmpty(t, held.Owner())
+
+		_, err = second.TryAcquire(ctx, lockName)
+		assert.True(t, errors.Is(err, yalock.ErrLockHeld))
+
+		other, err := second.TryAcquire(ctx, "other")
+		require.Nil(t, err)
+		assert.Equal(t, uint64(1), other.Fence(), "fences are per lock")
+
+		require.Nil(t, held.Release(ctx))
+
+		select {
+		case <-held.Done():
+		default:
+			t.Fatal("Done must be closed after Release")
+		}
+
+		err = held.Release(ctx)
+		assert.True(t, errors.Is(err, yalock.ErrLockNotHeld))
+
+		next, err := second.TryAcquire(ctx, lockName)
+		require.Nil(t, err)
+		assert.Equal(t, uint64(2), next.Fence())
+		assert.NotEqual(t, held.Owner(), next.Owner())
+
+		require.Nil(t, next.Release(ctx))
+		require.Nil(t, other.Release(ctx))
+	})
+}
+
+func TestLocker_Lease_Works(t *testing.T) {
+	ctx := context.Background()
+
+	forEachBackend(t, func(t *testing.T, newLocker func(yalock.Config) locker) {
+		t.Run("[Expiry] an unrenewed lease is taken over", func(t *testing.T) {
+			config := yalock.Config{TTL: 20 * time.Millisecond, RenewInterval: -1}
+
+			stale, err := newLocker(config).TryAcquire(ctx, lockName+"-expiry")
+			require.Nil(t, err)
+
+			time.Sleep(40 * time.Millisecond)
+
+			current, err := newLocker(config).TryAcquire(ctx, lockName+"-expiry")
+			require.Nil(t, err)
+			assert.Greater(t, current.Fence(), stale.Fence())
+
+			err = stale.Renew(ctx)
+			assert.True(t, errors.Is(err, yalock.ErrLockNotHeld))
+
+			err = stale.Release(ctx)
+			assert.True(t, errors.Is(err, yalock.ErrLockNotHeld))
+
+			require.Nil(t, current.Release(ctx))
+		})
+
+		t.Run("[Renewal] a renewed lease outlives its TTL", func(t *testing.T) {
+			config := yalock.Config{TTL: 60 * time.Millisecond}
+
+			held, err := newLocker(config).TryAcquire(ctx, lockName+"-renewal")
+			require.Nil(t, err)
+
+			time.Sleep(200 * time.Millisecond)
+
+			_, err = newLocker(config).TryAcquire(ctx, lockName+"-renewal")
+			assert.True(t, errors.Is(err, yalock.ErrLockHeld))
+
+			require.Nil(t, held.Release(ctx))
+		})
+	})
+}
+
+func TestLocker_Acquire_Works(t *testing.T) {
+	ctx := context.Background()
+
+	forEachBackend(t, func(t *testing.T, newLocker func(yalock.Config) locker) {
+		config := yalock.Config{RetryInterval: 5 * time.Millisecond}
+
+		held, err := newLocker(config).TryAcquire(ctx, lockName)
+		require.Nil(t, err)
+
+		t.Run("[Acquire] gives up when ctx is done", func(t *testing.T) {
+			timeout, cancel := context.WithTimeout(ctx, 30*time.Millisecond)
+			defer cancel()
+
+			_, err := newLocker(config).Acquire(timeout, lockName)
+			assert.True(t, errors.Is(err, yalock.ErrLockHeld))
+			assert.True(t, errors.Is(err, context.DeadlineExceeded))
+		})
+
+		t.Run("[Acquire] waits for the holder to release", func(t *testing.T) {
+			time.AfterFunc(20*time.Millisecond, func() { _ = held.Release(ctx) })
+
+			next, err := newLocker(config).Acquire(ctx, lockName)
+			require.Nil(t, err)
+			assert.Greater(t, next.Fence(), held.Fence())
+
+			require.Nil(t, next.Release(ctx))
+		})
+	})
+}
+
+func TestLocker_Do_MutualExclusion(t *testing.T) {
+	ctx := context.Background()
+
+	forEachBackend(t, func(t *testing.T, newLocker func(yalock.Config) locker) {
+		const workers = 8
+
+		var (
+			wg      sync.WaitGroup
+			inside  atomic.Int32
+			overlap atomic.Bool
+			fences  sync.Map
+		)
+
+		for range workers {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				worker := newLocker(yalock.Config{RetryInterval: time.Millisecond})
+
+				err := worker.Do(ctx, lockName, func(_ context.Context, fence uint64) yaerrors.Error {
+					if inside.Add(1) > 1 {
+						overlap.Store(true)
+					}
+
+					_, duplicate := fences.LoadOrStore(fence, struct{}{})
+					assert.False(t, duplicate, "fence %d handed out twice", fence)
+
+					time.Sleep(time.Millisecond)
+					inside.Add(-1)
+
+					return nil
+				})
+				assert.Nil(t, err)
+			}()
+		}
+
+		wg.Wait()
+
+		assert.False(t, overlap.Load())
+	})
+}
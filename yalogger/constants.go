@@ -1,6 +1,9 @@
 package yalogger
 
-import "time"
+import (
+	"log/slog"
+	"time"
+)
 
 type Level uint8
 
@@ -29,14 +32,68 @@ type BaseLoggerType uint8
 
 const (
 	Logrus BaseLoggerType = iota
+	Slog
+)
+
+// Format selects how a base logger renders entries.
+type Format uint8
+
+const (
+	// TextFormat renders entries as key=value lines.
+	TextFormat Format = iota
+	// JSONFormat renders entries as one JSON object per line.
+	JSONFormat
 )
 
+// Levels of the Slog backend that log/slog has no name for. Trace sits below
+// slog.LevelDebug, Fatal and Panic above slog.LevelError, with the same spacing of 4.
+const (
+	SlogLevelTrace slog.Level = slog.LevelDebug - 4
+	SlogLevelFatal slog.Level = slog.LevelError + 4
+	SlogLevelPanic slog.Level = slog.LevelError + 8
+)
+
+// SlogGroupSeparator joins a slog group name and an attribute key into the field name
+// NewSlogHandler writes.
+const SlogGroupSeparator = "."
+
+// slogLevelNames names the custom levels in the output of the Slog backend.
+var slogLevelNames = map[slog.Level]string{
+	SlogLevelTrace: "TRACE",
+	SlogLevelFatal: "FATAL",
+	SlogLevelPanic: "PANIC",
+}
+
 const (
 	KeyRequestID       = "request_id"
 	KeySystemRequestID = "system_request_id"
 	KeyUserID          = "user_id"
+	// KeyComponent is the field that names the component of a logger. Its string value
+	// selects the level override of LevelController.
+	KeyComponent = "component"
+	// KeyTraceID and KeySpanID carry the W3C trace and span IDs of the span context
+	// in the context a Logger was resolved from.
+	KeyTraceID = "trace_id"
+	KeySpanID  = "span_id"
 )
 
+// TraceParentHeader is the W3C trace context header GinAccessLogger reads and the key
+// of the value ContextWithTraceParent takes.
+const TraceParentHeader = "traceparent"
+
+// ComponentSeparators are the characters after which a component name continues its
+// parent component, so "yascheduler-client" inherits the override of "yascheduler".
+const ComponentSeparators = "-./"
+
+// GormComponent is the component NewGormLogger sets when its Logger has none.
+const GormComponent = "gorm"
+
+// DefaultSamplingTick is the counting window of SamplingConfig when Tick is not set.
+const DefaultSamplingTick = time.Second
+
+// samplerCounterCount is the number of counters messages are hashed into by sampling.
+const samplerCounterCount = 4096
+
 // GinContextLoggerKey is the default Gin context key used to store request-scoped loggers.
 const GinContextLoggerKey = "ContextLogger"
 
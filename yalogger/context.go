@@ -7,7 +7,8 @@ import "context"
 type contextLoggerKey struct{}
 
 // ContextWithLogger stores the given Logger in the context so that downstream code
-// can reuse the same structured fields via LoggerFromContext.
+// can reuse the same structured fields via LoggerFromContext. When ctx carries a span
+// context, the stored Logger gets its KeyTraceID and KeySpanID fields.
 //
 // Parameters:
 //
@@ -30,11 +31,13 @@ func ContextWithLogger(ctx context.Context, log Logger) context.Context {
 		return ctx
 	}
 
-	return context.WithValue(ctx, contextLoggerKey{}, log)
+	return context.WithValue(ctx, contextLoggerKey{}, withTraceFields(ctx, log))
 }
 
 // LoggerFromContext returns the Logger stored in the context merged with the
-// fallback's fields. Fields from the context Logger take precedence on conflict.
+// fallback's fields. Fields from the context Logger take precedence on conflict. The
+// KeyTraceID and KeySpanID fields of the span context in ctx are set last, so they
+// name the current span even when the stored Logger was built in a parent one.
 //
 // Parameters:
 //
@@ -55,12 +58,12 @@ func LoggerFromContext(ctx context.Context, fallback Logger) Logger {
 
 	contextLog, ok := ctx.Value(contextLoggerKey{}).(Logger)
 	if !ok || contextLog == nil {
-		return fallback
+		return withTraceFields(ctx, fallback)
 	}
 
 	if fallback == nil {
-		return contextLog
+		return withTraceFields(ctx, contextLog)
 	}
 
-	return fallback.MergeFields(contextLog)
+	return withTraceFields(ctx, fallback.MergeFields(contextLog))
 }
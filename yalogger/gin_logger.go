@@ -1,6 +1,7 @@
 package yalogger
 
 import (
+	"context"
 	"fmt"
 	"io"
 	stdhttp "net/http"
@@ -9,6 +10,7 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/propagation"
 )
 
 // GinLoggerConfig defines the configuration options for the Gin logging helpers.
@@ -17,9 +19,12 @@ import (
 // Defaults to GinContextLoggerKey when empty.
 // DisableRequestContext: Whether to skip attaching the Logger to the request's
 // context (ctx.Request.Context()).
+// DisableTraceContext: Whether GinAccessLogger skips reading the W3C traceparent and
+// tracestate headers and starting a span context for the request.
 type GinLoggerConfig struct {
 	ContextKey            string
 	DisableRequestContext bool
+	DisableTraceContext   bool
 }
 
 // ginLogLevel represents the severity used when forwarding raw Gin output that
@@ -128,7 +133,8 @@ func SetGinContextLogger(ctx *gin.Context, log Logger, config *GinLoggerConfig)
 }
 
 // GinLoggerFromContext returns the request Logger stored in the Gin context (or the
-// request's context) merged with the fallback's fields.
+// request's context) merged with the fallback's fields, with the trace fields of the
+// request's span context.
 //
 // Parameters:
 //
@@ -154,29 +160,36 @@ func GinLoggerFromContext(
 
 	cfg := normalizeGinLoggerConfig(config)
 
+	var requestCtx context.Context
+	if ctx.Request != nil {
+		requestCtx = ctx.Request.Context()
+	}
+
 	value, exists := ctx.Get(cfg.ContextKey)
 	if exists {
 		contextLog, ok := value.(Logger)
 		if ok && contextLog != nil {
 			if fallback == nil {
-				return contextLog
+				return withTraceFields(requestCtx, contextLog)
 			}
 
-			return fallback.MergeFields(contextLog)
+			return withTraceFields(requestCtx, fallback.MergeFields(contextLog))
 		}
 	}
 
-	if ctx.Request == nil {
-		return fallback
-	}
-
-	return LoggerFromContext(ctx.Request.Context(), fallback)
+	return LoggerFromContext(requestCtx, fallback)
 }
 
 // GinAccessLogger returns a Gin middleware that logs one compact access line per
 // request. The level is chosen from the response status: 5xx logs at Error, 4xx at
 // Warn, and everything else at Debug.
 //
+// Unless config.DisableTraceContext is set, the middleware continues the trace of the
+// W3C traceparent header (or starts a new one) with a span of its own on the
+// request's context, so every Logger resolved from that context, and every job or
+// mail sent with it, carries the same trace_id. Register it before the middleware
+// whose logs should carry the trace.
+//
 // Parameters:
 //
 //   - log: the fallback Logger used when the request carries none.
@@ -190,9 +203,19 @@ func GinLoggerFromContext(
 //
 //	router.Use(yalogger.GinAccessLogger(logger, nil))
 func GinAccessLogger(log Logger, config *GinLoggerConfig) gin.HandlerFunc {
+	cfg := normalizeGinLoggerConfig(config)
+
 	return func(ctx *gin.Context) {
 		start := time.Now()
 
+		if !cfg.DisableTraceContext && ctx.Request != nil {
+			requestCtx := traceContextPropagator.Extract(
+				ctx.Request.Context(),
+				propagation.HeaderCarrier(ctx.Request.Header),
+			)
+			ctx.Request = ctx.Request.WithContext(ContextWithChildSpan(requestCtx))
+		}
+
 		ctx.Next()
 
 		requestLog := GinLoggerFromContext(ctx, log, config)
@@ -35,7 +35,9 @@ type compactGormLogger struct {
 //
 // Parameters:
 //
-//   - log: the underlying Logger that receives the rendered GORM messages.
+//   - log: the underlying Logger that receives the rendered GORM messages. Unless it
+//     already has a KeyComponent field, GormComponent is set, so LevelController
+//     overrides for "gorm" apply.
 //   - config: the logger configuration. If nil, sensible defaults are used
 //     (Info level, DefaultGormSlowQueryThreshold, and record-not-found errors ignored).
 //
@@ -57,6 +59,10 @@ func NewGormLogger(log Logger, config *GormLoggerConfig) gormlogger.Interface {
 		}
 	}
 
+	if log != nil && log.GetField(KeyComponent) == nil {
+		log = log.WithField(KeyComponent, GormComponent)
+	}
+
 	return &compactGormLogger{
 		log:                  log,
 		level:                config.Level,
@@ -0,0 +1,274 @@
+package yalogger
+
+import (
+	"encoding/json"
+	"maps"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// LevelController holds the level of a base logger and the level overrides of its
+// components, and can change them at runtime. Every Logger built from a base logger
+// reads its controller on each entry, so a change applies to them at once, without
+// rebuilding loggers.
+//
+// A component is the string value of the KeyComponent field. Its override also applies
+// to sub-components whose name continues it after one of ComponentSeparators: an
+// override for "yascheduler" covers "yascheduler-client" unless that one has its own.
+//
+// LevelController is an http.Handler, see ServeHTTP.
+type LevelController struct {
+	level      atomic.Uint32
+	components atomic.Pointer[map[string]Level]
+	mutex      sync.Mutex
+}
+
+// levelPayload is the JSON body read and written by LevelController.ServeHTTP.
+type levelPayload struct {
+	Level      string            `json:"level,omitempty"`
+	Components map[string]string `json:"components,omitempty"`
+}
+
+// levelError is the JSON body of a rejected LevelController.ServeHTTP request.
+type levelError struct {
+	Error string `json:"error"`
+}
+
+// NewLevelController creates a LevelController.
+//
+// Parameters:
+//
+//   - level: the level of loggers without a component override.
+//   - components: the level overrides keyed by component name. The map is copied.
+//
+// Returns:
+//
+//   - *LevelController: a controller to pass in Config.LevelController.
+//
+// Example usage:
+//
+//	levels := yalogger.NewLevelController(yalogger.InfoLevel, map[string]yalogger.Level{
+//	    "yascheduler": yalogger.DebugLevel,
+//	    "gorm":        yalogger.WarnLevel,
+//	})
+//
+//	base := yalogger.NewBaseLogger(&yalogger.Config{LevelController: levels})
+func NewLevelController(level Level, components map[string]Level) *LevelController {
+	controller := &LevelController{}
+	controller.level.Store(uint32(level))
+
+	overrides := maps.Clone(components)
+	if overrides == nil {
+		overrides = map[string]Level{}
+	}
+
+	controller.components.Store(&overrides)
+
+	return controller
+}
+
+// Level returns the level of loggers without a component override.
+func (c *LevelController) Level() Level {
+	return Level(c.level.Load())
+}
+
+// SetLevel changes the level of loggers without a component override.
+//
+// Example usage:
+//
+//	levels.SetLevel(yalogger.DebugLevel)
+func (c *LevelController) SetLevel(level Level) {
+	c.level.Store(uint32(level))
+}
+
+// ComponentLevel returns the level override of component, if any. Only an override set
+// for exactly this name is returned; see Enabled for the lookup used when logging.
+func (c *LevelController) ComponentLevel(component string) (Level, bool) {
+	level, ok := (*c.components.Load())[component]
+
+	return level, ok
+}
+
+// ComponentLevels returns a copy of all level overrides keyed by component name.
+func (c *LevelController) ComponentLevels() map[string]Level {
+	return maps.Clone(*c.components.Load())
+}
+
+// SetComponentLevel sets the level override of component.
+//
+// Example usage:
+//
+//	levels.SetComponentLevel("gorm", yalogger.WarnLevel)
+func (c *LevelController) SetComponentLevel(component string, level Level) {
+	c.updateComponents(func(components map[string]Level) {
+		components[component] = level
+	})
+}
+
+// DeleteComponentLevel removes the level override of component, so it logs at the
+// level of its parent component or of the controller again.
+func (c *LevelController) DeleteComponentLevel(component string) {
+	c.updateComponents(func(components map[string]Level) {
+		delete(components, component)
+	})
+}
+
+// Enabled reports whether an entry at level is written by a logger of component. An
+// empty component uses the controller level.
+//
+// Example usage:
+//
+//	if levels.Enabled(yalogger.TraceLevel, "yatgbot") {
+//	    logger.Tracef("update: %+v", update)
+//	}
+func (c *LevelController) Enabled(level Level, component string) bool {
+	threshold := c.Level()
+
+	if component != "" {
+		if override, ok := c.lookupComponent(component); ok {
+			threshold = override
+		}
+	}
+
+	return level <= threshold
+}
+
+// ServeHTTP reports and changes the levels as JSON.
+//
+// GET answers with the current levels:
+//
+//	{"level":"info","components":{"gorm":"warn"}}
+//
+// PUT takes a body of the same shape and answers with the levels after the change. An
+// omitted level is left as is, and a component with an empty level loses its override.
+// A body with an unknown level is rejected with 400 and nothing is changed.
+//
+// Example usage:
+//
+//	mux.Handle("/debug/log-levels", levels)
+func (c *LevelController) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	switch request.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		if err := c.apply(request); err != nil {
+			writeLevelJSON(writer, http.StatusBadRequest, levelError{Error: err.Error()})
+
+			return
+		}
+	default:
+		writer.Header().Set("Allow", strings.Join([]string{http.MethodGet, http.MethodPut}, ", "))
+		writeLevelJSON(
+			writer,
+			http.StatusMethodNotAllowed,
+			levelError{Error: http.StatusText(http.StatusMethodNotAllowed)},
+		)
+
+		return
+	}
+
+	payload := levelPayload{
+		Level:      levelName(c.Level()),
+		Components: map[string]string{},
+	}
+
+	for component, level := range *c.components.Load() {
+		payload.Components[component] = levelName(level)
+	}
+
+	writeLevelJSON(writer, http.StatusOK, payload)
+}
+
+// apply decodes a PUT body and applies it once every level in it is valid.
+func (c *LevelController) apply(request *http.Request) error {
+	var payload levelPayload
+
+	if err := json.NewDecoder(request.Body).Decode(&payload); err != nil {
+		return err
+	}
+
+	var level Level
+
+	if payload.Level != "" {
+		if err := level.Unmarshal(payload.Level); err != nil {
+			return err
+		}
+	}
+
+	overrides := make(map[string]Level, len(payload.Components))
+
+	for component, name := range payload.Components {
+		if name == "" {
+			continue
+		}
+
+		var override Level
+		if err := override.Unmarshal(name); err != nil {
+			return err
+		}
+
+		overrides[component] = override
+	}
+
+	if payload.Level != "" {
+		c.SetLevel(level)
+	}
+
+	c.updateComponents(func(components map[string]Level) {
+		for component, name := range payload.Components {
+			if name == "" {
+				delete(components, component)
+			} else {
+				components[component] = overrides[component]
+			}
+		}
+	})
+
+	return nil
+}
+
+// updateComponents applies update to a copy of the overrides and swaps it in, so
+// loggers read the overrides without locking.
+func (c *LevelController) updateComponents(update func(components map[string]Level)) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	components := maps.Clone(*c.components.Load())
+	update(components)
+	c.components.Store(&components)
+}
+
+// lookupComponent returns the override of component or of its nearest parent.
+func (c *LevelController) lookupComponent(component string) (Level, bool) {
+	components := *c.components.Load()
+	if len(components) == 0 {
+		return 0, false
+	}
+
+	for {
+		if level, ok := components[component]; ok {
+			return level, true
+		}
+
+		index := strings.LastIndexAny(component, ComponentSeparators)
+		if index <= 0 {
+			return 0, false
+		}
+
+		component = component[:index]
+	}
+}
+
+// levelName returns the lower-case name of level, as accepted by Level.Unmarshal.
+func levelName(level Level) string {
+	return strings.ToLower(level.String())
+}
+
+// writeLevelJSON writes body as the JSON response of LevelController.ServeHTTP.
+func writeLevelJSON(writer http.ResponseWriter, status int, body any) {
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(status)
+
+	_ = json.NewEncoder(writer).Encode(body)
+}
@@ -0,0 +1,136 @@
+package yalogger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLevelControllerComponentOverrides(t *testing.T) {
+	levels := NewLevelController(InfoLevel, map[string]Level{
+		"yascheduler":       DebugLevel,
+		"yascheduler-local": TraceLevel,
+		"gorm":              WarnLevel,
+	})
+
+	buffer := bytes.NewBuffer(nil)
+	log := NewBaseLogger(&Config{
+		DisableTimestamp: true,
+		Output:           buffer,
+		LevelController:  levels,
+	}).NewLogger()
+
+	log.Debug("root debug")
+	log.WithField(KeyComponent, "yascheduler-client").Debug("client debug")
+	log.WithField(KeyComponent, "yascheduler-client").Trace("client trace")
+	log.WithField(KeyComponent, "yascheduler-local").Trace("local trace")
+	NewGormLogger(log, nil).Info(context.Background(), "gorm info")
+	log.WithField(KeyComponent, GormComponent).Warn("gorm warn")
+
+	output := buffer.String()
+	for _, hidden := range []string{"root debug", "client trace", "gorm info"} {
+		if strings.Contains(output, hidden) {
+			t.Fatalf("expected %q to be filtered, got %q", hidden, output)
+		}
+	}
+
+	for _, visible := range []string{"client debug", "local trace", "gorm warn"} {
+		if !strings.Contains(output, visible) {
+			t.Fatalf("expected %q to be written, got %q", visible, output)
+		}
+	}
+
+	levels.SetLevel(DebugLevel)
+	levels.DeleteComponentLevel("yascheduler-local")
+	buffer.Reset()
+
+	log.Debug("root debug")
+	log.WithField(KeyComponent, "yascheduler-local").Trace("local trace")
+
+	output = buffer.String()
+	if !strings.Contains(output, "root debug") || strings.Contains(output, "local trace") {
+		t.Fatalf("expected runtime changes to apply to existing loggers, got %q", output)
+	}
+}
+
+func TestLevelControllerServeHTTP(t *testing.T) {
+	levels := NewLevelController(InfoLevel, map[string]Level{"gorm": WarnLevel})
+
+	recorder := httptest.NewRecorder()
+	levels.ServeHTTP(recorder, httptest.NewRequestWithContext(
+		context.Background(),
+		http.MethodPut,
+		"/log-levels",
+		strings.NewReader(`{"level":"debug","components":{"gorm":"","yascheduler":"trace"}}`),
+	))
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", recorder.Code, recorder.Body)
+	}
+
+	var payload levelPayload
+	if err := json.Unmarshal(recorder.Body.Bytes(), &payload); err != nil {
+		t.Fatal(err)
+	}
+
+	if payload.Level != "debug" || len(payload.Components) != 1 ||
+		payload.Components["yascheduler"] != "trace" {
+		t.Fatalf("unexpected levels %+v", payload)
+	}
+
+	if levels.Level() != DebugLevel {
+		t.Fatalf("expected the controller level to change, got %v", levels.Level())
+	}
+
+	recorder = httptest.NewRecorder()
+	levels.ServeHTTP(recorder, httptest.NewRequestWithContext(
+		context.Background(),
+		http.MethodPut,
+		"/log-levels",
+		strings.NewReader(`{"level":"info","components":{"gorm":"loud"}}`),
+	))
+
+	if recorder.Code != http.StatusBadRequest || levels.Level() != DebugLevel {
+		t.Fatalf("expected a rejected request to change nothing, got %d", recorder.Code)
+	}
+
+	recorder = httptest.NewRecorder()
+	levels.ServeHTTP(recorder, httptest.NewRequestWithContext(
+		context.Background(),
+		http.MethodDelete,
+		"/log-levels",
+		nil,
+	))
+
+	if recorder.Code != http.StatusMethodNotAllowed || recorder.Header().Get("Allow") == "" {
+		t.Fatalf("expected 405 with an Allow header, got %d", recorder.Code)
+	}
+}
+
+func TestJSONFormat(t *testing.T) {
+	for _, backend := range []BaseLoggerType{Logrus, Slog} {
+		buffer := bytes.NewBuffer(nil)
+		log := NewBaseLogger(&Config{
+			BaseLoggerType:   backend,
+			Level:            InfoLevel,
+			DisableTimestamp: true,
+			Output:           buffer,
+			Format:           JSONFormat,
+		}).NewLogger()
+
+		log.WithUserID(42).Info("hello")
+
+		var entry map[string]any
+		if err := json.Unmarshal(buffer.Bytes(), &entry); err != nil {
+			t.Fatalf("backend %d: expected one JSON object, got %q: %v", backend, buffer, err)
+		}
+
+		if entry["msg"] != "hello" || entry[KeyUserID] != 42.0 {
+			t.Fatalf("backend %d: unexpected entry %v", backend, entry)
+		}
+	}
+}
@@ -11,12 +11,14 @@ import (
 // It wraps a logrus.Entry to provide structured logging.
 type logrusAdapter struct {
 	entry *logrus.Entry
+	gate  *logGate
 }
 
 // baseLogrus holds a reference to a logrus.Logger instance.
 // It serves as the base logger from which new Logger instances can be created.
 type baseLogrus struct {
 	logger *logrus.Logger
+	gate   *logGate
 }
 
 // NewBaseLogger creates and configures a new base logger based on the provided configuration.
@@ -42,14 +44,17 @@ func NewBaseLogger(config *Config) BaseLogger {
 	switch config.BaseLoggerType {
 	case Logrus:
 		base := logrus.New()
-		base.SetLevel(logrus.Level(config.Level))
-		base.SetFormatter(&logrus.TextFormatter{
-			FullTimestamp:    config.FullTimestamp,
-			TimestampFormat:  config.TimestampFormat,
-			DisableTimestamp: config.DisableTimestamp,
-		})
+		// Levels are checked by the gate, which knows the component overrides.
+		base.SetLevel(logrus.TraceLevel)
+		base.SetFormatter(logrusFormatter(config))
+
+		if config.Output != nil {
+			base.SetOutput(config.Output)
+		}
 
-		return &baseLogrus{logger: base}
+		return &baseLogrus{logger: base, gate: newLogGate(config)}
+	case Slog:
+		return newBaseSlog(config)
 	default:
 		panic("Unsupported logger type, you are a teapot!!!")
 	}
@@ -62,7 +67,7 @@ func NewBaseLogger(config *Config) BaseLogger {
 //
 //   - Logger: A new instance of logrusAdapter that wraps a new logrus.Entry derived from the base logger.
 func (b *baseLogrus) NewLogger() Logger {
-	return &logrusAdapter{entry: logrus.NewEntry(b.logger)}
+	return &logrusAdapter{entry: logrus.NewEntry(b.logger), gate: b.gate}
 }
 
 // Info logs a message at the Info level.
@@ -75,7 +80,9 @@ func (b *baseLogrus) NewLogger() Logger {
 //
 //	logger.Info("Application started")
 func (l *logrusAdapter) Info(msg string) {
-	l.entry.Info(msg)
+	if l.gate.allow(InfoLevel, l.entry.Data, msg) {
+		l.entry.Info(msg)
+	}
 }
 
 // Infof logs a formatted message at the Info level.
@@ -89,7 +96,9 @@ func (l *logrusAdapter) Info(msg string) {
 //
 //	logger.Infof("Server is listening on port %d", port)
 func (l *logrusAdapter) Infof(format string, args ...any) {
-	l.entry.Infof(format, args...)
+	if l.gate.allow(InfoLevel, l.entry.Data, format) {
+		l.entry.Infof(format, args...)
+	}
 }
 
 // Error logs a message at the Error level.
@@ -102,7 +111,9 @@ func (l *logrusAdapter) Infof(format string, args ...any) {
 //
 //	logger.Error("Database connection failed")
 func (l *logrusAdapter) Error(msg string) {
-	l.entry.Error(msg)
+	if l.gate.allow(ErrorLevel, l.entry.Data, msg) {
+		l.entry.Error(msg)
+	}
 }
 
 // Errorf logs a formatted error message at the Error level.
@@ -116,7 +127,9 @@ func (l *logrusAdapter) Error(msg string) {
 //
 //	logger.Errorf("Failed to read file: %s", filename)
 func (l *logrusAdapter) Errorf(format string, args ...any) {
-	l.entry.Errorf(format, args...)
+	if l.gate.allow(ErrorLevel, l.entry.Data, format) {
+		l.entry.Errorf(format, args...)
+	}
 }
 
 // Warn logs a warning message at the Warn level.
@@ -129,7 +142,9 @@ func (l *logrusAdapter) Errorf(format string, args ...any) {
 //
 //	logger.Warn("Low disk space")
 func (l *logrusAdapter) Warn(msg string) {
-	l.entry.Warn(msg)
+	if l.gate.allow(WarnLevel, l.entry.Data, msg) {
+		l.entry.Warn(msg)
+	}
 }
 
 // Warnf logs a formatted warning message at the Warn level.
@@ -143,7 +158,9 @@ func (l *logrusAdapter) Warn(msg string) {
 //
 //	logger.Warnf("Cache miss rate: %.2f%%", rate)
 func (l *logrusAdapter) Warnf(format string, args ...any) {
-	l.entry.Warnf(format, args...)
+	if l.gate.allow(WarnLevel, l.entry.Data, format) {
+		l.entry.Warnf(format, args...)
+	}
 }
 
 // Debug logs a debug message at the Debug level.
@@ -156,7 +173,9 @@ func (l *logrusAdapter) Warnf(format string, args ...any) {
 //
 //	logger.Debug("User object created")
 func (l *logrusAdapter) Debug(msg string) {
-	l.entry.Debug(msg)
+	if l.gate.allow(DebugLevel, l.entry.Data, msg) {
+		l.entry.Debug(msg)
+	}
 }
 
 // Debugf logs a formatted debug message at the Debug level.
@@ -170,7 +189,9 @@ func (l *logrusAdapter) Debug(msg string) {
 //
 //	logger.Debugf("Response time: %dms", ms)
 func (l *logrusAdapter) Debugf(format string, args ...any) {
-	l.entry.Debugf(format, args...)
+	if l.gate.allow(DebugLevel, l.entry.Data, format) {
+		l.entry.Debugf(format, args...)
+	}
 }
 
 // Fatal logs a message at the Fatal level and terminates the application.
@@ -237,7 +258,9 @@ func (l *logrusAdapter) Panicf(format string, args ...any) {
 //
 //	logger.Trace("Entered handler function")
 func (l *logrusAdapter) Trace(msg string) {
-	l.entry.Trace(msg)
+	if l.gate.allow(TraceLevel, l.entry.Data, msg) {
+		l.entry.Trace(msg)
+	}
 }
 
 // Tracef logs a formatted trace message at the Trace level.
@@ -251,7 +274,9 @@ func (l *logrusAdapter) Trace(msg string) {
 //
 //	logger.Tracef("Payload: %+v", payload)
 func (l *logrusAdapter) Tracef(format string, args ...any) {
-	l.entry.Tracef(format, args...)
+	if l.gate.allow(TraceLevel, l.entry.Data, format) {
+		l.entry.Tracef(format, args...)
+	}
 }
 
 // WithField returns a new Logger instance with a single key-value pair added to the log context.
@@ -265,7 +290,7 @@ func (l *logrusAdapter) Tracef(format string, args ...any) {
 //
 //	logger.WithField("user_id", 42).Info("User logged in")
 func (l *logrusAdapter) WithField(key string, value any) Logger {
-	return &logrusAdapter{entry: l.entry.WithField(key, value)}
+	return &logrusAdapter{entry: l.entry.WithField(key, value), gate: l.gate}
 }
 
 // WithFields returns a new Logger instance with multiple key-value pairs added to the log context.
@@ -278,7 +303,7 @@ func (l *logrusAdapter) WithField(key string, value any) Logger {
 //
 //	logger.WithFields(map[string]any{"user_id": 42, "role": "admin"}).Info("Access granted")
 func (l *logrusAdapter) WithFields(fields map[string]any) Logger {
-	return &logrusAdapter{entry: l.entry.WithFields(fields)}
+	return &logrusAdapter{entry: l.entry.WithFields(fields), gate: l.gate}
 }
 
 // WithRequestStringID returns a new Logger instance with a string request ID added to the context.
@@ -291,7 +316,7 @@ func (l *logrusAdapter) WithFields(fields map[string]any) Logger {
 //
 //	logger.WithRequestStringID("req-123").Info("Request started")
 func (l *logrusAdapter) WithRequestStringID(id string) Logger {
-	return &logrusAdapter{entry: l.entry.WithField(KeyRequestID, id)}
+	return &logrusAdapter{entry: l.entry.WithField(KeyRequestID, id), gate: l.gate}
 }
 
 // WithRequestUUID returns a new Logger instance with a UUID-based request ID added to the context.
@@ -304,7 +329,7 @@ func (l *logrusAdapter) WithRequestStringID(id string) Logger {
 //
 //	logger.WithRequestUUID(uuid.New()).Info("Tracking UUID request")
 func (l *logrusAdapter) WithRequestUUID(id uuid.UUID) Logger {
-	return &logrusAdapter{entry: l.entry.WithField(KeyRequestID, id)}
+	return &logrusAdapter{entry: l.entry.WithField(KeyRequestID, id), gate: l.gate}
 }
 
 // WithRequestID returns a new Logger instance with a numeric request ID added to the context.
@@ -317,7 +342,7 @@ func (l *logrusAdapter) WithRequestUUID(id uuid.UUID) Logger {
 //
 //	logger.WithRequestID(1001).Info("Handling request")
 func (l *logrusAdapter) WithRequestID(id uint64) Logger {
-	return &logrusAdapter{entry: l.entry.WithField(KeyRequestID, id)}
+	return &logrusAdapter{entry: l.entry.WithField(KeyRequestID, id), gate: l.gate}
 }
 
 // WithRandomRequestID returns a new Logger instance with a randomly generated numeric request ID.
@@ -329,6 +354,7 @@ func (l *logrusAdapter) WithRandomRequestID() Logger {
 	return &logrusAdapter{
 		//nolint:gosec // Randomness quality here could be neglected, as this is just for logging
 		entry: l.entry.WithField(KeyRequestID, rand.Uint64()),
+		gate:  l.gate,
 	}
 }
 
@@ -342,7 +368,7 @@ func (l *logrusAdapter) WithRandomRequestID() Logger {
 //
 //	logger.WithSystemRequestID(3).Info("Using config #3")
 func (l *logrusAdapter) WithSystemRequestID(id uint8) Logger {
-	return &logrusAdapter{entry: l.entry.WithField(KeySystemRequestID, id)}
+	return &logrusAdapter{entry: l.entry.WithField(KeySystemRequestID, id), gate: l.gate}
 }
 
 // WithUserID returns a new Logger instance with a user ID added to the log context.
@@ -355,7 +381,7 @@ func (l *logrusAdapter) WithSystemRequestID(id uint8) Logger {
 //
 //	logger.WithUserID(12345).Info("User performed action")
 func (l *logrusAdapter) WithUserID(userID uint64) Logger {
-	return &logrusAdapter{entry: l.entry.WithField(KeyUserID, userID)}
+	return &logrusAdapter{entry: l.entry.WithField(KeyUserID, userID), gate: l.gate}
 }
 
 // GetFields returns the current log context fields as a map.
@@ -408,6 +434,7 @@ func (l *logrusAdapter) GetField(key string) any {
 func (l *logrusAdapter) MergeFields(other Logger) Logger {
 	return &logrusAdapter{
 		entry: l.entry.WithFields(other.GetFields()),
+		gate:  l.gate,
 	}
 }
 
@@ -423,3 +450,19 @@ func (l *logrusAdapter) MergeFields(other Logger) Logger {
 func (l *logrusAdapter) DeleteField(key string) {
 	delete(l.entry.Data, key)
 }
+
+// logrusFormatter builds the formatter of config.
+func logrusFormatter(config *Config) logrus.Formatter {
+	if config.Format == JSONFormat {
+		return &logrus.JSONFormatter{
+			TimestampFormat:  config.TimestampFormat,
+			DisableTimestamp: config.DisableTimestamp,
+		}
+	}
+
+	return &logrus.TextFormatter{
+		FullTimestamp:    config.FullTimestamp,
+		TimestampFormat:  config.TimestampFormat,
+		DisableTimestamp: config.DisableTimestamp,
+	}
+}
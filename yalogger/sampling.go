@@ -0,0 +1,135 @@
+package yalogger
+
+import (
+	"hash/fnv"
+	"sync/atomic"
+	"time"
+)
+
+// SamplingConfig defines how entries of hot paths are sampled.
+//
+// Entries are counted per level and message (the format string for the formatted
+// methods) within each Tick. The first First entries of a tick are written, then only
+// every Thereafter-th one; a Thereafter of 0 drops the rest of the tick.
+//
+// Levels: The levels that are sampled. Every level is sampled when empty.
+// Tick: The length of a counting window. Defaults to DefaultSamplingTick.
+// First: The entries of a message written per tick before sampling starts.
+// Thereafter: After First, one in Thereafter entries of a message is written.
+type SamplingConfig struct {
+	Levels     []Level
+	Tick       time.Duration
+	First      uint64
+	Thereafter uint64
+}
+
+// sampler decides which entries SamplingConfig keeps. Messages are hashed into a fixed
+// set of counters, so memory stays bounded and rare collisions only share a budget.
+type sampler struct {
+	levels     [TraceLevel + 1]bool
+	tick       int64
+	first      uint64
+	thereafter uint64
+	counters   [samplerCounterCount]samplerCounter
+}
+
+// samplerCounter counts the entries of one bucket within the current tick.
+type samplerCounter struct {
+	resetAt atomic.Int64
+	count   atomic.Uint64
+}
+
+// logGate decides whether an entry is written: the level of its component first, then
+// sampling. Fatal and Panic entries bypass it, since they end the program.
+type logGate struct {
+	levels  *LevelController
+	sampler *sampler
+}
+
+// newLogGate builds the gate of config.
+func newLogGate(config *Config) *logGate {
+	levels := config.LevelController
+	if levels == nil {
+		levels = NewLevelController(config.Level, config.ComponentLevels)
+	}
+
+	return &logGate{levels: levels, sampler: newSampler(config.Sampling)}
+}
+
+// allow reports whether an entry at level with fields and message msg is written.
+func (g *logGate) allow(level Level, fields map[string]any, msg string) bool {
+	if g == nil {
+		return true
+	}
+
+	component, _ := fields[KeyComponent].(string)
+	if !g.levels.Enabled(level, component) {
+		return false
+	}
+
+	return g.sampler.allow(level, msg)
+}
+
+// newSampler builds the sampler of config, or returns nil when config is nil.
+func newSampler(config *SamplingConfig) *sampler {
+	if config == nil {
+		return nil
+	}
+
+	tick := config.Tick
+	if tick <= 0 {
+		tick = DefaultSamplingTick
+	}
+
+	result := &sampler{
+		tick:       int64(tick),
+		first:      config.First,
+		thereafter: config.Thereafter,
+	}
+
+	for level := range result.levels {
+		result.levels[level] = len(config.Levels) == 0
+	}
+
+	for _, level := range config.Levels {
+		if level <= TraceLevel {
+			result.levels[level] = true
+		}
+	}
+
+	return result
+}
+
+// allow counts the entry and reports whether it is within the sampling budget.
+func (s *sampler) allow(level Level, msg string) bool {
+	if s == nil || level > TraceLevel || !s.levels[level] {
+		return true
+	}
+
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte{byte(level)})
+	_, _ = hash.Write([]byte(msg))
+
+	count := s.counters[hash.Sum32()%samplerCounterCount].inc(time.Now().UnixNano(), s.tick)
+	if count <= s.first {
+		return true
+	}
+
+	return s.thereafter != 0 && (count-s.first)%s.thereafter == 0
+}
+
+// inc counts an entry at now and returns its position within the current tick.
+func (c *samplerCounter) inc(now, tick int64) uint64 {
+	resetAt := c.resetAt.Load()
+	if resetAt > now {
+		return c.count.Add(1)
+	}
+
+	c.count.Store(1)
+
+	if !c.resetAt.CompareAndSwap(resetAt, now+tick) {
+		return c.count.Add(1)
+	}
+
+	return 1
+}
@@ -0,0 +1,64 @@
+package yalogger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSamplingKeepsFirstThenEveryNth(t *testing.T) {
+	buffer := bytes.NewBuffer(nil)
+	log := NewBaseLogger(&Config{
+		BaseLoggerType:   Slog,
+		Level:            TraceLevel,
+		DisableTimestamp: true,
+		Output:           buffer,
+		Sampling: &SamplingConfig{
+			Levels:     []Level{TraceLevel},
+			Tick:       time.Hour,
+			First:      2,
+			Thereafter: 3,
+		},
+	}).NewLogger()
+
+	for update := range 10 {
+		log.Tracef("update %d", update)
+		log.Debugf("debug %d", update)
+	}
+
+	output := buffer.String()
+	if traces := strings.Count(output, "msg=\"update"); traces != 4 {
+		t.Fatalf("expected updates 0, 1, 4 and 7 to be kept, got %d in %q", traces, output)
+	}
+
+	for _, kept := range []string{"update 0", "update 1", "update 4", "update 7"} {
+		if !strings.Contains(output, kept) {
+			t.Fatalf("expected %q to be kept, got %q", kept, output)
+		}
+	}
+
+	if debugs := strings.Count(output, "msg=\"debug"); debugs != 10 {
+		t.Fatalf("expected debug entries not to be sampled, got %d", debugs)
+	}
+}
+
+func TestSamplingResetsEveryTick(t *testing.T) {
+	sampling := newSampler(&SamplingConfig{Tick: time.Hour, First: 1})
+
+	if !sampling.allow(InfoLevel, "hot") || sampling.allow(InfoLevel, "hot") {
+		t.Fatal("expected only the first entry of the tick to be kept")
+	}
+
+	if !sampling.allow(WarnLevel, "hot") {
+		t.Fatal("expected levels to be counted separately")
+	}
+
+	for index := range sampling.counters {
+		sampling.counters[index].resetAt.Store(0)
+	}
+
+	if !sampling.allow(InfoLevel, "hot") {
+		t.Fatal("expected a new tick to restart the budget")
+	}
+}
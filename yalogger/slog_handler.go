@@ -0,0 +1,143 @@
+package yalogger
+
+import (
+	"context"
+	"log/slog"
+	"maps"
+	"strings"
+)
+
+// slogHandler is a slog.Handler that writes every record into a Logger.
+type slogHandler struct {
+	log    Logger
+	fields map[string]any
+	group  string
+}
+
+// NewSlogHandler returns a slog.Handler that writes records into log, so libraries that
+// log through log/slog end up in the same structured logs as the rest of the app.
+//
+// The Logger stored in the record's context by ContextWithLogger (or the Gin helpers)
+// is merged over log, the way LoggerFromContext does it, so request and user IDs stay
+// attached. Attributes become fields; attributes inside a group are named
+// "group.key". Records at Error level or above are written with Error, and the Trace,
+// Debug, Info and Warn ranges map to the Logger method of the same name. Level
+// filtering is left to log.
+//
+// Parameters:
+//
+//   - log: the Logger that receives the records. If nil, a default base logger is used.
+//
+// Returns:
+//
+//   - slog.Handler: a handler writing into log.
+//
+// Example usage:
+//
+//	slog.SetDefault(slog.New(yalogger.NewSlogHandler(logger)))
+//
+//	slog.InfoContext(ctx, "cache warmed", "entries", 128)
+func NewSlogHandler(log Logger) slog.Handler {
+	if log == nil {
+		log = NewBaseLogger(nil).NewLogger()
+	}
+
+	return &slogHandler{log: log, fields: map[string]any{}}
+}
+
+// Enabled reports true for every level: the Logger filters by its own level.
+func (h *slogHandler) Enabled(_ context.Context, _ slog.Level) bool {
+	return true
+}
+
+// Handle writes record into the Logger of ctx merged over the handler's Logger.
+func (h *slogHandler) Handle(ctx context.Context, record slog.Record) error {
+	fields := make(map[string]any, len(h.fields)+record.NumAttrs())
+	maps.Copy(fields, h.fields)
+
+	record.Attrs(func(attr slog.Attr) bool {
+		addSlogAttr(fields, h.group, attr)
+
+		return true
+	})
+
+	log := LoggerFromContext(ctx, h.log)
+	if len(fields) > 0 {
+		log = log.WithFields(fields)
+	}
+
+	switch {
+	case record.Level >= slog.LevelError:
+		log.Error(record.Message)
+	case record.Level >= slog.LevelWarn:
+		log.Warn(record.Message)
+	case record.Level >= slog.LevelInfo:
+		log.Info(record.Message)
+	case record.Level >= slog.LevelDebug:
+		log.Debug(record.Message)
+	default:
+		log.Trace(record.Message)
+	}
+
+	return nil
+}
+
+// WithAttrs returns a handler that adds attrs to every record.
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	next := h.clone()
+
+	for _, attr := range attrs {
+		addSlogAttr(next.fields, next.group, attr)
+	}
+
+	return next
+}
+
+// WithGroup returns a handler that nests the attributes of later records under name.
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	next := h.clone()
+	next.group = joinSlogGroup(h.group, name)
+
+	return next
+}
+
+// clone copies the handler so its fields can be extended.
+func (h *slogHandler) clone() *slogHandler {
+	return &slogHandler{log: h.log, fields: maps.Clone(h.fields), group: h.group}
+}
+
+// addSlogAttr stores attr in fields under group, flattening nested groups.
+func addSlogAttr(fields map[string]any, group string, attr slog.Attr) {
+	attr.Value = attr.Value.Resolve()
+
+	if attr.Equal(slog.Attr{}) {
+		return
+	}
+
+	if attr.Value.Kind() != slog.KindGroup {
+		fields[joinSlogGroup(group, attr.Key)] = attr.Value.Any()
+
+		return
+	}
+
+	nested := joinSlogGroup(group, attr.Key)
+	for _, child := range attr.Value.Group() {
+		addSlogAttr(fields, nested, child)
+	}
+}
+
+// joinSlogGroup joins a group prefix and a key, skipping an empty part.
+func joinSlogGroup(group, key string) string {
+	switch {
+	case group == "":
+		return key
+	case key == "":
+		return group
+	default:
+		return strings.Join([]string{group, key}, SlogGroupSeparator)
+	}
+}
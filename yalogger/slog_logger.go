@@ -0,0 +1,516 @@
+package yalogger
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"math/rand/v2"
+	"os"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// slogAdapter is an adapter that implements the Logger interface using a slog.Logger.
+// The context fields are kept in a map, so they can be read, merged and deleted like
+// the fields of a logrus entry, and are attached to every record as attributes.
+type slogAdapter struct {
+	logger *slog.Logger
+	fields map[string]any
+	gate   *logGate
+}
+
+// baseSlog holds a reference to a slog.Logger instance.
+// It serves as the base logger from which new Logger instances can be created.
+type baseSlog struct {
+	logger *slog.Logger
+	gate   *logGate
+}
+
+// newBaseSlog builds the slog base logger of config. A config.SlogHandler is used as
+// is; otherwise a text or JSON handler, by config.Format, writes to config.Output, or
+// to stderr. Levels are checked by the gate, so the built handler enables them all.
+func newBaseSlog(config *Config) *baseSlog {
+	handler := config.SlogHandler
+	if handler == nil {
+		output := config.Output
+		if output == nil {
+			output = os.Stderr
+		}
+
+		options := &slog.HandlerOptions{
+			Level:       SlogLevelTrace,
+			ReplaceAttr: slogReplaceAttr(config),
+		}
+
+		if config.Format == JSONFormat {
+			handler = slog.NewJSONHandler(output, options)
+		} else {
+			handler = slog.NewTextHandler(output, options)
+		}
+	}
+
+	return &baseSlog{logger: slog.New(handler), gate: newLogGate(config)}
+}
+
+// NewLogger creates a new Logger instance from the base slog logger.
+//
+// Returns:
+//
+//   - Logger: A new instance of slogAdapter without fields.
+func (b *baseSlog) NewLogger() Logger {
+	return &slogAdapter{logger: b.logger, fields: map[string]any{}, gate: b.gate}
+}
+
+// Info logs a message at the Info level.
+//
+// Parameters:
+//
+//   - msg: the message to log.
+//
+// Example usage:
+//
+//	logger.Info("Application started")
+func (l *slogAdapter) Info(msg string) {
+	l.log(InfoLevel, msg)
+}
+
+// Infof logs a formatted message at the Info level.
+//
+// Parameters:
+//
+//   - format: the message format.
+//   - args: the arguments for the format.
+//
+// Example usage:
+//
+//	logger.Infof("Server is listening on port %d", port)
+func (l *slogAdapter) Infof(format string, args ...any) {
+	l.logf(InfoLevel, format, args...)
+}
+
+// Error logs a message at the Error level.
+//
+// Parameters:
+//
+//   - msg: the error message.
+//
+// Example usage:
+//
+//	logger.Error("Database connection failed")
+func (l *slogAdapter) Error(msg string) {
+	l.log(ErrorLevel, msg)
+}
+
+// Errorf logs a formatted error message at the Error level.
+//
+// Parameters:
+//
+//   - format: the message format.
+//   - args: the arguments for the format.
+//
+// Example usage:
+//
+//	logger.Errorf("Failed to read file: %s", filename)
+func (l *slogAdapter) Errorf(format string, args ...any) {
+	l.logf(ErrorLevel, format, args...)
+}
+
+// Warn logs a warning message at the Warn level.
+//
+// Parameters:
+//
+//   - msg: the warning message.
+//
+// Example usage:
+//
+//	logger.Warn("Low disk space")
+func (l *slogAdapter) Warn(msg string) {
+	l.log(WarnLevel, msg)
+}
+
+// Warnf logs a formatted warning message at the Warn level.
+//
+// Parameters:
+//
+//   - format: the message format.
+//   - args: the arguments for the format.
+//
+// Example usage:
+//
+//	logger.Warnf("Cache miss rate: %.2f%%", rate)
+func (l *slogAdapter) Warnf(format string, args ...any) {
+	l.logf(WarnLevel, format, args...)
+}
+
+// Debug logs a debug message at the Debug level.
+//
+// Parameters:
+//
+//   - msg: the debug message.
+//
+// Example usage:
+//
+//	logger.Debug("User object created")
+func (l *slogAdapter) Debug(msg string) {
+	l.log(DebugLevel, msg)
+}
+
+// Debugf logs a formatted debug message at the Debug level.
+//
+// Parameters:
+//
+//   - format: the message format.
+//   - args: the arguments for the format.
+//
+// Example usage:
+//
+//	logger.Debugf("Response time: %dms", ms)
+func (l *slogAdapter) Debugf(format string, args ...any) {
+	l.logf(DebugLevel, format, args...)
+}
+
+// Fatal logs a message at the Fatal level and terminates the application with exit
+// code 1, like the logrus backend.
+//
+// Parameters:
+//
+//   - msg: the fatal error message.
+//
+// Example usage:
+//
+//	logger.Fatal("Configuration missing. Exiting.")
+func (l *slogAdapter) Fatal(msg string) {
+	l.write(SlogLevelFatal, msg)
+	os.Exit(1)
+}
+
+// Fatalf logs a formatted fatal error message at the Fatal level and terminates the
+// application with exit code 1.
+//
+// Parameters:
+//
+//   - format: the message format.
+//   - args: the arguments for the format.
+//
+// Example usage:
+//
+//	logger.Fatalf("Cannot load config file: %s", path)
+func (l *slogAdapter) Fatalf(format string, args ...any) {
+	l.write(SlogLevelFatal, fmt.Sprintf(format, args...))
+	os.Exit(1)
+}
+
+// Panic logs a message at the Panic level and then panics with it, like the logrus
+// backend.
+//
+// Parameters:
+//
+//   - msg: the panic message.
+//
+// Example usage:
+//
+//	logger.Panic("Unexpected error occurred")
+func (l *slogAdapter) Panic(msg string) {
+	l.write(SlogLevelPanic, msg)
+	//nolint:forbidigo // Panic is the documented contract of Logger.Panic
+	panic(msg)
+}
+
+// Panicf logs a formatted panic message at the Panic level and then panics with it.
+//
+// Parameters:
+//
+//   - format: the message format.
+//   - args: the arguments for the format.
+//
+// Example usage:
+//
+//	logger.Panicf("Critical failure: %s", err)
+func (l *slogAdapter) Panicf(format string, args ...any) {
+	msg := fmt.Sprintf(format, args...)
+
+	l.write(SlogLevelPanic, msg)
+	//nolint:forbidigo // Panic is the documented contract of Logger.Panicf
+	panic(msg)
+}
+
+// Trace logs a message at the Trace level, providing fine-grained debugging information.
+//
+// Parameters:
+//
+//   - msg: the trace message.
+//
+// Example usage:
+//
+//	logger.Trace("Entered handler function")
+func (l *slogAdapter) Trace(msg string) {
+	l.log(TraceLevel, msg)
+}
+
+// Tracef logs a formatted trace message at the Trace level.
+//
+// Parameters:
+//
+//   - format: the message format.
+//   - args: the arguments for the format.
+//
+// Example usage:
+//
+//	logger.Tracef("Payload: %+v", payload)
+func (l *slogAdapter) Tracef(format string, args ...any) {
+	l.logf(TraceLevel, format, args...)
+}
+
+// WithField returns a new Logger instance with a single key-value pair added to the log context.
+//
+// Parameters:
+//
+//   - key: the context field key.
+//   - value: the context field value.
+//
+// Example usage:
+//
+//	logger.WithField("user_id", 42).Info("User logged in")
+func (l *slogAdapter) WithField(key string, value any) Logger {
+	return l.with(map[string]any{key: value})
+}
+
+// WithFields returns a new Logger instance with multiple key-value pairs added to the log context.
+//
+// Parameters:
+//
+//   - fields: a map containing field keys and their corresponding values.
+//
+// Example usage:
+//
+//	logger.WithFields(map[string]any{"user_id": 42, "role": "admin"}).Info("Access granted")
+func (l *slogAdapter) WithFields(fields map[string]any) Logger {
+	return l.with(fields)
+}
+
+// WithRequestStringID returns a new Logger instance with a string request ID added to the context.
+//
+// Parameters:
+//
+//   - id: the request ID as a string.
+//
+// Example usage:
+//
+//	logger.WithRequestStringID("req-123").Info("Request started")
+func (l *slogAdapter) WithRequestStringID(id string) Logger {
+	return l.WithField(KeyRequestID, id)
+}
+
+// WithRequestUUID returns a new Logger instance with a UUID-based request ID added to the context.
+//
+// Parameters:
+//
+//   - id: the UUID for the request.
+//
+// Example usage:
+//
+//	logger.WithRequestUUID(uuid.New()).Info("Tracking UUID request")
+func (l *slogAdapter) WithRequestUUID(id uuid.UUID) Logger {
+	return l.WithField(KeyRequestID, id)
+}
+
+// WithRequestID returns a new Logger instance with a numeric request ID added to the context.
+//
+// Parameters:
+//
+//   - id: the numeric request ID.
+//
+// Example usage:
+//
+//	logger.WithRequestID(1001).Info("Handling request")
+func (l *slogAdapter) WithRequestID(id uint64) Logger {
+	return l.WithField(KeyRequestID, id)
+}
+
+// WithRandomRequestID returns a new Logger instance with a randomly generated numeric request ID.
+//
+// Example usage:
+//
+//	logger.WithRandomRequestID().Info("Generated random request ID")
+func (l *slogAdapter) WithRandomRequestID() Logger {
+	//nolint:gosec // Randomness quality here could be neglected, as this is just for logging
+	return l.WithField(KeyRequestID, rand.Uint64())
+}
+
+// WithSystemRequestID returns a new Logger instance with a system configuration ID added to the context.
+//
+// Parameters:
+//
+//   - id: the system configuration ID (uint8).
+//
+// Example usage:
+//
+//	logger.WithSystemRequestID(3).Info("Using config #3")
+func (l *slogAdapter) WithSystemRequestID(id uint8) Logger {
+	return l.WithField(KeySystemRequestID, id)
+}
+
+// WithUserID returns a new Logger instance with a user ID added to the log context.
+//
+// Parameters:
+//
+//   - userID: the user identifier.
+//
+// Example usage:
+//
+//	logger.WithUserID(12345).Info("User performed action")
+func (l *slogAdapter) WithUserID(userID uint64) Logger {
+	return l.WithField(KeyUserID, userID)
+}
+
+// GetFields returns the current log context fields as a map.
+//
+// Returns:
+//
+//   - map[string]any: a map containing the current log context fields.
+func (l *slogAdapter) GetFields() map[string]any {
+	return l.fields
+}
+
+// GetField returns the value of a specific field from the log context.
+//
+// Parameters:
+//
+//   - key: the field key.
+//
+// Returns:
+//
+//   - any: the value of the field. if the field is not found, it returns nil.
+//
+// Example usage:
+//
+//	val := logger.GetField("user_id")
+func (l *slogAdapter) GetField(key string) any {
+	return l.fields[key]
+}
+
+// MergeFields merges fields from another Logger instance into the current log context.
+//
+// If there are overlapping keys, the values from the other Logger will overwrite the current context.
+//
+// Parameters:
+//
+//   - other: the Logger instance whose fields will be merged into the current context.
+//
+// Example usage:
+//
+//	logger1 := logger.WithField("user_id", 42)
+//	logger2 := logger.WithField("session_id", "abc123")
+//	mergedLogger := logger1.MergeFields(logger2)
+func (l *slogAdapter) MergeFields(other Logger) Logger {
+	return l.with(other.GetFields())
+}
+
+// DeleteField removes a field from the current log context.
+//
+// Parameters:
+//
+//   - key: the field key to remove.
+//
+// Example usage:
+//
+//	logger.DeleteField("user_id")
+func (l *slogAdapter) DeleteField(key string) {
+	delete(l.fields, key)
+}
+
+// with returns a copy of the adapter with fields added over its own.
+func (l *slogAdapter) with(fields map[string]any) *slogAdapter {
+	merged := make(map[string]any, len(l.fields)+len(fields))
+	maps.Copy(merged, l.fields)
+	maps.Copy(merged, fields)
+
+	return &slogAdapter{logger: l.logger, fields: merged, gate: l.gate}
+}
+
+// log writes msg at level when the gate allows it.
+func (l *slogAdapter) log(level Level, msg string) {
+	if l.gate.allow(level, l.fields, msg) {
+		l.write(slogLevel(level), msg)
+	}
+}
+
+// logf formats the message only when level is allowed by the gate and enabled.
+func (l *slogAdapter) logf(level Level, format string, args ...any) {
+	if !l.gate.allow(level, l.fields, format) ||
+		!l.logger.Enabled(context.Background(), slogLevel(level)) {
+		return
+	}
+
+	l.write(slogLevel(level), fmt.Sprintf(format, args...))
+}
+
+// write writes msg at level with the context fields as attributes, sorted by key.
+func (l *slogAdapter) write(level slog.Level, msg string) {
+	ctx := context.Background()
+	if !l.logger.Enabled(ctx, level) {
+		return
+	}
+
+	attrs := make([]slog.Attr, 0, len(l.fields))
+
+	for _, key := range slices.Sorted(maps.Keys(l.fields)) {
+		attrs = append(attrs, slog.Any(key, l.fields[key]))
+	}
+
+	l.logger.LogAttrs(ctx, level, msg, attrs...)
+}
+
+// slogLevel maps a yalogger Level to the slog level it enables.
+func slogLevel(level Level) slog.Level {
+	switch level {
+	case PanicLevel:
+		return SlogLevelPanic
+	case FatalLevel:
+		return SlogLevelFatal
+	case ErrorLevel:
+		return slog.LevelError
+	case WarnLevel:
+		return slog.LevelWarn
+	case InfoLevel:
+		return slog.LevelInfo
+	case DebugLevel:
+		return slog.LevelDebug
+	default:
+		return SlogLevelTrace
+	}
+}
+
+// slogReplaceAttr names the Trace, Fatal and Panic levels and applies the timestamp
+// settings of config to the default text handler.
+func slogReplaceAttr(config *Config) func(groups []string, attr slog.Attr) slog.Attr {
+	return func(groups []string, attr slog.Attr) slog.Attr {
+		if len(groups) > 0 {
+			return attr
+		}
+
+		switch attr.Key {
+		case slog.TimeKey:
+			if config.DisableTimestamp {
+				return slog.Attr{}
+			}
+
+			if config.TimestampFormat != "" {
+				return slog.String(slog.TimeKey, attr.Value.Time().Format(config.TimestampFormat))
+			}
+		case slog.LevelKey:
+			level, ok := attr.Value.Any().(slog.Level)
+			if !ok {
+				return attr
+			}
+
+			if name, named := slogLevelNames[level]; named {
+				return slog.String(slog.LevelKey, name)
+			}
+		}
+
+		return attr
+	}
+}
@@ -0,0 +1,159 @@
+package yalogger
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newBufferedSlogLogger(level Level) (Logger, *bytes.Buffer) {
+	buffer := bytes.NewBuffer(nil)
+
+	base := NewBaseLogger(&Config{
+		BaseLoggerType:   Slog,
+		Level:            level,
+		DisableTimestamp: true,
+		Output:           buffer,
+	})
+
+	return base.NewLogger(), buffer
+}
+
+func TestSlogLoggerLevelsAndFields(t *testing.T) {
+	log, buffer := newBufferedSlogLogger(DebugLevel)
+
+	log.Trace("hidden")
+	log.WithUserID(42).WithRequestStringID("req-1").Infof("user %s", "ya")
+	log.Debug("visible")
+
+	output := buffer.String()
+	if strings.Contains(output, "hidden") {
+		t.Fatalf("expected trace to be filtered at debug level, got %q", output)
+	}
+
+	for _, expected := range []string{
+		`level=INFO msg="user ya" request_id=req-1 user_id=42`,
+		"level=DEBUG msg=visible",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Fatalf("expected output to contain %q, got %q", expected, output)
+		}
+	}
+
+	if strings.Contains(output, "time=") {
+		t.Fatalf("expected timestamps to be disabled, got %q", output)
+	}
+}
+
+func TestSlogLoggerFieldOperations(t *testing.T) {
+	log, _ := newBufferedSlogLogger(TraceLevel)
+
+	first := log.WithField("scope", "global")
+	second := log.WithFields(map[string]any{"scope": "request", KeyUserID: uint64(7)})
+	merged := first.MergeFields(second)
+
+	if first.GetField("scope") != "global" {
+		t.Fatalf("expected WithFields to leave the parent untouched, got %v", first.GetField("scope"))
+	}
+
+	if merged.GetField("scope") != "request" || merged.GetField(KeyUserID) != uint64(7) {
+		t.Fatalf("unexpected merged fields %v", merged.GetFields())
+	}
+
+	merged.DeleteField("scope")
+
+	if merged.GetField("scope") != nil {
+		t.Fatalf("expected scope to be deleted, got %v", merged.GetField("scope"))
+	}
+}
+
+func TestSlogLoggerTraceAndPanicLevelNames(t *testing.T) {
+	log, buffer := newBufferedSlogLogger(TraceLevel)
+
+	log.Trace("deep")
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("expected Panic to panic")
+			}
+		}()
+
+		log.Panicf("boom %d", 1)
+	}()
+
+	output := buffer.String()
+	for _, expected := range []string{"level=TRACE msg=deep", `level=PANIC msg="boom 1"`} {
+		if !strings.Contains(output, expected) {
+			t.Fatalf("expected output to contain %q, got %q", expected, output)
+		}
+	}
+}
+
+func TestSlogLoggerWithGinAndGorm(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	log, buffer := newBufferedSlogLogger(TraceLevel)
+
+	router := gin.New()
+	router.Use(GinAccessLogger(log, nil))
+	router.GET("/ok", func(ctx *gin.Context) {
+		SetGinContextLogger(ctx, log.WithField(KeyRequestID, "req-ok"), nil)
+		NewGormLogger(log, nil).Info(ctx.Request.Context(), "table %s", "users")
+		ctx.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/ok", nil)
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	output := buffer.String()
+	for _, expected := range []string{
+		"[GIN] RUN status=200",
+		"[GORM] INFO: table users",
+		"request_id=req-ok",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Fatalf("expected output to contain %q, got %q", expected, output)
+		}
+	}
+}
+
+func TestSlogHandlerWritesIntoLogger(t *testing.T) {
+	log, buffer := newBufferedLogger(TraceLevel)
+
+	logger := slog.New(NewSlogHandler(log.WithField("scope", "global"))).
+		With("component", "cache").
+		WithGroup("stats")
+
+	ctx := ContextWithLogger(
+		context.Background(),
+		log.WithUserID(42).WithRequestStringID("req-1"),
+	)
+
+	logger.InfoContext(ctx, "cache warmed", "entries", 128, slog.Group("ttl", "max", time.Minute))
+	logger.DebugContext(context.Background(), "probe")
+	logger.Log(context.Background(), SlogLevelTrace, "deep")
+	logger.WarnContext(context.Background(), "slow")
+	logger.ErrorContext(context.Background(), "failed")
+
+	output := buffer.String()
+	for _, expected := range []string{
+		`level=info msg="cache warmed" component=cache request_id=req-1 scope=global ` +
+			`stats.entries=128 stats.ttl.max=1m0s user_id=42`,
+		"level=debug msg=probe",
+		"level=trace msg=deep",
+		"level=warning msg=slow",
+		"level=error msg=failed",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Fatalf("expected output to contain %q, got %q", expected, output)
+		}
+	}
+}
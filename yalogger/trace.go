@@ -0,0 +1,152 @@
+package yalogger
+
+import (
+	"context"
+	"encoding/binary"
+	"math/rand/v2"
+
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// traceContextPropagator reads and writes W3C traceparent and tracestate values.
+var traceContextPropagator = propagation.TraceContext{}
+
+// ContextWithTraceParent returns ctx carrying the remote span context of a W3C
+// traceparent value, so spans started from it continue the caller's trace.
+//
+// Parameters:
+//
+//   - ctx: the parent context. If nil, a new background context is used.
+//   - traceParent: the traceparent value. An empty or malformed one leaves ctx unchanged.
+//
+// Returns:
+//
+//   - context.Context: a child context carrying the span context of traceParent.
+//
+// Example usage:
+//
+//	ctx = yalogger.ContextWithTraceParent(ctx, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+func ContextWithTraceParent(ctx context.Context, traceParent string) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if traceParent == "" {
+		return ctx
+	}
+
+	return traceContextPropagator.Extract(
+		ctx,
+		propagation.MapCarrier{TraceParentHeader: traceParent},
+	)
+}
+
+// TraceParentFromContext returns the W3C traceparent value of the span context in ctx,
+// ready to be carried to another process, or an empty string when ctx has none.
+//
+// Example usage:
+//
+//	request.Header.Set(yalogger.TraceParentHeader, yalogger.TraceParentFromContext(ctx))
+func TraceParentFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+
+	carrier := propagation.MapCarrier{}
+	traceContextPropagator.Inject(ctx, carrier)
+
+	return carrier.Get(TraceParentHeader)
+}
+
+// ContextWithChildSpan returns ctx carrying a new span of the trace in ctx, or the
+// first span of a new trace when ctx has none. Use it where work of a trace starts
+// in this process, such as an incoming request or a scheduled job, so its log lines
+// get their own span_id.
+//
+// A span started by an OpenTelemetry tracer is used the same way: Loggers read
+// whatever span context ctx carries. This helper only mints IDs for correlation and
+// records nothing.
+//
+// Example usage:
+//
+//	ctx = yalogger.ContextWithChildSpan(yalogger.ContextWithTraceParent(ctx, traceParent))
+func ContextWithChildSpan(ctx context.Context) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	parent := trace.SpanContextFromContext(ctx)
+
+	config := trace.SpanContextConfig{
+		TraceID:    parent.TraceID(),
+		SpanID:     newSpanID(),
+		TraceFlags: parent.TraceFlags(),
+		TraceState: parent.TraceState(),
+	}
+
+	if !parent.IsValid() {
+		config.TraceID = newTraceID()
+		config.TraceFlags = trace.FlagsSampled
+	}
+
+	return trace.ContextWithSpanContext(ctx, trace.NewSpanContext(config))
+}
+
+// TraceFields returns the KeyTraceID and KeySpanID fields of the span context in ctx,
+// or nil when ctx has none.
+//
+// Example usage:
+//
+//	logger.WithFields(yalogger.TraceFields(ctx)).Info("Job started")
+func TraceFields(ctx context.Context) map[string]any {
+	if ctx == nil {
+		return nil
+	}
+
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.IsValid() {
+		return nil
+	}
+
+	return map[string]any{
+		KeyTraceID: spanContext.TraceID().String(),
+		KeySpanID:  spanContext.SpanID().String(),
+	}
+}
+
+// withTraceFields adds the trace fields of ctx to log. A nil log stays nil.
+func withTraceFields(ctx context.Context, log Logger) Logger {
+	fields := TraceFields(ctx)
+	if log == nil || fields == nil {
+		return log
+	}
+
+	return log.WithFields(fields)
+}
+
+// newTraceID returns a random, valid trace ID.
+func newTraceID() trace.TraceID {
+	var traceID trace.TraceID
+
+	for !traceID.IsValid() {
+		//nolint:gosec // Trace IDs correlate logs, they are not secrets
+		binary.BigEndian.PutUint64(traceID[:8], rand.Uint64())
+		//nolint:gosec // Trace IDs correlate logs, they are not secrets
+		binary.BigEndian.PutUint64(traceID[8:], rand.Uint64())
+	}
+
+	return traceID
+}
+
+// newSpanID returns a random, valid span ID.
+func newSpanID() trace.SpanID {
+	var spanID trace.SpanID
+
+	for !spanID.IsValid() {
+		//nolint:gosec // Span IDs correlate logs, they are not secrets
+		binary.BigEndian.PutUint64(spanID[:], rand.Uint64())
+	}
+
+	return spanID
+}
@@ -0,0 +1,100 @@
+package yalogger
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	testTraceID     = "4bf92f3577b34da6a3ce929d0e0e4736"
+	testSpanID      = "00f067aa0ba902b7"
+	testTraceParent = "00-" + testTraceID + "-" + testSpanID + "-01"
+)
+
+func TestTraceParentRoundTripAndChildSpan(t *testing.T) {
+	ctx := ContextWithTraceParent(context.Background(), testTraceParent)
+
+	if got := TraceParentFromContext(ctx); got != testTraceParent {
+		t.Fatalf("expected %q, got %q", testTraceParent, got)
+	}
+
+	child := TraceFields(ContextWithChildSpan(ctx))
+	if child[KeyTraceID] != testTraceID || child[KeySpanID] == testSpanID {
+		t.Fatalf("expected a new span of the same trace, got %v", child)
+	}
+
+	if fields := TraceFields(ContextWithTraceParent(context.Background(), "garbage")); fields != nil {
+		t.Fatalf("expected a malformed traceparent to be ignored, got %v", fields)
+	}
+
+	root := TraceFields(ContextWithChildSpan(context.Background()))
+	if root[KeyTraceID] == nil || root[KeySpanID] == nil {
+		t.Fatalf("expected a new trace without a parent, got %v", root)
+	}
+}
+
+func TestLoggerFromContextCarriesTraceFields(t *testing.T) {
+	log := NewBaseLogger(nil).NewLogger()
+	ctx := ContextWithTraceParent(context.Background(), testTraceParent)
+
+	stored := ContextWithLogger(ctx, log.WithUserID(42))
+	if got := LoggerFromContext(stored, nil).GetField(KeyTraceID); got != testTraceID {
+		t.Fatalf("expected the stored logger to carry the trace, got %v", got)
+	}
+
+	child := ContextWithChildSpan(stored)
+	resolved := LoggerFromContext(child, log)
+
+	if resolved.GetField(KeySpanID) == testSpanID || resolved.GetField(KeyUserID) != uint64(42) {
+		t.Fatalf("expected the child span over the stored fields, got %v", resolved.GetFields())
+	}
+
+	if got := LoggerFromContext(ctx, log).GetField(KeyTraceID); got != testTraceID {
+		t.Fatalf("expected the fallback to carry the trace, got %v", got)
+	}
+}
+
+func TestGinAccessLoggerContinuesTraceParent(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	log, buffer := newBufferedLogger(TraceLevel)
+
+	router := gin.New()
+	router.Use(GinAccessLogger(log, nil))
+	router.GET("/ok", func(ctx *gin.Context) {
+		GinLoggerFromContext(ctx, log, nil).Info("handler")
+		ctx.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/ok", nil)
+	req.Header.Set(TraceParentHeader, testTraceParent)
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected a handler and an access line, got %q", lines)
+	}
+
+	for _, line := range lines {
+		if !strings.Contains(line, "trace_id="+testTraceID) ||
+			strings.Contains(line, "span_id="+testSpanID) {
+			t.Fatalf("expected the line to continue the trace in a new span, got %q", line)
+		}
+	}
+
+	buffer.Reset()
+
+	router = gin.New()
+	router.Use(GinAccessLogger(log, &GinLoggerConfig{DisableTraceContext: true}))
+	router.GET("/ok", func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	if strings.Contains(buffer.String(), "trace_id=") {
+		t.Fatalf("expected no trace fields when disabled, got %q", buffer.String())
+	}
+}
@@ -1,22 +1,39 @@
 package yalogger
 
 import (
+	"io"
+	"log/slog"
+
 	"github.com/google/uuid"
 )
 
 // Config defines the configuration options for the logger.
 //
-// BaseLoggerType: The type of logger to use (e.g., Logrus).
+// BaseLoggerType: The type of logger to use (Logrus or Slog).
 // Level: The minimum log level to output (e.g., Info).
 // FullTimestamp: Whether to include the full timestamp in log messages.
 // DisableTimestamp: Whether to disable timestamps in log messages.
 // TimestampFormat: The format to use for timestamps in log messages.
+// Output: Where log messages are written. Defaults to stderr when nil.
+// SlogHandler: The handler of the Slog backend. When nil, a slog text or JSON handler
+// writing to Output is built from Format and the timestamp options.
+// Format: How entries are rendered (TextFormat or JSONFormat).
+// Sampling: Sampling of repeated entries on hot paths. Disabled when nil.
+// ComponentLevels: Level overrides keyed by the KeyComponent field (e.g. "gorm").
+// LevelController: Shared levels that can be changed at runtime. When set, Level and
+// ComponentLevels are ignored; otherwise a controller is built from them.
 type Config struct {
 	BaseLoggerType   BaseLoggerType
 	Level            Level
 	FullTimestamp    bool
 	DisableTimestamp bool
 	TimestampFormat  string
+	Output           io.Writer
+	SlogHandler      slog.Handler
+	Format           Format
+	Sampling         *SamplingConfig
+	ComponentLevels  map[string]Level
+	LevelController  *LevelController
 }
 
 // BaseLogger is an interface for creating new Logger instances.
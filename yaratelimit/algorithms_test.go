@@ -0,0 +1,259 @@
+package yaratelimit_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yacache"
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yaerrors"
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yaratelimit"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errBackendDown = errors.New("backend down")
+
+// unreadableCache is a memory cache whose reads fail as a dropped backend
+// connection would.
+type unreadableCache struct {
+	yacache.Cache[yacache.MemoryContainer]
+}
+
+func (unreadableCache) Get(context.Context, string) (string, yaerrors.Error) {
+	return "", yaerrors.FromError(http.StatusInternalServerError, errBackendDown, "get")
+}
+
+var algorithms = map[string]yaratelimit.Algorithm{
+	"FixedWindow":          yaratelimit.FixedWindow,
+	"SlidingLog":           yaratelimit.SlidingLog,
+	"SlidingWindowCounter": yaratelimit.SlidingWindowCounter,
+	"TokenBucket":          yaratelimit.TokenBucket,
+}
+
+func newRedisCache(t *testing.T) yacache.Cache[*redis.Client] {
+	t.Helper()
+
+	mr, err := miniredis.Run()
+	require.NoError(t, err)
+
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+
+	t.Cleanup(func() {
+		_ = client.Close()
+
+		mr.Close()
+	})
+
+	return yacache.NewCache(client)
+}
+
+// newNearCache starts a miniredis instance and wraps it in a yacache.NearCache.
+func newNearCache(t *testing.T) yacache.Cache[*redis.Client] {
+	t.Helper()
+
+	mr, err := miniredis.Run()
+	require.NoError(t, err)
+
+	near := yacache.NewNearCache(
+		redis.NewClient(&redis.Options{Addr: mr.Addr()}),
+		yacache.NearCacheConfig{},
+	)
+
+	t.Cleanup(func() {
+		_ = near.Close()
+
+		mr.Close()
+	})
+
+	return near
+}
+
+// forEachBackend runs fn against a limiter built on the memory, the Redis and
+// the near-cache backend.
+func forEachBackend(
+	t *testing.T,
+	algorithm yaratelimit.Algorithm,
+	limit uint8,
+	rate time.Duration,
+	fn func(t *testing.T, limiter yaratelimit.RateLimiter),
+) {
+	t.Helper()
+
+	t.Run("Memory", func(t *testing.T) {
+		cache := yacache.NewCache(yacache.NewMemoryContainer())
+
+		fn(t, yaratelimit.NewRateLimiter(cache, algorithm, limit, rate))
+	})
+
+	t.Run("Redis", func(t *testing.T) {
+		fn(t, yaratelimit.NewRateLimiter(newRedisCache(t), algorithm, limit, rate))
+	})
+
+	t.Run("NearCache", func(t *testing.T) {
+		fn(t, yaratelimit.NewRateLimiter(newNearCache(t), algorithm, limit, rate))
+	})
+}
+
+func TestNewRateLimiter_SelectsAlgorithm(t *testing.T) {
+	cache := yacache.NewCache(yacache.NewMemoryContainer())
+
+	assert.IsType(
+		t,
+		&yaratelimit.RateLimit[yacache.MemoryContainer]{},
+		yaratelimit.NewRateLimiter(cache, yaratelimit.FixedWindow, 1, time.Second),
+	)
+	assert.IsType(
+		t,
+		&yaratelimit.SlidingLogRateLimit[yacache.MemoryContainer]{},
+		yaratelimit.NewRateLimiter(cache, yaratelimit.SlidingLog, 1, time.Second),
+	)
+	assert.IsType(
+		t,
+		&yaratelimit.SlidingWindowCounterRateLimit[yacache.MemoryContainer]{},
+		yaratelimit.NewRateLimiter(cache, yaratelimit.SlidingWindowCounter, 1, time.Second),
+	)
+	assert.IsType(
+		t,
+		&yaratelimit.TokenBucketRateLimit[yacache.MemoryContainer]{},
+		yaratelimit.NewRateLimiter(cache, yaratelimit.TokenBucket, 1, time.Second),
+	)
+}
+
+func TestAlgorithms_AllowExactlyLimit(t *testing.T) {
+	ctx := context.Background()
+
+	const limit = 5
+
+	for name, algorithm := range algorithms {
+		t.Run(name, func(t *testing.T) {
+			forEachBackend(t, algorithm, limit, time.Hour, func(t *testing.T, limiter yaratelimit.RateLimiter) {
+				for hit := range limit {
+					banned, err := limiter.Increment(ctx, TestUserID, TestGroup)
+					require.Nil(t, err)
+					assert.False(t, banned, "hit %d must be allowed", hit)
+				}
+
+				banned, err := limiter.Increment(ctx, TestUserID, TestGroup)
+				require.Nil(t, err)
+				assert.True(t, banned)
+
+				storage, err := limiter.Get(ctx, TestUserID, TestGroup)
+				require.Nil(t, err)
+				assert.Equal(t, uint8(limit), storage.Limit)
+			})
+		})
+	}
+}
+
+func TestAlgorithms_CheckBannedAgreesWithIncrement(t *testing.T) {
+	ctx := context.Background()
+
+	const limit = 4
+
+	for name, algorithm := range algorithms {
+		t.Run(name, func(t *testing.T) {
+			forEachBackend(t, algorithm, limit, time.Hour, func(t *testing.T, limiter yaratelimit.RateLimiter) {
+				for hit := 0; hit <= limit+1; hit++ {
+					checkBannedResult, _ := limiter.CheckBanned(ctx, TestUserID, TestGroup)
+
+					incrementResult, err := limiter.Increment(ctx, TestUserID, TestGroup)
+					require.Nil(t, err)
+
+					assert.Equal(t, checkBannedResult, incrementResult, "hit %d", hit)
+				}
+			})
+		})
+	}
+}
+
+func TestAlgorithms_RefreshUnbans(t *testing.T) {
+	ctx := context.Background()
+
+	const limit = 2
+
+	for name, algorithm := range algorithms {
+		t.Run(name, func(t *testing.T) {
+			forEachBackend(t, algorithm, limit, time.Hour, func(t *testing.T, limiter yaratelimit.RateLimiter) {
+				for range limit {
+					_, _ = limiter.Increment(ctx, TestUserID, TestGroup)
+				}
+
+				require.Nil(t, limiter.Refresh(ctx, TestUserID, TestGroup))
+
+				storage, err := limiter.Get(ctx, TestUserID, TestGroup)
+				require.Nil(t, err)
+				assert.Equal(t, uint8(1), storage.Limit)
+
+				banned, err := limiter.CheckBanned(ctx, TestUserID, TestGroup)
+				require.Nil(t, err)
+				assert.False(t, banned)
+			})
+		})
+	}
+}
+
+func TestSlidingLog_NoBurstAcrossBoundary(t *testing.T) {
+	ctx := context.Background()
+
+	const rate = 200 * time.Millisecond
+
+	forEachBackend(t, yaratelimit.SlidingLog, 3, rate, func(t *testing.T, limiter yaratelimit.RateLimiter) {
+		for range 3 {
+			_, _ = limiter.Increment(ctx, TestUserID, TestGroup)
+		}
+
+		time.Sleep(rate / 2)
+
+		banned, _ := limiter.Increment(ctx, TestUserID, TestGroup)
+		assert.True(t, banned, "half a window later the trailing interval is still full")
+
+		time.Sleep(rate/2 + 20*time.Millisecond)
+
+		banned, _ = limiter.Increment(ctx, TestUserID, TestGroup)
+		assert.False(t, banned, "the first hits have left the trailing interval")
+	})
+}
+
+func TestTokenBucket_Refills(t *testing.T) {
+	ctx := context.Background()
+
+	const rate = 200 * time.Millisecond
+
+	forEachBackend(t, yaratelimit.TokenBucket, 2, rate, func(t *testing.T, limiter yaratelimit.RateLimiter) {
+		_, _ = limiter.Increment(ctx, TestUserID, TestGroup)
+		_, _ = limiter.Increment(ctx, TestUserID, TestGroup)
+
+		banned, _ := limiter.Increment(ctx, TestUserID, TestGroup)
+		assert.True(t, banned, "the burst has drained the bucket")
+
+		time.Sleep(rate/2 + 20*time.Millisecond)
+
+		banned, _ = limiter.Increment(ctx, TestUserID, TestGroup)
+		assert.False(t, banned, "one token has been refilled")
+
+		banned, _ = limiter.Increment(ctx, TestUserID, TestGroup)
+		assert.True(t, banned, "only one token has been refilled")
+	})
+}
+
+func TestAlgorithms_ReadFailureIsReturned(t *testing.T) {
+	ctx := context.Background()
+
+	for name, algorithm := range algorithms {
+		t.Run(name, func(t *testing.T) {
+			cache := unreadableCache{yacache.NewCache(yacache.NewMemoryContainer())}
+			limiter := yaratelimit.NewRateLimiter[yacache.MemoryContainer](cache, algorithm, 1, time.Hour)
+
+			_, err := limiter.Increment(ctx, TestUserID, TestGroup)
+			require.ErrorIs(t, err, errBackendDown)
+
+			_, getErr := cache.Cache.Get(ctx, yaratelimit.FormatKey(TestUserID, TestGroup))
+			assert.ErrorIs(t, getErr, yacache.ErrNotFoundValue, "nothing must be written")
+		})
+	}
+}
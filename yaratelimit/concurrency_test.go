@@ -0,0 +1,118 @@
+package yaratelimit_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yacache"
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yaratelimit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlgorithms_ConcurrentIncrementPassesExactlyLimit(t *testing.T) {
+	ctx := context.Background()
+
+	const (
+		limit   = 50
+		callers = 400
+	)
+
+	for name, algorithm := range algorithms {
+		t.Run(name, func(t *testing.T) {
+			forEachBackend(t, algorithm, limit, time.Hour, func(t *testing.T, limiter yaratelimit.RateLimiter) {
+				var (
+					passed atomic.Int64
+					failed atomic.Int64
+					start  = make(chan struct{})
+					wg     sync.WaitGroup
+				)
+
+				for range callers {
+					wg.Go(func() {
+						<-start
+
+						banned, err := limiter.Increment(ctx, TestUserID, TestGroup)
+						if err != nil {
+							failed.Add(1)
+
+							return
+						}
+
+						if !banned {
+							passed.Add(1)
+						}
+					})
+				}
+
+				close(start)
+				wg.Wait()
+
+				require.Zero(t, failed.Load())
+				assert.Equal(t, int64(limit), passed.Load())
+
+				storage, err := limiter.Get(ctx, TestUserID, TestGroup)
+				require.Nil(t, err)
+				assert.Equal(t, uint8(limit), storage.Limit)
+			})
+		})
+	}
+}
+
+func TestAlgorithms_ConcurrentLimitersShareCache(t *testing.T) {
+	const (
+		limit   = 20
+		callers = 200
+	)
+
+	memory := yacache.NewCache(yacache.NewMemoryContainer())
+	redis := newRedisCache(t)
+
+	for name, algorithm := range algorithms {
+		t.Run(name, func(t *testing.T) {
+			t.Run("Memory", func(t *testing.T) {
+				assert.Equal(t, int64(limit), countPassedReplicas(callers, func() yaratelimit.RateLimiter {
+					return yaratelimit.NewRateLimiter(memory, algorithm, limit, time.Hour)
+				}, name))
+			})
+
+			t.Run("Redis", func(t *testing.T) {
+				assert.Equal(t, int64(limit), countPassedReplicas(callers, func() yaratelimit.RateLimiter {
+					return yaratelimit.NewRateLimiter(redis, algorithm, limit, time.Hour)
+				}, name))
+			})
+		})
+	}
+}
+
+// countPassedReplicas fires one Increment per caller, each through its own
+// limiter instance like separate replicas sharing a cache would, and counts
+// the hits that were let through.
+func countPassedReplicas(
+	callers int,
+	newLimiter func() yaratelimit.RateLimiter,
+	group string,
+) int64 {
+	var (
+		passed atomic.Int64
+		wg     sync.WaitGroup
+	)
+
+	for range callers {
+		limiter := newLimiter()
+
+		wg.Go(func() {
+			banned, err := limiter.Increment(context.Background(), TestUserID, group)
+			if err == nil && !banned {
+				passed.Add(1)
+			}
+		})
+	}
+
+	wg.Wait()
+
+	return passed.Load()
+}
@@ -0,0 +1,44 @@
+package yaratelimit
+
+// Algorithm selects the rate-limiting strategy built by NewRateLimiter.
+type Algorithm uint8
+
+const (
+	// FixedWindow counts hits in consecutive, non-overlapping windows of Rate.
+	// It is the cheapest strategy but lets a client send up to 2×Limit hits
+	// across a window boundary.
+	FixedWindow Algorithm = iota
+	// SlidingLog keeps the timestamp of every accepted hit and allows at most
+	// Limit hits within any trailing Rate-long interval. Exact, at the cost of
+	// storing up to Limit timestamps per subject.
+	SlidingLog
+	// SlidingWindowCounter approximates a sliding window by weighting the
+	// previous fixed window's count by how much of it still overlaps the
+	// trailing Rate-long interval. Constant storage, near-exact.
+	SlidingWindowCounter
+	// TokenBucket holds up to Limit tokens, refilled continuously at Limit
+	// tokens per Rate. Each hit spends one token, so bursts of up to Limit are
+	// allowed only after the bucket had time to refill.
+	TokenBucket
+)
+
+const (
+	keyPrefix                     = "rate-limit"
+	slidingLogKeyPrefix           = "rate-limit-sliding-log"
+	slidingWindowCounterKeyPrefix = "rate-limit-sliding-window"
+	tokenBucketKeyPrefix          = "rate-limit-token-bucket"
+)
+
+const (
+	valueSeparator = ","
+
+	slidingWindowCounterFields = 3
+	tokenBucketFields          = 2
+
+	// slidingWindowCounterTTLWindows is how many windows a sliding-window
+	// counter record is kept for: the current one plus the previous one it is
+	// weighted against.
+	slidingWindowCounterTTLWindows = 2
+
+	millisecondsInSecond = 1000
+)
@@ -0,0 +1,99 @@
+package yaratelimit_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yaratelimit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlgorithms_AllowReportsDecision(t *testing.T) {
+	const (
+		limit = 3
+		rate  = time.Minute
+	)
+
+	for name, algorithm := range algorithms {
+		t.Run(name, func(t *testing.T) {
+			forEachBackend(t, algorithm, limit, rate, func(t *testing.T, limiter yaratelimit.RateLimiter) {
+				ctx := context.Background()
+
+				for remaining := limit - 1; remaining >= 0; remaining-- {
+					decision, err := limiter.Allow(ctx, 1, "decision")
+					require.NoError(t, err)
+
+					assert.True(t, decision.Allowed)
+					assert.Equal(t, uint8(limit), decision.Limit)
+					assert.Equal(t, uint8(remaining), decision.Remaining)
+					assert.Zero(t, decision.RetryAfter)
+					assert.True(t, decision.ResetAt.After(time.Now()))
+				}
+
+				decision, err := limiter.Allow(ctx, 1, "decision")
+				require.NoError(t, err)
+
+				assert.False(t, decision.Allowed)
+				assert.Zero(t, decision.Remaining)
+				assert.Positive(t, decision.RetryAfter)
+				assert.LessOrEqual(t, decision.RetryAfter, 2*rate)
+				assert.True(t, decision.ResetAt.After(time.Now()))
+			})
+		})
+	}
+}
+
+func TestAlgorithms_RetryAfter(t *testing.T) {
+	const rate = time.Minute
+
+	t.Run("SlidingLog waits for the oldest hit", func(t *testing.T) {
+		forEachBackend(t, yaratelimit.SlidingLog, 2, rate, func(t *testing.T, limiter yaratelimit.RateLimiter) {
+			ctx := context.Background()
+
+			for range 2 {
+				_, err := limiter.Allow(ctx, 1, "retry")
+				require.NoError(t, err)
+			}
+
+			decision, err := limiter.Allow(ctx, 1, "retry")
+			require.NoError(t, err)
+
+			assert.False(t, decision.Allowed)
+			assert.InDelta(t, rate.Seconds(), decision.RetryAfter.Seconds(), 1)
+		})
+	})
+
+	t.Run("TokenBucket waits for one token", func(t *testing.T) {
+		forEachBackend(t, yaratelimit.TokenBucket, 4, rate, func(t *testing.T, limiter yaratelimit.RateLimiter) {
+			ctx := context.Background()
+
+			for range 4 {
+				_, err := limiter.Allow(ctx, 1, "retry")
+				require.NoError(t, err)
+			}
+
+			decision, err := limiter.Allow(ctx, 1, "retry")
+			require.NoError(t, err)
+
+			assert.False(t, decision.Allowed)
+			assert.InDelta(t, (rate / 4).Seconds(), decision.RetryAfter.Seconds(), 1)
+			assert.InDelta(t, rate.Seconds(), time.Until(decision.ResetAt).Seconds(), 1)
+		})
+	})
+
+	t.Run("Increment agrees with Allow", func(t *testing.T) {
+		forEachBackend(t, yaratelimit.FixedWindow, 1, rate, func(t *testing.T, limiter yaratelimit.RateLimiter) {
+			ctx := context.Background()
+
+			decision, err := limiter.Allow(ctx, 1, "retry")
+			require.NoError(t, err)
+			assert.True(t, decision.Allowed)
+
+			banned, err := limiter.Increment(ctx, 1, "retry")
+			require.NoError(t, err)
+			assert.True(t, banned)
+		})
+	})
+}
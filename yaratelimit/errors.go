@@ -0,0 +1,9 @@
+package yaratelimit
+
+import "errors"
+
+var (
+	ErrInvalidStorageFormat = errors.New("[RATELIMIT] invalid storage format")
+	ErrFailedToRunScript    = errors.New("[RATELIMIT] failed to run rate limit script")
+	ErrUnexpectedScriptType = errors.New("[RATELIMIT] unexpected rate limit script result")
+)
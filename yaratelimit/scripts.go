@@ -0,0 +1,125 @@
+package yaratelimit
+
+import "github.com/redis/go-redis/v9"
+
+// fixedWindowScript mirrors RateLimit.Increment.
+//
+//	KEYS[1] – record key
+//	ARGV[1] – now, unix milliseconds
+//	ARGV[2] – window, milliseconds
+//	ARGV[3] – limit
+var fixedWindowScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+local count = 0
+local first = math.floor(now / 1000)
+local value = redis.call('GET', KEYS[1])
+if value then
+	local storedCount, storedFirst = string.match(value, '^(%d+),(%d+)$')
+	storedCount = tonumber(storedCount)
+	storedFirst = tonumber(storedFirst)
+	if storedCount and storedFirst and now - window < storedFirst * 1000 then
+		if storedCount + 1 > limit then
+			return {1, value}
+		end
+		count = storedCount
+		first = storedFirst
+	end
+end
+local ttl = math.max(first * 1000 + window - now, 1)
+local record = (count + 1) .. ',' .. first
+redis.call('SET', KEYS[1], record, 'PX', ttl)
+return {0, record}
+`)
+
+// slidingLogScript mirrors SlidingLogRateLimit.Increment.
+//
+//	KEYS[1] – record key
+//	ARGV[1] – now, unix milliseconds
+//	ARGV[2] – window, milliseconds
+//	ARGV[3] – limit
+var slidingLogScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+local hits = {}
+local value = redis.call('GET', KEYS[1])
+if value then
+	for field in string.gmatch(value, '[^,]+') do
+		local hit = tonumber(field)
+		if hit and hit > now - window then
+			table.insert(hits, field)
+		end
+	end
+end
+if #hits >= limit then
+	return {1, table.concat(hits, ',')}
+end
+table.insert(hits, ARGV[1])
+local record = table.concat(hits, ',')
+redis.call('SET', KEYS[1], record, 'PX', math.max(window, 1))
+return {0, record}
+`)
+
+// slidingWindowCounterScript mirrors SlidingWindowCounterRateLimit.Increment.
+//
+//	KEYS[1] – record key
+//	ARGV[1] – now, unix milliseconds
+//	ARGV[2] – window, milliseconds
+//	ARGV[3] – limit
+var slidingWindowCounterScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local window = math.max(tonumber(ARGV[2]), 1)
+local limit = tonumber(ARGV[3])
+local start = now - (now % window)
+local current = 0
+local previous = 0
+local value = redis.call('GET', KEYS[1])
+if value then
+	local storedStart, storedCurrent, storedPrevious = string.match(value, '^(%d+),(%d+),(%d+)$')
+	storedStart = tonumber(storedStart)
+	if storedStart == start then
+		current = tonumber(storedCurrent)
+		previous = tonumber(storedPrevious)
+	elseif storedStart == start - window then
+		previous = tonumber(storedCurrent)
+	end
+end
+local estimate = previous * (window - (now - start)) / window + current
+if estimate + 1 > limit then
+	return {1, value or ''}
+end
+local record = start .. ',' .. (current + 1) .. ',' .. previous
+redis.call('SET', KEYS[1], record, 'PX', window * 2)
+return {0, record}
+`)
+
+// tokenBucketScript mirrors TokenBucketRateLimit.Increment.
+//
+//	KEYS[1] – record key
+//	ARGV[1] – now, unix milliseconds
+//	ARGV[2] – refill period, milliseconds
+//	ARGV[3] – capacity
+var tokenBucketScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local period = math.max(tonumber(ARGV[2]), 1)
+local capacity = tonumber(ARGV[3])
+local tokens = capacity
+local value = redis.call('GET', KEYS[1])
+if value then
+	local storedTokens, storedLast = string.match(value, '^([^,]+),(%d+)$')
+	storedTokens = tonumber(storedTokens)
+	storedLast = tonumber(storedLast)
+	if storedTokens and storedLast then
+		local elapsed = math.max(now - storedLast, 0)
+		tokens = math.min(capacity, storedTokens + elapsed * capacity / period)
+	end
+end
+if tokens < 1 then
+	return {1, value or ''}
+end
+local record = tostring(tokens - 1) .. ',' .. ARGV[1]
+redis.call('SET', KEYS[1], record, 'PX', period)
+return {0, record}
+`)
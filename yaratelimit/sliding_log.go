@@ -0,0 +1,231 @@
+package yaratelimit
+
+import (
+	"context"
+	"time"
+
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yacache"
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yaerrors"
+)
+
+// SlidingLogRateLimit allows at most Limit hits within any trailing
+// Rate-long interval by remembering the timestamp of every accepted hit.
+//
+// The record is stored under `rate-limit-sliding-log-<id>-<group>` as a CSV
+// list of unix millisecond timestamps, oldest first:
+//
+//	"1726860000120,1726860003480,1726860004001"
+//
+// The zero value is not valid; use NewSlidingLogRateLimit.
+type SlidingLogRateLimit[Cache yacache.Container] struct {
+	Cache yacache.Cache[Cache]
+	// Limit is the max allowed hits within any trailing Rate.
+	Limit uint8
+	// Rate is the length of the trailing interval.
+	Rate time.Duration
+}
+
+// NewSlidingLogRateLimit wires dependencies and returns a ready-to-use
+// sliding-log limiter.
+//
+// Example:
+//
+//	rl := yaratelimit.NewSlidingLogRateLimit(cache, 5, time.Minute)
+func NewSlidingLogRateLimit[Cache yacache.Container](
+	cache yacache.Cache[Cache],
+	limit uint8,
+	rate time.Duration,
+) *SlidingLogRateLimit[Cache] {
+	return &SlidingLogRateLimit[Cache]{
+		Cache: cache,
+		Limit: limit,
+		Rate:  rate,
+	}
+}
+
+// CheckBanned returns true if the next Increment would be rejected, i.e. the
+// trailing interval already holds Limit hits.
+//
+// Example:
+//
+//	banned, err := rl.CheckBanned(ctx, userID, "otp")
+func (r *SlidingLogRateLimit[Cache]) CheckBanned(
+	ctx context.Context,
+	id uint64,
+	group string,
+) (bool, yaerrors.Error) {
+	storage, err := r.Get(ctx, id, group)
+	if err != nil {
+		return false, err.Wrap("failed to check storage")
+	}
+
+	return storage.Limit >= r.Limit, nil
+}
+
+// Refresh drops the log for (id, group) and records a single hit at the
+// current time.
+//
+// Example:
+//
+//	_ = rl.Refresh(ctx, 42, "otp")
+func (r *SlidingLogRateLimit[Cache]) Refresh(
+	ctx context.Context,
+	id uint64,
+	group string,
+) yaerrors.Error {
+	if err := r.Cache.Set(
+		ctx,
+		formatAlgorithmKey(slidingLogKeyPrefix, id, group),
+		formatInts(time.Now().UnixMilli()),
+		r.Rate,
+	); err != nil {
+		return err.Wrap("failed to set refreshed storage")
+	}
+
+	return nil
+}
+
+// Allow records a hit for (id, group) unless the trailing interval already
+// holds Limit hits, in which case the hit is rejected and nothing is
+// recorded. A rejected subject may retry once its oldest counted hit leaves
+// the interval.
+//
+// Example:
+//
+//	decision, err := rl.Allow(ctx, userID, "signin")
+//	if !decision.Allowed { /* reject, retry after decision.RetryAfter */ }
+func (r *SlidingLogRateLimit[Cache]) Allow(
+	ctx context.Context,
+	id uint64,
+	group string,
+) (*Decision, yaerrors.Error) {
+	now := time.Now().UnixMilli()
+
+	record, banned, err := apply(
+		ctx,
+		r.Cache,
+		formatAlgorithmKey(slidingLogKeyPrefix, id, group),
+		slidingLogScript,
+		[]any{now, r.Rate.Milliseconds(), r.Limit},
+		func(value string, _ bool) (string, time.Duration, bool) {
+			hits := r.activeHits(value, now)
+
+			if len(hits) >= int(r.Limit) {
+				return value, 0, true
+			}
+
+			return formatInts(append(hits, now)...), r.Rate, false
+		},
+	)
+	if err != nil {
+		return nil, err.Wrap("failed to increment sliding log")
+	}
+
+	return r.decide(record, banned, now), nil
+}
+
+// Increment records a hit for (id, group) like Allow and returns true,
+// without recording anything, if the trailing interval already holds Limit
+// hits.
+//
+// Example:
+//
+//	banned, err := rl.Increment(ctx, userID, "signin")
+//	if banned { /* reject */ }
+func (r *SlidingLogRateLimit[Cache]) Increment(
+	ctx context.Context,
+	id uint64,
+	group string,
+) (bool, yaerrors.Error) {
+	decision, err := r.Allow(ctx, id, group)
+	if err != nil {
+		return false, err
+	}
+
+	return !decision.Allowed, nil
+}
+
+// Get returns the number of hits within the trailing interval and the unix
+// second of the oldest of them (zero when the interval is empty).
+//
+// Example:
+//
+//	st, _ := rl.Get(ctx, 42, "otp")
+//	fmt.Println(st.Limit, st.FirstRequest)
+func (r *SlidingLogRateLimit[Cache]) Get(
+	ctx context.Context,
+	id uint64,
+	group string,
+) (*Storage, yaerrors.Error) {
+	value, err := r.Cache.Get(ctx, formatAlgorithmKey(slidingLogKeyPrefix, id, group))
+	if err != nil {
+		return nil, err.Wrap("failed to get storage")
+	}
+
+	if _, parseErr := parseInts(value); parseErr != nil {
+		return nil, parseErr.Wrap("failed to parse sliding log")
+	}
+
+	hits := r.activeHits(value, time.Now().UnixMilli())
+
+	storage := &Storage{
+		Limit: clampUsage(int64(len(hits))),
+	}
+
+	if len(hits) > 0 {
+		storage.FirstRequest = unixSeconds(hits[0])
+	}
+
+	return storage, nil
+}
+
+// decide describes the log Allow left behind (or observed, when banned). The
+// quota is fully restored once the newest hit leaves the interval; a banned
+// subject waits for the hit that keeps the log at Limit to leave it.
+func (r *SlidingLogRateLimit[Cache]) decide(record string, banned bool, now int64) *Decision {
+	hits := r.activeHits(record, now)
+	rate := r.Rate.Milliseconds()
+
+	decision := &Decision{
+		Allowed:   !banned,
+		Limit:     r.Limit,
+		Remaining: clampUsage(int64(r.Limit) - int64(len(hits))),
+		ResetAt:   time.UnixMilli(now),
+	}
+
+	if len(hits) > 0 {
+		decision.ResetAt = time.UnixMilli(hits[len(hits)-1] + rate)
+	}
+
+	if banned {
+		decision.RetryAfter = r.Rate
+
+		if r.Limit > 0 && len(hits) >= int(r.Limit) {
+			decision.RetryAfter = waitFor(float64(hits[len(hits)-int(r.Limit)] + rate - now))
+		}
+	}
+
+	return decision
+}
+
+// activeHits parses the stored log and keeps only the hits that still fall
+// into the trailing interval ending at now. An unparsable record is treated
+// as empty so that a corrupted value heals on the next hit.
+func (r *SlidingLogRateLimit[Cache]) activeHits(value string, now int64) []int64 {
+	hits, err := parseInts(value)
+	if err != nil {
+		return nil
+	}
+
+	windowStart := now - r.Rate.Milliseconds()
+
+	active := hits[:0]
+
+	for _, hit := range hits {
+		if hit > windowStart {
+			active = append(active, hit)
+		}
+	}
+
+	return active
+}
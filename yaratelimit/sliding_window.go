@@ -0,0 +1,316 @@
+package yaratelimit
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yacache"
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yaerrors"
+)
+
+// SlidingWindowCounterRateLimit approximates a sliding window with two fixed
+// windows: the hits of the previous window are weighted by the fraction of it
+// that still overlaps the trailing Rate-long interval and added to the hits
+// of the current window.
+//
+// The record is stored under `rate-limit-sliding-window-<id>-<group>` as a
+// CSV tuple of the current window start (unix milliseconds, aligned to Rate)
+// and both counters:
+//
+//	"<window_start_ms>,<current>,<previous>"
+//
+// The zero value is not valid; use NewSlidingWindowCounterRateLimit.
+type SlidingWindowCounterRateLimit[Cache yacache.Container] struct {
+	Cache yacache.Cache[Cache]
+	// Limit is the max allowed (estimated) hits within any trailing Rate.
+	Limit uint8
+	// Rate is the window size.
+	Rate time.Duration
+}
+
+// slidingWindowCounter is the parsed sliding-window-counter record.
+type slidingWindowCounter struct {
+	start    int64
+	current  int64
+	previous int64
+}
+
+// NewSlidingWindowCounterRateLimit wires dependencies and returns a
+// ready-to-use sliding-window-counter limiter.
+//
+// Example:
+//
+//	rl := yaratelimit.NewSlidingWindowCounterRateLimit(cache, 100, time.Minute)
+func NewSlidingWindowCounterRateLimit[Cache yacache.Container](
+	cache yacache.Cache[Cache],
+	limit uint8,
+	rate time.Duration,
+) *SlidingWindowCounterRateLimit[Cache] {
+	return &SlidingWindowCounterRateLimit[Cache]{
+		Cache: cache,
+		Limit: limit,
+		Rate:  rate,
+	}
+}
+
+// CheckBanned returns true if the next Increment would be rejected.
+//
+// Example:
+//
+//	banned, err := rl.CheckBanned(ctx, userID, "api")
+func (r *SlidingWindowCounterRateLimit[Cache]) CheckBanned(
+	ctx context.Context,
+	id uint64,
+	group string,
+) (bool, yaerrors.Error) {
+	storage, err := r.Get(ctx, id, group)
+	if err != nil {
+		return false, err.Wrap("failed to check storage")
+	}
+
+	return storage.Limit >= r.Limit, nil
+}
+
+// Refresh forgets both windows for (id, group) and records a single hit in
+// the current one.
+//
+// Example:
+//
+//	_ = rl.Refresh(ctx, 42, "api")
+func (r *SlidingWindowCounterRateLimit[Cache]) Refresh(
+	ctx context.Context,
+	id uint64,
+	group string,
+) yaerrors.Error {
+	if err := r.Cache.Set(
+		ctx,
+		formatAlgorithmKey(slidingWindowCounterKeyPrefix, id, group),
+		formatInts(r.windowStart(time.Now().UnixMilli()), 1, 0),
+		r.ttl(),
+	); err != nil {
+		return err.Wrap("failed to set refreshed storage")
+	}
+
+	return nil
+}
+
+// Allow records a hit for (id, group) unless the estimated count of the
+// trailing interval would exceed Limit, in which case the hit is rejected and
+// nothing is recorded.
+//
+// Example:
+//
+//	decision, err := rl.Allow(ctx, userID, "api")
+//	if !decision.Allowed { /* reject, retry after decision.RetryAfter */ }
+func (r *SlidingWindowCounterRateLimit[Cache]) Allow(
+	ctx context.Context,
+	id uint64,
+	group string,
+) (*Decision, yaerrors.Error) {
+	now := time.Now().UnixMilli()
+	window := r.window()
+
+	record, banned, err := apply(
+		ctx,
+		r.Cache,
+		formatAlgorithmKey(slidingWindowCounterKeyPrefix, id, group),
+		slidingWindowCounterScript,
+		[]any{now, window, r.Limit},
+		func(value string, _ bool) (string, time.Duration, bool) {
+			counter := r.roll(value, now)
+
+			if r.estimate(counter, now)+1 > float64(r.Limit) {
+				return value, 0, true
+			}
+
+			return formatInts(counter.start, counter.current+1, counter.previous), r.ttl(), false
+		},
+	)
+	if err != nil {
+		return nil, err.Wrap("failed to increment sliding window counter")
+	}
+
+	return r.decide(record, banned, now), nil
+}
+
+// Increment records a hit for (id, group) like Allow and returns true,
+// without recording anything, if the estimated count of the trailing
+// interval would exceed Limit.
+//
+// Example:
+//
+//	banned, err := rl.Increment(ctx, userID, "api")
+//	if banned { /* reject */ }
+func (r *SlidingWindowCounterRateLimit[Cache]) Increment(
+	ctx context.Context,
+	id uint64,
+	group string,
+) (bool, yaerrors.Error) {
+	decision, err := r.Allow(ctx, id, group)
+	if err != nil {
+		return false, err
+	}
+
+	return !decision.Allowed, nil
+}
+
+// Get returns the estimated hit count of the trailing interval, rounded up so
+// that Storage.Limit >= Limit exactly when the next hit would be rejected, and
+// the unix second the current window started at.
+//
+// Example:
+//
+//	st, _ := rl.Get(ctx, 42, "api")
+//	fmt.Println(st.Limit, st.FirstRequest)
+func (r *SlidingWindowCounterRateLimit[Cache]) Get(
+	ctx context.Context,
+	id uint64,
+	group string,
+) (*Storage, yaerrors.Error) {
+	value, err := r.Cache.Get(ctx, formatAlgorithmKey(slidingWindowCounterKeyPrefix, id, group))
+	if err != nil {
+		return nil, err.Wrap("failed to get storage")
+	}
+
+	if _, parseErr := parseSlidingWindowCounter(value); parseErr != nil {
+		return nil, parseErr.Wrap("failed to parse sliding window counter")
+	}
+
+	now := time.Now().UnixMilli()
+	counter := r.roll(value, now)
+
+	return &Storage{
+		Limit:        clampUsage(int64(math.Ceil(r.estimate(counter, now)))),
+		FirstRequest: unixSeconds(counter.start),
+	}, nil
+}
+
+// decide describes the record Allow left behind (or observed, when banned).
+// The quota is fully restored once every counted hit has aged out of both
+// windows; a banned subject waits until the estimate drops to Limit-1.
+func (r *SlidingWindowCounterRateLimit[Cache]) decide(
+	record string,
+	banned bool,
+	now int64,
+) *Decision {
+	counter := r.roll(record, now)
+	window := r.window()
+
+	decision := &Decision{
+		Allowed:   !banned,
+		Limit:     r.Limit,
+		Remaining: clampUsage(int64(math.Floor(float64(r.Limit) - r.estimate(counter, now)))),
+		ResetAt:   time.UnixMilli(now),
+	}
+
+	switch {
+	case counter.current > 0:
+		decision.ResetAt = time.UnixMilli(counter.start + window*slidingWindowCounterTTLWindows)
+	case counter.previous > 0:
+		decision.ResetAt = time.UnixMilli(counter.start + window)
+	}
+
+	if banned {
+		decision.RetryAfter = r.retryAfter(counter, now)
+	}
+
+	return decision
+}
+
+// retryAfter solves estimate(t)+1 <= Limit for the earliest t after now: still
+// within the current window while the previous one fades out, or otherwise in
+// the next window, where the current counter becomes the fading one.
+func (r *SlidingWindowCounterRateLimit[Cache]) retryAfter(
+	counter slidingWindowCounter,
+	now int64,
+) time.Duration {
+	if r.Limit == 0 {
+		return r.Rate
+	}
+
+	window := float64(r.window())
+	elapsed := float64(now - counter.start)
+	budget := float64(r.Limit) - 1
+
+	if counter.current <= int64(budget) && counter.previous > 0 {
+		fade := window * (1 - (budget-float64(counter.current))/float64(counter.previous))
+
+		return waitFor(fade - elapsed)
+	}
+
+	fade := window * (1 - budget/float64(max(counter.current, 1)))
+
+	return waitFor(window - elapsed + fade)
+}
+
+// roll parses the stored record and moves it forward to the window that
+// contains now: the current counter becomes the previous one when exactly one
+// window has passed, and both are reset when more than one has.
+func (r *SlidingWindowCounterRateLimit[Cache]) roll(value string, now int64) slidingWindowCounter {
+	start := r.windowStart(now)
+
+	stored, err := parseSlidingWindowCounter(value)
+	if err != nil {
+		return slidingWindowCounter{start: start}
+	}
+
+	switch stored.start {
+	case start:
+		return stored
+	case start - r.window():
+		return slidingWindowCounter{start: start, previous: stored.current}
+	default:
+		return slidingWindowCounter{start: start}
+	}
+}
+
+// estimate weights the previous window by its overlap with the trailing
+// interval ending at now and adds the current window's hits.
+func (r *SlidingWindowCounterRateLimit[Cache]) estimate(
+	counter slidingWindowCounter,
+	now int64,
+) float64 {
+	window := r.window()
+	overlap := float64(window-(now-counter.start)) / float64(window)
+
+	return float64(counter.previous)*overlap + float64(counter.current)
+}
+
+// windowStart aligns now down to the start of its window.
+func (r *SlidingWindowCounterRateLimit[Cache]) windowStart(now int64) int64 {
+	return now - now%r.window()
+}
+
+// window returns Rate in milliseconds, never less than one.
+func (r *SlidingWindowCounterRateLimit[Cache]) window() int64 {
+	return max(r.Rate.Milliseconds(), 1)
+}
+
+// ttl keeps the record for the current and the previous window.
+func (r *SlidingWindowCounterRateLimit[Cache]) ttl() time.Duration {
+	return time.Duration(r.window()*slidingWindowCounterTTLWindows) * time.Millisecond
+}
+
+// parseSlidingWindowCounter decodes a "<start>,<current>,<previous>" record.
+func parseSlidingWindowCounter(value string) (slidingWindowCounter, yaerrors.Error) {
+	fields, err := parseInts(value)
+	if err != nil {
+		return slidingWindowCounter{}, err.Wrap("failed to parse sliding window counter")
+	}
+
+	if len(fields) != slidingWindowCounterFields {
+		return slidingWindowCounter{}, yaerrors.FromError(
+			http.StatusInternalServerError,
+			ErrInvalidStorageFormat,
+			"unexpected sliding window counter field count",
+		)
+	}
+
+	return slidingWindowCounter{
+		start:    fields[0],
+		current:  fields[1],
+		previous: fields[2],
+	}, nil
+}
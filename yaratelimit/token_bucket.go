@@ -0,0 +1,293 @@
+package yaratelimit
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yacache"
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yaerrors"
+)
+
+// TokenBucketRateLimit holds up to Limit tokens per (id, group) and refills
+// them continuously at Limit tokens per Rate. Every accepted hit spends one
+// token, so a subject may burst up to Limit hits at once and afterwards
+// sustain one hit every Rate/Limit.
+//
+// The record is stored under `rate-limit-token-bucket-<id>-<group>` as a CSV
+// tuple of the remaining (fractional) tokens and the unix millisecond time
+// they were counted at:
+//
+//	"<tokens>,<last_refill_ms>"
+//
+// A missing record means a full bucket.
+//
+// The zero value is not valid; use NewTokenBucketRateLimit.
+type TokenBucketRateLimit[Cache yacache.Container] struct {
+	Cache yacache.Cache[Cache]
+	// Limit is the bucket capacity, i.e. the largest allowed burst.
+	Limit uint8
+	// Rate is how long an empty bucket takes to refill completely.
+	Rate time.Duration
+}
+
+// tokenBucket is the parsed token-bucket record.
+type tokenBucket struct {
+	tokens float64
+	last   int64
+}
+
+// NewTokenBucketRateLimit wires dependencies and returns a ready-to-use
+// token-bucket limiter.
+//
+// Example:
+//
+//	rl := yaratelimit.NewTokenBucketRateLimit(cache, 3, time.Minute)
+func NewTokenBucketRateLimit[Cache yacache.Container](
+	cache yacache.Cache[Cache],
+	limit uint8,
+	rate time.Duration,
+) *TokenBucketRateLimit[Cache] {
+	return &TokenBucketRateLimit[Cache]{
+		Cache: cache,
+		Limit: limit,
+		Rate:  rate,
+	}
+}
+
+// CheckBanned returns true if the next Increment would be rejected, i.e. less
+// than one token is available.
+//
+// Example:
+//
+//	banned, err := rl.CheckBanned(ctx, userID, "signin")
+func (r *TokenBucketRateLimit[Cache]) CheckBanned(
+	ctx context.Context,
+	id uint64,
+	group string,
+) (bool, yaerrors.Error) {
+	storage, err := r.Get(ctx, id, group)
+	if err != nil {
+		return false, err.Wrap("failed to check storage")
+	}
+
+	return storage.Limit >= r.Limit, nil
+}
+
+// Refresh refills the bucket for (id, group) and spends one token at the
+// current time.
+//
+// Example:
+//
+//	_ = rl.Refresh(ctx, 42, "signin")
+func (r *TokenBucketRateLimit[Cache]) Refresh(
+	ctx context.Context,
+	id uint64,
+	group string,
+) yaerrors.Error {
+	if err := r.Cache.Set(
+		ctx,
+		formatAlgorithmKey(tokenBucketKeyPrefix, id, group),
+		formatTokenBucket(tokenBucket{
+			tokens: float64(r.Limit) - 1,
+			last:   time.Now().UnixMilli(),
+		}),
+		r.Rate,
+	); err != nil {
+		return err.Wrap("failed to set refreshed storage")
+	}
+
+	return nil
+}
+
+// Allow spends one token for (id, group). When less than one token is
+// available the hit is rejected and nothing is spent; the subject may retry
+// once a whole token has been refilled.
+//
+// Example:
+//
+//	decision, err := rl.Allow(ctx, userID, "signin")
+//	if !decision.Allowed { /* reject, retry after decision.RetryAfter */ }
+func (r *TokenBucketRateLimit[Cache]) Allow(
+	ctx context.Context,
+	id uint64,
+	group string,
+) (*Decision, yaerrors.Error) {
+	now := time.Now().UnixMilli()
+
+	record, banned, err := apply(
+		ctx,
+		r.Cache,
+		formatAlgorithmKey(tokenBucketKeyPrefix, id, group),
+		tokenBucketScript,
+		[]any{now, r.period(), r.Limit},
+		func(value string, found bool) (string, time.Duration, bool) {
+			bucket := r.refill(value, found, now)
+
+			if bucket.tokens < 1 {
+				return value, 0, true
+			}
+
+			next := formatTokenBucket(tokenBucket{tokens: bucket.tokens - 1, last: now})
+
+			return next, r.Rate, false
+		},
+	)
+	if err != nil {
+		return nil, err.Wrap("failed to increment token bucket")
+	}
+
+	return r.decide(record, banned, now), nil
+}
+
+// Increment spends one token for (id, group) like Allow. When less than one
+// token is available it returns true and spends nothing.
+//
+// Example:
+//
+//	banned, err := rl.Increment(ctx, userID, "signin")
+//	if banned { /* reject */ }
+func (r *TokenBucketRateLimit[Cache]) Increment(
+	ctx context.Context,
+	id uint64,
+	group string,
+) (bool, yaerrors.Error) {
+	decision, err := r.Allow(ctx, id, group)
+	if err != nil {
+		return false, err
+	}
+
+	return !decision.Allowed, nil
+}
+
+// Get returns how many whole tokens have been spent (Limit minus the whole
+// tokens available after refilling up to now) and the unix second of the
+// last accepted hit.
+//
+// Example:
+//
+//	st, _ := rl.Get(ctx, 42, "signin")
+//	fmt.Println(st.Limit, st.FirstRequest)
+func (r *TokenBucketRateLimit[Cache]) Get(
+	ctx context.Context,
+	id uint64,
+	group string,
+) (*Storage, yaerrors.Error) {
+	value, err := r.Cache.Get(ctx, formatAlgorithmKey(tokenBucketKeyPrefix, id, group))
+	if err != nil {
+		return nil, err.Wrap("failed to get storage")
+	}
+
+	stored, parseErr := parseTokenBucket(value)
+	if parseErr != nil {
+		return nil, parseErr.Wrap("failed to parse token bucket")
+	}
+
+	bucket := r.refill(value, true, time.Now().UnixMilli())
+
+	return &Storage{
+		Limit:        clampUsage(int64(r.Limit) - int64(math.Floor(bucket.tokens))),
+		FirstRequest: unixSeconds(stored.last),
+	}, nil
+}
+
+// decide describes the bucket Allow left behind (or observed, when banned).
+// The quota is fully restored once the bucket refills to Limit; a banned
+// subject waits for the next whole token.
+func (r *TokenBucketRateLimit[Cache]) decide(record string, banned bool, now int64) *Decision {
+	bucket := r.refill(record, record != "", now)
+	period := float64(r.period())
+
+	decision := &Decision{
+		Allowed:   !banned,
+		Limit:     r.Limit,
+		Remaining: clampUsage(int64(math.Floor(bucket.tokens))),
+		ResetAt:   time.UnixMilli(now),
+	}
+
+	if r.Limit > 0 {
+		refill := (float64(r.Limit) - bucket.tokens) * period / float64(r.Limit)
+		decision.ResetAt = time.UnixMilli(now + int64(math.Ceil(refill)))
+	}
+
+	if banned {
+		decision.RetryAfter = r.Rate
+
+		if r.Limit > 0 {
+			decision.RetryAfter = waitFor((1 - bucket.tokens) * period / float64(r.Limit))
+		}
+	}
+
+	return decision
+}
+
+// refill parses the stored record and adds the tokens earned since it was
+// last written, capped at Limit. A missing or unparsable record is a full
+// bucket.
+func (r *TokenBucketRateLimit[Cache]) refill(value string, found bool, now int64) tokenBucket {
+	capacity := float64(r.Limit)
+
+	if !found {
+		return tokenBucket{tokens: capacity, last: now}
+	}
+
+	bucket, err := parseTokenBucket(value)
+	if err != nil {
+		return tokenBucket{tokens: capacity, last: now}
+	}
+
+	elapsed := max(now-bucket.last, 0)
+
+	return tokenBucket{
+		tokens: math.Min(capacity, bucket.tokens+float64(elapsed)*capacity/float64(r.period())),
+		last:   now,
+	}
+}
+
+// period returns Rate in milliseconds, never less than one.
+func (r *TokenBucketRateLimit[Cache]) period() int64 {
+	return max(r.Rate.Milliseconds(), 1)
+}
+
+// parseTokenBucket decodes a "<tokens>,<last_refill_ms>" record.
+func parseTokenBucket(value string) (tokenBucket, yaerrors.Error) {
+	fields := strings.Split(value, valueSeparator)
+	if len(fields) != tokenBucketFields {
+		return tokenBucket{}, yaerrors.FromError(
+			http.StatusInternalServerError,
+			ErrInvalidStorageFormat,
+			"unexpected token bucket field count",
+		)
+	}
+
+	tokens, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return tokenBucket{}, yaerrors.FromError(
+			http.StatusInternalServerError,
+			errors.Join(err, ErrInvalidStorageFormat),
+			"couldn't parse tokens",
+		)
+	}
+
+	last, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil {
+		return tokenBucket{}, yaerrors.FromError(
+			http.StatusInternalServerError,
+			errors.Join(err, ErrInvalidStorageFormat),
+			"couldn't parse last refill time",
+		)
+	}
+
+	return tokenBucket{tokens: tokens, last: last}, nil
+}
+
+// formatTokenBucket encodes a token-bucket record.
+func formatTokenBucket(bucket tokenBucket) string {
+	return strconv.FormatFloat(bucket.tokens, 'f', -1, 64) +
+		valueSeparator +
+		strconv.FormatInt(bucket.last, 10)
+}
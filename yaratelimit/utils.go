@@ -0,0 +1,217 @@
+package yaratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yacache"
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yaerrors"
+	"github.com/redis/go-redis/v9"
+)
+
+// step is one read-modify-write transition of a limiter record. It receives
+// the stored value (found is false when there is none) and returns the
+// resulting record, its TTL and the ban decision. A banned step returns the
+// record it observed, which is never written back.
+type step func(value string, found bool) (record string, ttl time.Duration, banned bool)
+
+// invalidator is implemented by caches that keep local copies of Redis keys,
+// such as yacache.NearCache, which must be told about writes made via Raw.
+type invalidator interface {
+	Invalidate(ctx context.Context, keys ...string) yaerrors.Error
+}
+
+// apply runs transition atomically against the record stored under key.
+//
+// On *redis.Client the transition is delegated to script, which must
+// implement exactly the same logic as transition and reply {banned, record}.
+// Any other backend runs an optimistic loop: read the record, compute the
+// transition and publish it with Cache.CompareAndSwap, starting over if
+// another caller changed the record in between; a missing record reads as
+// none, while any other read failure is returned. The backend is told apart by
+// the Cache type parameter, so Raw is only called on *redis.Client (it may be
+// an O(n) snapshot on memory backends). A record written by script is
+// invalidated on caches implementing invalidator.
+func apply[Cache yacache.Container](
+	ctx context.Context,
+	cache yacache.Cache[Cache],
+	key string,
+	script *redis.Script,
+	args []any,
+	transition step,
+) (string, bool, yaerrors.Error) {
+	var container Cache
+
+	if _, ok := any(container).(*redis.Client); ok {
+		client, _ := any(cache.Raw()).(*redis.Client) //nolint:errcheck // checked on the type parameter above
+
+		record, banned, err := runScript(ctx, client, script, key, args...)
+		if err != nil || banned {
+			return record, banned, err
+		}
+
+		if near, ok := cache.(invalidator); ok {
+			if invalidateErr := near.Invalidate(ctx, key); invalidateErr != nil {
+				return "", false, invalidateErr.Wrap("failed to invalidate rate limit record")
+			}
+		}
+
+		return record, false, nil
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return "", false, yaerrors.FromError(
+				http.StatusInternalServerError,
+				err,
+				fmt.Sprintf("gave up updating rate limit record `%s`", key),
+			)
+		}
+
+		value, getErr := cache.Get(ctx, key)
+		if getErr != nil {
+			if !errors.Is(getErr, yacache.ErrNotFoundValue) {
+				return "", false, getErr.Wrap("failed to read rate limit record")
+			}
+
+			value = ""
+		}
+
+		record, ttl, banned := transition(value, getErr == nil)
+		if banned {
+			return record, true, nil
+		}
+
+		swapped, err := cache.CompareAndSwap(ctx, key, value, record, ttl)
+		if err != nil {
+			return "", false, err.Wrap("failed to store rate limit record")
+		}
+
+		if swapped {
+			return record, false, nil
+		}
+	}
+}
+
+// runScript evaluates a single-key limiter script and converts its
+// {banned, record} reply.
+func runScript(
+	ctx context.Context,
+	client *redis.Client,
+	script *redis.Script,
+	key string,
+	args ...any,
+) (string, bool, yaerrors.Error) {
+	result, err := script.Run(ctx, client, []string{key}, args...).Slice()
+	if err != nil {
+		return "", false, yaerrors.FromError(
+			http.StatusInternalServerError,
+			errors.Join(err, ErrFailedToRunScript),
+			fmt.Sprintf("[REDIS] failed to run rate limit script by `%s`", key),
+		)
+	}
+
+	const replyLen = 2
+
+	if len(result) != replyLen {
+		return "", false, yaerrors.FromError(
+			http.StatusInternalServerError,
+			ErrUnexpectedScriptType,
+			fmt.Sprintf("[REDIS] rate limit script replied %d values by `%s`", len(result), key),
+		)
+	}
+
+	banned, ok := result[0].(int64)
+	if !ok {
+		return "", false, yaerrors.FromError(
+			http.StatusInternalServerError,
+			ErrUnexpectedScriptType,
+			fmt.Sprintf("[REDIS] rate limit script replied %T by `%s`", result[0], key),
+		)
+	}
+
+	record, ok := result[1].(string)
+	if !ok {
+		return "", false, yaerrors.FromError(
+			http.StatusInternalServerError,
+			ErrUnexpectedScriptType,
+			fmt.Sprintf("[REDIS] rate limit script replied %T by `%s`", result[1], key),
+		)
+	}
+
+	return record, banned == 1, nil
+}
+
+// formatAlgorithmKey builds the cache key for (id, group) under prefix so that
+// records of different algorithms never collide.
+func formatAlgorithmKey(prefix string, id uint64, group string) string {
+	return fmt.Sprintf("%s-%d-%s", prefix, id, group)
+}
+
+// parseInts splits a CSV record into int64 fields.
+func parseInts(value string) ([]int64, yaerrors.Error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	fields := strings.Split(value, valueSeparator)
+
+	result := make([]int64, 0, len(fields))
+
+	for _, field := range fields {
+		number, err := strconv.ParseInt(field, 10, 64)
+		if err != nil {
+			return nil, yaerrors.FromError(
+				http.StatusInternalServerError,
+				errors.Join(err, ErrInvalidStorageFormat),
+				"couldn't parse storage field",
+			)
+		}
+
+		result = append(result, number)
+	}
+
+	return result, nil
+}
+
+// formatInts joins int64 fields into a CSV record.
+func formatInts(values ...int64) string {
+	fields := make([]string, 0, len(values))
+
+	for _, value := range values {
+		fields = append(fields, strconv.FormatInt(value, 10))
+	}
+
+	return strings.Join(fields, valueSeparator)
+}
+
+// clampUsage converts a usage figure into the uint8 Storage.Limit field.
+func clampUsage(usage int64) uint8 {
+	const maxUsage = int64(^uint8(0))
+
+	switch {
+	case usage < 0:
+		return 0
+	case usage > maxUsage:
+		return uint8(maxUsage)
+	default:
+		return uint8(usage)
+	}
+}
+
+// waitFor converts a (possibly fractional) millisecond wait into a
+// Decision.RetryAfter, rounded up and never less than a millisecond.
+func waitFor(milliseconds float64) time.Duration {
+	return max(time.Duration(math.Ceil(milliseconds))*time.Millisecond, time.Millisecond)
+}
+
+// unixSeconds converts a unix millisecond timestamp to unix seconds.
+func unixSeconds(milliseconds int64) int64 {
+	return milliseconds / millisecondsInSecond
+}
@@ -1,6 +1,23 @@
-// Package yaratelimit implements a simple fixed-window rate limiter backed by
-// a yacache.Cache. It stores a per-(id, group) counter alongside the unix
-// timestamp of the first request in the current window.
+// Package yaratelimit implements rate limiters backed by a yacache.Cache,
+// keyed per (id, group). Four algorithms share the RateLimiter interface and
+// are selected at construction through NewRateLimiter:
+//
+//   - FixedWindow (RateLimit) – a counter plus the first request of the
+//     current window. Cheapest, but allows up to 2×Limit hits across a
+//     window boundary.
+//   - SlidingLog (SlidingLogRateLimit) – exact trailing window built from the
+//     timestamps of accepted hits.
+//   - SlidingWindowCounter (SlidingWindowCounterRateLimit) – weighted blend
+//     of the previous and current fixed windows.
+//   - TokenBucket (TokenBucketRateLimit) – burst control: up to Limit hits at
+//     once, refilled at Limit per Rate.
+//
+// Every Increment is a single atomic read-modify-write: a Lua script on a
+// *redis.Client backend and an optimistic Cache.CompareAndSwap loop on any
+// other, so concurrent callers and replicas never lose hits.
+//
+// The remainder of this overview describes the fixed-window limiter; each
+// other algorithm documents its own storage format on its type.
 //
 // # Storage layout
 //
@@ -26,6 +43,13 @@
 //
 // # Semantics
 //
+//   - Allow(ctx, id, group) -> (*Decision, err)
+//
+//     Records a hit exactly like Increment and reports the full outcome:
+//     whether the hit was accepted, the remaining quota, when the quota is
+//     fully restored and, for a rejected hit, how long to wait before
+//     retrying. Suited for RateLimit-* / Retry-After response headers.
+//
 //   - Increment(ctx, id, group) -> (banned bool, err)
 //
 //     Increments the counter if inside the current window or refreshes the
@@ -61,12 +85,12 @@ import (
 	"github.com/YaCodeDev/GoYaCodeDevUtils/yaerrors"
 )
 
-// RateLimiter exposes the behaviour of a fixed-window rate limiter backed by a cache.
+// RateLimiter exposes the behaviour of a rate limiter backed by a cache.
 //
 // Example:
 //
 //	cache := yacache.NewCache(yacache.NewMemoryContainer())
-//	rl := yaratelimit.NewRateLimit(cache, 5, time.Minute)
+//	rl := yaratelimit.NewRateLimiter(cache, yaratelimit.SlidingLog, 5, time.Minute)
 //	banned, err := rl.Increment(ctx, 42, "signin")
 //	_ = banned; _ = err
 type RateLimiter interface {
@@ -78,15 +102,24 @@ type RateLimiter interface {
 		group string,
 	) (bool, yaerrors.Error)
 
-	// Refresh resets the window for (id, group) to count=1 at current timestamp.
+	// Refresh resets the state for (id, group) as if exactly one hit had been
+	// recorded at the current timestamp.
 	Refresh(
 		ctx context.Context,
 		id uint64,
 		group string,
 	) yaerrors.Error
 
-	// Increment applies a hit inside the current window or refreshes if expired.
-	// It returns true if the subject should be treated as banned after this hit.
+	// Allow records a hit for (id, group) exactly like Increment and
+	// describes the outcome as a Decision.
+	Allow(
+		ctx context.Context,
+		id uint64,
+		group string,
+	) (*Decision, yaerrors.Error)
+
+	// Increment records a hit for (id, group). It returns true, without
+	// recording the hit, if the hit exceeds the limit.
 	Increment(
 		ctx context.Context,
 		id uint64,
@@ -107,6 +140,9 @@ type RateLimiter interface {
 type IRateLimit = RateLimiter
 
 // Storage is the parsed representation of the CSV value in the cache.
+//
+// The sliding and token-bucket limiters report their own state in the same
+// shape; see their Get methods for the exact meaning of both fields.
 type Storage struct {
 	// Limit is the count within the current window (despite the name, it stores the current usage).
 	Limit uint8
@@ -114,6 +150,56 @@ type Storage struct {
 	FirstRequest int64
 }
 
+// Decision is the outcome of a single Allow call.
+//
+// Example:
+//
+//	decision, err := rl.Allow(ctx, userID, "api")
+//	if err != nil { /* handle */ }
+//	if !decision.Allowed {
+//	    fmt.Println("retry in", decision.RetryAfter)
+//	}
+type Decision struct {
+	// Allowed reports whether the hit was accepted (and recorded).
+	Allowed bool
+	// Limit is the configured limit of the limiter.
+	Limit uint8
+	// Remaining is how many more hits would be accepted right now.
+	Remaining uint8
+	// ResetAt is when the subject's quota is fully restored.
+	ResetAt time.Time
+	// RetryAfter is how long a rejected subject has to wait before its next
+	// hit can be accepted. It is zero when Allowed is true.
+	RetryAfter time.Duration
+}
+
+// NewRateLimiter builds the limiter implementing algorithm. Unknown
+// algorithms fall back to FixedWindow.
+//
+// Example:
+//
+//	otp := yaratelimit.NewRateLimiter(cache, yaratelimit.TokenBucket, 3, 10*time.Minute)
+//	banned, err := otp.Increment(ctx, userID, "otp")
+func NewRateLimiter[Cache yacache.Container](
+	cache yacache.Cache[Cache],
+	algorithm Algorithm,
+	limit uint8,
+	rate time.Duration,
+) RateLimiter {
+	switch algorithm {
+	case SlidingLog:
+		return NewSlidingLogRateLimit(cache, limit, rate)
+	case SlidingWindowCounter:
+		return NewSlidingWindowCounterRateLimit(cache, limit, rate)
+	case TokenBucket:
+		return NewTokenBucketRateLimit(cache, limit, rate)
+	case FixedWindow:
+		return NewRateLimit(cache, limit, rate)
+	default:
+		return NewRateLimit(cache, limit, rate)
+	}
+}
+
 // RateLimit is a fixed-window limiter backed by a yacache.Cache.
 // The zero value is not valid; use NewRateLimit.
 type RateLimit[Cache yacache.Container] struct {
@@ -167,13 +253,63 @@ func (r *RateLimit[Cache]) CheckBanned(
 		return false, err.Wrap("failed to check storage")
 	}
 
-	return storage.Limit+1 > r.Limit, nil
+	return r.inWindow(storage.FirstRequest, time.Now()) && storage.Limit >= r.Limit, nil
 }
 
-// Increment records a hit for (id, group).
+// Allow records a hit for (id, group).
 // If the window is still active, it increments the counter.
 // If the window expired, it Refreshes the window (count=1).
-// Returns true if the subject is now banned (count >= Limit).
+// The hit is rejected, and not recorded, if the count would exceed Limit;
+// the subject may retry once the window ends.
+//
+// The whole read-modify-write is one atomic step: a Lua script on a
+// *redis.Client backend and a Cache.CompareAndSwap loop on any other, so
+// concurrent callers for the same (id, group) never lose hits and exactly
+// Limit of them pass per window. The record expires when its window ends.
+//
+// Example:
+//
+//	decision, err := rl.Allow(ctx, userID, "api:v1")
+//	if !decision.Allowed { /* reject, retry after decision.RetryAfter */ }
+func (r *RateLimit[Cache]) Allow(
+	ctx context.Context,
+	id uint64,
+	group string,
+) (*Decision, yaerrors.Error) {
+	now := time.Now()
+	window := r.Rate.Milliseconds()
+
+	record, banned, err := apply(
+		ctx,
+		r.Cache,
+		FormatKey(id, group),
+		fixedWindowScript,
+		[]any{now.UnixMilli(), window, r.Limit},
+		func(value string, found bool) (string, time.Duration, bool) {
+			storage, parseErr := parseValue(value)
+			if !found || parseErr != nil || !r.inWindow(storage.FirstRequest, now) {
+				return FormatValue(1, now.Unix()), r.windowTTL(now.Unix(), now), false
+			}
+
+			if int(storage.Limit)+1 > int(r.Limit) {
+				return value, 0, true
+			}
+
+			next := FormatValue(storage.Limit+1, storage.FirstRequest)
+
+			return next, r.windowTTL(storage.FirstRequest, now), false
+		},
+	)
+	if err != nil {
+		return nil, err.Wrap("failed to increment storage")
+	}
+
+	return r.decide(record, banned, now), nil
+}
+
+// Increment records a hit for (id, group) like Allow and returns true,
+// without recording the hit, if the subject is banned (count would exceed
+// Limit).
 //
 // Example:
 //
@@ -184,37 +320,12 @@ func (r *RateLimit[Cache]) Increment(
 	id uint64,
 	group string,
 ) (bool, yaerrors.Error) {
-	storage, err := r.Get(ctx, id, group)
+	decision, err := r.Allow(ctx, id, group)
 	if err != nil {
-		if err := r.Refresh(ctx, id, group); err != nil {
-			return false, err.Wrap("failed to refresh")
-		}
-
-		return false, nil
-	}
-
-	if time.Now().Add(-r.Rate).Before(time.Unix(storage.FirstRequest, 0)) {
-		if storage.Limit+1 > r.Limit {
-			return true, nil
-		}
-
-		if err := r.Cache.Set(
-			ctx,
-			FormatKey(id, group),
-			FormatValue(storage.Limit+1, storage.FirstRequest),
-			0,
-		); err != nil {
-			return false, err.Wrap("failed to increment storage")
-		}
-	} else {
-		if err := r.Refresh(ctx, id, group); err != nil {
-			return false, err.Wrap("failed to refresh")
-		}
-
-		return false, nil
+		return false, err
 	}
 
-	return storage.Limit+1 > r.Limit, nil
+	return !decision.Allowed, nil
 }
 
 // Refresh resets the window for (id, group) to count=1 at the current timestamp.
@@ -227,11 +338,13 @@ func (r *RateLimit[Cache]) Refresh(
 	id uint64,
 	group string,
 ) yaerrors.Error {
+	now := time.Now()
+
 	if err := r.Cache.Set(
 		ctx,
 		FormatKey(id, group),
-		fmt.Sprintf("%d,%d", 1, time.Now().Unix()),
-		0,
+		FormatValue(1, now.Unix()),
+		r.windowTTL(now.Unix(), now),
 	); err != nil {
 		return err.Wrap("failed to set refreshed storage")
 	}
@@ -251,17 +364,63 @@ func (r *RateLimit[Cache]) Get(
 	id uint64,
 	group string,
 ) (*Storage, yaerrors.Error) {
-	value, yaerr := r.Cache.Get(ctx, FormatKey(id, group))
-	if yaerr != nil {
-		return nil, yaerr.Wrap("failed to get storage")
+	value, err := r.Cache.Get(ctx, FormatKey(id, group))
+	if err != nil {
+		return nil, err.Wrap("failed to get storage")
+	}
+
+	storage, err := parseValue(value)
+	if err != nil {
+		return nil, err.Wrap("failed to parse storage")
 	}
 
+	return storage, nil
+}
+
+// inWindow reports whether the window that started at the unix second
+// firstRequest is still active at now.
+func (r *RateLimit[Cache]) inWindow(firstRequest int64, now time.Time) bool {
+	return now.Add(-r.Rate).Before(time.Unix(firstRequest, 0))
+}
+
+// windowTTL returns how long the window that started at the unix second
+// firstRequest has left at now, never less than a millisecond.
+func (r *RateLimit[Cache]) windowTTL(firstRequest int64, now time.Time) time.Duration {
+	return max(time.Unix(firstRequest, 0).Add(r.Rate).Sub(now), time.Millisecond)
+}
+
+// decide describes the record Allow left behind (or observed, when banned).
+func (r *RateLimit[Cache]) decide(record string, banned bool, now time.Time) *Decision {
+	storage, err := parseValue(record)
+	if err != nil {
+		storage = &Storage{FirstRequest: now.Unix()}
+	}
+
+	resetAt := time.Unix(storage.FirstRequest, 0).Add(r.Rate)
+
+	decision := &Decision{
+		Allowed:   !banned,
+		Limit:     r.Limit,
+		Remaining: clampUsage(int64(r.Limit) - int64(storage.Limit)),
+		ResetAt:   resetAt,
+	}
+
+	if banned {
+		decision.RetryAfter = waitFor(float64(resetAt.Sub(now).Milliseconds()))
+	}
+
+	return decision
+}
+
+// parseValue parses a "<count>,<first_unix_sec>" cache value.
+func parseValue(value string) (*Storage, yaerrors.Error) {
 	const separate = 2
 
-	values := strings.Split(value, ",")
+	values := strings.Split(value, valueSeparator)
 	if len(values) != separate {
-		return nil, yaerrors.FromString(
+		return nil, yaerrors.FromError(
 			http.StatusInternalServerError,
+			ErrInvalidStorageFormat,
 			"invalid storage format",
 		)
 	}
@@ -0,0 +1,309 @@
+package yascheduler_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yaerrors"
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yascheduler"
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yascheduler/engine"
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yascheduler/protocol"
+)
+
+const (
+	testCertValidity = time.Hour
+	testAuthHost     = "127.0.0.1"
+	testRefusalWait  = 200 * time.Millisecond
+)
+
+var testAuthSecret = []byte("shared executor secret")
+
+// testPKI is a throwaway certificate authority with one leaf it issued for
+// each side of the connection.
+type testPKI struct {
+	pool   *x509.CertPool
+	server tls.Certificate
+	client tls.Certificate
+}
+
+func newTestPKI(t *testing.T) *testPKI {
+	t.Helper()
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate ca key failed: %v", err)
+	}
+
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "yascheduler test ca"},
+		NotBefore:             time.Now().Add(-time.Minute),
+		NotAfter:              time.Now().Add(testCertValidity),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatalf("create ca certificate failed: %v", err)
+	}
+
+	caCert, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		t.Fatalf("parse ca certificate failed: %v", err)
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(caCert)
+
+	return &testPKI{
+		pool:   pool,
+		server: issueTestCert(t, caCert, caKey, 2, x509.ExtKeyUsageServerAuth),
+		client: issueTestCert(t, caCert, caKey, 3, x509.ExtKeyUsageClientAuth),
+	}
+}
+
+func issueTestCert(
+	t *testing.T,
+	ca *x509.Certificate,
+	caKey *ecdsa.PrivateKey,
+	serial int64,
+	usage x509.ExtKeyUsage,
+) tls.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate leaf key failed: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: testAuthHost},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(testCertValidity),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		IPAddresses:  []net.IP{net.ParseIP(testAuthHost)},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+	if err != nil {
+		t.Fatalf("create leaf certificate failed: %v", err)
+	}
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+// startAuthScheduler serves engine.Authenticate on every accepted
+// connection, admits what passes it, and reports every outcome.
+func startAuthScheduler(t *testing.T, cfg *engine.AuthConfig) (string, <-chan yaerrors.Error) {
+	t.Helper()
+
+	listener, err := engine.Listen(context.Background(), "tcp", testAuthHost+":0", cfg)
+	if err != nil {
+		t.Fatalf("listen failed: %v", err)
+	}
+
+	var (
+		connsMu sync.Mutex
+		conns   []net.Conn
+	)
+
+	t.Cleanup(func() {
+		_ = listener.Close()
+
+		connsMu.Lock()
+		defer connsMu.Unlock()
+
+		for _, conn := range conns {
+			_ = conn.Close()
+		}
+	})
+
+	outcomes := make(chan yaerrors.Error, 64)
+
+	go func() {
+		for {
+			conn, acceptErr := listener.Accept()
+			if acceptErr != nil {
+				return
+			}
+
+			connsMu.Lock()
+			conns = append(conns, conn)
+			connsMu.Unlock()
+
+			go func() {
+				correlationID, _, authErr := engine.Authenticate(conn, cfg)
+				if authErr == nil {
+					_ = protocol.WriteFrame(conn, correlationID, &protocol.RegisterAck{
+						Accepted:                true,
+						HeartbeatIntervalMillis: testHeartbeatMilli,
+					}, protocol.Limits{})
+				} else {
+					_ = conn.Close()
+				}
+
+				select {
+				case outcomes <- authErr:
+				default:
+				}
+			}()
+		}
+	}()
+
+	return listener.Addr().String(), outcomes
+}
+
+func runAuthClient(t *testing.T, cfg *yascheduler.Config) *yascheduler.Client {
+	t.Helper()
+
+	client, err := yascheduler.New(cfg, yascheduler.NewRegistry(), nil)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		_ = client.Run(ctx)
+	}()
+
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	return client
+}
+
+func nextOutcome(t *testing.T, outcomes <-chan yaerrors.Error) yaerrors.Error {
+	t.Helper()
+
+	select {
+	case outcome := <-outcomes:
+		return outcome
+	case <-time.After(testReadTimeout):
+		t.Fatal("no connection was authenticated in time")
+
+		return nil
+	}
+}
+
+func TestClientRegistersOverMutualTLSWithSecret(t *testing.T) {
+	t.Parallel()
+
+	pki := newTestPKI(t)
+	address, outcomes := startAuthScheduler(t, &engine.AuthConfig{
+		TLS: &tls.Config{
+			Certificates: []tls.Certificate{pki.server},
+			ClientCAs:    pki.pool,
+			MinVersion:   tls.VersionTLS13,
+		},
+		Secret: testAuthSecret,
+	})
+
+	client := runAuthClient(t, &yascheduler.Config{
+		Address:      address,
+		ExecutorType: testExecutorType,
+		TLS: &tls.Config{
+			Certificates: []tls.Certificate{pki.client},
+			RootCAs:      pki.pool,
+			MinVersion:   tls.VersionTLS13,
+		},
+		AuthSecret: testAuthSecret,
+	})
+
+	if err := nextOutcome(t, outcomes); err != nil {
+		t.Fatalf("authentication failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), testReadTimeout)
+	defer cancel()
+
+	if err := client.AwaitReady(ctx); err != nil {
+		t.Fatalf("AwaitReady failed: %v", err)
+	}
+}
+
+func TestSchedulerRefusesUnauthenticatedClients(t *testing.T) {
+	t.Parallel()
+
+	pki := newTestPKI(t)
+	serverTLS := &tls.Config{
+		Certificates: []tls.Certificate{pki.server},
+		ClientCAs:    pki.pool,
+		MinVersion:   tls.VersionTLS13,
+	}
+
+	cases := []struct {
+		name      string
+		clientTLS *tls.Config
+		secret    []byte
+		wantErr   error
+	}{
+		{
+			name:      "when the secret differs / then the challenge refuses it",
+			clientTLS: &tls.Config{Certificates: []tls.Certificate{pki.client}, RootCAs: pki.pool},
+			secret:    []byte("wrong secret"),
+			wantErr:   engine.ErrUnauthenticated,
+		},
+		{
+			name:      "when no client certificate is presented / then the handshake refuses it",
+			clientTLS: &tls.Config{RootCAs: pki.pool},
+			secret:    testAuthSecret,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			address, outcomes := startAuthScheduler(t, &engine.AuthConfig{
+				TLS:    serverTLS,
+				Secret: testAuthSecret,
+			})
+
+			testCase.clientTLS.MinVersion = tls.VersionTLS13
+
+			client := runAuthClient(t, &yascheduler.Config{
+				Address:      address,
+				ExecutorType: testExecutorType,
+				TLS:          testCase.clientTLS,
+				AuthSecret:   testCase.secret,
+			})
+
+			err := nextOutcome(t, outcomes)
+			if err == nil {
+				t.Fatal("the connection should have been refused")
+			}
+
+			if testCase.wantErr != nil && !errors.Is(err, testCase.wantErr) {
+				t.Fatalf("err = %v, want %v", err, testCase.wantErr)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), testRefusalWait)
+			defer cancel()
+
+			if readyErr := client.AwaitReady(ctx); readyErr == nil {
+				t.Error("a refused client should never become ready")
+			}
+		})
+	}
+}
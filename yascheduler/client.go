@@ -2,6 +2,7 @@ package yascheduler
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"math/rand/v2"
@@ -24,9 +25,14 @@ import (
 // type keeps every correlated round trip on the single pending map, so
 // connection teardown releases every blocked caller in one pass.
 type pendingReply struct {
-	upsertAck *protocol.JobUpsertAck
-	deleteAck *protocol.JobDeleteAck
-	labelAck  *protocol.LabelUpdateAck
+	upsertAck  *protocol.JobUpsertAck
+	deleteAck  *protocol.JobDeleteAck
+	labelAck   *protocol.LabelUpdateAck
+	listAck    *protocol.JobListAck
+	getAck     *protocol.JobGetAck
+	controlAck *protocol.JobControlAck
+	historyAck *protocol.ExecutionListAck
+	attemptAck *protocol.AttemptListAck
 }
 
 // Client maintains one long-lived TCP connection to the yascheduler
@@ -178,9 +184,7 @@ func (c *Client) connectAndServe(
 	execCtx context.Context,
 	backoff yabackoff.Backoff,
 ) yaerrors.Error {
-	dialer := net.Dialer{Timeout: c.cfg.DialTimeout}
-
-	conn, dialErr := dialer.DialContext(ctx, "tcp", c.cfg.Address)
+	conn, dialErr := c.dial(ctx)
 	if dialErr != nil {
 		return yaerrors.FromError(
 			http.StatusBadGateway,
@@ -205,6 +209,19 @@ func (c *Client) connectAndServe(
 	return c.serve(ctx, execCtx, conn, heartbeatInterval)
 }
 
+// dial opens one connection to the scheduler, completing the TLS
+// handshake first when TLS is configured.
+func (c *Client) dial(ctx context.Context) (net.Conn, error) {
+	netDialer := &net.Dialer{Timeout: c.cfg.DialTimeout}
+	if c.cfg.TLS == nil {
+		return netDialer.DialContext(ctx, "tcp", c.cfg.Address)
+	}
+
+	tlsDialer := &tls.Dialer{NetDialer: netDialer, Config: c.cfg.TLS}
+
+	return tlsDialer.DialContext(ctx, "tcp", c.cfg.Address)
+}
+
 // register performs the registration exchange on a fresh connection.
 func (c *Client) register(conn net.Conn) (time.Duration, yaerrors.Error) {
 	if deadlineErr := conn.SetDeadline(time.Now().Add(c.cfg.DialTimeout)); deadlineErr != nil {
@@ -224,12 +241,9 @@ func (c *Client) register(conn net.Conn) (time.Duration, yaerrors.Error) {
 		Labels:          c.labelSet.Values(),
 	}
 
-	if err := protocol.WriteFrame(
-		conn,
-		c.nextCorrelation(),
-		registerMsg,
-		c.cfg.Limits,
-	); err != nil {
+	correlationID := c.nextCorrelation()
+
+	if err := protocol.WriteFrame(conn, correlationID, registerMsg, c.cfg.Limits); err != nil {
 		return 0, err.Wrap(logTag + " send register")
 	}
 
@@ -238,9 +252,23 @@ func (c *Client) register(conn net.Conn) (time.Duration, yaerrors.Error) {
 		return 0, err.Wrap(logTag + " read register ack")
 	}
 
+	if challenge, ok := msg.(*protocol.AuthChallenge); ok {
+		if msg, err = c.answerChallenge(conn, correlationID, registerMsg, challenge); err != nil {
+			return 0, err.Wrap(logTag + " register")
+		}
+	}
+
 	switch ack := msg.(type) {
 	case *protocol.RegisterAck:
 		if !ack.Accepted {
+			if ack.Error != nil && ack.Error.Code == protocol.ErrorCodeUnauthenticated {
+				return 0, yaerrors.FromError(
+					http.StatusUnauthorized,
+					ErrUnauthenticated,
+					logTag+" register: "+wireErrorText(ack.Error),
+				)
+			}
+
 			return 0, yaerrors.FromError(
 				http.StatusForbidden,
 				ErrRegistrationRejected,
@@ -258,6 +286,14 @@ func (c *Client) register(conn net.Conn) (time.Duration, yaerrors.Error) {
 
 		return c.resolveHeartbeatInterval(ack.HeartbeatIntervalMillis), nil
 	case *protocol.Fault:
+		if ack.Cause.Code == protocol.ErrorCodeUnauthenticated {
+			return 0, yaerrors.FromError(
+				http.StatusUnauthorized,
+				ErrUnauthenticated,
+				logTag+" register fault: "+ack.Cause.Message,
+			)
+		}
+
 		return 0, yaerrors.FromError(
 			http.StatusBadGateway,
 			ErrRegistrationRejected,
@@ -272,6 +308,39 @@ func (c *Client) register(conn net.Conn) (time.Duration, yaerrors.Error) {
 	}
 }
 
+// answerChallenge signs an authentication challenge to the pending
+// registration with the configured secret and returns the message that
+// answers the signed response, which settles the registration.
+func (c *Client) answerChallenge(
+	conn net.Conn,
+	correlationID protocol.CorrelationID,
+	registerMsg *protocol.Register,
+	challenge *protocol.AuthChallenge,
+) (protocol.Message, yaerrors.Error) {
+	if len(c.cfg.AuthSecret) == 0 {
+		return nil, yaerrors.FromError(
+			http.StatusUnauthorized,
+			ErrAuthSecretMissing,
+			logTag+" auth challenge",
+		)
+	}
+
+	response := &protocol.AuthResponse{
+		Token: protocol.AuthToken(c.cfg.AuthSecret, challenge.Nonce, registerMsg),
+	}
+
+	if err := protocol.WriteFrame(conn, correlationID, response, c.cfg.Limits); err != nil {
+		return nil, err.Wrap(logTag + " send auth response")
+	}
+
+	_, msg, err := protocol.ReadMessage(conn, c.cfg.Limits)
+	if err != nil {
+		return nil, err.Wrap(logTag + " read register ack")
+	}
+
+	return msg, nil
+}
+
 // resolveHeartbeatInterval clamps the cadence the scheduler assigned into
 // the range this client is willing to honour. A zero value falls back to
 // the configured interval; anything shorter than MinHeartbeatInterval or
@@ -525,6 +594,26 @@ func (c *Client) handleMessage(
 	case *protocol.LabelUpdateAck:
 		c.completePending(header.CorrelationID, pendingReply{labelAck: m})
 
+		return nil
+	case *protocol.JobListAck:
+		c.completePending(header.CorrelationID, pendingReply{listAck: m})
+
+		return nil
+	case *protocol.JobGetAck:
+		c.completePending(header.CorrelationID, pendingReply{getAck: m})
+
+		return nil
+	case *protocol.JobControlAck:
+		c.completePending(header.CorrelationID, pendingReply{controlAck: m})
+
+		return nil
+	case *protocol.ExecutionListAck:
+		c.completePending(header.CorrelationID, pendingReply{historyAck: m})
+
+		return nil
+	case *protocol.AttemptListAck:
+		c.completePending(header.CorrelationID, pendingReply{attemptAck: m})
+
 		return nil
 	case *protocol.ResultDelivery:
 		c.handleResultDelivery(m)
@@ -1,9 +1,12 @@
 package yascheduler
 
 import (
+	"errors"
+	"net"
 	"testing"
 	"time"
 
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yascheduler/engine"
 	"github.com/YaCodeDev/GoYaCodeDevUtils/yascheduler/protocol"
 )
 
@@ -115,3 +118,64 @@ func TestResolveHeartbeatIntervalStaysInBounds(t *testing.T) {
 		})
 	}
 }
+
+// TestRegisterAnswersAuthenticationChallenge drives the client side of the
+// registration exchange against engine.Authenticate, so both ends of the
+// challenge are the shipped code and a refusal surfaces as its own error.
+func TestRegisterAnswersAuthenticationChallenge(t *testing.T) {
+	t.Parallel()
+
+	schedulerSecret := []byte("scheduler secret")
+
+	cases := []struct {
+		name    string
+		secret  []byte
+		wantErr error
+	}{
+		{name: "when the secret matches / then the registration is admitted", secret: schedulerSecret},
+		{
+			name:    "when the secret differs / then the registration is unauthenticated",
+			secret:  []byte("another secret"),
+			wantErr: ErrUnauthenticated,
+		},
+		{name: "when no secret is configured / then the challenge is refused locally", wantErr: ErrAuthSecretMissing},
+	}
+
+	for _, testCase := range cases {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := newInternalClient(t, NewRegistry(), configuredInterval)
+			client.cfg.AuthSecret = testCase.secret
+
+			clientConn, schedulerConn := net.Pipe()
+			t.Cleanup(func() {
+				_ = clientConn.Close()
+				_ = schedulerConn.Close()
+			})
+
+			go func() {
+				authConfig := &engine.AuthConfig{Secret: schedulerSecret}
+
+				correlationID, _, err := engine.Authenticate(schedulerConn, authConfig)
+				if err != nil {
+					return
+				}
+
+				_ = protocol.WriteFrame(schedulerConn, correlationID, &protocol.RegisterAck{
+					Accepted:                true,
+					HeartbeatIntervalMillis: assignedMillis,
+				}, protocol.Limits{})
+			}()
+
+			_, err := client.register(clientConn)
+			if testCase.wantErr == nil && err != nil {
+				t.Fatalf("register failed: %v", err)
+			}
+
+			if testCase.wantErr != nil && !errors.Is(err, testCase.wantErr) {
+				t.Fatalf("err = %v, want %v", err, testCase.wantErr)
+			}
+		})
+	}
+}
@@ -882,6 +882,287 @@ func TestClientDeleteJobRefused(t *testing.T) {
 	}
 }
 
+func TestClientListJobsPagesThroughEveryJob(t *testing.T) {
+	t.Parallel()
+
+	fs := startFakeScheduler(t)
+	running := startClient(t, fs, yascheduler.NewRegistry())
+
+	conn, _ := acceptAndRegister(t, fs)
+	defer func() { _ = conn.Close() }()
+
+	awaitCtx, awaitCancel := context.WithTimeout(context.Background(), testReadTimeout)
+	defer awaitCancel()
+
+	if err := running.client.AwaitReady(awaitCtx); err != nil {
+		t.Fatalf("AwaitReady failed: %v", err)
+	}
+
+	type listOutcome struct {
+		jobs []*yascheduler.JobInfo
+		err  error
+	}
+
+	outcome := make(chan listOutcome, 1)
+
+	go func() {
+		listCtx, listCancel := context.WithTimeout(context.Background(), testReadTimeout)
+		defer listCancel()
+
+		jobs, listErr := running.client.ListJobs(listCtx, "")
+
+		outcome <- listOutcome{jobs: jobs, err: listErr}
+	}()
+
+	firstPage := protocol.JobInfo{JobUUID: protocol.JobUUID{1}, JobKey: "job-a", Enabled: true}
+	secondPage := protocol.JobInfo{JobUUID: protocol.JobUUID{2}, JobKey: "job-b"}
+
+	header, list := waitForMessage[*protocol.JobList](t, conn)
+	if list.ExecutorType != "" || !list.After.IsZero() {
+		t.Fatalf("the first page should list every type from the start: %+v", list)
+	}
+
+	writeMessage(t, conn, header.CorrelationID, &protocol.JobListAck{
+		Jobs: []protocol.JobInfo{firstPage},
+		More: true,
+	})
+
+	header, list = waitForMessage[*protocol.JobList](t, conn)
+	if list.After != firstPage.JobUUID {
+		t.Fatalf("the second page should resume after the first: %+v", list)
+	}
+
+	writeMessage(t, conn, header.CorrelationID, &protocol.JobListAck{
+		Jobs: []protocol.JobInfo{secondPage},
+	})
+
+	select {
+	case result := <-outcome:
+		if result.err != nil {
+			t.Fatalf("ListJobs failed: %v", result.err)
+		}
+
+		if len(result.jobs) != 2 ||
+			result.jobs[0].Key != "job-a" || result.jobs[0].Paused ||
+			result.jobs[1].Key != "job-b" || !result.jobs[1].Paused {
+			t.Fatalf("both pages should be listed in order: %+v", result.jobs)
+		}
+	case <-time.After(testReadTimeout):
+		t.Fatal("ListJobs did not finish")
+	}
+}
+
+func TestClientJobControlSurfacesOutcomes(t *testing.T) {
+	t.Parallel()
+
+	fs := startFakeScheduler(t)
+	running := startClient(t, fs, yascheduler.NewRegistry())
+
+	conn, _ := acceptAndRegister(t, fs)
+	defer func() { _ = conn.Close() }()
+
+	awaitCtx, awaitCancel := context.WithTimeout(context.Background(), testReadTimeout)
+	defer awaitCancel()
+
+	if err := running.client.AwaitReady(awaitCtx); err != nil {
+		t.Fatalf("AwaitReady failed: %v", err)
+	}
+
+	cases := []struct {
+		name string
+		ack  protocol.JobControlAck
+		want error
+	}{
+		{
+			name: "when the job is paused / then the trigger answers ErrJobPaused",
+			ack: protocol.JobControlAck{
+				Found: true,
+				Error: &protocol.WireError{Code: protocol.ErrorCodeJobPaused},
+			},
+			want: yascheduler.ErrJobPaused,
+		},
+		{
+			name: "when the job is absent / then the trigger answers ErrJobNotFound",
+			want: yascheduler.ErrJobNotFound,
+		},
+		{
+			name: "when the job is enabled / then the trigger answers its execution",
+			ack:  protocol.JobControlAck{Found: true, ExecutionID: 7},
+		},
+	}
+
+	type triggerOutcome struct {
+		executionID protocol.ExecutionID
+		err         error
+	}
+
+	for _, testCase := range cases {
+		outcome := make(chan triggerOutcome, 1)
+
+		go func() {
+			triggerCtx, triggerCancel := context.WithTimeout(
+				context.Background(),
+				testReadTimeout,
+			)
+			defer triggerCancel()
+
+			executionID, triggerErr := running.client.TriggerNow(triggerCtx, "", "job-a")
+
+			outcome <- triggerOutcome{executionID: executionID, err: triggerErr}
+		}()
+
+		header, control := waitForMessage[*protocol.JobControl](t, conn)
+		if control.Action != protocol.JobActionTrigger || control.ExecutorType != testExecutorType {
+			t.Fatalf("%s: unexpected control %+v", testCase.name, control)
+		}
+
+		ack := testCase.ack
+		ack.JobKey = control.JobKey
+		ack.Action = control.Action
+		writeMessage(t, conn, header.CorrelationID, &ack)
+
+		select {
+		case result := <-outcome:
+			if testCase.want == nil &&
+				(result.err != nil || result.executionID != testCase.ack.ExecutionID) {
+				t.Fatalf("%s: got %d, %v", testCase.name, result.executionID, result.err)
+			}
+
+			if testCase.want != nil && !errors.Is(result.err, testCase.want) {
+				t.Fatalf("%s: err = %v", testCase.name, result.err)
+			}
+		case <-time.After(testReadTimeout):
+			t.Fatalf("%s: TriggerNow did not finish", testCase.name)
+		}
+	}
+}
+
+func TestClientListAttemptsPagesThroughEveryAttempt(t *testing.T) {
+	t.Parallel()
+
+	fs := startFakeScheduler(t)
+	running := startClient(t, fs, yascheduler.NewRegistry())
+
+	conn, _ := acceptAndRegister(t, fs)
+	defer func() { _ = conn.Close() }()
+
+	awaitCtx, awaitCancel := context.WithTimeout(context.Background(), testReadTimeout)
+	defer awaitCancel()
+
+	if err := running.client.AwaitReady(awaitCtx); err != nil {
+		t.Fatalf("AwaitReady failed: %v", err)
+	}
+
+	type attemptsOutcome struct {
+		attempts []*yascheduler.AttemptInfo
+		err      error
+	}
+
+	outcome := make(chan attemptsOutcome, 1)
+
+	go func() {
+		listCtx, listCancel := context.WithTimeout(context.Background(), testReadTimeout)
+		defer listCancel()
+
+		attempts, listErr := running.client.ListAttempts(listCtx, 17)
+
+		outcome <- attemptsOutcome{attempts: attempts, err: listErr}
+	}()
+
+	header, list := waitForMessage[*protocol.AttemptList](t, conn)
+	if list.ExecutionID != 17 || list.After != 0 {
+		t.Fatalf("the first page should start from the first attempt: %+v", list)
+	}
+
+	writeMessage(t, conn, header.CorrelationID, &protocol.AttemptListAck{
+		ExecutionID: 17,
+		Found:       true,
+		Attempts: []protocol.AttemptInfo{{
+			AttemptID: 170,
+			Number:    1,
+			State:     protocol.AttemptStateLost,
+		}},
+		More: true,
+	})
+
+	header, list = waitForMessage[*protocol.AttemptList](t, conn)
+	if list.After != 1 {
+		t.Fatalf("the second page should resume after the first: %+v", list)
+	}
+
+	writeMessage(t, conn, header.CorrelationID, &protocol.AttemptListAck{
+		ExecutionID: 17,
+		Found:       true,
+		Attempts: []protocol.AttemptInfo{{
+			AttemptID: 171,
+			Number:    2,
+			State:     protocol.AttemptStateSucceeded,
+		}},
+	})
+
+	select {
+	case result := <-outcome:
+		if result.err != nil {
+			t.Fatalf("ListAttempts failed: %v", result.err)
+		}
+
+		if len(result.attempts) != 2 ||
+			result.attempts[0].AttemptID != 170 ||
+			result.attempts[1].State != protocol.AttemptStateSucceeded {
+			t.Fatalf("both pages should be listed in order: %+v", result.attempts)
+		}
+	case <-time.After(testReadTimeout):
+		t.Fatal("ListAttempts did not finish")
+	}
+}
+
+func TestClientListExecutionsAbsentJob(t *testing.T) {
+	t.Parallel()
+
+	fs := startFakeScheduler(t)
+	running := startClient(t, fs, yascheduler.NewRegistry())
+
+	conn, _ := acceptAndRegister(t, fs)
+	defer func() { _ = conn.Close() }()
+
+	awaitCtx, awaitCancel := context.WithTimeout(context.Background(), testReadTimeout)
+	defer awaitCancel()
+
+	if err := running.client.AwaitReady(awaitCtx); err != nil {
+		t.Fatalf("AwaitReady failed: %v", err)
+	}
+
+	outcome := make(chan error, 1)
+
+	go func() {
+		listCtx, listCancel := context.WithTimeout(context.Background(), testReadTimeout)
+		defer listCancel()
+
+		_, listErr := running.client.ListExecutions(
+			listCtx,
+			yascheduler.ExecutionQuery{Key: "job-a", Before: 9, Limit: 5},
+		)
+
+		outcome <- listErr
+	}()
+
+	header, list := waitForMessage[*protocol.ExecutionList](t, conn)
+	if list.ExecutorType != testExecutorType || list.Before != 9 || list.Limit != 5 {
+		t.Fatalf("the query should address this client's own type: %+v", list)
+	}
+
+	writeMessage(t, conn, header.CorrelationID, &protocol.ExecutionListAck{JobKey: list.JobKey})
+
+	select {
+	case listErr := <-outcome:
+		if !errors.Is(listErr, yascheduler.ErrJobNotFound) {
+			t.Fatalf("an absent job should answer ErrJobNotFound: %v", listErr)
+		}
+	case <-time.After(testReadTimeout):
+		t.Fatal("ListExecutions did not finish")
+	}
+}
+
 func TestClientCancelsRunningExecution(t *testing.T) {
 	t.Parallel()
 
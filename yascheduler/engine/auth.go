@@ -0,0 +1,215 @@
+package engine
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yaerrors"
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yascheduler/protocol"
+)
+
+// AuthConfig configures how a transport admits executor connections. The
+// zero value admits every connection that opens with a Register, so
+// authentication is opt-in per field.
+type AuthConfig struct {
+	// TLS, when set, serves connections over TLS. A config naming ClientCAs
+	// without a ClientAuth policy requires and verifies a client
+	// certificate (mutual TLS).
+	TLS *tls.Config
+
+	// Secret, when set, answers every Register with an AuthChallenge the
+	// executor must answer with protocol.AuthToken under the same secret.
+	Secret []byte
+
+	// HandshakeTimeout bounds the TLS handshake and the registration
+	// exchange together. Zero falls back to DefaultHandshakeTimeout.
+	HandshakeTimeout time.Duration
+
+	// Limits bounds every frame read during the registration exchange.
+	Limits protocol.Limits
+}
+
+// NewListener wraps inner so every accepted connection speaks TLS when
+// cfg.TLS is set, and returns inner unchanged otherwise. Accepted
+// connections still have to pass Authenticate before they register.
+func NewListener(inner net.Listener, cfg *AuthConfig) net.Listener {
+	if cfg.TLS == nil {
+		return inner
+	}
+
+	return tls.NewListener(inner, serverTLSConfig(cfg.TLS))
+}
+
+// Listen announces on the local network address and wraps the listener
+// with NewListener.
+func Listen(
+	ctx context.Context,
+	network string,
+	address string,
+	cfg *AuthConfig,
+) (net.Listener, yaerrors.Error) {
+	var listenConfig net.ListenConfig
+
+	listener, err := listenConfig.Listen(ctx, network, address)
+	if err != nil {
+		return nil, yaerrors.FromError(
+			http.StatusInternalServerError,
+			err,
+			logTag+" listen",
+		)
+	}
+
+	return NewListener(listener, cfg), nil
+}
+
+// Authenticate runs the registration exchange on a freshly accepted
+// connection, before anything of it reaches the ExecutorRegistry: it
+// completes the TLS handshake when cfg.TLS is set, reads the Register the
+// executor opens with, and, when cfg.Secret is set, challenges it and
+// verifies the token. It returns the correlation ID the RegisterAck must
+// answer and the authenticated registration for the transport to pass to
+// ExecutorRegistry.Register.
+//
+// A refused connection is told why where the protocol allows it: a first
+// frame other than Register draws a Fault and a failed challenge a refused
+// RegisterAck, both carrying protocol.ErrorCodeUnauthenticated. The error
+// returned then wraps ErrUnauthenticated and the transport closes the
+// connection.
+func Authenticate(
+	conn net.Conn,
+	cfg *AuthConfig,
+) (protocol.CorrelationID, *protocol.Register, yaerrors.Error) {
+	timeout := cfg.HandshakeTimeout
+	if timeout <= 0 {
+		timeout = DefaultHandshakeTimeout
+	}
+
+	if err := conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		return 0, nil, yaerrors.FromError(
+			http.StatusBadGateway,
+			err,
+			logTag+" authenticate deadline",
+		)
+	}
+
+	if cfg.TLS != nil {
+		tlsConn, isTLS := conn.(*tls.Conn)
+		if !isTLS {
+			return 0, nil, yaerrors.FromError(
+				http.StatusUnauthorized,
+				ErrTLSRequired,
+				logTag+" authenticate",
+			)
+		}
+
+		if err := tlsConn.Handshake(); err != nil {
+			return 0, nil, yaerrors.FromError(
+				http.StatusUnauthorized,
+				err,
+				logTag+" tls handshake",
+			)
+		}
+	}
+
+	header, msg, err := protocol.ReadMessage(conn, cfg.Limits)
+	if err != nil {
+		return 0, nil, err.Wrap(logTag + " read register")
+	}
+
+	register, isRegister := msg.(*protocol.Register)
+	if !isRegister {
+		return 0, nil, refuse(conn, header.CorrelationID, &protocol.Fault{
+			Cause: protocol.WireError{
+				Code:    protocol.ErrorCodeUnauthenticated,
+				Message: authReasonRegisterFirst,
+			},
+		}, cfg.Limits)
+	}
+
+	if len(cfg.Secret) > 0 {
+		if err = challenge(conn, header.CorrelationID, register, cfg); err != nil {
+			return 0, nil, err.Wrap(logTag + " authenticate")
+		}
+	}
+
+	if deadlineErr := conn.SetDeadline(time.Time{}); deadlineErr != nil {
+		return 0, nil, yaerrors.FromError(
+			http.StatusBadGateway,
+			deadlineErr,
+			logTag+" clear authenticate deadline",
+		)
+	}
+
+	return header.CorrelationID, register, nil
+}
+
+// challenge sends a fresh nonce and verifies the token the executor
+// answers it with, refusing the registration when the answer is anything
+// but the right token.
+func challenge(
+	conn net.Conn,
+	correlationID protocol.CorrelationID,
+	register *protocol.Register,
+	cfg *AuthConfig,
+) yaerrors.Error {
+	nonce := protocol.NewAuthNonce()
+
+	if err := protocol.WriteFrame(
+		conn,
+		correlationID,
+		&protocol.AuthChallenge{Nonce: nonce},
+		cfg.Limits,
+	); err != nil {
+		return err.Wrap(logTag + " send auth challenge")
+	}
+
+	_, msg, err := protocol.ReadMessage(conn, cfg.Limits)
+	if err != nil {
+		return err.Wrap(logTag + " read auth response")
+	}
+
+	response, isResponse := msg.(*protocol.AuthResponse)
+	if isResponse && protocol.VerifyAuthToken(cfg.Secret, nonce, register, response.Token) {
+		return nil
+	}
+
+	return refuse(conn, correlationID, &protocol.RegisterAck{
+		Error: &protocol.WireError{
+			Code:    protocol.ErrorCodeUnauthenticated,
+			Message: authReasonBadToken,
+		},
+	}, cfg.Limits)
+}
+
+// refuse tells the peer why its connection is refused and returns the
+// error the caller reports. The answer is best effort: the connection is
+// closed either way, so a failed write changes nothing.
+func refuse(
+	conn net.Conn,
+	correlationID protocol.CorrelationID,
+	answer protocol.Message,
+	limits protocol.Limits,
+) yaerrors.Error {
+	_ = protocol.WriteFrame(conn, correlationID, answer, limits)
+
+	return yaerrors.FromError(
+		http.StatusUnauthorized,
+		ErrUnauthenticated,
+		logTag+" authenticate",
+	)
+}
+
+// serverTLSConfig returns the TLS configuration a listener serves with:
+// a config naming ClientCAs without a ClientAuth policy requires and
+// verifies a client certificate instead of silently ignoring the pool.
+func serverTLSConfig(base *tls.Config) *tls.Config {
+	config := base.Clone()
+	if config.ClientCAs != nil && config.ClientAuth == tls.NoClientCert {
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return config
+}
@@ -0,0 +1,75 @@
+package engine_test
+
+import (
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yascheduler/engine"
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yascheduler/protocol"
+)
+
+func TestAuthenticateAdmitsOnlyARegistration(t *testing.T) {
+	t.Parallel()
+
+	register := &protocol.Register{
+		ProtocolVersion: protocol.CurrentVersion,
+		ExecutorType:    "billing",
+		InstanceID:      "billing-1",
+		Capacity:        1,
+	}
+
+	cases := []struct {
+		name  string
+		first protocol.Message
+		admit bool
+	}{
+		{name: "when no secret is configured / then a registration is admitted unchallenged", first: register, admit: true},
+		{name: "when the first frame is not a registration / then a fault refuses it", first: &protocol.Heartbeat{}},
+	}
+
+	for _, testCase := range cases {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			executorConn, schedulerConn := net.Pipe()
+			t.Cleanup(func() {
+				_ = executorConn.Close()
+				_ = schedulerConn.Close()
+			})
+
+			const correlationID protocol.CorrelationID = 7
+
+			go func() {
+				_ = protocol.WriteFrame(executorConn, correlationID, testCase.first, protocol.Limits{})
+			}()
+
+			answered := make(chan protocol.Message, 1)
+
+			go func() {
+				_, msg, err := protocol.ReadMessage(executorConn, protocol.Limits{})
+				if err == nil {
+					answered <- msg
+				}
+			}()
+
+			gotID, got, err := engine.Authenticate(schedulerConn, &engine.AuthConfig{})
+			if testCase.admit {
+				if err != nil || gotID != correlationID || got.InstanceID != register.InstanceID {
+					t.Fatalf("got %d %+v (%v), want the registration admitted", gotID, got, err)
+				}
+
+				return
+			}
+
+			if !errors.Is(err, engine.ErrUnauthenticated) {
+				t.Fatalf("err = %v, want ErrUnauthenticated", err)
+			}
+
+			fault, isFault := (<-answered).(*protocol.Fault)
+			if !isFault || fault.Cause.Code != protocol.ErrorCodeUnauthenticated {
+				t.Errorf("answer = %+v, want a Fault with ErrorCodeUnauthenticated", fault)
+			}
+		})
+	}
+}
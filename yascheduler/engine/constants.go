@@ -34,6 +34,10 @@ const (
 	// that states none of its own.
 	DefaultRetryMaxDelay = time.Minute
 
+	// DefaultHandshakeTimeout bounds the TLS handshake and registration
+	// exchange Authenticate runs on a fresh connection.
+	DefaultHandshakeTimeout = 10 * time.Second
+
 	// DefaultBackfillMaxAge caps how old a missed occurrence may be and
 	// still be materialized.
 	DefaultBackfillMaxAge = 24 * time.Hour
@@ -55,6 +59,23 @@ const (
 // dispatches.
 const DefaultDispatchBatch store.BatchLimit = 256
 
+// DefaultJobListLimit is the page size of a job listing that asks for no
+// particular one.
+const DefaultJobListLimit store.BatchLimit = 100
+
+// MaxJobListLimit caps the page size of one job listing, so a single
+// acknowledgement stays well inside the frame size limit.
+const MaxJobListLimit store.BatchLimit = 500
+
+// DefaultExecutionListLimit is the page size of an execution or attempt
+// listing that asks for no particular one.
+const DefaultExecutionListLimit store.BatchLimit = 50
+
+// MaxExecutionListLimit caps the page size of one execution or attempt
+// listing. Every record carries up to three strings of MaxStringLen, so the
+// cap keeps a full page inside the default frame size limit.
+const MaxExecutionListLimit store.BatchLimit = 100
+
 // DefaultBackfillMaxCount caps how many missed occurrences one job
 // materializes.
 const DefaultBackfillMaxCount store.OccurrenceCount = 100
@@ -71,6 +92,32 @@ const DefaultMaxPendingResultsPerInstance store.OccurrenceCount = 256
 // without overflowing a time.Duration.
 const maxIntervalMillis uint64 = math.MaxInt64 / uint64(time.Millisecond)
 
+// maxCachedCronSchedules caps how many parsed cron schedules the engine
+// keeps; expressions past the cap are parsed on every use.
+const maxCachedCronSchedules = 1024
+
+// cronSearchHorizon bounds the search for the next cron occurrence. Eight
+// years cover the longest gap between two February 29ths, so an expression
+// that finds nothing within it never matches.
+const cronSearchHorizon = 8 * 366 * 24 * time.Hour
+
+// Cron expression layout.
+const (
+	cronFieldsWithoutSeconds = 5
+	cronFieldsWithSeconds    = 6
+	cronDayField             = 3
+	cronWeekdayField         = 5
+	cronSundayAlias          = 7
+	cronHoursPerDay          = 24
+)
+
+// Wall-clock units used by cron occurrence counting.
+const (
+	secondsPerMinute = 60
+	secondsPerHour   = 60 * secondsPerMinute
+	secondsPerDay    = 24 * secondsPerHour
+)
+
 // maxInstanceLabels caps how many routing labels one live connection holds
 // at once. It mirrors the wire cap on a single label list, so a connection
 // cannot grow past what one registration is allowed to announce.
@@ -103,6 +150,21 @@ const (
 	upsertReasonZeroInterval = "interval must not be zero"
 	upsertReasonWideInterval = "interval is out of range"
 	upsertReasonUnknownKind  = "unknown schedule kind"
+	upsertReasonInvalidCron  = "invalid cron expression"
+	upsertReasonUnknownZone  = "unknown time zone"
+	upsertReasonNeverMatches = "cron expression never matches"
+)
+
+// Refusal reasons answered to a job control request.
+const (
+	controlReasonUnknownAction = "unknown job action"
+	controlReasonJobPaused     = "job is paused"
+)
+
+// Refusal reasons answered to an unauthenticated connection.
+const (
+	authReasonRegisterFirst = "registration required before any other message"
+	authReasonBadToken      = "authentication failed"
 )
 
 const (
@@ -0,0 +1,579 @@
+package engine
+
+import (
+	"fmt"
+	"math/bits"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yaerrors"
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yascheduler/protocol"
+)
+
+// cronSchedule is a parsed cron expression bound to its time zone. Every
+// set holds one bit per value its field allows.
+//
+// Occurrences are wall-clock matches in the location, resolved to instants
+// around daylight-saving transitions the way classic cron does: a wall
+// time skipped by a forward jump fires once at the jump, and a wall time
+// repeated by a backward jump fires only at its first instant. Expressions
+// that match every hour instead follow real time, so they neither fire at
+// the jump nor skip the repeated hour.
+type cronSchedule struct {
+	seconds  uint64
+	minutes  uint64
+	hours    uint64
+	days     uint64
+	months   uint64
+	weekdays uint64
+
+	// anyDay and anyWeekday record a day field written as "*" or "?".
+	// When both day fields are restricted, a day matches either of them.
+	anyDay     bool
+	anyWeekday bool
+
+	location *time.Location
+}
+
+// cronField describes the values and names one expression field accepts.
+type cronField struct {
+	name     string
+	min      int
+	max      int
+	names    map[string]int
+	question bool
+}
+
+var cronFields = [cronFieldsWithSeconds]cronField{
+	{name: "second", min: 0, max: 59},
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day-of-month", min: 1, max: 31, question: true},
+	{name: "month", min: 1, max: 12, names: map[string]int{
+		"JAN": 1, "FEB": 2, "MAR": 3, "APR": 4, "MAY": 5, "JUN": 6,
+		"JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12,
+	}},
+	{name: "day-of-week", min: 0, max: 7, question: true, names: map[string]int{
+		"SUN": 0, "MON": 1, "TUE": 2, "WED": 3, "THU": 4, "FRI": 5, "SAT": 6,
+	}},
+}
+
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+type cronCacheKey struct {
+	expression string
+	timeZone   string
+}
+
+// cronCache holds parsed schedules, so the hot paths neither re-parse an
+// expression nor reload its zone database entry on every occurrence. It
+// stops growing at maxCachedCronSchedules entries.
+var (
+	cronCache     sync.Map
+	cronCacheSize atomic.Int64
+)
+
+// parseCronSchedule parses a 5-field (minute hour day-of-month month
+// day-of-week) or 6-field (with a leading second) cron expression, or one
+// of the @yearly, @monthly, @weekly, @daily and @hourly macros, bound to
+// an IANA time zone. An empty time zone means UTC.
+func parseCronSchedule(expression, timeZone string) (*cronSchedule, yaerrors.Error) {
+	location, err := loadCronLocation(timeZone)
+	if err != nil {
+		return nil, err
+	}
+
+	if macro, ok := cronMacros[strings.ToLower(strings.TrimSpace(expression))]; ok {
+		expression = macro
+	}
+
+	fields := strings.Fields(expression)
+
+	switch len(fields) {
+	case cronFieldsWithSeconds:
+	case cronFieldsWithoutSeconds:
+		fields = append([]string{"0"}, fields...)
+	default:
+		return nil, yaerrors.FromError(
+			http.StatusBadRequest,
+			ErrInvalidCronExpression,
+			fmt.Sprintf(logTag+" cron expression %q: want 5 or 6 fields", expression),
+		)
+	}
+
+	schedule := &cronSchedule{
+		location:   location,
+		anyDay:     isCronWildcard(fields[cronDayField]),
+		anyWeekday: isCronWildcard(fields[cronWeekdayField]),
+	}
+
+	sets := [cronFieldsWithSeconds]*uint64{
+		&schedule.seconds,
+		&schedule.minutes,
+		&schedule.hours,
+		&schedule.days,
+		&schedule.months,
+		&schedule.weekdays,
+	}
+
+	for index, field := range cronFields {
+		set, fieldErr := field.parse(fields[index])
+		if fieldErr != nil {
+			return nil, fieldErr.Wrap(fmt.Sprintf(logTag+" cron expression %q", expression))
+		}
+
+		*sets[index] = set
+	}
+
+	if schedule.weekdays&(1<<cronSundayAlias) != 0 {
+		schedule.weekdays = schedule.weekdays&^(1<<cronSundayAlias) | 1
+	}
+
+	return schedule, nil
+}
+
+// cachedCronSchedule returns the parsed schedule of a cron spec, or false
+// when the spec does not parse.
+func cachedCronSchedule(spec protocol.ScheduleSpec) (schedule *cronSchedule, ok bool) {
+	key := cronCacheKey{expression: spec.CronExpression, timeZone: spec.TimeZone}
+
+	if cached, found := cronCache.Load(key); found {
+		schedule, ok = cached.(*cronSchedule)
+
+		return schedule, ok
+	}
+
+	schedule, err := parseCronSchedule(spec.CronExpression, spec.TimeZone)
+	if err != nil {
+		return nil, false
+	}
+
+	if cronCacheSize.Add(1) <= maxCachedCronSchedules {
+		cronCache.Store(key, schedule)
+	} else {
+		cronCacheSize.Add(-1)
+	}
+
+	return schedule, true
+}
+
+func loadCronLocation(timeZone string) (location *time.Location, err yaerrors.Error) {
+	if timeZone == "" {
+		return time.UTC, nil
+	}
+
+	// "Local" names whatever zone the scheduler host runs in, which two
+	// scheduler instances need not agree on.
+	if timeZone == time.Local.String() {
+		return nil, yaerrors.FromError(
+			http.StatusBadRequest,
+			ErrUnknownTimeZone,
+			fmt.Sprintf(logTag+" time zone %q", timeZone),
+		)
+	}
+
+	location, loadErr := time.LoadLocation(timeZone)
+	if loadErr != nil {
+		return nil, yaerrors.FromError(
+			http.StatusBadRequest,
+			ErrUnknownTimeZone,
+			fmt.Sprintf(logTag+" time zone %q (%v)", timeZone, loadErr),
+		)
+	}
+
+	return location, nil
+}
+
+func isCronWildcard(field string) bool {
+	return field == "*" || field == "?"
+}
+
+// parse turns one comma-separated field into its value set.
+func (f cronField) parse(text string) (set uint64, err yaerrors.Error) {
+	for item := range strings.SplitSeq(text, ",") {
+		bitsOfItem, itemErr := f.parseItem(item)
+		if itemErr != nil {
+			return 0, itemErr
+		}
+
+		set |= bitsOfItem
+	}
+
+	return set, nil
+}
+
+// parseItem turns one "*", "?", "value", "low-high" or "low/step" item,
+// optionally stepped, into its value set.
+func (f cronField) parseItem(item string) (set uint64, err yaerrors.Error) {
+	rangeText, stepText, stepped := strings.Cut(item, "/")
+
+	low, high, step := f.min, f.max, 1
+
+	if rangeText != "*" && (rangeText != "?" || !f.question) {
+		lowText, highText, ranged := strings.Cut(rangeText, "-")
+
+		if low, err = f.value(lowText); err != nil {
+			return 0, err
+		}
+
+		switch {
+		case ranged:
+			if high, err = f.value(highText); err != nil {
+				return 0, err
+			}
+		case !stepped:
+			high = low
+		}
+	}
+
+	if stepped {
+		parsed, convErr := strconv.Atoi(stepText)
+		if convErr != nil || parsed < 1 {
+			return 0, f.invalid(item)
+		}
+
+		step = parsed
+	}
+
+	if low > high {
+		return 0, f.invalid(item)
+	}
+
+	for value := low; value <= high; value += step {
+		set |= 1 << value
+	}
+
+	return set, nil
+}
+
+func (f cronField) value(text string) (value int, err yaerrors.Error) {
+	if named, ok := f.names[strings.ToUpper(text)]; ok {
+		return named, nil
+	}
+
+	value, convErr := strconv.Atoi(text)
+	if convErr != nil || value < f.min || value > f.max {
+		return 0, f.invalid(text)
+	}
+
+	return value, nil
+}
+
+func (f cronField) invalid(text string) yaerrors.Error {
+	return yaerrors.FromError(
+		http.StatusBadRequest,
+		ErrInvalidCronExpression,
+		fmt.Sprintf(logTag+" %s field %q", f.name, text),
+	)
+}
+
+// zoneSegment is a span of instants over which a location keeps one UTC
+// offset, so its wall clock advances together with them. Instants and
+// offsets are in seconds; the end is exclusive.
+type zoneSegment struct {
+	start      int64
+	end        int64
+	hasStart   bool
+	hasEnd     bool
+	offset     int64
+	prevOffset int64
+}
+
+func (c *cronSchedule) segmentAt(instant int64) (segment zoneSegment) {
+	at := time.Unix(instant, 0).In(c.location)
+	_, offset := at.Zone()
+	start, end := at.ZoneBounds()
+
+	segment.offset = int64(offset)
+
+	if !start.IsZero() {
+		_, prevOffset := start.Add(-time.Second).Zone()
+
+		segment.start, segment.hasStart = start.Unix(), true
+		segment.prevOffset = int64(prevOffset)
+	}
+
+	if !end.IsZero() {
+		segment.end, segment.hasEnd = end.Unix(), true
+	}
+
+	return segment
+}
+
+// everyHour reports whether the expression matches every hour, which makes
+// it follow real time across daylight-saving transitions.
+func (c *cronSchedule) everyHour() bool {
+	return c.hours == 1<<cronHoursPerDay-1
+}
+
+// repeatedUntil returns the first instant of segment whose wall time was
+// not already shown by the previous segment. Fixed-time expressions skip
+// the repeated wall times of a backward jump.
+func (c *cronSchedule) repeatedUntil(segment zoneSegment) (first int64, repeats bool) {
+	if c.everyHour() || !segment.hasStart || segment.prevOffset <= segment.offset {
+		return 0, false
+	}
+
+	return segment.start + segment.prevOffset - segment.offset, true
+}
+
+// gapMatches reports whether a fixed-time expression matches a wall time
+// skipped by the forward jump that starts segment; such an occurrence
+// fires at the jump.
+func (c *cronSchedule) gapMatches(segment zoneSegment) bool {
+	if c.everyHour() || !segment.hasStart || segment.prevOffset >= segment.offset {
+		return false
+	}
+
+	_, matches := c.nextWall(
+		segment.start+segment.prevOffset,
+		segment.start+segment.offset,
+	)
+
+	return matches
+}
+
+// firstFrom returns the first occurrence at or after lo and before limit,
+// in unix seconds.
+func (c *cronSchedule) firstFrom(lo, limit int64) (occurrence int64, exists bool) {
+	for instant := lo; instant < limit; {
+		segment := c.segmentAt(instant)
+
+		if segment.hasStart && instant == segment.start && c.gapMatches(segment) {
+			return instant, true
+		}
+
+		from := instant
+		if first, repeats := c.repeatedUntil(segment); repeats {
+			from = max(from, first)
+		}
+
+		end := limit
+		if segment.hasEnd {
+			end = min(end, segment.end)
+		}
+
+		if from < end {
+			if wall, found := c.nextWall(from+segment.offset, end+segment.offset); found {
+				return wall - segment.offset, true
+			}
+		}
+
+		if !segment.hasEnd {
+			return 0, false
+		}
+
+		instant = segment.end
+	}
+
+	return 0, false
+}
+
+// count returns how many occurrences fall in [lo, hi], in unix seconds.
+func (c *cronSchedule) count(lo, hi int64) (total uint64) {
+	for instant := lo; instant <= hi; {
+		segment := c.segmentAt(instant)
+
+		if segment.hasStart && instant == segment.start && c.gapMatches(segment) {
+			if _, normal := c.nextWall(instant+segment.offset, instant+segment.offset+1); !normal {
+				total++
+			}
+		}
+
+		from := instant
+		if first, repeats := c.repeatedUntil(segment); repeats {
+			from = max(from, first)
+		}
+
+		end := hi + 1
+		if segment.hasEnd {
+			end = min(end, segment.end)
+		}
+
+		if from < end {
+			total += c.countWall(from+segment.offset, end+segment.offset)
+		}
+
+		if !segment.hasEnd || segment.end > hi {
+			return total
+		}
+
+		instant = segment.end
+	}
+
+	return total
+}
+
+// newestFrom returns the latest instant in [lo, hi] from which at least
+// keep occurrences remain up to hi, or lo when fewer than keep do.
+func (c *cronSchedule) newestFrom(lo, hi int64, keep uint64) (from int64) {
+	if c.count(lo, hi) <= keep {
+		return lo
+	}
+
+	low, high := lo, hi
+	for low < high {
+		middle := low + (high-low+1)/2
+
+		if c.count(middle, hi) >= keep {
+			low = middle
+		} else {
+			high = middle - 1
+		}
+	}
+
+	return low
+}
+
+// nextWall returns the first matching wall time in [wall, limit). Wall
+// times are seconds on a calendar without offsets, read through UTC.
+func (c *cronSchedule) nextWall(wall, limit int64) (match int64, found bool) {
+	for wall < limit {
+		at := time.Unix(wall, 0).UTC()
+		year, month, day := at.Date()
+		hour, minute, second := at.Clock()
+
+		if !hasCronBit(c.months, int(month)) {
+			wall = time.Date(year, month+1, 1, 0, 0, 0, 0, time.UTC).Unix()
+
+			continue
+		}
+
+		if !c.dayMatches(at) {
+			wall = time.Date(year, month, day+1, 0, 0, 0, 0, time.UTC).Unix()
+
+			continue
+		}
+
+		if next := nextCronBit(c.hours, hour); next != hour {
+			if next < 0 {
+				wall = time.Date(year, month, day+1, 0, 0, 0, 0, time.UTC).Unix()
+			} else {
+				wall = time.Date(year, month, day, next, 0, 0, 0, time.UTC).Unix()
+			}
+
+			continue
+		}
+
+		if next := nextCronBit(c.minutes, minute); next != minute {
+			if next < 0 {
+				wall = time.Date(year, month, day, hour+1, 0, 0, 0, time.UTC).Unix()
+			} else {
+				wall = time.Date(year, month, day, hour, next, 0, 0, time.UTC).Unix()
+			}
+
+			continue
+		}
+
+		if next := nextCronBit(c.seconds, second); next != second {
+			if next < 0 {
+				wall = time.Date(year, month, day, hour, minute+1, 0, 0, time.UTC).Unix()
+			} else {
+				wall += int64(next - second)
+			}
+
+			continue
+		}
+
+		return wall, true
+	}
+
+	return 0, false
+}
+
+// countWall returns how many wall times in [from, to) match.
+func (c *cronSchedule) countWall(from, to int64) (total uint64) {
+	perDay := uint64(bits.OnesCount64(c.hours)) *
+		uint64(bits.OnesCount64(c.minutes)) *
+		uint64(bits.OnesCount64(c.seconds))
+
+	firstDay := from - ((from%secondsPerDay)+secondsPerDay)%secondsPerDay
+
+	for day := firstDay; day < to; day += secondsPerDay {
+		at := time.Unix(day, 0).UTC()
+		if !hasCronBit(c.months, int(at.Month())) || !c.dayMatches(at) {
+			continue
+		}
+
+		lo, hi := max(from, day)-day, min(to, day+secondsPerDay)-day
+		if lo == 0 && hi == secondsPerDay {
+			total += perDay
+
+			continue
+		}
+
+		total += c.countDay(int(lo), int(hi))
+	}
+
+	return total
+}
+
+// countDay returns how many times of day in [from, to) seconds match.
+func (c *cronSchedule) countDay(from, to int) (total uint64) {
+	perHour := uint64(bits.OnesCount64(c.minutes)) * uint64(bits.OnesCount64(c.seconds))
+
+	for hour := nextCronBit(c.hours, from/secondsPerHour); hour >= 0 &&
+		hour*secondsPerHour < to; hour = nextCronBit(c.hours, hour+1) {
+		hourStart := hour * secondsPerHour
+		if hourStart >= from && hourStart+secondsPerHour <= to {
+			total += perHour
+
+			continue
+		}
+
+		for minute := nextCronBit(c.minutes, 0); minute >= 0; minute = nextCronBit(c.minutes, minute+1) {
+			minuteStart := hourStart + minute*secondsPerMinute
+
+			lo := max(from, minuteStart) - minuteStart
+			hi := min(to, minuteStart+secondsPerMinute) - minuteStart
+
+			if lo < hi {
+				window := uint64(1)<<hi - uint64(1)<<lo
+				total += uint64(bits.OnesCount64(c.seconds & window))
+			}
+		}
+	}
+
+	return total
+}
+
+func (c *cronSchedule) dayMatches(at time.Time) bool {
+	day := hasCronBit(c.days, at.Day())
+	weekday := hasCronBit(c.weekdays, int(at.Weekday()))
+
+	if c.anyDay || c.anyWeekday {
+		return day && weekday
+	}
+
+	return day || weekday
+}
+
+func hasCronBit(set uint64, value int) bool {
+	return set&(1<<value) != 0
+}
+
+// nextCronBit returns the smallest value in set at or above from, or -1.
+func nextCronBit(set uint64, from int) (value int) {
+	if from >= bits.UintSize {
+		return -1
+	}
+
+	rest := set >> from << from
+	if rest == 0 {
+		return -1
+	}
+
+	return bits.TrailingZeros64(rest)
+}
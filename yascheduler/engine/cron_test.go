@@ -0,0 +1,249 @@
+package engine
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata" // the DST cases must not depend on the host zone database
+
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yascheduler/protocol"
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yascheduler/store"
+)
+
+const newYork = "America/New_York"
+
+func cronSpec(expression, timeZone string) protocol.ScheduleSpec {
+	return protocol.ScheduleSpec{
+		Kind:           protocol.ScheduleKindCron,
+		CronExpression: expression,
+		TimeZone:       timeZone,
+	}
+}
+
+func utcTime(layout string) time.Time {
+	parsed, err := time.Parse(time.RFC3339, layout)
+	if err != nil {
+		panic(err)
+	}
+
+	return parsed.UTC()
+}
+
+func TestCronNextOccurrence(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name       string
+		spec       protocol.ScheduleSpec
+		after      string
+		occurrence string
+	}{
+		{
+			name:       "when it is Friday after 09:00 / then every weekday at 09:00 Moscow is Monday",
+			spec:       cronSpec("0 9 * * MON-FRI", "Europe/Moscow"),
+			after:      "2024-07-19T07:00:00Z",
+			occurrence: "2024-07-22T06:00:00Z",
+		},
+		{
+			name:       "when a month has started / then the first of the month is next month",
+			spec:       cronSpec("@monthly", ""),
+			after:      "2024-01-31T23:59:59Z",
+			occurrence: "2024-02-01T00:00:00Z",
+		},
+		{
+			name:       "when the expression has seconds / then the second field applies",
+			spec:       cronSpec("*/15 * * * * *", ""),
+			after:      "2024-01-01T00:00:15Z",
+			occurrence: "2024-01-01T00:00:30Z",
+		},
+		{
+			name:       "when both day fields are restricted / then either of them matches",
+			spec:       cronSpec("0 0 13 * FRI", ""),
+			after:      "2024-09-01T00:00:00Z",
+			occurrence: "2024-09-06T00:00:00Z",
+		},
+		{
+			name:       "when the wall time is skipped by a forward jump / then it fires at the jump",
+			spec:       cronSpec("30 2 * * *", newYork),
+			after:      "2024-03-10T05:00:00Z",
+			occurrence: "2024-03-10T07:00:00Z",
+		},
+		{
+			name:       "when the skipped day has passed / then the wall time fires normally again",
+			spec:       cronSpec("30 2 * * *", newYork),
+			after:      "2024-03-10T07:00:00Z",
+			occurrence: "2024-03-11T06:30:00Z",
+		},
+		{
+			name:       "when a fixed time is repeated by a backward jump / then only the first instant fires",
+			spec:       cronSpec("30 1 * * *", newYork),
+			after:      "2024-11-03T05:30:00Z",
+			occurrence: "2024-11-04T06:30:00Z",
+		},
+		{
+			name:       "when an hourly expression meets a backward jump / then both instants fire",
+			spec:       cronSpec("30 * * * *", newYork),
+			after:      "2024-11-03T05:30:00Z",
+			occurrence: "2024-11-03T06:30:00Z",
+		},
+		{
+			name:       "when an hourly expression meets a forward jump / then the skipped hour does not fire",
+			spec:       cronSpec("30 * * * *", newYork),
+			after:      "2024-03-10T06:30:00Z",
+			occurrence: "2024-03-10T07:30:00Z",
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			occurrence, exists := nextOccurrence(testCase.spec, utcTime(testCase.after))
+			if !exists || !occurrence.Equal(utcTime(testCase.occurrence)) {
+				t.Fatalf("got %v (%v), want %s", occurrence, exists, testCase.occurrence)
+			}
+
+			if !scheduleContains(testCase.spec, occurrence) {
+				t.Errorf("the next occurrence %v should belong to the schedule", occurrence)
+			}
+		})
+	}
+}
+
+func TestCronScheduleContains(t *testing.T) {
+	t.Parallel()
+
+	fixed := cronSpec("30 1 * * *", newYork)
+	if scheduleContains(fixed, utcTime("2024-11-03T06:30:00Z")) {
+		t.Error("the repeated instant of a fixed time should not be an occurrence")
+	}
+
+	anchored := fixed
+	anchored.StartUnixNano = utcTime("2024-11-05T00:00:00Z").UnixNano()
+
+	if scheduleContains(anchored, utcTime("2024-11-04T06:30:00Z")) {
+		t.Error("an occurrence before the anchor should not belong to the schedule")
+	}
+
+	if scheduleContains(fixed, utcTime("2024-11-04T06:30:00Z").Add(time.Millisecond)) {
+		t.Error("an instant between whole seconds should not be an occurrence")
+	}
+}
+
+func TestCronCountMatchesIteration(t *testing.T) {
+	t.Parallel()
+
+	expressions := []string{"30 1 * * *", "30 2 * * *", "*/20 * * * *", "0 0,30 1-3 * * *"}
+	windows := [][2]string{
+		{"2024-03-09T00:00:00Z", "2024-03-12T00:00:00Z"},
+		{"2024-11-02T00:00:00Z", "2024-11-05T00:00:00Z"},
+	}
+
+	for _, expression := range expressions {
+		cron, err := parseCronSchedule(expression, newYork)
+		if err != nil {
+			t.Fatalf("%q: %v", expression, err)
+		}
+
+		for _, window := range windows {
+			lo, hi := utcTime(window[0]).Unix(), utcTime(window[1]).Unix()
+
+			var iterated uint64
+
+			for from := lo; ; iterated++ {
+				occurrence, found := cron.firstFrom(from, hi+1)
+				if !found {
+					break
+				}
+
+				from = occurrence + 1
+			}
+
+			if counted := cron.count(lo, hi); counted != iterated {
+				t.Errorf("%q over %v: counted %d, iterated %d", expression, window, counted, iterated)
+			}
+		}
+	}
+}
+
+func TestCronMissedOccurrences(t *testing.T) {
+	t.Parallel()
+
+	const (
+		countCap        = store.OccurrenceCount(3)
+		expectedSkipped = store.OccurrenceCount(7)
+	)
+
+	spec := cronSpec("0 * * * *", newYork)
+	spec.StartUnixNano = utcTime("2024-11-03T00:00:00Z").UnixNano()
+
+	missed, skipped := missedOccurrences(
+		spec,
+		utcTime("2024-11-03T09:30:00Z"),
+		countCap,
+		noAgeCap,
+	)
+
+	want := []time.Time{
+		utcTime("2024-11-03T07:00:00Z"),
+		utcTime("2024-11-03T08:00:00Z"),
+		utcTime("2024-11-03T09:00:00Z"),
+	}
+
+	if len(missed) != len(want) {
+		t.Fatalf("want the newest %d occurrences oldest first, got %v", len(want), missed)
+	}
+
+	for index := range want {
+		if !missed[index].Equal(want[index]) {
+			t.Fatalf("want %v, got %v", want, missed)
+		}
+	}
+
+	if skipped != expectedSkipped {
+		t.Errorf("want %d skipped, got %d", expectedSkipped, skipped)
+	}
+
+	missed, skipped = missedOccurrences(
+		spec,
+		utcTime("2024-11-03T09:30:00Z"),
+		noOccurrenceCap,
+		time.Hour,
+	)
+
+	if len(missed) != 1 || !missed[0].Equal(want[2]) || skipped != expectedSkipped+2 {
+		t.Errorf("want only the occurrence within the age cap, got %v skipped %d", missed, skipped)
+	}
+}
+
+func TestValidateCronUpsert(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		expression string
+		timeZone   string
+		reason     string
+	}{
+		{expression: "0 9 * * 1-5", timeZone: "Europe/Moscow"},
+		{expression: "0 9 * *", reason: upsertReasonInvalidCron},
+		{expression: "61 * * * *", reason: upsertReasonInvalidCron},
+		{expression: "*/0 * * * *", reason: upsertReasonInvalidCron},
+		{expression: "0 9 * * FRI-MON", reason: upsertReasonInvalidCron},
+		{expression: "0 9 * * *", timeZone: "Mars/Olympus", reason: upsertReasonUnknownZone},
+		{expression: "0 9 * * *", timeZone: "Local", reason: upsertReasonUnknownZone},
+		{expression: "0 0 30 2 *", reason: upsertReasonNeverMatches},
+	}
+
+	for _, testCase := range cases {
+		reason, valid := validateCron(cronSpec(testCase.expression, testCase.timeZone))
+		if reason != testCase.reason || valid != (testCase.reason == "") {
+			t.Errorf(
+				"%q in %q: got %q (%v), want %q",
+				testCase.expression,
+				testCase.timeZone,
+				reason,
+				valid,
+				testCase.reason,
+			)
+		}
+	}
+}
@@ -145,6 +145,7 @@ func (e *engine) dispatchExecution(
 		ScheduledUnixNano: execution.ScheduledAt.UnixNano(),
 		TimeoutMillis:     executionTimeoutMillisNone,
 		DeliverResult:     job.ResultMode == protocol.ResultModeDeliver,
+		TraceParent:       job.TraceParent,
 	}
 
 	entry.AddInFlight(attempt.ID)
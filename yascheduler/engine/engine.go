@@ -1,7 +1,11 @@
 // Package engine implements the scheduling core of yascheduler: it holds
 // the connected executors, decides which of them runs a due occurrence, and
 // settles the outcome. A transport owns connections and message framing and
-// calls the Handle methods here; the engine itself speaks no network.
+// calls the Handle methods here; the engine itself speaks no network. The
+// one connection-level building block it ships is admission: Listen and
+// Authenticate let a transport serve TLS and run the registration
+// challenge, so an unauthenticated connection is refused before its
+// registration reaches the ExecutorRegistry.
 //
 // # Label-pinned routing
 //
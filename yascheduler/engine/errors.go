@@ -18,3 +18,26 @@ var (
 	// connection past the label cap.
 	ErrLabelLimitExceeded = errors.New("routing label limit exceeded")
 )
+
+// Cron schedule failures. A job upsert carrying either one is refused.
+var (
+	// ErrInvalidCronExpression reports a cron expression that does not
+	// parse.
+	ErrInvalidCronExpression = errors.New("invalid cron expression")
+
+	// ErrUnknownTimeZone reports a cron time zone that is not an IANA zone
+	// name the scheduler host knows.
+	ErrUnknownTimeZone = errors.New("unknown time zone")
+)
+
+// Connection admission failures. A transport closes a connection refused
+// with either one before its registration reaches the ExecutorRegistry.
+var (
+	// ErrUnauthenticated reports a connection that did not open with a
+	// registration or failed the authentication challenge.
+	ErrUnauthenticated = errors.New("executor connection is not authenticated")
+
+	// ErrTLSRequired reports a plain connection handed to Authenticate
+	// while TLS is configured.
+	ErrTLSRequired = errors.New("connection is not TLS but TLS is required")
+)
@@ -40,6 +40,7 @@ func (e *engine) HandleJobUpsert(
 		Overlap:             upsert.Overlap,
 		Pin:                 upsert.Pin,
 		ResultMode:          upsert.ResultMode,
+		TraceParent:         upsert.TraceParent,
 		SubmitterInstanceID: instanceID,
 	}
 
@@ -791,17 +792,44 @@ func validateUpsert(upsert *protocol.JobUpsert) (reason string, valid bool) {
 		}
 
 		return "", true
+	case protocol.ScheduleKindCron:
+		return validateCron(upsert.Schedule)
 	default:
 		return upsertReasonUnknownKind, false
 	}
 }
 
+func validateCron(spec protocol.ScheduleSpec) (reason string, valid bool) {
+	cron, err := parseCronSchedule(spec.CronExpression, spec.TimeZone)
+	if err != nil {
+		if err.IsError(ErrUnknownTimeZone) {
+			return upsertReasonUnknownZone, false
+		}
+
+		return upsertReasonInvalidCron, false
+	}
+
+	first := unixSecondsFrom(scheduleStart(spec))
+	if _, exists := cron.firstFrom(first, first+int64(cronSearchHorizon/time.Second)); !exists {
+		return upsertReasonNeverMatches, false
+	}
+
+	return "", true
+}
+
 func validateDelete(del *protocol.JobDelete) (reason string, valid bool) {
-	if del.JobKey == "" {
+	return validateJobAddress(del.JobKey, del.ExecutorType)
+}
+
+func validateJobAddress(
+	jobKey string,
+	executorType protocol.ExecutorType,
+) (reason string, valid bool) {
+	if jobKey == "" {
 		return upsertReasonEmptyKey, false
 	}
 
-	if del.ExecutorType == "" {
+	if executorType == "" {
 		return upsertReasonEmptyType, false
 	}
 
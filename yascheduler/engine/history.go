@@ -0,0 +1,240 @@
+package engine
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yaerrors"
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yascheduler/protocol"
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yascheduler/store"
+)
+
+func (e *engine) HandleExecutionList(
+	ctx context.Context,
+	_ protocol.InstanceID,
+	list *protocol.ExecutionList,
+) *protocol.ExecutionListAck {
+	ack := &protocol.ExecutionListAck{JobKey: list.JobKey}
+
+	if reason, valid := validateJobAddress(list.JobKey, list.ExecutorType); !valid {
+		ack.Error = &protocol.WireError{
+			Code:    protocol.ErrorCodeMalformedFrame,
+			Message: reason,
+		}
+
+		return ack
+	}
+
+	job, err := e.jobs.GetJobByKey(ctx, list.ExecutorType, store.JobKey(list.JobKey))
+	if err != nil {
+		if errors.Is(err, store.ErrJobNotFound) {
+			return ack
+		}
+
+		e.log.Errorf(logTag+" execution history lookup failed: %v", err)
+		ack.Error = internalWireError(err)
+
+		return ack
+	}
+
+	ack.Found = true
+
+	executions, err := e.executions.ExecutionsForJob(ctx, job.ID)
+	if err != nil {
+		e.log.Errorf(logTag+" execution history lookup failed: %v", err)
+		ack.Error = internalWireError(err)
+
+		return ack
+	}
+
+	executions = slices.DeleteFunc(executions, func(execution *store.Execution) bool {
+		return list.Before != 0 && execution.ID >= list.Before
+	})
+	slices.SortFunc(executions, func(a, b *store.Execution) int {
+		return cmp.Compare(b.ID, a.ID)
+	})
+
+	executions, ack.More = historyPage(executions, list.Limit)
+	ack.Executions = make([]protocol.ExecutionInfo, 0, len(executions))
+
+	for _, execution := range executions {
+		info, infoErr := e.executionInfo(ctx, execution)
+		if infoErr != nil {
+			e.log.Errorf(logTag+" execution history lookup failed: %v", infoErr)
+
+			return &protocol.ExecutionListAck{
+				JobKey: list.JobKey,
+				Found:  true,
+				Error:  internalWireError(infoErr),
+			}
+		}
+
+		ack.Executions = append(ack.Executions, info)
+	}
+
+	return ack
+}
+
+func (e *engine) HandleAttemptList(
+	ctx context.Context,
+	_ protocol.InstanceID,
+	list *protocol.AttemptList,
+) *protocol.AttemptListAck {
+	ack := &protocol.AttemptListAck{ExecutionID: list.ExecutionID}
+
+	if _, err := e.executions.GetExecution(ctx, list.ExecutionID); err != nil {
+		if errors.Is(err, store.ErrExecutionNotFound) {
+			return ack
+		}
+
+		e.log.Errorf(logTag+" attempt history lookup failed: %v", err)
+		ack.Error = internalWireError(err)
+
+		return ack
+	}
+
+	ack.Found = true
+
+	attempts, err := e.attempts.AttemptsForExecution(ctx, list.ExecutionID)
+	if err != nil {
+		e.log.Errorf(logTag+" attempt history lookup failed: %v", err)
+		ack.Error = internalWireError(err)
+
+		return ack
+	}
+
+	attempts = slices.DeleteFunc(attempts, func(attempt *store.Attempt) bool {
+		return uint32(attempt.Number) <= list.After
+	})
+	slices.SortFunc(attempts, compareAttempts)
+
+	attempts, ack.More = historyPage(attempts, list.Limit)
+	ack.Attempts = make([]protocol.AttemptInfo, 0, len(attempts))
+
+	for _, attempt := range attempts {
+		ack.Attempts = append(ack.Attempts, protocol.AttemptInfo{
+			AttemptID:          attempt.ID,
+			Number:             uint32(attempt.Number),
+			InstanceID:         attempt.InstanceID,
+			State:              wireAttemptState(attempt.State),
+			Error:              string(attempt.Error),
+			DispatchedUnixNano: attempt.CreatedAt.UnixNano(),
+			UpdatedUnixNano:    attempt.UpdatedAt.UnixNano(),
+		})
+	}
+
+	return ack
+}
+
+// executionInfo renders one execution for the wire, summarizing its
+// attempts: how many there were, where the latest ran, and when the first
+// was dispatched.
+func (e *engine) executionInfo(
+	ctx context.Context,
+	execution *store.Execution,
+) (protocol.ExecutionInfo, yaerrors.Error) {
+	attempts, err := e.attempts.AttemptsForExecution(ctx, execution.ID)
+	if err != nil {
+		return protocol.ExecutionInfo{}, err
+	}
+
+	info := protocol.ExecutionInfo{
+		ExecutionID:       execution.ID,
+		ScheduledUnixNano: execution.ScheduledAt.UnixNano(),
+		State:             wireExecutionState(execution.State),
+		Backfilled:        bool(execution.Backfilled),
+		AttemptCount:      uint32(len(attempts)), //nolint:gosec // bounded by the attempt numbering
+		LastError:         string(execution.LastError),
+		WaitReason:        string(execution.WaitReason),
+	}
+
+	if len(attempts) > 0 {
+		first := slices.MinFunc(attempts, compareAttempts)
+		latest := slices.MaxFunc(attempts, compareAttempts)
+
+		info.StartedUnixNano = first.CreatedAt.UnixNano()
+		info.InstanceID = latest.InstanceID
+	}
+
+	if execution.State.Terminal() {
+		info.FinishedUnixNano = execution.UpdatedAt.UnixNano()
+	}
+
+	return info, nil
+}
+
+// historyPage cuts records to one page of the requested size, falling back
+// to DefaultExecutionListLimit and capped at MaxExecutionListLimit, and
+// reports whether records were left over.
+func historyPage[T any](records []T, requested uint32) (page []T, more bool) {
+	limit := store.BatchLimit(requested)
+	if limit == 0 {
+		limit = DefaultExecutionListLimit
+	}
+
+	limit = min(limit, MaxExecutionListLimit)
+
+	if store.BatchLimit(len(records)) <= limit {
+		return records, false
+	}
+
+	return records[:limit], true
+}
+
+func compareAttempts(a, b *store.Attempt) int {
+	return cmp.Compare(a.Number, b.Number)
+}
+
+func wireExecutionState(state store.ExecutionState) protocol.ExecutionState {
+	switch state {
+	case store.StateScheduled:
+		return protocol.ExecutionStateScheduled
+	case store.StateReady:
+		return protocol.ExecutionStateReady
+	case store.StateWaitingExecutor:
+		return protocol.ExecutionStateWaitingExecutor
+	case store.StateWaitingCompatible:
+		return protocol.ExecutionStateWaitingCompatible
+	case store.StateWaitingLabel:
+		return protocol.ExecutionStateWaitingLabel
+	case store.StateDispatching:
+		return protocol.ExecutionStateDispatching
+	case store.StateRunning:
+		return protocol.ExecutionStateRunning
+	case store.StateRetryWait:
+		return protocol.ExecutionStateRetryWait
+	case store.StateSucceeded:
+		return protocol.ExecutionStateSucceeded
+	case store.StateFailed:
+		return protocol.ExecutionStateFailed
+	case store.StateCancelled:
+		return protocol.ExecutionStateCancelled
+	case store.StateSkipped:
+		return protocol.ExecutionStateSkipped
+	default:
+		return 0
+	}
+}
+
+func wireAttemptState(state store.AttemptState) protocol.AttemptState {
+	switch state {
+	case store.AttemptDispatched:
+		return protocol.AttemptStateDispatched
+	case store.AttemptAccepted:
+		return protocol.AttemptStateAccepted
+	case store.AttemptSucceeded:
+		return protocol.AttemptStateSucceeded
+	case store.AttemptFunctionFailed:
+		return protocol.AttemptStateFunctionFailed
+	case store.AttemptInfraFailed:
+		return protocol.AttemptStateInfraFailed
+	case store.AttemptLost:
+		return protocol.AttemptStateLost
+	case store.AttemptCancelled:
+		return protocol.AttemptStateCancelled
+	default:
+		return 0
+	}
+}
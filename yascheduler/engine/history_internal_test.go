@@ -0,0 +1,39 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yascheduler/protocol"
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yascheduler/store"
+)
+
+const (
+	executionStateCount = 12
+	attemptStateCount   = 7
+)
+
+func TestWireStatesCoverEveryStoreState(t *testing.T) {
+	t.Parallel()
+
+	executionStates := make(map[protocol.ExecutionState]store.ExecutionState)
+
+	for state := range store.ExecutionState(executionStateCount) {
+		wire := wireExecutionState(state + 1)
+		if previous, taken := executionStates[wire]; wire == 0 || taken {
+			t.Errorf("execution state %s maps to %d, already taken by %s", state+1, wire, previous)
+		}
+
+		executionStates[wire] = state + 1
+	}
+
+	attemptStates := make(map[protocol.AttemptState]store.AttemptState)
+
+	for state := range store.AttemptState(attemptStateCount) {
+		wire := wireAttemptState(state + 1)
+		if previous, taken := attemptStates[wire]; wire == 0 || taken {
+			t.Errorf("attempt state %d maps to %d, already taken by %d", state+1, wire, previous)
+		}
+
+		attemptStates[wire] = state + 1
+	}
+}
@@ -0,0 +1,167 @@
+package engine_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yascheduler/protocol"
+)
+
+const (
+	historyBackfillHours = 3
+	historyPageSize      = 2
+)
+
+func (f *engineFixture) listExecutions(
+	key string,
+	before protocol.ExecutionID,
+	limit uint32,
+) *protocol.ExecutionListAck {
+	return f.engine.HandleExecutionList(
+		context.Background(),
+		submitterInstance,
+		&protocol.ExecutionList{
+			JobKey:       key,
+			ExecutorType: workerType,
+			Before:       before,
+			Limit:        limit,
+		},
+	)
+}
+
+func (f *engineFixture) listAttempts(executionID protocol.ExecutionID) *protocol.AttemptListAck {
+	return f.engine.HandleAttemptList(
+		context.Background(),
+		submitterInstance,
+		&protocol.AttemptList{ExecutionID: executionID},
+	)
+}
+
+func TestEngineExecutionListSummarizesAttempts(t *testing.T) {
+	t.Parallel()
+
+	fixture := newFixture(t, testConfig())
+	_, sender := fixture.registerWorker(
+		firstWorker,
+		protocol.FunctionSpec{Name: workerFunction},
+	)
+	fixture.start(t)
+
+	jobID := fixture.upsert(t, oneShotJob("job-history", baseTime.Add(-time.Minute)))
+	fixture.awaitRequests(t, sender, 1)
+
+	executionID := fixture.soleExecution(t, jobID).ID
+	fixture.accept(t, firstWorker, executionID)
+	fixture.finish(t, firstWorker, executionID, false, false)
+
+	ack := fixture.listExecutions("job-history", 0, 0)
+	if ack.Error != nil || !ack.Found || len(ack.Executions) != 1 || ack.More {
+		t.Fatalf("the settled execution should be listed: %+v", ack)
+	}
+
+	execution := ack.Executions[0]
+	if execution.ExecutionID != executionID ||
+		execution.State != protocol.ExecutionStateFailed ||
+		execution.AttemptCount != 1 ||
+		execution.InstanceID != firstWorker ||
+		execution.LastError != failureMessage {
+		t.Errorf("the execution should report its failed attempt: %+v", execution)
+	}
+
+	if execution.StartedUnixNano == 0 || execution.FinishedUnixNano == 0 {
+		t.Errorf("a settled execution should report when it started and finished: %+v", execution)
+	}
+
+	attempts := fixture.listAttempts(executionID)
+	if attempts.Error != nil || !attempts.Found || len(attempts.Attempts) != 1 {
+		t.Fatalf("the attempt should be listed: %+v", attempts)
+	}
+
+	attempt := attempts.Attempts[0]
+	if attempt.Number != 1 ||
+		attempt.InstanceID != firstWorker ||
+		attempt.State != protocol.AttemptStateFunctionFailed ||
+		attempt.Error != failureMessage {
+		t.Errorf("the attempt should report its failure: %+v", attempt)
+	}
+}
+
+func TestEngineExecutionListPagesNewestFirst(t *testing.T) {
+	t.Parallel()
+
+	fixture := newFixture(t, testConfig())
+
+	jobID := fixture.upsert(t, intervalJob(
+		"job-history-pages",
+		baseTime.Add(-historyBackfillHours*time.Hour),
+		time.Hour,
+	))
+	stored := len(fixture.executions(t, jobID))
+
+	listed := make([]protocol.ExecutionInfo, 0, stored)
+
+	var before protocol.ExecutionID
+
+	for {
+		ack := fixture.listExecutions("job-history-pages", before, historyPageSize)
+		if ack.Error != nil || !ack.Found || len(ack.Executions) > historyPageSize {
+			t.Fatalf("each page should be found and capped: %+v", ack)
+		}
+
+		listed = append(listed, ack.Executions...)
+
+		if !ack.More {
+			break
+		}
+
+		before = ack.Executions[len(ack.Executions)-1].ExecutionID
+	}
+
+	if len(listed) != stored {
+		t.Fatalf("paging should list every execution once: got %d, want %d", len(listed), stored)
+	}
+
+	backfilled := 0
+
+	for index, execution := range listed {
+		if index > 0 && execution.ExecutionID >= listed[index-1].ExecutionID {
+			t.Errorf("executions should be listed newest first: %+v", listed)
+		}
+
+		if execution.Backfilled {
+			backfilled++
+		}
+
+		if execution.AttemptCount != 0 || execution.StartedUnixNano != 0 {
+			t.Errorf("an undispatched execution should report no attempts: %+v", execution)
+		}
+	}
+
+	if backfilled == 0 {
+		t.Error("the replayed occurrences should be reported as backfilled")
+	}
+}
+
+func TestEngineExecutionHistoryAbsentAnswersNotFound(t *testing.T) {
+	t.Parallel()
+
+	fixture := newFixture(t, testConfig())
+
+	if ack := fixture.listExecutions("job-history-missing", 0, 0); ack.Error != nil || ack.Found {
+		t.Errorf("an absent job should answer not found with no error: %+v", ack)
+	}
+
+	if ack := fixture.listAttempts(protocol.ExecutionID(1)); ack.Error != nil || ack.Found {
+		t.Errorf("an absent execution should answer not found with no error: %+v", ack)
+	}
+
+	refused := fixture.engine.HandleExecutionList(
+		context.Background(),
+		submitterInstance,
+		&protocol.ExecutionList{JobKey: "job-history-typeless"},
+	)
+	if refused.Error == nil || refused.Error.Code != protocol.ErrorCodeMalformedFrame {
+		t.Errorf("an empty executor type should be refused as malformed: %+v", refused.Error)
+	}
+}
@@ -0,0 +1,277 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yaerrors"
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yascheduler/protocol"
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yascheduler/store"
+)
+
+func (e *engine) HandleJobList(
+	ctx context.Context,
+	_ protocol.InstanceID,
+	list *protocol.JobList,
+) *protocol.JobListAck {
+	limit := store.BatchLimit(list.Limit)
+	if limit == 0 {
+		limit = DefaultJobListLimit
+	}
+
+	limit = min(limit, MaxJobListLimit)
+
+	jobs, err := e.jobs.ListJobs(ctx, list.ExecutorType, list.After, limit+1)
+	if err != nil {
+		e.log.Errorf(logTag+" job listing failed: %v", err)
+
+		return &protocol.JobListAck{Error: internalWireError(err)}
+	}
+
+	more := store.BatchLimit(len(jobs)) > limit
+	if more {
+		jobs = jobs[:limit]
+	}
+
+	infos, err := e.jobInfos(ctx, jobs)
+	if err != nil {
+		e.log.Errorf(logTag+" job listing failed: %v", err)
+
+		return &protocol.JobListAck{Error: internalWireError(err)}
+	}
+
+	for index := range infos {
+		infos[index].Args = nil
+	}
+
+	return &protocol.JobListAck{Jobs: infos, More: more}
+}
+
+func (e *engine) HandleJobGet(
+	ctx context.Context,
+	_ protocol.InstanceID,
+	get *protocol.JobGet,
+) *protocol.JobGetAck {
+	if reason, valid := validateJobAddress(get.JobKey, get.ExecutorType); !valid {
+		return &protocol.JobGetAck{
+			JobKey: get.JobKey,
+			Error: &protocol.WireError{
+				Code:    protocol.ErrorCodeMalformedFrame,
+				Message: reason,
+			},
+		}
+	}
+
+	job, err := e.jobs.GetJobByKey(ctx, get.ExecutorType, store.JobKey(get.JobKey))
+	if err != nil {
+		if errors.Is(err, store.ErrJobNotFound) {
+			return &protocol.JobGetAck{JobKey: get.JobKey}
+		}
+
+		e.log.Errorf(logTag+" job lookup failed: %v", err)
+
+		return &protocol.JobGetAck{JobKey: get.JobKey, Error: internalWireError(err)}
+	}
+
+	infos, err := e.jobInfos(ctx, []*store.Job{job})
+	if err != nil {
+		e.log.Errorf(logTag+" job lookup failed: %v", err)
+
+		return &protocol.JobGetAck{JobKey: get.JobKey, Error: internalWireError(err)}
+	}
+
+	return &protocol.JobGetAck{JobKey: get.JobKey, Job: &infos[0]}
+}
+
+func (e *engine) HandleJobControl(
+	ctx context.Context,
+	_ protocol.InstanceID,
+	control *protocol.JobControl,
+) *protocol.JobControlAck {
+	ack := &protocol.JobControlAck{JobKey: control.JobKey, Action: control.Action}
+
+	if reason, valid := validateControl(control); !valid {
+		ack.Error = &protocol.WireError{
+			Code:    protocol.ErrorCodeMalformedFrame,
+			Message: reason,
+		}
+
+		return ack
+	}
+
+	job, err := e.jobs.GetJobByKey(ctx, control.ExecutorType, store.JobKey(control.JobKey))
+	if err != nil {
+		if errors.Is(err, store.ErrJobNotFound) {
+			return ack
+		}
+
+		e.log.Errorf(logTag+" job control lookup failed: %v", err)
+		ack.Error = internalWireError(err)
+
+		return ack
+	}
+
+	ack.Found = true
+
+	switch control.Action {
+	case protocol.JobActionPause:
+		ack.Changed, err = e.pauseJob(ctx, job)
+	case protocol.JobActionResume:
+		ack.Changed, err = e.resumeJob(ctx, job)
+	case protocol.JobActionTrigger:
+		if !job.Enabled {
+			ack.Error = &protocol.WireError{
+				Code:    protocol.ErrorCodeJobPaused,
+				Message: controlReasonJobPaused,
+			}
+
+			return ack
+		}
+
+		ack.ExecutionID, err = e.triggerJob(ctx, job)
+	}
+
+	if err != nil {
+		e.log.Errorf(logTag+" job control failed: %v", err)
+		ack.Error = internalWireError(err)
+	}
+
+	return ack
+}
+
+// pauseJob disables a job. Its pending occurrences stay where they are:
+// one that comes due while the job is paused is cancelled at dispatch, and
+// one still ahead when the job resumes runs as scheduled. Attempts already
+// dispatched or running finish on their own.
+func (e *engine) pauseJob(ctx context.Context, job *store.Job) (bool, yaerrors.Error) {
+	if !job.Enabled {
+		return false, nil
+	}
+
+	if err := e.jobs.SetJobEnabled(ctx, job.ID, false); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// resumeJob enables a paused job and materializes its schedule exactly as
+// an enabled upsert does, so the job's backfill policy decides what the
+// pause missed.
+func (e *engine) resumeJob(ctx context.Context, job *store.Job) (bool, yaerrors.Error) {
+	if job.Enabled {
+		return false, nil
+	}
+
+	if err := e.jobs.SetJobEnabled(ctx, job.ID, true); err != nil {
+		return false, err
+	}
+
+	job.Enabled = true
+
+	e.materializeJob(ctx, job, e.now())
+	e.Notify()
+
+	return true, nil
+}
+
+// triggerJob materializes one extra occurrence due now. The regular
+// schedule is left as it is: the triggered occurrence runs alongside it
+// under the job's own overlap, retry and result policies.
+func (e *engine) triggerJob(
+	ctx context.Context,
+	job *store.Job,
+) (protocol.ExecutionID, yaerrors.Error) {
+	execution, _, err := e.executions.CreateExecution(
+		ctx,
+		job.ID,
+		e.now(),
+		store.StateScheduled,
+		false,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	e.Notify()
+
+	return execution.ID, nil
+}
+
+// jobInfos describes jobs with the instant each enabled one's earliest
+// pending occurrence becomes due, looked up for all of them in one store
+// query. A paused job has no next run: whatever it has pending is
+// cancelled if it comes due before the job resumes.
+func (e *engine) jobInfos(ctx context.Context, jobs []*store.Job) ([]protocol.JobInfo, yaerrors.Error) {
+	enabled := make([]protocol.JobUUID, 0, len(jobs))
+
+	for _, job := range jobs {
+		if job.Enabled {
+			enabled = append(enabled, job.ID)
+		}
+	}
+
+	nextRuns := map[protocol.JobUUID]time.Time{}
+
+	if len(enabled) > 0 {
+		var err yaerrors.Error
+
+		nextRuns, err = e.executions.NextRunTimes(ctx, enabled)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	infos := make([]protocol.JobInfo, 0, len(jobs))
+
+	for _, job := range jobs {
+		info := protocol.JobInfo{
+			JobUUID:             job.ID,
+			JobKey:              string(job.Key),
+			ExecutorType:        job.ExecutorType,
+			Function:            job.Function,
+			Args:                []byte(job.Args),
+			Schedule:            job.Schedule,
+			Enabled:             bool(job.Enabled),
+			Backfill:            job.Backfill,
+			Retry:               job.Retry,
+			Overlap:             job.Overlap,
+			Pin:                 job.Pin,
+			ResultMode:          job.ResultMode,
+			SubmitterInstanceID: job.SubmitterInstanceID,
+			SkippedOccurrences:  uint64(job.SkippedOccurrences),
+			CreatedUnixNano:     job.CreatedAt.UnixNano(),
+			UpdatedUnixNano:     job.UpdatedAt.UnixNano(),
+		}
+
+		if nextRun, pending := nextRuns[job.ID]; pending && bool(job.Enabled) {
+			info.NextRunUnixNano = nextRun.UnixNano()
+		}
+
+		infos = append(infos, info)
+	}
+
+	return infos, nil
+}
+
+func validateControl(control *protocol.JobControl) (reason string, valid bool) {
+	if reason, valid = validateJobAddress(control.JobKey, control.ExecutorType); !valid {
+		return reason, false
+	}
+
+	switch control.Action {
+	case protocol.JobActionPause, protocol.JobActionResume, protocol.JobActionTrigger:
+		return "", true
+	default:
+		return controlReasonUnknownAction, false
+	}
+}
+
+func internalWireError(err yaerrors.Error) *protocol.WireError {
+	return &protocol.WireError{
+		Code:      protocol.ErrorCodeInternal,
+		Retryable: true,
+		Message:   err.UnwrapLastError(),
+	}
+}
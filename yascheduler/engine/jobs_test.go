@@ -0,0 +1,263 @@
+package engine_test
+
+import (
+	"bytes"
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yascheduler/protocol"
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yascheduler/store"
+)
+
+const (
+	disabledReason = store.WaitReason("job disabled")
+
+	listedJobs   = 3
+	listPageSize = 2
+)
+
+var managedArgs = []byte{0x91, 0x2a}
+
+func (f *engineFixture) getJob(key string) *protocol.JobGetAck {
+	return f.engine.HandleJobGet(
+		context.Background(),
+		submitterInstance,
+		&protocol.JobGet{JobKey: key, ExecutorType: workerType},
+	)
+}
+
+func (f *engineFixture) controlJob(key string, action protocol.JobAction) *protocol.JobControlAck {
+	return f.engine.HandleJobControl(
+		context.Background(),
+		submitterInstance,
+		&protocol.JobControl{JobKey: key, ExecutorType: workerType, Action: action},
+	)
+}
+
+func TestEngineJobGetReportsDefinitionAndNextRun(t *testing.T) {
+	t.Parallel()
+
+	fixture := newFixture(t, testConfig())
+
+	upsert := intervalJob("job-get", baseTime.Add(time.Hour), time.Hour)
+	upsert.Args = managedArgs
+	jobID := fixture.upsert(t, upsert)
+
+	ack := fixture.getJob("job-get")
+	if ack.Error != nil || ack.Job == nil {
+		t.Fatalf("a stored job should be found: %+v", ack)
+	}
+
+	job := ack.Job
+	if job.JobUUID != jobID || job.JobKey != "job-get" || !job.Enabled {
+		t.Errorf("the job definition should round-trip: %+v", job)
+	}
+
+	if !bytes.Equal(job.Args, managedArgs) {
+		t.Errorf("a fetched job should carry its arguments: got %v", job.Args)
+	}
+
+	if job.SubmitterInstanceID != submitterInstance {
+		t.Errorf("the submitter should be reported: got %q", job.SubmitterInstanceID)
+	}
+
+	if job.NextRunUnixNano != baseTime.Add(time.Hour).UnixNano() {
+		t.Errorf(
+			"the next run should be the pending occurrence: got %v",
+			time.Unix(0, job.NextRunUnixNano).UTC(),
+		)
+	}
+}
+
+func TestEngineJobGetUnknownKeyAnswersNil(t *testing.T) {
+	t.Parallel()
+
+	fixture := newFixture(t, testConfig())
+
+	ack := fixture.getJob("job-get-missing")
+	if ack.Error != nil || ack.Job != nil {
+		t.Errorf("an absent job should answer nil with no error: %+v", ack)
+	}
+
+	refused := fixture.engine.HandleJobGet(
+		context.Background(),
+		submitterInstance,
+		&protocol.JobGet{JobKey: "job-get-typeless"},
+	)
+	if refused.Error == nil || refused.Error.Code != protocol.ErrorCodeMalformedFrame {
+		t.Errorf("an empty executor type should be refused as malformed: %+v", refused.Error)
+	}
+}
+
+func TestEngineJobListPagesInIdentifierOrder(t *testing.T) {
+	t.Parallel()
+
+	fixture := newFixture(t, testConfig())
+
+	for _, key := range []string{"job-list-a", "job-list-b", "job-list-c"} {
+		upsert := oneShotJob(key, baseTime.Add(time.Hour))
+		upsert.Args = managedArgs
+		fixture.upsert(t, upsert)
+	}
+
+	fixture.controlJob("job-list-b", protocol.JobActionPause)
+
+	first := fixture.engine.HandleJobList(
+		context.Background(),
+		submitterInstance,
+		&protocol.JobList{Limit: listPageSize},
+	)
+	if first.Error != nil || len(first.Jobs) != listPageSize || !first.More {
+		t.Fatalf("the first page should be full with more to come: %+v", first)
+	}
+
+	last := first.Jobs[len(first.Jobs)-1].JobUUID
+
+	second := fixture.engine.HandleJobList(
+		context.Background(),
+		submitterInstance,
+		&protocol.JobList{After: last, Limit: listPageSize},
+	)
+	if second.Error != nil || len(second.Jobs) != listedJobs-listPageSize || second.More {
+		t.Fatalf("the second page should hold the rest: %+v", second)
+	}
+
+	listed := slices.Concat(first.Jobs, second.Jobs)
+
+	paused := 0
+
+	for index, job := range listed {
+		if len(job.Args) != 0 {
+			t.Errorf("a listed job should carry no arguments: %q", job.JobKey)
+		}
+
+		if index > 0 && bytes.Compare(listed[index-1].JobUUID[:], job.JobUUID[:]) >= 0 {
+			t.Errorf("jobs should be listed in identifier order: %v", listed)
+		}
+
+		if !job.Enabled {
+			paused++
+		}
+	}
+
+	if paused != 1 {
+		t.Errorf("the paused job should be listed too: got %d paused", paused)
+	}
+}
+
+func TestEngineJobPauseAndResumeKeepPendingOccurrence(t *testing.T) {
+	t.Parallel()
+
+	fixture := newFixture(t, testConfig())
+
+	jobID := fixture.upsert(t, intervalJob("job-pause", baseTime.Add(time.Hour), time.Hour))
+	executionID := fixture.soleExecution(t, jobID).ID
+
+	paused := fixture.controlJob("job-pause", protocol.JobActionPause)
+	if paused.Error != nil || !paused.Found || !paused.Changed {
+		t.Fatalf("pausing an enabled job should change it: %+v", paused)
+	}
+
+	if again := fixture.controlJob("job-pause", protocol.JobActionPause); !again.Found ||
+		again.Changed || again.Error != nil {
+		t.Errorf("pausing a paused job should change nothing: %+v", again)
+	}
+
+	if got := fixture.getJob("job-pause").Job; got.Enabled || got.NextRunUnixNano != 0 {
+		t.Errorf("a paused job should report no next run: %+v", got)
+	}
+
+	resumed := fixture.controlJob("job-pause", protocol.JobActionResume)
+	if resumed.Error != nil || !resumed.Changed {
+		t.Fatalf("resuming a paused job should change it: %+v", resumed)
+	}
+
+	if execution := fixture.execution(t, executionID); execution.State != store.StateScheduled {
+		t.Errorf("an occurrence still ahead should survive the pause: got %s", execution.State)
+	}
+
+	if !bool(fixture.job(t, jobID).Enabled) {
+		t.Error("the resumed job should be enabled")
+	}
+}
+
+func TestEngineJobPausedOccurrenceIsCancelledAtDispatch(t *testing.T) {
+	t.Parallel()
+
+	fixture := newFixture(t, testConfig())
+	_, sender := fixture.registerWorker(
+		firstWorker,
+		protocol.FunctionSpec{Name: workerFunction},
+	)
+
+	jobID := fixture.upsert(t, oneShotJob("job-pause-due", baseTime.Add(time.Minute)))
+	executionID := fixture.soleExecution(t, jobID).ID
+
+	fixture.controlJob("job-pause-due", protocol.JobActionPause)
+	fixture.clock.Advance(time.Hour)
+	fixture.start(t)
+
+	fixture.awaitExecutionState(t, executionID, store.StateCancelled)
+
+	if reason := fixture.execution(t, executionID).WaitReason; reason != disabledReason {
+		t.Errorf("the cancellation should carry the disabled reason: got %q", reason)
+	}
+
+	if sender.requestCount() != 0 {
+		t.Error("a paused job should not be dispatched")
+	}
+}
+
+func TestEngineJobTriggerDispatchesExtraOccurrence(t *testing.T) {
+	t.Parallel()
+
+	fixture := newFixture(t, testConfig())
+	_, sender := fixture.registerWorker(
+		firstWorker,
+		protocol.FunctionSpec{Name: workerFunction},
+	)
+	fixture.start(t)
+
+	jobID := fixture.upsert(t, intervalJob("job-trigger", baseTime.Add(time.Hour), time.Hour))
+	scheduledID := fixture.soleExecution(t, jobID).ID
+
+	ack := fixture.controlJob("job-trigger", protocol.JobActionTrigger)
+	if ack.Error != nil || !ack.Found || ack.ExecutionID == 0 {
+		t.Fatalf("triggering a job should create an execution: %+v", ack)
+	}
+
+	fixture.awaitRequests(t, sender, 1)
+
+	if request := sender.requests()[0]; request.ExecutionID != ack.ExecutionID {
+		t.Errorf("the triggered execution should be dispatched: got %d", request.ExecutionID)
+	}
+
+	if execution := fixture.execution(t, scheduledID); execution.State != store.StateScheduled {
+		t.Errorf("the regular occurrence should be untouched: got %s", execution.State)
+	}
+}
+
+func TestEngineJobControlRefusals(t *testing.T) {
+	t.Parallel()
+
+	fixture := newFixture(t, testConfig())
+	fixture.upsert(t, oneShotJob("job-control", baseTime.Add(time.Hour)))
+	fixture.controlJob("job-control", protocol.JobActionPause)
+
+	trigger := fixture.controlJob("job-control", protocol.JobActionTrigger)
+	if trigger.Error == nil || trigger.Error.Code != protocol.ErrorCodeJobPaused {
+		t.Errorf("triggering a paused job should be refused: %+v", trigger.Error)
+	}
+
+	unknown := fixture.controlJob("job-control", protocol.JobAction(0))
+	if unknown.Error == nil || unknown.Error.Code != protocol.ErrorCodeMalformedFrame {
+		t.Errorf("an unknown action should be refused as malformed: %+v", unknown.Error)
+	}
+
+	missing := fixture.controlJob("job-control-missing", protocol.JobActionResume)
+	if missing.Error != nil || missing.Found {
+		t.Errorf("an absent job should answer not found with no error: %+v", missing)
+	}
+}
@@ -43,6 +43,25 @@ func scheduleStart(spec protocol.ScheduleSpec) (start time.Time) {
 	return time.Unix(0, spec.StartUnixNano).UTC()
 }
 
+func scheduleCron(spec protocol.ScheduleSpec) (cron *cronSchedule, ok bool) {
+	if spec.Kind != protocol.ScheduleKindCron {
+		return nil, false
+	}
+
+	return cachedCronSchedule(spec)
+}
+
+// unixSecondsFrom returns the first whole unix second at or after at. Cron
+// occurrences fall on whole seconds.
+func unixSecondsFrom(at time.Time) (seconds int64) {
+	seconds = at.Unix()
+	if at.Nanosecond() > 0 {
+		seconds++
+	}
+
+	return seconds
+}
+
 func scheduleContains(
 	spec protocol.ScheduleSpec,
 	occurrence time.Time,
@@ -63,6 +82,16 @@ func scheduleContains(
 		}
 
 		return occurrence.Sub(start)%interval == 0
+	case protocol.ScheduleKindCron:
+		cron, ok := scheduleCron(spec)
+		if !ok || occurrence.Nanosecond() != 0 || occurrence.Before(start) {
+			return false
+		}
+
+		seconds := occurrence.Unix()
+		found, exists := cron.firstFrom(seconds, seconds+1)
+
+		return exists && found == seconds
 	default:
 		return false
 	}
@@ -95,6 +124,20 @@ func nextOccurrence(
 		periods := int64(elapsed/interval) + 1
 
 		return start.Add(time.Duration(periods * int64(interval))), true
+	case protocol.ScheduleKindCron:
+		cron, ok := scheduleCron(spec)
+		if !ok {
+			return time.Time{}, false
+		}
+
+		from := max(after.Unix()+1, unixSecondsFrom(start))
+
+		seconds, found := cron.firstFrom(from, from+int64(cronSearchHorizon/time.Second))
+		if !found {
+			return time.Time{}, false
+		}
+
+		return time.Unix(seconds, 0).UTC(), true
 	default:
 		return time.Time{}, false
 	}
@@ -160,7 +203,54 @@ func missedOccurrences(
 		}
 
 		return collected, total - store.OccurrenceCount(len(collected))
+	case protocol.ScheduleKindCron:
+		return missedCronOccurrences(spec, until, maxCount, maxAge)
 	default:
 		return nil, 0
 	}
 }
+
+// missedCronOccurrences is missedOccurrences for cron schedules: it keeps
+// the newest occurrences in [start, until] within the count and age caps,
+// oldest first, and counts the rest as skipped.
+func missedCronOccurrences(
+	spec protocol.ScheduleSpec,
+	until time.Time,
+	maxCount store.OccurrenceCount,
+	maxAge time.Duration,
+) (missed []time.Time, skipped store.OccurrenceCount) {
+	cron, ok := scheduleCron(spec)
+	if !ok {
+		return nil, 0
+	}
+
+	first, last := unixSecondsFrom(scheduleStart(spec)), until.Unix()
+	if first > last {
+		return nil, 0
+	}
+
+	total := store.OccurrenceCount(cron.count(first, last))
+
+	from := first
+	if maxAge > 0 {
+		from = max(from, unixSecondsFrom(until.Add(-maxAge)))
+	}
+
+	if maxCount > 0 && from <= last {
+		from = cron.newestFrom(from, last, uint64(maxCount))
+	}
+
+	collected := make([]time.Time, 0)
+
+	for from <= last {
+		seconds, found := cron.firstFrom(from, last+1)
+		if !found {
+			break
+		}
+
+		collected = append(collected, time.Unix(seconds, 0).UTC())
+		from = seconds + 1
+	}
+
+	return collected, total - store.OccurrenceCount(len(collected))
+}
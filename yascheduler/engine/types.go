@@ -59,6 +59,59 @@ type Engine interface {
 		del *protocol.JobDelete,
 	) *protocol.JobDeleteAck
 
+	// HandleJobList answers one page of stored jobs, enabled or paused, in
+	// identifier order. Listed jobs carry no arguments; HandleJobGet
+	// returns them.
+	HandleJobList(
+		ctx context.Context,
+		instanceID protocol.InstanceID,
+		list *protocol.JobList,
+	) *protocol.JobListAck
+
+	// HandleJobGet answers the job addressed by key and executor type,
+	// with the instant its next pending occurrence becomes due. An absent
+	// job answers a nil Job with no error.
+	HandleJobGet(
+		ctx context.Context,
+		instanceID protocol.InstanceID,
+		get *protocol.JobGet,
+	) *protocol.JobGetAck
+
+	// HandleJobControl pauses, resumes or triggers the job addressed by key
+	// and executor type and answers the acknowledgement to send back.
+	// Pausing disables the job: an occurrence that comes due while it is
+	// paused is cancelled, and running work finishes on its own. Resuming
+	// enables it and materializes its schedule as an enabled upsert does,
+	// so its backfill policy decides what the pause missed.
+	// Triggering adds one occurrence due now beside the regular schedule
+	// and is refused with ErrorCodeJobPaused on a paused job. Pausing a
+	// paused job or resuming an enabled one answers Changed false with no
+	// error, and an absent job answers Found false with no error.
+	HandleJobControl(
+		ctx context.Context,
+		instanceID protocol.InstanceID,
+		control *protocol.JobControl,
+	) *protocol.JobControlAck
+
+	// HandleExecutionList answers one page of the executions of the job
+	// addressed by key and executor type, newest first, each with a
+	// summary of its attempts. An absent job answers Found false with no
+	// error.
+	HandleExecutionList(
+		ctx context.Context,
+		instanceID protocol.InstanceID,
+		list *protocol.ExecutionList,
+	) *protocol.ExecutionListAck
+
+	// HandleAttemptList answers one page of the attempts of one execution
+	// in attempt-number order. An absent execution answers Found false
+	// with no error.
+	HandleAttemptList(
+		ctx context.Context,
+		instanceID protocol.InstanceID,
+		list *protocol.AttemptList,
+	) *protocol.AttemptListAck
+
 	// HandleExecAccept records whether an executor admitted a dispatched
 	// attempt.
 	HandleExecAccept(
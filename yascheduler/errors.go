@@ -48,6 +48,14 @@ var (
 	// executor's registration.
 	ErrRegistrationRejected = errors.New("registration rejected")
 
+	// ErrUnauthenticated reports a scheduler that refused this executor's
+	// registration because it failed authentication.
+	ErrUnauthenticated = errors.New("registration failed authentication")
+
+	// ErrAuthSecretMissing reports an authentication challenge received
+	// by a client configured without an AuthSecret.
+	ErrAuthSecretMissing = errors.New("scheduler requires authentication but no secret is configured")
+
 	// ErrConnectionClosed reports a connection that ended while a
 	// response was still awaited.
 	ErrConnectionClosed = errors.New("connection closed")
@@ -62,6 +70,26 @@ var (
 	// ErrDeleteRejected reports a job delete the scheduler refused.
 	ErrDeleteRejected = errors.New("job delete rejected")
 
+	// ErrJobNotFound reports a job lookup or control addressing no stored
+	// job.
+	ErrJobNotFound = errors.New("job not found")
+
+	// ErrJobPaused reports a trigger of a paused job, which would only be
+	// cancelled at dispatch.
+	ErrJobPaused = errors.New("job is paused")
+
+	// ErrExecutionNotFound reports an attempt listing addressing no stored
+	// execution.
+	ErrExecutionNotFound = errors.New("execution not found")
+
+	// ErrJobQueryRejected reports a job listing, lookup or history query
+	// the scheduler refused.
+	ErrJobQueryRejected = errors.New("job query rejected")
+
+	// ErrJobControlRejected reports a job pause, resume or trigger the
+	// scheduler refused.
+	ErrJobControlRejected = errors.New("job control rejected")
+
 	// ErrUnexpectedMessage reports a frame that is invalid for the
 	// current connection state.
 	ErrUnexpectedMessage = errors.New("unexpected message")
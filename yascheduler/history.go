@@ -0,0 +1,173 @@
+package yascheduler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yaerrors"
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yascheduler/protocol"
+)
+
+// ListExecutions returns one page of the execution history of the job the
+// query addresses, newest first; an empty executor type addresses this
+// client's own. An absent job answers ErrJobNotFound.
+func (c *Client) ListExecutions(
+	ctx context.Context,
+	query ExecutionQuery,
+) (*ExecutionPage, yaerrors.Error) {
+	if query.ExecutorType == "" {
+		query.ExecutorType = c.cfg.ExecutorType
+	}
+
+	reply, err := c.roundTrip(ctx, executionListFor(query), "list executions")
+	if err != nil {
+		return nil, err
+	}
+
+	if reply.historyAck == nil {
+		return nil, unexpectedReply("list executions")
+	}
+
+	return executionPageFromAck(reply.historyAck)
+}
+
+// ListAttempts returns every attempt of one execution in attempt-number
+// order. An absent execution, including one already purged by retention,
+// answers ErrExecutionNotFound.
+func (c *Client) ListAttempts(
+	ctx context.Context,
+	executionID protocol.ExecutionID,
+) ([]*AttemptInfo, yaerrors.Error) {
+	return listAttemptPages(
+		executionID,
+		func(list *protocol.AttemptList) (*protocol.AttemptListAck, yaerrors.Error) {
+			reply, err := c.roundTrip(ctx, list, "list attempts")
+			if err != nil {
+				return nil, err
+			}
+
+			if reply.attemptAck == nil {
+				return nil, unexpectedReply("list attempts")
+			}
+
+			return reply.attemptAck, nil
+		},
+	)
+}
+
+func executionListFor(query ExecutionQuery) *protocol.ExecutionList {
+	return &protocol.ExecutionList{
+		JobKey:       query.Key,
+		ExecutorType: query.ExecutorType,
+		Before:       query.Before,
+		Limit:        query.Limit,
+	}
+}
+
+func executionPageFromAck(ack *protocol.ExecutionListAck) (*ExecutionPage, yaerrors.Error) {
+	if ack.Error != nil {
+		return nil, yaerrors.FromError(
+			http.StatusBadRequest,
+			ErrJobQueryRejected,
+			logTag+" list executions: "+wireErrorText(ack.Error),
+		)
+	}
+
+	if !ack.Found {
+		return nil, yaerrors.FromError(
+			http.StatusNotFound,
+			ErrJobNotFound,
+			logTag+" list executions: "+ack.JobKey,
+		)
+	}
+
+	page := &ExecutionPage{
+		Executions: make([]*ExecutionInfo, 0, len(ack.Executions)),
+		More:       ack.More,
+	}
+
+	for index := range ack.Executions {
+		execution := &ack.Executions[index]
+
+		page.Executions = append(page.Executions, &ExecutionInfo{
+			ExecutionID:  execution.ExecutionID,
+			ScheduledAt:  time.Unix(0, execution.ScheduledUnixNano).UTC(),
+			State:        execution.State,
+			Backfilled:   execution.Backfilled,
+			AttemptCount: execution.AttemptCount,
+			Instance:     execution.InstanceID,
+			StartedAt:    optionalTime(execution.StartedUnixNano),
+			FinishedAt:   optionalTime(execution.FinishedUnixNano),
+			LastError:    execution.LastError,
+			WaitReason:   execution.WaitReason,
+		})
+	}
+
+	return page, nil
+}
+
+// listAttemptPages drives a paged attempt listing through fetch until the
+// scheduler reports no more attempts. Either scheduler implementation
+// supplies its own fetch, so both page identically.
+func listAttemptPages(
+	executionID protocol.ExecutionID,
+	fetch func(list *protocol.AttemptList) (*protocol.AttemptListAck, yaerrors.Error),
+) ([]*AttemptInfo, yaerrors.Error) {
+	list := &protocol.AttemptList{ExecutionID: executionID}
+	attempts := make([]*AttemptInfo, 0)
+
+	for {
+		ack, err := fetch(list)
+		if err != nil {
+			return nil, err.Wrap(logTag + " list attempts")
+		}
+
+		if ack.Error != nil {
+			return nil, yaerrors.FromError(
+				http.StatusBadRequest,
+				ErrJobQueryRejected,
+				logTag+" list attempts: "+wireErrorText(ack.Error),
+			)
+		}
+
+		if !ack.Found {
+			return nil, yaerrors.FromError(
+				http.StatusNotFound,
+				ErrExecutionNotFound,
+				logTag+" list attempts: "+strconv.FormatUint(uint64(executionID), 10),
+			)
+		}
+
+		for index := range ack.Attempts {
+			attempt := &ack.Attempts[index]
+
+			attempts = append(attempts, &AttemptInfo{
+				AttemptID:    attempt.AttemptID,
+				Number:       attempt.Number,
+				Instance:     attempt.InstanceID,
+				State:        attempt.State,
+				Error:        attempt.Error,
+				DispatchedAt: time.Unix(0, attempt.DispatchedUnixNano).UTC(),
+				UpdatedAt:    time.Unix(0, attempt.UpdatedUnixNano).UTC(),
+			})
+		}
+
+		if !ack.More || len(ack.Attempts) == 0 {
+			return attempts, nil
+		}
+
+		list.After = ack.Attempts[len(ack.Attempts)-1].Number
+	}
+}
+
+// optionalTime turns a wire instant into a time, keeping zero as the zero
+// time rather than the Unix epoch.
+func optionalTime(unixNano int64) time.Time {
+	if unixNano == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(0, unixNano).UTC()
+}
@@ -3,9 +3,11 @@ package yascheduler
 import (
 	"context"
 	"net/http"
+	"time"
 
 	"github.com/YaCodeDev/GoYaCodeDevUtils/yaencoding"
 	"github.com/YaCodeDev/GoYaCodeDevUtils/yaerrors"
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yalogger"
 	"github.com/YaCodeDev/GoYaCodeDevUtils/yascheduler/protocol"
 	"github.com/google/uuid"
 )
@@ -18,7 +20,10 @@ import (
 // empty on spec.Function are stamped from the local registry when the
 // target function is registered there, and a backfill mode of
 // BackfillModeInherit is stamped with the given default before sending.
+// The trace context of ctx travels with the job, so its executions log
+// under the caller's trace.
 func buildJobUpsert(
+	ctx context.Context,
 	spec *JobSpec,
 	registry *Registry,
 	defaultExecutorType protocol.ExecutorType,
@@ -95,6 +100,7 @@ func buildJobUpsert(
 		Overlap:      spec.Overlap,
 		Pin:          spec.Pin,
 		ResultMode:   spec.ResultMode,
+		TraceParent:  protocol.TraceParent(yalogger.TraceParentFromContext(ctx)),
 	}, nil
 }
 
@@ -113,7 +119,13 @@ func (c *Client) UpsertJob(
 	ctx context.Context,
 	spec *JobSpec,
 ) (*Submission, yaerrors.Error) {
-	upsert, err := buildJobUpsert(spec, c.registry, c.cfg.ExecutorType, c.cfg.DefaultBackfill)
+	upsert, err := buildJobUpsert(
+		ctx,
+		spec,
+		c.registry,
+		c.cfg.ExecutorType,
+		c.cfg.DefaultBackfill,
+	)
 	if err != nil {
 		return nil, err.Wrap(logTag + " upsert job")
 	}
@@ -253,3 +265,300 @@ func (c *Client) DeleteJob(
 		return ack.Deleted, nil
 	}
 }
+
+// ListJobs returns every job stored on the scheduler for the given
+// executor type, paused ones included, in identifier order; unlike the
+// other job calls, an empty executor type lists every type. The listing is
+// fetched page by page, so it is not a snapshot: a job stored or deleted
+// meanwhile may or may not appear. Listed jobs carry no Args; GetJob
+// returns them.
+func (c *Client) ListJobs(
+	ctx context.Context,
+	executorType protocol.ExecutorType,
+) ([]*JobInfo, yaerrors.Error) {
+	return listJobPages(
+		executorType,
+		func(list *protocol.JobList) (*protocol.JobListAck, yaerrors.Error) {
+			reply, err := c.roundTrip(ctx, list, "list jobs")
+			if err != nil {
+				return nil, err
+			}
+
+			if reply.listAck == nil {
+				return nil, unexpectedReply("list jobs")
+			}
+
+			return reply.listAck, nil
+		},
+	)
+}
+
+// GetJob returns the job addressed by key within the given executor type
+// on the scheduler; an empty executor type addresses this client's own. An
+// absent job answers ErrJobNotFound.
+func (c *Client) GetJob(
+	ctx context.Context,
+	executorType protocol.ExecutorType,
+	key string,
+) (*JobInfo, yaerrors.Error) {
+	if executorType == "" {
+		executorType = c.cfg.ExecutorType
+	}
+
+	reply, err := c.roundTrip(
+		ctx,
+		&protocol.JobGet{JobKey: key, ExecutorType: executorType},
+		"get job",
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if reply.getAck == nil {
+		return nil, unexpectedReply("get job")
+	}
+
+	return jobFromGetAck(reply.getAck)
+}
+
+// PauseJob stops scheduling the job addressed by key within the given
+// executor type; an empty executor type addresses this client's own. An
+// occurrence that comes due while the job is paused is cancelled, and work
+// already running finishes on its own. It reports whether the job was
+// enabled, so pausing a paused job answers false with no error; an absent
+// job answers ErrJobNotFound.
+func (c *Client) PauseJob(
+	ctx context.Context,
+	executorType protocol.ExecutorType,
+	key string,
+) (bool, yaerrors.Error) {
+	ack, err := c.controlJob(ctx, executorType, key, protocol.JobActionPause)
+	if err != nil {
+		return false, err
+	}
+
+	return ack.Changed, nil
+}
+
+// ResumeJob schedules the paused job addressed by key within the given
+// executor type again; an empty executor type addresses this client's own.
+// The job's backfill policy decides what the pause missed, as when an
+// upsert enables a disabled job. It reports whether the job was paused, so
+// resuming an enabled job answers false with no error; an absent job
+// answers ErrJobNotFound.
+func (c *Client) ResumeJob(
+	ctx context.Context,
+	executorType protocol.ExecutorType,
+	key string,
+) (bool, yaerrors.Error) {
+	ack, err := c.controlJob(ctx, executorType, key, protocol.JobActionResume)
+	if err != nil {
+		return false, err
+	}
+
+	return ack.Changed, nil
+}
+
+// TriggerNow runs the job addressed by key within the given executor type
+// once more, due immediately, and returns the execution it created; an
+// empty executor type addresses this client's own. The regular schedule is
+// left as it is. A paused job answers ErrJobPaused and an absent one
+// ErrJobNotFound. A delivered result of the triggered run reaches the
+// job's submitter like any other.
+func (c *Client) TriggerNow(
+	ctx context.Context,
+	executorType protocol.ExecutorType,
+	key string,
+) (protocol.ExecutionID, yaerrors.Error) {
+	ack, err := c.controlJob(ctx, executorType, key, protocol.JobActionTrigger)
+	if err != nil {
+		return 0, err
+	}
+
+	return ack.ExecutionID, nil
+}
+
+func (c *Client) controlJob(
+	ctx context.Context,
+	executorType protocol.ExecutorType,
+	key string,
+	action protocol.JobAction,
+) (*protocol.JobControlAck, yaerrors.Error) {
+	if executorType == "" {
+		executorType = c.cfg.ExecutorType
+	}
+
+	reply, err := c.roundTrip(
+		ctx,
+		&protocol.JobControl{JobKey: key, ExecutorType: executorType, Action: action},
+		"control job",
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if reply.controlAck == nil {
+		return nil, unexpectedReply("control job")
+	}
+
+	return checkControlAck(reply.controlAck)
+}
+
+// roundTrip sends one correlated request and waits for its reply. The
+// client must hold a registered connection; use AwaitReady first when
+// racing startup.
+func (c *Client) roundTrip(
+	ctx context.Context,
+	request protocol.Message,
+	operation string,
+) (pendingReply, yaerrors.Error) {
+	correlationID := c.nextCorrelation()
+
+	waiter, err := c.registerPending(correlationID)
+	if err != nil {
+		return pendingReply{}, err.Wrap(logTag + " " + operation)
+	}
+
+	if err = c.enqueueFrame(correlationID, request); err != nil {
+		c.unregisterPending(correlationID)
+
+		return pendingReply{}, err.Wrap(logTag + " " + operation)
+	}
+
+	select {
+	case <-ctx.Done():
+		c.unregisterPending(correlationID)
+
+		return pendingReply{}, yaerrors.FromError(
+			http.StatusServiceUnavailable,
+			ctx.Err(),
+			logTag+" "+operation,
+		)
+	case reply, open := <-waiter:
+		if !open {
+			return pendingReply{}, yaerrors.FromError(
+				http.StatusServiceUnavailable,
+				ErrConnectionClosed,
+				logTag+" "+operation,
+			)
+		}
+
+		return reply, nil
+	}
+}
+
+func unexpectedReply(operation string) yaerrors.Error {
+	return yaerrors.FromError(
+		http.StatusBadGateway,
+		ErrUnexpectedMessage,
+		logTag+" "+operation,
+	)
+}
+
+// listJobPages drives a paged job listing through fetch until the
+// scheduler reports no more jobs. Either scheduler implementation supplies
+// its own fetch, so both page identically.
+func listJobPages(
+	executorType protocol.ExecutorType,
+	fetch func(list *protocol.JobList) (*protocol.JobListAck, yaerrors.Error),
+) ([]*JobInfo, yaerrors.Error) {
+	list := &protocol.JobList{ExecutorType: executorType}
+	jobs := make([]*JobInfo, 0)
+
+	for {
+		ack, err := fetch(list)
+		if err != nil {
+			return nil, err.Wrap(logTag + " list jobs")
+		}
+
+		if ack.Error != nil {
+			return nil, yaerrors.FromError(
+				http.StatusBadRequest,
+				ErrJobQueryRejected,
+				logTag+" list jobs: "+wireErrorText(ack.Error),
+			)
+		}
+
+		for index := range ack.Jobs {
+			jobs = append(jobs, jobInfoFromWire(&ack.Jobs[index]))
+		}
+
+		if !ack.More || len(ack.Jobs) == 0 {
+			return jobs, nil
+		}
+
+		list.After = ack.Jobs[len(ack.Jobs)-1].JobUUID
+	}
+}
+
+func jobFromGetAck(ack *protocol.JobGetAck) (*JobInfo, yaerrors.Error) {
+	if ack.Error != nil {
+		return nil, yaerrors.FromError(
+			http.StatusBadRequest,
+			ErrJobQueryRejected,
+			logTag+" get job: "+wireErrorText(ack.Error),
+		)
+	}
+
+	if ack.Job == nil {
+		return nil, yaerrors.FromError(
+			http.StatusNotFound,
+			ErrJobNotFound,
+			logTag+" get job: "+ack.JobKey,
+		)
+	}
+
+	return jobInfoFromWire(ack.Job), nil
+}
+
+// checkControlAck turns a refused or unmatched job control into the
+// matching error, so both scheduler implementations surface one.
+func checkControlAck(ack *protocol.JobControlAck) (*protocol.JobControlAck, yaerrors.Error) {
+	if ack.Error != nil {
+		if ack.Error.Code == protocol.ErrorCodeJobPaused {
+			return nil, yaerrors.FromError(
+				http.StatusConflict,
+				ErrJobPaused,
+				logTag+" control job: "+ack.JobKey,
+			)
+		}
+
+		return nil, yaerrors.FromError(
+			http.StatusBadRequest,
+			ErrJobControlRejected,
+			logTag+" control job: "+wireErrorText(ack.Error),
+		)
+	}
+
+	if !ack.Found {
+		return nil, yaerrors.FromError(
+			http.StatusNotFound,
+			ErrJobNotFound,
+			logTag+" control job: "+ack.JobKey,
+		)
+	}
+
+	return ack, nil
+}
+
+func jobInfoFromWire(info *protocol.JobInfo) *JobInfo {
+	return &JobInfo{
+		JobUUID:            info.JobUUID,
+		Key:                info.JobKey,
+		ExecutorType:       info.ExecutorType,
+		Function:           info.Function,
+		Args:               info.Args,
+		Schedule:           info.Schedule,
+		Paused:             !info.Enabled,
+		Backfill:           info.Backfill,
+		Retry:              info.Retry,
+		Overlap:            info.Overlap,
+		Pin:                info.Pin,
+		ResultMode:         info.ResultMode,
+		Submitter:          info.SubmitterInstanceID,
+		SkippedOccurrences: info.SkippedOccurrences,
+		NextRunAt:          optionalTime(info.NextRunUnixNano),
+		CreatedAt:          time.Unix(0, info.CreatedUnixNano).UTC(),
+		UpdatedAt:          time.Unix(0, info.UpdatedUnixNano).UTC(),
+	}
+}
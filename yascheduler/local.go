@@ -45,7 +45,8 @@ type LocalConfig struct {
 
 	// Store persists jobs, executions, attempts, and results. Leave nil
 	// for an in-memory store with package defaults; supply a
-	// store/redisstore Store for state that survives restarts.
+	// store/redisstore or store/sqlstore Store for state that survives
+	// restarts.
 	Store store.Store
 }
 
@@ -367,6 +368,7 @@ func (l *Local) UpsertJob(
 	spec *JobSpec,
 ) (*Submission, yaerrors.Error) {
 	upsert, err := buildJobUpsert(
+		ctx,
 		spec,
 		l.runtime.registry,
 		l.cfg.ExecutorType,
@@ -424,6 +426,142 @@ func (l *Local) DeleteJob(
 	return ack.Deleted, nil
 }
 
+// ListJobs returns every job stored on the embedded engine for the given
+// executor type, paused ones included, in identifier order; unlike the
+// other job calls, an empty executor type lists every type. Listed jobs
+// carry no Args; GetJob returns them.
+func (l *Local) ListJobs(
+	ctx context.Context,
+	executorType protocol.ExecutorType,
+) ([]*JobInfo, yaerrors.Error) {
+	return listJobPages(
+		executorType,
+		func(list *protocol.JobList) (*protocol.JobListAck, yaerrors.Error) {
+			return l.engine.HandleJobList(ctx, l.cfg.InstanceID, list), nil
+		},
+	)
+}
+
+// GetJob returns the job addressed by key within the given executor type
+// on the embedded engine; an empty executor type addresses this
+// scheduler's own. An absent job answers ErrJobNotFound.
+func (l *Local) GetJob(
+	ctx context.Context,
+	executorType protocol.ExecutorType,
+	key string,
+) (*JobInfo, yaerrors.Error) {
+	if executorType == "" {
+		executorType = l.cfg.ExecutorType
+	}
+
+	return jobFromGetAck(l.engine.HandleJobGet(ctx, l.cfg.InstanceID, &protocol.JobGet{
+		JobKey:       key,
+		ExecutorType: executorType,
+	}))
+}
+
+// PauseJob stops scheduling the job addressed by key within the given
+// executor type; an empty executor type addresses this scheduler's own. An
+// occurrence that comes due while the job is paused is cancelled, and work
+// already running finishes on its own. It reports whether the job was
+// enabled; an absent job answers ErrJobNotFound.
+func (l *Local) PauseJob(
+	ctx context.Context,
+	executorType protocol.ExecutorType,
+	key string,
+) (bool, yaerrors.Error) {
+	ack, err := l.controlJob(ctx, executorType, key, protocol.JobActionPause)
+	if err != nil {
+		return false, err
+	}
+
+	return ack.Changed, nil
+}
+
+// ResumeJob schedules the paused job addressed by key within the given
+// executor type again; an empty executor type addresses this scheduler's
+// own. The job's backfill policy decides what the pause missed. It reports
+// whether the job was paused; an absent job answers ErrJobNotFound.
+func (l *Local) ResumeJob(
+	ctx context.Context,
+	executorType protocol.ExecutorType,
+	key string,
+) (bool, yaerrors.Error) {
+	ack, err := l.controlJob(ctx, executorType, key, protocol.JobActionResume)
+	if err != nil {
+		return false, err
+	}
+
+	return ack.Changed, nil
+}
+
+// TriggerNow runs the job addressed by key within the given executor type
+// once more, due immediately, and returns the execution it created; an
+// empty executor type addresses this scheduler's own. The regular schedule
+// is left as it is. A paused job answers ErrJobPaused and an absent one
+// ErrJobNotFound.
+func (l *Local) TriggerNow(
+	ctx context.Context,
+	executorType protocol.ExecutorType,
+	key string,
+) (protocol.ExecutionID, yaerrors.Error) {
+	ack, err := l.controlJob(ctx, executorType, key, protocol.JobActionTrigger)
+	if err != nil {
+		return 0, err
+	}
+
+	return ack.ExecutionID, nil
+}
+
+func (l *Local) controlJob(
+	ctx context.Context,
+	executorType protocol.ExecutorType,
+	key string,
+	action protocol.JobAction,
+) (*protocol.JobControlAck, yaerrors.Error) {
+	if executorType == "" {
+		executorType = l.cfg.ExecutorType
+	}
+
+	return checkControlAck(l.engine.HandleJobControl(ctx, l.cfg.InstanceID, &protocol.JobControl{
+		JobKey:       key,
+		ExecutorType: executorType,
+		Action:       action,
+	}))
+}
+
+// ListExecutions returns one page of the execution history of the job the
+// query addresses on the embedded engine, newest first; an empty executor
+// type addresses this scheduler's own. An absent job answers
+// ErrJobNotFound.
+func (l *Local) ListExecutions(
+	ctx context.Context,
+	query ExecutionQuery,
+) (*ExecutionPage, yaerrors.Error) {
+	if query.ExecutorType == "" {
+		query.ExecutorType = l.cfg.ExecutorType
+	}
+
+	return executionPageFromAck(
+		l.engine.HandleExecutionList(ctx, l.cfg.InstanceID, executionListFor(query)),
+	)
+}
+
+// ListAttempts returns every attempt of one execution on the embedded
+// engine in attempt-number order. An absent execution, including one
+// already purged by retention, answers ErrExecutionNotFound.
+func (l *Local) ListAttempts(
+	ctx context.Context,
+	executionID protocol.ExecutionID,
+) ([]*AttemptInfo, yaerrors.Error) {
+	return listAttemptPages(
+		executionID,
+		func(list *protocol.AttemptList) (*protocol.AttemptListAck, yaerrors.Error) {
+			return l.engine.HandleAttemptList(ctx, l.cfg.InstanceID, list), nil
+		},
+	)
+}
+
 // AnnounceLabels adds routing labels to the set this executor announces,
 // waking any job pinned to them.
 func (l *Local) AnnounceLabels(
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/YaCodeDev/GoYaCodeDevUtils/yaerrors"
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yalogger"
 	"github.com/YaCodeDev/GoYaCodeDevUtils/yascheduler"
 	"github.com/YaCodeDev/GoYaCodeDevUtils/yascheduler/engine"
 	"github.com/YaCodeDev/GoYaCodeDevUtils/yascheduler/protocol"
@@ -199,6 +200,62 @@ func TestLocalRunsJobEndToEnd(t *testing.T) {
 	running.stop(t)
 }
 
+func TestLocalCarriesTraceContextIntoExecutions(t *testing.T) {
+	t.Parallel()
+
+	const (
+		traceID     = "4bf92f3577b34da6a3ce929d0e0e4736"
+		traceParent = "00-" + traceID + "-00f067aa0ba902b7-01"
+	)
+
+	registry := yascheduler.NewRegistry()
+	got := make(chan map[string]any, 1)
+
+	registerLocalFunction(t, registry, func(ctx context.Context, value int64) (int64, error) {
+		got <- yalogger.TraceFields(ctx)
+
+		return value, nil
+	})
+
+	running := startLocal(t, &yascheduler.LocalConfig{
+		ExecutorType: localExecutorType,
+		Engine:       fastLocalEngine(),
+	}, registry)
+
+	ctx, cancel := context.WithTimeout(
+		yalogger.ContextWithTraceParent(context.Background(), traceParent),
+		localAwaitTimeout,
+	)
+	defer cancel()
+
+	submission, err := running.local.UpsertJob(ctx, &yascheduler.JobSpec{
+		Key:      "trace-context",
+		Function: protocol.FunctionSpec{Name: localFunctionName},
+		Args:     localArgValue,
+		Schedule: oneShotNow(),
+	})
+	if err != nil {
+		t.Fatalf("UpsertJob failed: %v", err)
+	}
+
+	submission.Close()
+
+	select {
+	case fields := <-got:
+		if fields[yalogger.KeyTraceID] != traceID {
+			t.Fatalf("execution trace_id = %v, want %s", fields[yalogger.KeyTraceID], traceID)
+		}
+
+		if fields[yalogger.KeySpanID] == "00f067aa0ba902b7" {
+			t.Fatal("execution reused the submitter span instead of starting its own")
+		}
+	case <-time.After(localExecuteTimeout):
+		t.Fatal("function never executed")
+	}
+
+	running.stop(t)
+}
+
 func TestLocalDrainsRunningFunctionOnStop(t *testing.T) {
 	t.Parallel()
 
@@ -462,6 +519,172 @@ func TestLocalDeleteJobWithdrawsPeriodicJob(t *testing.T) {
 	running.stop(t)
 }
 
+func TestLocalJobManagement(t *testing.T) {
+	t.Parallel()
+
+	registry := yascheduler.NewRegistry()
+
+	var calls atomic.Int64
+
+	registerLocalFunction(t, registry, func(_ context.Context, value int64) (int64, error) {
+		calls.Add(1)
+
+		return value, nil
+	})
+
+	running := startLocal(t, &yascheduler.LocalConfig{
+		ExecutorType: localExecutorType,
+		Engine:       fastLocalEngine(),
+	}, registry)
+
+	const jobKey = "local-managed"
+
+	start := time.Now().UTC().Add(time.Hour)
+
+	upsertLocalJob(t, running, &yascheduler.JobSpec{
+		Key:      jobKey,
+		Function: protocol.FunctionSpec{Name: localFunctionName},
+		Args:     localArgValue,
+		Schedule: protocol.ScheduleSpec{
+			Kind:           protocol.ScheduleKindFixedInterval,
+			StartUnixNano:  start.UnixNano(),
+			IntervalMillis: uint64(time.Hour / time.Millisecond),
+		},
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), localAwaitTimeout)
+	defer cancel()
+
+	job, err := running.local.GetJob(ctx, "", jobKey)
+	if err != nil {
+		t.Fatalf("GetJob failed: %v", err)
+	}
+
+	if job.Paused || !job.NextRunAt.Equal(start) || len(job.Args) == 0 {
+		t.Fatalf("the stored job should be enabled and due at its start: %+v", job)
+	}
+
+	jobs, err := running.local.ListJobs(ctx, "")
+	if err != nil || len(jobs) != 1 || jobs[0].Key != jobKey {
+		t.Fatalf("the stored job should be listed: %+v, %v", jobs, err)
+	}
+
+	if paused, pauseErr := running.local.PauseJob(ctx, "", jobKey); pauseErr != nil || !paused {
+		t.Fatalf("pausing the job should change it: %v, %v", paused, pauseErr)
+	}
+
+	if _, triggerErr := running.local.TriggerNow(ctx, "", jobKey); !errors.Is(
+		triggerErr,
+		yascheduler.ErrJobPaused,
+	) {
+		t.Fatalf("triggering a paused job should answer ErrJobPaused: %v", triggerErr)
+	}
+
+	if resumed, resumeErr := running.local.ResumeJob(ctx, "", jobKey); resumeErr != nil || !resumed {
+		t.Fatalf("resuming the job should change it: %v, %v", resumed, resumeErr)
+	}
+
+	if _, triggerErr := running.local.TriggerNow(ctx, "", jobKey); triggerErr != nil {
+		t.Fatalf("TriggerNow failed: %v", triggerErr)
+	}
+
+	deadline := time.Now().Add(localExecuteTimeout)
+
+	for calls.Load() < 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("the triggered occurrence should run")
+		}
+
+		time.Sleep(localPollInterval)
+	}
+
+	if _, getErr := running.local.GetJob(ctx, "", "local-missing"); !errors.Is(
+		getErr,
+		yascheduler.ErrJobNotFound,
+	) {
+		t.Fatalf("an absent job should answer ErrJobNotFound: %v", getErr)
+	}
+
+	running.stop(t)
+}
+
+func TestLocalExecutionHistory(t *testing.T) {
+	t.Parallel()
+
+	registry := yascheduler.NewRegistry()
+	failure := errors.New("history failure")
+
+	registerLocalFunction(t, registry, func(_ context.Context, _ int64) (int64, error) {
+		return 0, yascheduler.NonRetryable(failure)
+	})
+
+	running := startLocal(t, &yascheduler.LocalConfig{
+		ExecutorType: localExecutorType,
+		Engine:       fastLocalEngine(),
+	}, registry)
+
+	const jobKey = "local-history"
+
+	upsertLocalJob(t, running, &yascheduler.JobSpec{
+		Key:      jobKey,
+		Function: protocol.FunctionSpec{Name: localFunctionName},
+		Args:     localArgValue,
+		Schedule: oneShotNow(),
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), localExecuteTimeout)
+	defer cancel()
+
+	var execution *yascheduler.ExecutionInfo
+
+	for execution == nil || execution.State != protocol.ExecutionStateFailed {
+		if ctx.Err() != nil {
+			t.Fatalf("the execution should settle as failed: %+v", execution)
+		}
+
+		page, err := running.local.ListExecutions(ctx, yascheduler.ExecutionQuery{Key: jobKey})
+		if err != nil || len(page.Executions) != 1 || page.More {
+			t.Fatalf("the sole execution should be listed: %+v, %v", page, err)
+		}
+
+		execution = page.Executions[0]
+
+		time.Sleep(localPollInterval)
+	}
+
+	if execution.AttemptCount != 1 ||
+		execution.Instance != running.local.InstanceID() ||
+		execution.LastError != failure.Error() ||
+		execution.StartedAt.IsZero() || execution.FinishedAt.IsZero() {
+		t.Fatalf("the execution should report its failed attempt: %+v", execution)
+	}
+
+	attempts, err := running.local.ListAttempts(ctx, execution.ExecutionID)
+	if err != nil || len(attempts) != 1 {
+		t.Fatalf("the attempt should be listed: %+v, %v", attempts, err)
+	}
+
+	if attempts[0].Number != 1 || attempts[0].State != protocol.AttemptStateFunctionFailed {
+		t.Fatalf("the attempt should report its failure: %+v", attempts[0])
+	}
+
+	if _, listErr := running.local.ListExecutions(
+		ctx,
+		yascheduler.ExecutionQuery{Key: "local-missing"},
+	); !errors.Is(listErr, yascheduler.ErrJobNotFound) {
+		t.Fatalf("an absent job should answer ErrJobNotFound: %v", listErr)
+	}
+
+	if _, listErr := running.local.ListAttempts(
+		ctx,
+		execution.ExecutionID+1,
+	); !errors.Is(listErr, yascheduler.ErrExecutionNotFound) {
+		t.Fatalf("an absent execution should answer ErrExecutionNotFound: %v", listErr)
+	}
+
+	running.stop(t)
+}
+
 func TestLocalRequestResponseRoundTrip(t *testing.T) {
 	t.Parallel()
 
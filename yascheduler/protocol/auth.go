@@ -0,0 +1,38 @@
+package protocol
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+)
+
+// NewAuthNonce returns AuthNonceSize fresh random bytes for one
+// AuthChallenge.
+func NewAuthNonce() []byte {
+	nonce := make([]byte, AuthNonceSize)
+	_, _ = rand.Read(nonce) // crypto/rand.Read never fails; it aborts the process instead
+
+	return nonce
+}
+
+// AuthToken derives the token an executor answers an AuthChallenge with:
+// HMAC-SHA256 under secret over the length-prefixed nonce followed by the
+// marshalled Register payload. The token is therefore bound to one
+// challenge and to the exact registration it admits, so it can be replayed
+// neither on another connection nor with altered functions or labels.
+func AuthToken(secret, nonce []byte, register *Register) []byte {
+	w := newPayloadWriter()
+	w.writeBytes(nonce)
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(w.buf)
+	mac.Write(register.MarshalPayload())
+
+	return mac.Sum(nil)
+}
+
+// VerifyAuthToken reports, in constant time, whether token is the
+// AuthToken for secret, nonce and register.
+func VerifyAuthToken(secret, nonce []byte, register *Register, token []byte) bool {
+	return hmac.Equal(token, AuthToken(secret, nonce, register))
+}
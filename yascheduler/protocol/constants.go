@@ -14,11 +14,44 @@ const (
 	Version2 uint8 = 2
 
 	// Version3 adds job deletion: the JobDelete and JobDeleteAck message
-	// types.
+	// types. It is no longer spoken: it is kept named so a rejected
+	// version byte can be recognised.
 	Version3 uint8 = 3
 
+	// Version4 adds trace context: JobUpsert and ExecRequest end with the
+	// W3C traceparent of the trace the job was submitted in. It is no
+	// longer spoken: it is kept named so a rejected version byte can be
+	// recognised.
+	Version4 uint8 = 4
+
+	// Version5 adds cron schedules: ScheduleKindCron, and a schedule spec
+	// that ends with the cron expression and IANA time zone. It is no
+	// longer spoken: it is kept named so a rejected version byte can be
+	// recognised.
+	Version5 uint8 = 5
+
+	// Version6 adds registration authentication: the AuthChallenge and
+	// AuthResponse message types and ErrorCodeUnauthenticated. It is no
+	// longer spoken: it is kept named so a rejected version byte can be
+	// recognised.
+	Version6 uint8 = 6
+
+	// Version7 adds job management: the JobList, JobGet and JobControl
+	// message types with their acknowledgements, and
+	// ErrorCodeJobPaused. It is no longer spoken: it is kept named so a
+	// rejected version byte can be recognised.
+	Version7 uint8 = 7
+
+	// Version8 adds execution history: the ExecutionList and AttemptList
+	// message types with their acknowledgements.
+	Version8 uint8 = 8
+
 	// CurrentVersion is the protocol version this package speaks.
-	CurrentVersion = Version3
+	CurrentVersion = Version8
+
+	// AuthNonceSize is the byte length of the nonce an AuthChallenge
+	// carries.
+	AuthNonceSize = 32
 
 	// HeaderSize is the fixed byte length of an encoded frame header.
 	HeaderSize = 20
@@ -52,7 +85,7 @@ const (
 	DefaultMaxResultBytes uint32 = 1 << 16
 )
 
-// Message types of protocol version 3.
+// Message types of protocol version 8.
 const (
 	// MessageTypeRegister carries an executor registration request.
 	MessageTypeRegister MessageType = 1
@@ -111,6 +144,43 @@ const (
 
 	// MessageTypeJobDeleteAck answers a job delete.
 	MessageTypeJobDeleteAck MessageType = 18
+
+	// MessageTypeAuthChallenge asks a registering executor to prove it
+	// holds the shared secret.
+	MessageTypeAuthChallenge MessageType = 19
+
+	// MessageTypeAuthResponse answers an authentication challenge.
+	MessageTypeAuthResponse MessageType = 20
+
+	// MessageTypeJobList asks for one page of stored jobs.
+	MessageTypeJobList MessageType = 21
+
+	// MessageTypeJobListAck answers a job list.
+	MessageTypeJobListAck MessageType = 22
+
+	// MessageTypeJobGet asks for one stored job.
+	MessageTypeJobGet MessageType = 23
+
+	// MessageTypeJobGetAck answers a job get.
+	MessageTypeJobGetAck MessageType = 24
+
+	// MessageTypeJobControl pauses, resumes, or triggers one stored job.
+	MessageTypeJobControl MessageType = 25
+
+	// MessageTypeJobControlAck answers a job control.
+	MessageTypeJobControlAck MessageType = 26
+
+	// MessageTypeExecutionList asks for one page of a job's executions.
+	MessageTypeExecutionList MessageType = 27
+
+	// MessageTypeExecutionListAck answers an execution list.
+	MessageTypeExecutionListAck MessageType = 28
+
+	// MessageTypeAttemptList asks for one page of an execution's attempts.
+	MessageTypeAttemptList MessageType = 29
+
+	// MessageTypeAttemptListAck answers an attempt list.
+	MessageTypeAttemptListAck MessageType = 30
 )
 
 // Structured wire error codes.
@@ -181,6 +251,14 @@ const (
 	// ErrorCodeNoLabeledExecutor reports a job whose pin label matches no
 	// connected executor under PinPolicyStrict.
 	ErrorCodeNoLabeledExecutor ErrorCode = 17
+
+	// ErrorCodeUnauthenticated reports a connection refused because it
+	// failed the registration authentication challenge.
+	ErrorCodeUnauthenticated ErrorCode = 18
+
+	// ErrorCodeJobPaused reports a job control refused because the job it
+	// addresses is paused.
+	ErrorCodeJobPaused ErrorCode = 19
 )
 
 // Schedule kinds.
@@ -191,6 +269,10 @@ const (
 	// ScheduleKindFixedInterval runs a job every IntervalMillis starting
 	// at StartUnixNano.
 	ScheduleKindFixedInterval ScheduleKind = 2
+
+	// ScheduleKindCron runs a job at every wall-clock time CronExpression
+	// matches in TimeZone, from StartUnixNano on.
+	ScheduleKindCron ScheduleKind = 3
 )
 
 // Backfill modes.
@@ -266,6 +348,100 @@ const (
 	ResultModeDeliver ResultMode = 1
 )
 
+// Job control actions.
+const (
+	// JobActionPause disables a job; an occurrence that comes due while it
+	// is paused is cancelled.
+	JobActionPause JobAction = 1
+
+	// JobActionResume enables a paused job and schedules it again.
+	JobActionResume JobAction = 2
+
+	// JobActionTrigger runs a job once immediately, outside its schedule.
+	JobActionTrigger JobAction = 3
+)
+
+// Execution states, as execution history reports them. The values are
+// frozen wire values; the last four are terminal.
+const (
+	// ExecutionStateScheduled is an occurrence waiting for its scheduled
+	// time.
+	ExecutionStateScheduled ExecutionState = 1
+
+	// ExecutionStateReady is an occurrence whose time has come and which
+	// is waiting for a dispatch slot.
+	ExecutionStateReady ExecutionState = 2
+
+	// ExecutionStateWaitingExecutor is an occurrence held back because no
+	// executor of the job's type is connected.
+	ExecutionStateWaitingExecutor ExecutionState = 3
+
+	// ExecutionStateWaitingCompatible is an occurrence held back because
+	// no connected executor registers a compatible function.
+	ExecutionStateWaitingCompatible ExecutionState = 4
+
+	// ExecutionStateDispatching is an occurrence handed to an executor and
+	// awaiting its acceptance.
+	ExecutionStateDispatching ExecutionState = 5
+
+	// ExecutionStateRunning is an occurrence an executor accepted and is
+	// running.
+	ExecutionStateRunning ExecutionState = 6
+
+	// ExecutionStateRetryWait is an occurrence whose function failed and
+	// which is waiting out its retry delay.
+	ExecutionStateRetryWait ExecutionState = 7
+
+	// ExecutionStateSucceeded is an occurrence whose function returned
+	// without an error.
+	ExecutionStateSucceeded ExecutionState = 8
+
+	// ExecutionStateFailed is an occurrence that exhausted its retries.
+	ExecutionStateFailed ExecutionState = 9
+
+	// ExecutionStateCancelled is an occurrence cancelled before it
+	// settled.
+	ExecutionStateCancelled ExecutionState = 10
+
+	// ExecutionStateSkipped is an occurrence never dispatched, because
+	// backfill or the overlap policy dropped it.
+	ExecutionStateSkipped ExecutionState = 11
+
+	// ExecutionStateWaitingLabel is an occurrence held back because no
+	// connected executor announces the label its job pins to.
+	ExecutionStateWaitingLabel ExecutionState = 12
+)
+
+// Attempt states, as execution history reports them.
+const (
+	// AttemptStateDispatched is an attempt sent to an executor and
+	// awaiting its acceptance.
+	AttemptStateDispatched AttemptState = 1
+
+	// AttemptStateAccepted is an attempt the executor took responsibility
+	// for.
+	AttemptStateAccepted AttemptState = 2
+
+	// AttemptStateSucceeded is an attempt whose function returned without
+	// an error.
+	AttemptStateSucceeded AttemptState = 3
+
+	// AttemptStateFunctionFailed is an attempt whose function ran and
+	// returned an error.
+	AttemptStateFunctionFailed AttemptState = 4
+
+	// AttemptStateInfraFailed is an attempt that never ran because
+	// delivery or the executor itself failed.
+	AttemptStateInfraFailed AttemptState = 5
+
+	// AttemptStateLost is an attempt whose executor stopped reporting
+	// before the attempt settled.
+	AttemptStateLost AttemptState = 6
+
+	// AttemptStateCancelled is an attempt cancelled before it settled.
+	AttemptStateCancelled AttemptState = 7
+)
+
 // DefaultMaxRetries is the default number of function-error retries after
 // the initial execution.
 const DefaultMaxRetries uint32 = 3
@@ -292,6 +468,21 @@ const minFunctionSpecSize = 16
 // any label is allocated.
 const minLabelSize = 4
 
+// minJobInfoSize is the smallest wire size of one job info: the fixed-width
+// fields plus every length-prefixed field empty. A declared job count above
+// the remaining payload divided by this cannot be satisfied, so it is
+// rejected before any job info is allocated.
+const minJobInfoSize = 155
+
+// minExecutionInfoSize is the smallest wire size of one execution info,
+// and minAttemptInfoSize that of one attempt info: the fixed-width fields
+// plus every length-prefixed field empty. Declared counts are bounded by
+// them exactly as job info counts are by minJobInfoSize.
+const (
+	minExecutionInfoSize = 50
+	minAttemptInfoSize   = 37
+)
+
 // uuidSize is the wire width of a JobUUID.
 const uuidSize = 16
 
@@ -272,6 +272,41 @@ func TestLabelUpdateRejectsLabelCountBeyondPayload(t *testing.T) {
 	}
 }
 
+// TestListAcksRejectRecordCountBeyondPayload proves a job, execution or
+// attempt page cannot declare more records than its payload could hold.
+func TestListAcksRejectRecordCountBeyondPayload(t *testing.T) {
+	t.Parallel()
+
+	count := binary.BigEndian.AppendUint32(nil, hostileLabelCount)
+
+	cases := []struct {
+		name    string
+		ack     protocol.Message
+		payload []byte
+	}{
+		{name: "job list ack", ack: &protocol.JobListAck{}, payload: count},
+		{
+			name: "execution list ack",
+			ack:  &protocol.ExecutionListAck{},
+			// empty job key, found flag, then the count
+			payload: append([]byte{0, 0, 0, 0, 1}, count...),
+		},
+		{
+			name: "attempt list ack",
+			ack:  &protocol.AttemptListAck{},
+			// execution id, found flag, then the count
+			payload: append(make([]byte, 8), append([]byte{1}, count...)...),
+		},
+	}
+
+	for _, testCase := range cases {
+		err := testCase.ack.UnmarshalPayload(testCase.payload, protocol.Limits{})
+		if err == nil || !errors.Is(err, protocol.ErrShortBuffer) {
+			t.Fatalf("%s: err = %v, want ErrShortBuffer", testCase.name, err)
+		}
+	}
+}
+
 // TestDecodeMessageNestedLengthsStayBounded feeds every message type a
 // payload whose length prefixes all claim the maximum, and asserts each
 // decode fails cleanly rather than panicking or honouring the claim.
@@ -201,7 +201,9 @@ func (m *HeartbeatAck) UnmarshalPayload(payload []byte, limits Limits) yaerrors.
 // ExecRequest asks an executor to run one attempt of one execution.
 // DeliverResult reports whether the scheduler will hold the result of this
 // attempt for the caller that requested the job, so the executor knows the
-// result payload it returns is not discarded.
+// result payload it returns is not discarded. TraceParent is the trace
+// context the job was upserted with, so the execution continues that
+// trace.
 type ExecRequest struct {
 	JobUUID           JobUUID
 	ExecutionID       ExecutionID
@@ -212,6 +214,7 @@ type ExecRequest struct {
 	ScheduledUnixNano int64
 	TimeoutMillis     uint32
 	DeliverResult     bool
+	TraceParent       TraceParent
 }
 
 // Type implements Message.
@@ -229,6 +232,7 @@ func (m *ExecRequest) MarshalPayload() []byte {
 	w.writeInt64(m.ScheduledUnixNano)
 	w.writeUint32(m.TimeoutMillis)
 	w.writeBool(m.DeliverResult)
+	w.writeString(string(m.TraceParent))
 
 	return w.buf
 }
@@ -272,6 +276,13 @@ func (m *ExecRequest) UnmarshalPayload(payload []byte, limits Limits) yaerrors.E
 		return err.Wrap(logTag + " exec request: deliver result")
 	}
 
+	traceParent, err := r.readString()
+	if err != nil {
+		return err.Wrap(logTag + " exec request: trace parent")
+	}
+
+	m.TraceParent = TraceParent(traceParent)
+
 	return r.finish()
 }
 
@@ -443,7 +454,9 @@ func (m *Fault) UnmarshalPayload(payload []byte, limits Limits) yaerrors.Error {
 // JobUpsert creates or updates the job identified by the client-chosen
 // JobKey. Upserts with the same JobKey address the same job. JobUUID is
 // minted by the client, so the job carries one identity from the moment it
-// is described rather than only after the scheduler answers.
+// is described rather than only after the scheduler answers. TraceParent
+// is the trace context of the upserting caller; every execution of the job
+// carries it.
 type JobUpsert struct {
 	JobUUID      JobUUID
 	JobKey       string
@@ -457,6 +470,7 @@ type JobUpsert struct {
 	Overlap      OverlapPolicy
 	Pin          PinSpec
 	ResultMode   ResultMode
+	TraceParent  TraceParent
 }
 
 // Type implements Message.
@@ -470,22 +484,14 @@ func (m *JobUpsert) MarshalPayload() []byte {
 	w.writeString(string(m.ExecutorType))
 	encodeFunctionSpec(w, &m.Function)
 	w.writeBytes(m.Args)
-	w.writeUint8(uint8(m.Schedule.Kind))
-	w.writeInt64(m.Schedule.StartUnixNano)
-	w.writeUint64(m.Schedule.IntervalMillis)
+	encodeScheduleSpec(w, &m.Schedule)
 	w.writeBool(m.Enabled)
-	w.writeUint8(uint8(m.Backfill.Mode))
-	w.writeUint32(m.Backfill.MaxCount)
-	w.writeUint64(m.Backfill.MaxAgeMillis)
-	w.writeUint8(uint8(m.Retry.Policy))
-	w.writeUint32(m.Retry.MaxRetries)
-	w.writeUint64(m.Retry.InitialDelayMillis)
-	w.writeUint64(m.Retry.MaxDelayMillis)
-	w.writeUint64(m.Retry.MultiplierBits)
+	encodeBackfillSpec(w, &m.Backfill)
+	encodeRetrySpec(w, &m.Retry)
 	w.writeUint8(uint8(m.Overlap))
-	w.writeLabel(m.Pin.Label)
-	w.writeUint8(uint8(m.Pin.Policy))
+	encodePinSpec(w, &m.Pin)
 	w.writeUint8(uint8(m.ResultMode))
+	w.writeString(string(m.TraceParent))
 
 	return w.buf
 }
@@ -557,6 +563,13 @@ func (m *JobUpsert) UnmarshalPayload(payload []byte, limits Limits) yaerrors.Err
 
 	m.ResultMode = ResultMode(resultMode)
 
+	traceParent, err := r.readString()
+	if err != nil {
+		return err.Wrap(logTag + " job upsert: trace parent")
+	}
+
+	m.TraceParent = TraceParent(traceParent)
+
 	return r.finish()
 }
 
@@ -911,255 +924,1185 @@ func (m *JobDeleteAck) UnmarshalPayload(payload []byte, limits Limits) yaerrors.
 	return r.finish()
 }
 
-// DecodeMessage decodes payload into the typed message matching t.
-func DecodeMessage(t MessageType, payload []byte, limits Limits) (Message, yaerrors.Error) {
-	var msg Message
+// AuthChallenge answers a Register on a scheduler that requires
+// authentication, before the RegisterAck. Nonce is AuthNonceSize fresh
+// random bytes the executor must sign with AuthToken.
+type AuthChallenge struct {
+	Nonce []byte
+}
 
-	switch t {
-	case MessageTypeRegister:
-		msg = &Register{}
-	case MessageTypeRegisterAck:
-		msg = &RegisterAck{}
-	case MessageTypeHeartbeat:
-		msg = &Heartbeat{}
-	case MessageTypeHeartbeatAck:
-		msg = &HeartbeatAck{}
-	case MessageTypeExecRequest:
-		msg = &ExecRequest{}
-	case MessageTypeExecAccept:
-		msg = &ExecAccept{}
-	case MessageTypeExecResult:
-		msg = &ExecResult{}
-	case MessageTypeCancel:
-		msg = &Cancel{}
-	case MessageTypeProtocolError:
-		msg = &Fault{}
-	case MessageTypeJobUpsert:
-		msg = &JobUpsert{}
-	case MessageTypeJobUpsertAck:
-		msg = &JobUpsertAck{}
-	case MessageTypeShutdown:
-		msg = &Shutdown{}
-	case MessageTypeLabelUpdate:
-		msg = &LabelUpdate{}
-	case MessageTypeLabelUpdateAck:
-		msg = &LabelUpdateAck{}
-	case MessageTypeResultDelivery:
-		msg = &ResultDelivery{}
-	case MessageTypeResultDeliveryAck:
-		msg = &ResultDeliveryAck{}
-	case MessageTypeJobDelete:
-		msg = &JobDelete{}
-	case MessageTypeJobDeleteAck:
-		msg = &JobDeleteAck{}
-	default:
-		return nil, yaerrors.FromError(
-			http.StatusBadRequest,
-			ErrUnknownMessageType,
-			logTag+" decode message",
-		)
-	}
+// Type implements Message.
+func (m *AuthChallenge) Type() MessageType { return MessageTypeAuthChallenge }
 
-	if err := msg.UnmarshalPayload(payload, limits); err != nil {
-		return nil, err.Wrap(logTag + " decode message")
-	}
+// MarshalPayload implements Message.
+func (m *AuthChallenge) MarshalPayload() []byte {
+	w := newPayloadWriter()
+	w.writeBytes(m.Nonce)
 
-	return msg, nil
+	return w.buf
 }
 
-func encodeFunctionSpec(w *payloadWriter, f *FunctionSpec) {
-	w.writeString(string(f.Name))
-	w.writeString(string(f.Version))
-	w.writeString(f.InputSignature)
-	w.writeString(f.OutputSignature)
-}
+// UnmarshalPayload implements Message.
+func (m *AuthChallenge) UnmarshalPayload(payload []byte, limits Limits) yaerrors.Error {
+	r := newPayloadReader(payload, limits)
 
-func decodeFunctionSpec(r *payloadReader, f *FunctionSpec) yaerrors.Error {
-	name, err := r.readString()
+	nonce, err := r.readBytes()
 	if err != nil {
-		return err.Wrap(logTag + " function spec: name")
+		return err.Wrap(logTag + " auth challenge: nonce")
 	}
 
-	f.Name = FunctionName(name)
+	m.Nonce = nonce
 
-	version, err := r.readString()
-	if err != nil {
-		return err.Wrap(logTag + " function spec: version")
-	}
+	return r.finish()
+}
 
-	f.Version = FunctionVersion(version)
+// AuthResponse answers an AuthChallenge with the token AuthToken derives
+// from the shared secret, the challenge nonce and the pending Register.
+type AuthResponse struct {
+	Token []byte
+}
 
-	if f.InputSignature, err = r.readString(); err != nil {
-		return err.Wrap(logTag + " function spec: input signature")
-	}
+// Type implements Message.
+func (m *AuthResponse) Type() MessageType { return MessageTypeAuthResponse }
 
-	if f.OutputSignature, err = r.readString(); err != nil {
-		return err.Wrap(logTag + " function spec: output signature")
-	}
+// MarshalPayload implements Message.
+func (m *AuthResponse) MarshalPayload() []byte {
+	w := newPayloadWriter()
+	w.writeBytes(m.Token)
 
-	return nil
+	return w.buf
 }
 
-func encodeWireError(w *payloadWriter, e *WireError) {
-	w.writeUint16(uint16(e.Code))
-	w.writeBool(e.Retryable)
-	w.writeString(e.Message)
-}
+// UnmarshalPayload implements Message.
+func (m *AuthResponse) UnmarshalPayload(payload []byte, limits Limits) yaerrors.Error {
+	r := newPayloadReader(payload, limits)
 
-func decodeWireError(r *payloadReader, e *WireError) yaerrors.Error {
-	code, err := r.readUint16()
+	token, err := r.readBytes()
 	if err != nil {
-		return err.Wrap(logTag + " wire error: code")
+		return err.Wrap(logTag + " auth response: token")
 	}
 
-	e.Code = ErrorCode(code)
-
-	if e.Retryable, err = r.readBool(); err != nil {
-		return err.Wrap(logTag + " wire error: retryable")
-	}
+	m.Token = token
 
-	if e.Message, err = r.readString(); err != nil {
-		return err.Wrap(logTag + " wire error: message")
-	}
+	return r.finish()
+}
 
-	return nil
+// JobList asks for one page of stored jobs, enabled or not, in JobUUID
+// order: the jobs whose identifier sorts after After, so the zero
+// identifier starts from the beginning. An empty ExecutorType lists every
+// executor type. Limit caps the page; zero asks for the scheduler's
+// default, and the scheduler may cap it lower.
+type JobList struct {
+	ExecutorType ExecutorType
+	After        JobUUID
+	Limit        uint32
 }
 
-func encodeOptionalWireError(w *payloadWriter, e *WireError) {
-	if e == nil {
-		w.writeBool(false)
+// Type implements Message.
+func (m *JobList) Type() MessageType { return MessageTypeJobList }
 
-		return
-	}
+// MarshalPayload implements Message.
+func (m *JobList) MarshalPayload() []byte {
+	w := newPayloadWriter()
+	w.writeString(string(m.ExecutorType))
+	w.writeUUID(m.After)
+	w.writeUint32(m.Limit)
 
-	w.writeBool(true)
-	encodeWireError(w, e)
+	return w.buf
 }
 
-func decodeOptionalWireError(r *payloadReader, target **WireError) yaerrors.Error {
-	present, err := r.readBool()
+// UnmarshalPayload implements Message.
+func (m *JobList) UnmarshalPayload(payload []byte, limits Limits) yaerrors.Error {
+	r := newPayloadReader(payload, limits)
+
+	executorType, err := r.readString()
 	if err != nil {
-		return err.Wrap(logTag + " optional wire error: presence")
+		return err.Wrap(logTag + " job list: executor type")
 	}
 
-	if !present {
-		*target = nil
+	m.ExecutorType = ExecutorType(executorType)
 
-		return nil
+	after, err := r.readUUID()
+	if err != nil {
+		return err.Wrap(logTag + " job list: after")
 	}
 
-	var wireError WireError
+	m.After = after
 
-	if err = decodeWireError(r, &wireError); err != nil {
-		return err.Wrap(logTag + " optional wire error: value")
+	if m.Limit, err = r.readUint32(); err != nil {
+		return err.Wrap(logTag + " job list: limit")
 	}
 
-	*target = &wireError
+	return r.finish()
+}
 
-	return nil
+// JobListAck answers a JobList with one page of jobs. Their Args are left
+// empty so a page stays small; JobGet returns them. More reports that
+// further jobs follow the last one of the page.
+type JobListAck struct {
+	Jobs  []JobInfo
+	More  bool
+	Error *WireError
 }
 
-func decodeExecutionRef(
-	r *payloadReader,
-	executionID *ExecutionID,
-	attemptID *AttemptID,
-) yaerrors.Error {
-	rawExecutionID, err := r.readUint64()
-	if err != nil {
-		return err.Wrap(logTag + " execution ref: execution id")
-	}
+// Type implements Message.
+func (m *JobListAck) Type() MessageType { return MessageTypeJobListAck }
 
-	*executionID = ExecutionID(rawExecutionID)
+// MarshalPayload implements Message.
+func (m *JobListAck) MarshalPayload() []byte {
+	w := newPayloadWriter()
+	w.writeUint32(uint32(len(m.Jobs))) //nolint:gosec // bounded by MaxFrameSize at encode
 
-	rawAttemptID, err := r.readUint64()
-	if err != nil {
-		return err.Wrap(logTag + " execution ref: attempt id")
+	for index := range m.Jobs {
+		encodeJobInfo(w, &m.Jobs[index])
 	}
 
-	*attemptID = AttemptID(rawAttemptID)
+	w.writeBool(m.More)
+	encodeOptionalWireError(w, m.Error)
 
-	return nil
+	return w.buf
 }
 
-func decodeScheduleSpec(r *payloadReader, s *ScheduleSpec) yaerrors.Error {
-	kind, err := r.readUint8()
-	if err != nil {
-		return err.Wrap(logTag + " schedule spec: kind")
-	}
+// UnmarshalPayload implements Message.
+func (m *JobListAck) UnmarshalPayload(payload []byte, limits Limits) yaerrors.Error {
+	r := newPayloadReader(payload, limits)
 
-	s.Kind = ScheduleKind(kind)
+	var err yaerrors.Error
 
-	if s.StartUnixNano, err = r.readInt64(); err != nil {
-		return err.Wrap(logTag + " schedule spec: start")
+	if m.Jobs, err = decodeInfoList(r, minJobInfoSize, decodeJobInfo); err != nil {
+		return err.Wrap(logTag + " job list ack: jobs")
 	}
 
-	if s.IntervalMillis, err = r.readUint64(); err != nil {
-		return err.Wrap(logTag + " schedule spec: interval")
+	if m.More, err = r.readBool(); err != nil {
+		return err.Wrap(logTag + " job list ack: more")
 	}
 
-	return nil
-}
-
-func decodeBackfillSpec(r *payloadReader, b *BackfillSpec) yaerrors.Error {
-	mode, err := r.readUint8()
-	if err != nil {
-		return err.Wrap(logTag + " backfill spec: mode")
+	if err = decodeOptionalWireError(r, &m.Error); err != nil {
+		return err.Wrap(logTag + " job list ack: error")
 	}
 
-	b.Mode = BackfillMode(mode)
+	return r.finish()
+}
 
-	if b.MaxCount, err = r.readUint32(); err != nil {
-		return err.Wrap(logTag + " backfill spec: max count")
-	}
+// JobGet asks for the job addressed by the client-chosen JobKey within its
+// ExecutorType, the same scope a JobUpsert addresses.
+type JobGet struct {
+	JobKey       string
+	ExecutorType ExecutorType
+}
 
-	if b.MaxAgeMillis, err = r.readUint64(); err != nil {
-		return err.Wrap(logTag + " backfill spec: max age")
-	}
+// Type implements Message.
+func (m *JobGet) Type() MessageType { return MessageTypeJobGet }
 
-	return nil
+// MarshalPayload implements Message.
+func (m *JobGet) MarshalPayload() []byte {
+	w := newPayloadWriter()
+	w.writeString(m.JobKey)
+	w.writeString(string(m.ExecutorType))
+
+	return w.buf
 }
 
-func decodePinSpec(r *payloadReader, p *PinSpec) yaerrors.Error {
-	label, err := r.readLabel()
+// UnmarshalPayload implements Message.
+func (m *JobGet) UnmarshalPayload(payload []byte, limits Limits) yaerrors.Error {
+	r := newPayloadReader(payload, limits)
+
+	jobKey, err := r.readString()
 	if err != nil {
-		return err.Wrap(logTag + " pin spec: label")
+		return err.Wrap(logTag + " job get: job key")
 	}
 
-	p.Label = label
+	m.JobKey = jobKey
 
-	policy, err := r.readUint8()
+	executorType, err := r.readString()
 	if err != nil {
-		return err.Wrap(logTag + " pin spec: policy")
+		return err.Wrap(logTag + " job get: executor type")
 	}
 
-	p.Policy = PinPolicy(policy)
+	m.ExecutorType = ExecutorType(executorType)
 
-	return nil
+	return r.finish()
 }
 
-func decodeRetrySpec(r *payloadReader, s *RetrySpec) yaerrors.Error {
-	policy, err := r.readUint8()
-	if err != nil {
-		return err.Wrap(logTag + " retry spec: policy")
-	}
+// JobGetAck answers a JobGet, echoing the job key the request carried. Job
+// is nil with no Error when no such job is stored, so only a refused or
+// failed lookup carries one.
+type JobGetAck struct {
+	JobKey string
+	Job    *JobInfo
+	Error  *WireError
+}
 
-	s.Policy = RetryPolicy(policy)
+// Type implements Message.
+func (m *JobGetAck) Type() MessageType { return MessageTypeJobGetAck }
 
-	if s.MaxRetries, err = r.readUint32(); err != nil {
-		return err.Wrap(logTag + " retry spec: max retries")
-	}
+// MarshalPayload implements Message.
+func (m *JobGetAck) MarshalPayload() []byte {
+	w := newPayloadWriter()
+	w.writeString(m.JobKey)
 
-	if s.InitialDelayMillis, err = r.readUint64(); err != nil {
-		return err.Wrap(logTag + " retry spec: initial delay")
+	if m.Job == nil {
+		w.writeBool(false)
+	} else {
+		w.writeBool(true)
+		encodeJobInfo(w, m.Job)
 	}
 
-	if s.MaxDelayMillis, err = r.readUint64(); err != nil {
-		return err.Wrap(logTag + " retry spec: max delay")
-	}
+	encodeOptionalWireError(w, m.Error)
 
-	if s.MultiplierBits, err = r.readUint64(); err != nil {
-		return err.Wrap(logTag + " retry spec: multiplier")
+	return w.buf
+}
+
+// UnmarshalPayload implements Message.
+func (m *JobGetAck) UnmarshalPayload(payload []byte, limits Limits) yaerrors.Error {
+	r := newPayloadReader(payload, limits)
+
+	jobKey, err := r.readString()
+	if err != nil {
+		return err.Wrap(logTag + " job get ack: job key")
+	}
+
+	m.JobKey = jobKey
+
+	found, err := r.readBool()
+	if err != nil {
+		return err.Wrap(logTag + " job get ack: found")
+	}
+
+	m.Job = nil
+
+	if found {
+		var job JobInfo
+
+		if err = decodeJobInfo(r, &job); err != nil {
+			return err.Wrap(logTag + " job get ack: job")
+		}
+
+		m.Job = &job
+	}
+
+	if err = decodeOptionalWireError(r, &m.Error); err != nil {
+		return err.Wrap(logTag + " job get ack: error")
+	}
+
+	return r.finish()
+}
+
+// JobControl applies Action to the job addressed by JobKey within its
+// ExecutorType. Pausing a paused job and resuming an enabled one change
+// nothing and are not errors; triggering a paused job is refused with
+// ErrorCodeJobPaused.
+type JobControl struct {
+	JobKey       string
+	ExecutorType ExecutorType
+	Action       JobAction
+}
+
+// Type implements Message.
+func (m *JobControl) Type() MessageType { return MessageTypeJobControl }
+
+// MarshalPayload implements Message.
+func (m *JobControl) MarshalPayload() []byte {
+	w := newPayloadWriter()
+	w.writeString(m.JobKey)
+	w.writeString(string(m.ExecutorType))
+	w.writeUint8(uint8(m.Action))
+
+	return w.buf
+}
+
+// UnmarshalPayload implements Message.
+func (m *JobControl) UnmarshalPayload(payload []byte, limits Limits) yaerrors.Error {
+	r := newPayloadReader(payload, limits)
+
+	jobKey, err := r.readString()
+	if err != nil {
+		return err.Wrap(logTag + " job control: job key")
+	}
+
+	m.JobKey = jobKey
+
+	executorType, err := r.readString()
+	if err != nil {
+		return err.Wrap(logTag + " job control: executor type")
+	}
+
+	m.ExecutorType = ExecutorType(executorType)
+
+	action, err := r.readUint8()
+	if err != nil {
+		return err.Wrap(logTag + " job control: action")
+	}
+
+	m.Action = JobAction(action)
+
+	return r.finish()
+}
+
+// JobControlAck answers a JobControl, echoing the job key and action the
+// request carried. Found reports whether the job exists; an absent job
+// answers false with no Error. Changed reports whether a pause or resume
+// flipped the job's state. ExecutionID names the execution a trigger
+// created.
+type JobControlAck struct {
+	JobKey      string
+	Action      JobAction
+	Found       bool
+	Changed     bool
+	ExecutionID ExecutionID
+	Error       *WireError
+}
+
+// Type implements Message.
+func (m *JobControlAck) Type() MessageType { return MessageTypeJobControlAck }
+
+// MarshalPayload implements Message.
+func (m *JobControlAck) MarshalPayload() []byte {
+	w := newPayloadWriter()
+	w.writeString(m.JobKey)
+	w.writeUint8(uint8(m.Action))
+	w.writeBool(m.Found)
+	w.writeBool(m.Changed)
+	w.writeUint64(uint64(m.ExecutionID))
+	encodeOptionalWireError(w, m.Error)
+
+	return w.buf
+}
+
+// UnmarshalPayload implements Message.
+func (m *JobControlAck) UnmarshalPayload(payload []byte, limits Limits) yaerrors.Error {
+	r := newPayloadReader(payload, limits)
+
+	jobKey, err := r.readString()
+	if err != nil {
+		return err.Wrap(logTag + " job control ack: job key")
+	}
+
+	m.JobKey = jobKey
+
+	action, err := r.readUint8()
+	if err != nil {
+		return err.Wrap(logTag + " job control ack: action")
+	}
+
+	m.Action = JobAction(action)
+
+	if m.Found, err = r.readBool(); err != nil {
+		return err.Wrap(logTag + " job control ack: found")
+	}
+
+	if m.Changed, err = r.readBool(); err != nil {
+		return err.Wrap(logTag + " job control ack: changed")
+	}
+
+	executionID, err := r.readUint64()
+	if err != nil {
+		return err.Wrap(logTag + " job control ack: execution id")
+	}
+
+	m.ExecutionID = ExecutionID(executionID)
+
+	if err = decodeOptionalWireError(r, &m.Error); err != nil {
+		return err.Wrap(logTag + " job control ack: error")
+	}
+
+	return r.finish()
+}
+
+// ExecutionList asks for one page of the executions of the job addressed
+// by JobKey within its ExecutorType, newest first: the executions whose
+// identifier sorts before Before, so the zero identifier starts from the
+// newest. Limit caps the page; zero asks for the scheduler's default, and
+// the scheduler may cap it lower. Executions are kept until the
+// scheduler's execution retention purges them.
+type ExecutionList struct {
+	JobKey       string
+	ExecutorType ExecutorType
+	Before       ExecutionID
+	Limit        uint32
+}
+
+// Type implements Message.
+func (m *ExecutionList) Type() MessageType { return MessageTypeExecutionList }
+
+// MarshalPayload implements Message.
+func (m *ExecutionList) MarshalPayload() []byte {
+	w := newPayloadWriter()
+	w.writeString(m.JobKey)
+	w.writeString(string(m.ExecutorType))
+	w.writeUint64(uint64(m.Before))
+	w.writeUint32(m.Limit)
+
+	return w.buf
+}
+
+// UnmarshalPayload implements Message.
+func (m *ExecutionList) UnmarshalPayload(payload []byte, limits Limits) yaerrors.Error {
+	r := newPayloadReader(payload, limits)
+
+	jobKey, err := r.readString()
+	if err != nil {
+		return err.Wrap(logTag + " execution list: job key")
+	}
+
+	m.JobKey = jobKey
+
+	executorType, err := r.readString()
+	if err != nil {
+		return err.Wrap(logTag + " execution list: executor type")
+	}
+
+	m.ExecutorType = ExecutorType(executorType)
+
+	before, err := r.readUint64()
+	if err != nil {
+		return err.Wrap(logTag + " execution list: before")
+	}
+
+	m.Before = ExecutionID(before)
+
+	if m.Limit, err = r.readUint32(); err != nil {
+		return err.Wrap(logTag + " execution list: limit")
+	}
+
+	return r.finish()
+}
+
+// ExecutionListAck answers an ExecutionList with one page of executions,
+// echoing the job key the request carried. Found is false with no Error
+// when no such job is stored. More reports that older executions follow
+// the last one of the page.
+type ExecutionListAck struct {
+	JobKey     string
+	Found      bool
+	Executions []ExecutionInfo
+	More       bool
+	Error      *WireError
+}
+
+// Type implements Message.
+func (m *ExecutionListAck) Type() MessageType { return MessageTypeExecutionListAck }
+
+// MarshalPayload implements Message.
+func (m *ExecutionListAck) MarshalPayload() []byte {
+	w := newPayloadWriter()
+	w.writeString(m.JobKey)
+	w.writeBool(m.Found)
+	w.writeUint32(uint32(len(m.Executions))) //nolint:gosec // bounded by MaxFrameSize at encode
+
+	for index := range m.Executions {
+		encodeExecutionInfo(w, &m.Executions[index])
+	}
+
+	w.writeBool(m.More)
+	encodeOptionalWireError(w, m.Error)
+
+	return w.buf
+}
+
+// UnmarshalPayload implements Message.
+func (m *ExecutionListAck) UnmarshalPayload(payload []byte, limits Limits) yaerrors.Error {
+	r := newPayloadReader(payload, limits)
+
+	jobKey, err := r.readString()
+	if err != nil {
+		return err.Wrap(logTag + " execution list ack: job key")
+	}
+
+	m.JobKey = jobKey
+
+	if m.Found, err = r.readBool(); err != nil {
+		return err.Wrap(logTag + " execution list ack: found")
+	}
+
+	if m.Executions, err = decodeInfoList(r, minExecutionInfoSize, decodeExecutionInfo); err != nil {
+		return err.Wrap(logTag + " execution list ack: executions")
+	}
+
+	if m.More, err = r.readBool(); err != nil {
+		return err.Wrap(logTag + " execution list ack: more")
+	}
+
+	if err = decodeOptionalWireError(r, &m.Error); err != nil {
+		return err.Wrap(logTag + " execution list ack: error")
+	}
+
+	return r.finish()
+}
+
+// AttemptList asks for one page of the attempts of one execution, in
+// attempt-number order: the attempts numbered after After, so zero starts
+// from the first. Limit caps the page; zero asks for the scheduler's
+// default, and the scheduler may cap it lower.
+type AttemptList struct {
+	ExecutionID ExecutionID
+	After       uint32
+	Limit       uint32
+}
+
+// Type implements Message.
+func (m *AttemptList) Type() MessageType { return MessageTypeAttemptList }
+
+// MarshalPayload implements Message.
+func (m *AttemptList) MarshalPayload() []byte {
+	w := newPayloadWriter()
+	w.writeUint64(uint64(m.ExecutionID))
+	w.writeUint32(m.After)
+	w.writeUint32(m.Limit)
+
+	return w.buf
+}
+
+// UnmarshalPayload implements Message.
+func (m *AttemptList) UnmarshalPayload(payload []byte, limits Limits) yaerrors.Error {
+	r := newPayloadReader(payload, limits)
+
+	executionID, err := r.readUint64()
+	if err != nil {
+		return err.Wrap(logTag + " attempt list: execution id")
+	}
+
+	m.ExecutionID = ExecutionID(executionID)
+
+	if m.After, err = r.readUint32(); err != nil {
+		return err.Wrap(logTag + " attempt list: after")
+	}
+
+	if m.Limit, err = r.readUint32(); err != nil {
+		return err.Wrap(logTag + " attempt list: limit")
+	}
+
+	return r.finish()
+}
+
+// AttemptListAck answers an AttemptList with one page of attempts,
+// echoing the execution ID the request carried. Found is false with no
+// Error when no such execution is stored. More reports that further
+// attempts follow the last one of the page.
+type AttemptListAck struct {
+	ExecutionID ExecutionID
+	Found       bool
+	Attempts    []AttemptInfo
+	More        bool
+	Error       *WireError
+}
+
+// Type implements Message.
+func (m *AttemptListAck) Type() MessageType { return MessageTypeAttemptListAck }
+
+// MarshalPayload implements Message.
+func (m *AttemptListAck) MarshalPayload() []byte {
+	w := newPayloadWriter()
+	w.writeUint64(uint64(m.ExecutionID))
+	w.writeBool(m.Found)
+	w.writeUint32(uint32(len(m.Attempts))) //nolint:gosec // bounded by MaxFrameSize at encode
+
+	for index := range m.Attempts {
+		encodeAttemptInfo(w, &m.Attempts[index])
+	}
+
+	w.writeBool(m.More)
+	encodeOptionalWireError(w, m.Error)
+
+	return w.buf
+}
+
+// UnmarshalPayload implements Message.
+func (m *AttemptListAck) UnmarshalPayload(payload []byte, limits Limits) yaerrors.Error {
+	r := newPayloadReader(payload, limits)
+
+	executionID, err := r.readUint64()
+	if err != nil {
+		return err.Wrap(logTag + " attempt list ack: execution id")
+	}
+
+	m.ExecutionID = ExecutionID(executionID)
+
+	if m.Found, err = r.readBool(); err != nil {
+		return err.Wrap(logTag + " attempt list ack: found")
+	}
+
+	if m.Attempts, err = decodeInfoList(r, minAttemptInfoSize, decodeAttemptInfo); err != nil {
+		return err.Wrap(logTag + " attempt list ack: attempts")
+	}
+
+	if m.More, err = r.readBool(); err != nil {
+		return err.Wrap(logTag + " attempt list ack: more")
+	}
+
+	if err = decodeOptionalWireError(r, &m.Error); err != nil {
+		return err.Wrap(logTag + " attempt list ack: error")
+	}
+
+	return r.finish()
+}
+
+// DecodeMessage decodes payload into the typed message matching t.
+func DecodeMessage(t MessageType, payload []byte, limits Limits) (Message, yaerrors.Error) {
+	var msg Message
+
+	switch t {
+	case MessageTypeRegister:
+		msg = &Register{}
+	case MessageTypeRegisterAck:
+		msg = &RegisterAck{}
+	case MessageTypeHeartbeat:
+		msg = &Heartbeat{}
+	case MessageTypeHeartbeatAck:
+		msg = &HeartbeatAck{}
+	case MessageTypeExecRequest:
+		msg = &ExecRequest{}
+	case MessageTypeExecAccept:
+		msg = &ExecAccept{}
+	case MessageTypeExecResult:
+		msg = &ExecResult{}
+	case MessageTypeCancel:
+		msg = &Cancel{}
+	case MessageTypeProtocolError:
+		msg = &Fault{}
+	case MessageTypeJobUpsert:
+		msg = &JobUpsert{}
+	case MessageTypeJobUpsertAck:
+		msg = &JobUpsertAck{}
+	case MessageTypeShutdown:
+		msg = &Shutdown{}
+	case MessageTypeLabelUpdate:
+		msg = &LabelUpdate{}
+	case MessageTypeLabelUpdateAck:
+		msg = &LabelUpdateAck{}
+	case MessageTypeResultDelivery:
+		msg = &ResultDelivery{}
+	case MessageTypeResultDeliveryAck:
+		msg = &ResultDeliveryAck{}
+	case MessageTypeJobDelete:
+		msg = &JobDelete{}
+	case MessageTypeJobDeleteAck:
+		msg = &JobDeleteAck{}
+	case MessageTypeAuthChallenge:
+		msg = &AuthChallenge{}
+	case MessageTypeAuthResponse:
+		msg = &AuthResponse{}
+	case MessageTypeJobList:
+		msg = &JobList{}
+	case MessageTypeJobListAck:
+		msg = &JobListAck{}
+	case MessageTypeJobGet:
+		msg = &JobGet{}
+	case MessageTypeJobGetAck:
+		msg = &JobGetAck{}
+	case MessageTypeJobControl:
+		msg = &JobControl{}
+	case MessageTypeJobControlAck:
+		msg = &JobControlAck{}
+	case MessageTypeExecutionList:
+		msg = &ExecutionList{}
+	case MessageTypeExecutionListAck:
+		msg = &ExecutionListAck{}
+	case MessageTypeAttemptList:
+		msg = &AttemptList{}
+	case MessageTypeAttemptListAck:
+		msg = &AttemptListAck{}
+	default:
+		return nil, yaerrors.FromError(
+			http.StatusBadRequest,
+			ErrUnknownMessageType,
+			logTag+" decode message",
+		)
+	}
+
+	if err := msg.UnmarshalPayload(payload, limits); err != nil {
+		return nil, err.Wrap(logTag + " decode message")
+	}
+
+	return msg, nil
+}
+
+func encodeFunctionSpec(w *payloadWriter, f *FunctionSpec) {
+	w.writeString(string(f.Name))
+	w.writeString(string(f.Version))
+	w.writeString(f.InputSignature)
+	w.writeString(f.OutputSignature)
+}
+
+func decodeFunctionSpec(r *payloadReader, f *FunctionSpec) yaerrors.Error {
+	name, err := r.readString()
+	if err != nil {
+		return err.Wrap(logTag + " function spec: name")
+	}
+
+	f.Name = FunctionName(name)
+
+	version, err := r.readString()
+	if err != nil {
+		return err.Wrap(logTag + " function spec: version")
+	}
+
+	f.Version = FunctionVersion(version)
+
+	if f.InputSignature, err = r.readString(); err != nil {
+		return err.Wrap(logTag + " function spec: input signature")
+	}
+
+	if f.OutputSignature, err = r.readString(); err != nil {
+		return err.Wrap(logTag + " function spec: output signature")
+	}
+
+	return nil
+}
+
+func encodeWireError(w *payloadWriter, e *WireError) {
+	w.writeUint16(uint16(e.Code))
+	w.writeBool(e.Retryable)
+	w.writeString(e.Message)
+}
+
+func decodeWireError(r *payloadReader, e *WireError) yaerrors.Error {
+	code, err := r.readUint16()
+	if err != nil {
+		return err.Wrap(logTag + " wire error: code")
+	}
+
+	e.Code = ErrorCode(code)
+
+	if e.Retryable, err = r.readBool(); err != nil {
+		return err.Wrap(logTag + " wire error: retryable")
+	}
+
+	if e.Message, err = r.readString(); err != nil {
+		return err.Wrap(logTag + " wire error: message")
+	}
+
+	return nil
+}
+
+func encodeOptionalWireError(w *payloadWriter, e *WireError) {
+	if e == nil {
+		w.writeBool(false)
+
+		return
+	}
+
+	w.writeBool(true)
+	encodeWireError(w, e)
+}
+
+func decodeOptionalWireError(r *payloadReader, target **WireError) yaerrors.Error {
+	present, err := r.readBool()
+	if err != nil {
+		return err.Wrap(logTag + " optional wire error: presence")
+	}
+
+	if !present {
+		*target = nil
+
+		return nil
+	}
+
+	var wireError WireError
+
+	if err = decodeWireError(r, &wireError); err != nil {
+		return err.Wrap(logTag + " optional wire error: value")
+	}
+
+	*target = &wireError
+
+	return nil
+}
+
+func decodeExecutionRef(
+	r *payloadReader,
+	executionID *ExecutionID,
+	attemptID *AttemptID,
+) yaerrors.Error {
+	rawExecutionID, err := r.readUint64()
+	if err != nil {
+		return err.Wrap(logTag + " execution ref: execution id")
+	}
+
+	*executionID = ExecutionID(rawExecutionID)
+
+	rawAttemptID, err := r.readUint64()
+	if err != nil {
+		return err.Wrap(logTag + " execution ref: attempt id")
+	}
+
+	*attemptID = AttemptID(rawAttemptID)
+
+	return nil
+}
+
+func encodeScheduleSpec(w *payloadWriter, s *ScheduleSpec) {
+	w.writeUint8(uint8(s.Kind))
+	w.writeInt64(s.StartUnixNano)
+	w.writeUint64(s.IntervalMillis)
+	w.writeString(s.CronExpression)
+	w.writeString(s.TimeZone)
+}
+
+func decodeScheduleSpec(r *payloadReader, s *ScheduleSpec) yaerrors.Error {
+	kind, err := r.readUint8()
+	if err != nil {
+		return err.Wrap(logTag + " schedule spec: kind")
+	}
+
+	s.Kind = ScheduleKind(kind)
+
+	if s.StartUnixNano, err = r.readInt64(); err != nil {
+		return err.Wrap(logTag + " schedule spec: start")
+	}
+
+	if s.IntervalMillis, err = r.readUint64(); err != nil {
+		return err.Wrap(logTag + " schedule spec: interval")
+	}
+
+	if s.CronExpression, err = r.readString(); err != nil {
+		return err.Wrap(logTag + " schedule spec: cron expression")
+	}
+
+	if s.TimeZone, err = r.readString(); err != nil {
+		return err.Wrap(logTag + " schedule spec: time zone")
+	}
+
+	return nil
+}
+
+func encodeBackfillSpec(w *payloadWriter, b *BackfillSpec) {
+	w.writeUint8(uint8(b.Mode))
+	w.writeUint32(b.MaxCount)
+	w.writeUint64(b.MaxAgeMillis)
+}
+
+func decodeBackfillSpec(r *payloadReader, b *BackfillSpec) yaerrors.Error {
+	mode, err := r.readUint8()
+	if err != nil {
+		return err.Wrap(logTag + " backfill spec: mode")
+	}
+
+	b.Mode = BackfillMode(mode)
+
+	if b.MaxCount, err = r.readUint32(); err != nil {
+		return err.Wrap(logTag + " backfill spec: max count")
+	}
+
+	if b.MaxAgeMillis, err = r.readUint64(); err != nil {
+		return err.Wrap(logTag + " backfill spec: max age")
+	}
+
+	return nil
+}
+
+func encodePinSpec(w *payloadWriter, p *PinSpec) {
+	w.writeLabel(p.Label)
+	w.writeUint8(uint8(p.Policy))
+}
+
+func decodePinSpec(r *payloadReader, p *PinSpec) yaerrors.Error {
+	label, err := r.readLabel()
+	if err != nil {
+		return err.Wrap(logTag + " pin spec: label")
+	}
+
+	p.Label = label
+
+	policy, err := r.readUint8()
+	if err != nil {
+		return err.Wrap(logTag + " pin spec: policy")
+	}
+
+	p.Policy = PinPolicy(policy)
+
+	return nil
+}
+
+func encodeRetrySpec(w *payloadWriter, s *RetrySpec) {
+	w.writeUint8(uint8(s.Policy))
+	w.writeUint32(s.MaxRetries)
+	w.writeUint64(s.InitialDelayMillis)
+	w.writeUint64(s.MaxDelayMillis)
+	w.writeUint64(s.MultiplierBits)
+}
+
+func decodeRetrySpec(r *payloadReader, s *RetrySpec) yaerrors.Error {
+	policy, err := r.readUint8()
+	if err != nil {
+		return err.Wrap(logTag + " retry spec: policy")
+	}
+
+	s.Policy = RetryPolicy(policy)
+
+	if s.MaxRetries, err = r.readUint32(); err != nil {
+		return err.Wrap(logTag + " retry spec: max retries")
+	}
+
+	if s.InitialDelayMillis, err = r.readUint64(); err != nil {
+		return err.Wrap(logTag + " retry spec: initial delay")
+	}
+
+	if s.MaxDelayMillis, err = r.readUint64(); err != nil {
+		return err.Wrap(logTag + " retry spec: max delay")
+	}
+
+	if s.MultiplierBits, err = r.readUint64(); err != nil {
+		return err.Wrap(logTag + " retry spec: multiplier")
+	}
+
+	return nil
+}
+
+func encodeJobInfo(w *payloadWriter, j *JobInfo) {
+	w.writeUUID(j.JobUUID)
+	w.writeString(j.JobKey)
+	w.writeString(string(j.ExecutorType))
+	encodeFunctionSpec(w, &j.Function)
+	w.writeBytes(j.Args)
+	encodeScheduleSpec(w, &j.Schedule)
+	w.writeBool(j.Enabled)
+	encodeBackfillSpec(w, &j.Backfill)
+	encodeRetrySpec(w, &j.Retry)
+	w.writeUint8(uint8(j.Overlap))
+	encodePinSpec(w, &j.Pin)
+	w.writeUint8(uint8(j.ResultMode))
+	w.writeString(string(j.SubmitterInstanceID))
+	w.writeUint64(j.SkippedOccurrences)
+	w.writeInt64(j.NextRunUnixNano)
+	w.writeInt64(j.CreatedUnixNano)
+	w.writeInt64(j.UpdatedUnixNano)
+}
+
+func decodeJobInfo(r *payloadReader, j *JobInfo) yaerrors.Error {
+	jobUUID, err := r.readUUID()
+	if err != nil {
+		return err.Wrap(logTag + " job info: job uuid")
+	}
+
+	j.JobUUID = jobUUID
+
+	if j.JobKey, err = r.readString(); err != nil {
+		return err.Wrap(logTag + " job info: job key")
+	}
+
+	executorType, err := r.readString()
+	if err != nil {
+		return err.Wrap(logTag + " job info: executor type")
+	}
+
+	j.ExecutorType = ExecutorType(executorType)
+
+	if err = decodeFunctionSpec(r, &j.Function); err != nil {
+		return err.Wrap(logTag + " job info: function spec")
+	}
+
+	if j.Args, err = r.readBytes(); err != nil {
+		return err.Wrap(logTag + " job info: args")
+	}
+
+	if err = decodeScheduleSpec(r, &j.Schedule); err != nil {
+		return err.Wrap(logTag + " job info: schedule")
+	}
+
+	if j.Enabled, err = r.readBool(); err != nil {
+		return err.Wrap(logTag + " job info: enabled")
+	}
+
+	if err = decodeBackfillSpec(r, &j.Backfill); err != nil {
+		return err.Wrap(logTag + " job info: backfill")
+	}
+
+	if err = decodeRetrySpec(r, &j.Retry); err != nil {
+		return err.Wrap(logTag + " job info: retry")
+	}
+
+	overlap, err := r.readUint8()
+	if err != nil {
+		return err.Wrap(logTag + " job info: overlap")
+	}
+
+	j.Overlap = OverlapPolicy(overlap)
+
+	if err = decodePinSpec(r, &j.Pin); err != nil {
+		return err.Wrap(logTag + " job info: pin")
+	}
+
+	resultMode, err := r.readUint8()
+	if err != nil {
+		return err.Wrap(logTag + " job info: result mode")
+	}
+
+	j.ResultMode = ResultMode(resultMode)
+
+	submitter, err := r.readString()
+	if err != nil {
+		return err.Wrap(logTag + " job info: submitter instance id")
+	}
+
+	j.SubmitterInstanceID = InstanceID(submitter)
+
+	if j.SkippedOccurrences, err = r.readUint64(); err != nil {
+		return err.Wrap(logTag + " job info: skipped occurrences")
+	}
+
+	if j.NextRunUnixNano, err = r.readInt64(); err != nil {
+		return err.Wrap(logTag + " job info: next run")
+	}
+
+	if j.CreatedUnixNano, err = r.readInt64(); err != nil {
+		return err.Wrap(logTag + " job info: created")
+	}
+
+	if j.UpdatedUnixNano, err = r.readInt64(); err != nil {
+		return err.Wrap(logTag + " job info: updated")
+	}
+
+	return nil
+}
+
+func encodeExecutionInfo(w *payloadWriter, e *ExecutionInfo) {
+	w.writeUint64(uint64(e.ExecutionID))
+	w.writeInt64(e.ScheduledUnixNano)
+	w.writeUint8(uint8(e.State))
+	w.writeBool(e.Backfilled)
+	w.writeUint32(e.AttemptCount)
+	w.writeString(string(e.InstanceID))
+	w.writeInt64(e.StartedUnixNano)
+	w.writeInt64(e.FinishedUnixNano)
+	w.writeString(e.LastError)
+	w.writeString(e.WaitReason)
+}
+
+func decodeExecutionInfo(r *payloadReader, e *ExecutionInfo) yaerrors.Error {
+	executionID, err := r.readUint64()
+	if err != nil {
+		return err.Wrap(logTag + " execution info: execution id")
+	}
+
+	e.ExecutionID = ExecutionID(executionID)
+
+	if e.ScheduledUnixNano, err = r.readInt64(); err != nil {
+		return err.Wrap(logTag + " execution info: scheduled")
+	}
+
+	state, err := r.readUint8()
+	if err != nil {
+		return err.Wrap(logTag + " execution info: state")
+	}
+
+	e.State = ExecutionState(state)
+
+	if e.Backfilled, err = r.readBool(); err != nil {
+		return err.Wrap(logTag + " execution info: backfilled")
+	}
+
+	if e.AttemptCount, err = r.readUint32(); err != nil {
+		return err.Wrap(logTag + " execution info: attempt count")
+	}
+
+	instanceID, err := r.readString()
+	if err != nil {
+		return err.Wrap(logTag + " execution info: instance id")
+	}
+
+	e.InstanceID = InstanceID(instanceID)
+
+	if e.StartedUnixNano, err = r.readInt64(); err != nil {
+		return err.Wrap(logTag + " execution info: started")
+	}
+
+	if e.FinishedUnixNano, err = r.readInt64(); err != nil {
+		return err.Wrap(logTag + " execution info: finished")
+	}
+
+	if e.LastError, err = r.readString(); err != nil {
+		return err.Wrap(logTag + " execution info: last error")
+	}
+
+	if e.WaitReason, err = r.readString(); err != nil {
+		return err.Wrap(logTag + " execution info: wait reason")
+	}
+
+	return nil
+}
+
+func encodeAttemptInfo(w *payloadWriter, a *AttemptInfo) {
+	w.writeUint64(uint64(a.AttemptID))
+	w.writeUint32(a.Number)
+	w.writeString(string(a.InstanceID))
+	w.writeUint8(uint8(a.State))
+	w.writeString(a.Error)
+	w.writeInt64(a.DispatchedUnixNano)
+	w.writeInt64(a.UpdatedUnixNano)
+}
+
+func decodeAttemptInfo(r *payloadReader, a *AttemptInfo) yaerrors.Error {
+	attemptID, err := r.readUint64()
+	if err != nil {
+		return err.Wrap(logTag + " attempt info: attempt id")
+	}
+
+	a.AttemptID = AttemptID(attemptID)
+
+	if a.Number, err = r.readUint32(); err != nil {
+		return err.Wrap(logTag + " attempt info: number")
+	}
+
+	instanceID, err := r.readString()
+	if err != nil {
+		return err.Wrap(logTag + " attempt info: instance id")
+	}
+
+	a.InstanceID = InstanceID(instanceID)
+
+	state, err := r.readUint8()
+	if err != nil {
+		return err.Wrap(logTag + " attempt info: state")
+	}
+
+	a.State = AttemptState(state)
+
+	if a.Error, err = r.readString(); err != nil {
+		return err.Wrap(logTag + " attempt info: error")
+	}
+
+	if a.DispatchedUnixNano, err = r.readInt64(); err != nil {
+		return err.Wrap(logTag + " attempt info: dispatched")
+	}
+
+	if a.UpdatedUnixNano, err = r.readInt64(); err != nil {
+		return err.Wrap(logTag + " attempt info: updated")
 	}
 
 	return nil
 }
+
+// decodeInfoList reads a length-prefixed list of fixed-layout records. The
+// declared count is rejected against the bytes left in the payload, given
+// the smallest encoding one record can have, before any record is
+// allocated, so a count a peer declares can never drive an allocation the
+// payload cannot justify.
+func decodeInfoList[T any](
+	r *payloadReader,
+	minSize int,
+	decode func(r *payloadReader, record *T) yaerrors.Error,
+) ([]T, yaerrors.Error) {
+	count, err := r.readUint32()
+	if err != nil {
+		return nil, err.Wrap(logTag + " info list: count")
+	}
+
+	if int(count) > r.remaining()/minSize {
+		return nil, yaerrors.FromError(
+			http.StatusBadRequest,
+			ErrShortBuffer,
+			logTag+" info list: count exceeds payload",
+		)
+	}
+
+	records := make([]T, count)
+
+	for index := range records {
+		if err = decode(r, &records[index]); err != nil {
+			return nil, err.Wrap(logTag + " info list: record")
+		}
+	}
+
+	return records, nil
+}
@@ -11,7 +11,7 @@
 //	0       4     magic (Magic, "YASC")
 //	4       1     protocol version
 //	5       1     message type
-//	6       2     flags (reserved, must be zero in version 3)
+//	6       2     flags (reserved, must be zero in version 8)
 //	8       8     correlation ID
 //	16      4     payload length
 //
@@ -23,9 +23,9 @@
 //
 // # Compatibility and versioning rules
 //
-// Version 3 is the only version this package speaks, and it is strict: a
+// Version 8 is the only version this package speaks, and it is strict: a
 // receiver must reject a frame carrying any other version byte, including
-// Version1 and Version2, by replying with a ProtocolError carrying
+// Version1 through Version7, by replying with a ProtocolError carrying
 // ErrorCodeUnsupportedVersion and closing the connection. There is no
 // negotiation and no downgrade. Unknown message types are protocol errors
 // as well. Future revisions extend the protocol only by adding new message
@@ -41,6 +41,17 @@
 // against Limits before any allocation trusts a length prefix, so a
 // malicious or broken peer cannot make the receiver allocate unbounded
 // memory or panic.
+//
+// # Authentication
+//
+// The connection may run over TLS, optionally with mutual certificate
+// authentication; the frame layout is the same either way. A scheduler
+// holding a shared secret answers the Register with an AuthChallenge
+// carrying a fresh nonce instead of the RegisterAck. The executor replies
+// with an AuthResponse carrying AuthToken(secret, nonce, register), and
+// only then does the scheduler admit or refuse the registration. A wrong
+// or missing token is refused with a RegisterAck carrying
+// ErrorCodeUnauthenticated, and the connection is closed.
 package protocol
 
 // Limits bounds every length-prefixed value a decoder will accept. A zero
@@ -24,6 +24,7 @@ const (
 	testAnnounceLabel  protocol.Label         = "region:eu-west"
 	testWithdrawLabel  protocol.Label         = "region:us-east"
 	testPinLabel       protocol.Label         = "gpu:a100"
+	testTraceParent    protocol.TraceParent   = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
 )
 
 // testJobUUID is a fixed identifier so encoded payloads stay byte-stable
@@ -42,7 +43,46 @@ func testFunctionSpec() protocol.FunctionSpec {
 	}
 }
 
+func testJobInfo() protocol.JobInfo {
+	return protocol.JobInfo{
+		JobUUID:      testJobUUID,
+		JobKey:       "report-daily",
+		ExecutorType: "report-service",
+		Function:     testFunctionSpec(),
+		Args:         []byte{4, 5},
+		Schedule: protocol.ScheduleSpec{
+			Kind:           protocol.ScheduleKindFixedInterval,
+			StartUnixNano:  testScheduledNanos,
+			IntervalMillis: 60000,
+		},
+		Enabled: true,
+		Backfill: protocol.BackfillSpec{
+			Mode:     protocol.BackfillModeEnabled,
+			MaxCount: 10,
+		},
+		Retry: protocol.RetrySpec{
+			Policy:     protocol.RetryPolicyFixed,
+			MaxRetries: protocol.DefaultMaxRetries,
+		},
+		Overlap: protocol.OverlapPolicySkip,
+		Pin: protocol.PinSpec{
+			Label:  testPinLabel,
+			Policy: protocol.PinPolicyStrict,
+		},
+		ResultMode:          protocol.ResultModeDeliver,
+		SubmitterInstanceID: "instance-1",
+		SkippedOccurrences:  3,
+		NextRunUnixNano:     testScheduledNanos,
+		CreatedUnixNano:     testScheduledNanos - 1,
+		UpdatedUnixNano:     testScheduledNanos,
+	}
+}
+
 func testMessages() []protocol.Message {
+	job := testJobInfo()
+	listed := testJobInfo()
+	listed.Args = nil
+
 	return []protocol.Message{
 		&protocol.Register{
 			ProtocolVersion: protocol.CurrentVersion,
@@ -75,6 +115,7 @@ func testMessages() []protocol.Message {
 			ScheduledUnixNano: testScheduledNanos,
 			TimeoutMillis:     testTimeoutMillis,
 			DeliverResult:     true,
+			TraceParent:       testTraceParent,
 		},
 		&protocol.ExecAccept{
 			ExecutionID: testExecutionID,
@@ -154,7 +195,21 @@ func testMessages() []protocol.Message {
 				Label:  testPinLabel,
 				Policy: protocol.PinPolicyPreferred,
 			},
-			ResultMode: protocol.ResultModeDeliver,
+			ResultMode:  protocol.ResultModeDeliver,
+			TraceParent: testTraceParent,
+		},
+		&protocol.JobUpsert{
+			JobUUID:      testJobUUID,
+			JobKey:       "report-weekday",
+			ExecutorType: "report-service",
+			Function:     testFunctionSpec(),
+			Schedule: protocol.ScheduleSpec{
+				Kind:           protocol.ScheduleKindCron,
+				StartUnixNano:  testScheduledNanos,
+				CronExpression: "0 9 * * MON-FRI",
+				TimeZone:       "Europe/Moscow",
+			},
+			Enabled: true,
 		},
 		&protocol.JobUpsertAck{
 			JobKey:   "report-daily",
@@ -214,6 +269,101 @@ func testMessages() []protocol.Message {
 				Message: "executor type is empty",
 			},
 		},
+		&protocol.AuthChallenge{Nonce: bytes.Repeat([]byte{0x5a}, protocol.AuthNonceSize)},
+		&protocol.AuthResponse{Token: []byte("token")},
+		&protocol.JobList{After: testJobUUID, Limit: 50},
+		&protocol.JobList{ExecutorType: "report-service"},
+		&protocol.JobListAck{Jobs: []protocol.JobInfo{listed, {}}, More: true},
+		&protocol.JobListAck{
+			Error: &protocol.WireError{
+				Code:      protocol.ErrorCodeInternal,
+				Retryable: true,
+				Message:   "store unavailable",
+			},
+		},
+		&protocol.JobGet{JobKey: "report-daily", ExecutorType: "report-service"},
+		&protocol.JobGetAck{JobKey: "report-daily", Job: &job},
+		&protocol.JobGetAck{JobKey: "report-daily"},
+		&protocol.JobControl{
+			JobKey:       "report-daily",
+			ExecutorType: "report-service",
+			Action:       protocol.JobActionTrigger,
+		},
+		&protocol.JobControlAck{
+			JobKey:      "report-daily",
+			Action:      protocol.JobActionTrigger,
+			Found:       true,
+			ExecutionID: testExecutionID,
+		},
+		&protocol.JobControlAck{
+			JobKey:  "report-daily",
+			Action:  protocol.JobActionPause,
+			Found:   true,
+			Changed: true,
+		},
+		&protocol.JobControlAck{
+			JobKey: "report-daily",
+			Action: protocol.JobActionTrigger,
+			Found:  true,
+			Error: &protocol.WireError{
+				Code:    protocol.ErrorCodeJobPaused,
+				Message: "job is paused",
+			},
+		},
+		&protocol.ExecutionList{
+			JobKey:       "report-daily",
+			ExecutorType: "report-service",
+			Before:       testExecutionID,
+			Limit:        20,
+		},
+		&protocol.ExecutionListAck{
+			JobKey: "report-daily",
+			Found:  true,
+			Executions: []protocol.ExecutionInfo{
+				{
+					ExecutionID:       testExecutionID,
+					ScheduledUnixNano: testScheduledNanos,
+					State:             protocol.ExecutionStateFailed,
+					Backfilled:        true,
+					AttemptCount:      testAttemptNumber,
+					InstanceID:        "instance-1",
+					StartedUnixNano:   testScheduledNanos + 1,
+					FinishedUnixNano:  testScheduledNanos + 2,
+					LastError:         "boom",
+				},
+				{
+					ExecutionID: testExecutionID + 1,
+					State:       protocol.ExecutionStateWaitingLabel,
+					WaitReason:  "no connected executor announces the pinned routing label",
+				},
+			},
+			More: true,
+		},
+		&protocol.ExecutionListAck{JobKey: "report-missing"},
+		&protocol.AttemptList{ExecutionID: testExecutionID, After: 1, Limit: 10},
+		&protocol.AttemptListAck{
+			ExecutionID: testExecutionID,
+			Found:       true,
+			Attempts: []protocol.AttemptInfo{
+				{
+					AttemptID:          testAttemptID,
+					Number:             testAttemptNumber,
+					InstanceID:         "instance-1",
+					State:              protocol.AttemptStateFunctionFailed,
+					Error:              "boom",
+					DispatchedUnixNano: testScheduledNanos,
+					UpdatedUnixNano:    testScheduledNanos + 1,
+				},
+			},
+		},
+		&protocol.AttemptListAck{
+			ExecutionID: testExecutionID,
+			Error: &protocol.WireError{
+				Code:      protocol.ErrorCodeInternal,
+				Retryable: true,
+				Message:   "store unavailable",
+			},
+		},
 	}
 }
 
@@ -324,9 +474,9 @@ func TestReadFrameRejectsBadMagic(t *testing.T) {
 }
 
 // TestReadFrameRejectsUnsupportedVersion proves the receiver fails closed
-// on any version byte other than the one it speaks. Version1 and Version2
-// are covered explicitly: protocol 3 does not negotiate and does not
-// downgrade, so a superseded frame is as unacceptable as an unknown future
+// on any version byte other than the one it speaks. Version1 through
+// Version7 are covered explicitly: protocol 8 does not negotiate and does
+// not downgrade, so a superseded frame is as unacceptable as an unknown future
 // one.
 func TestReadFrameRejectsUnsupportedVersion(t *testing.T) {
 	t.Parallel()
@@ -337,6 +487,11 @@ func TestReadFrameRejectsUnsupportedVersion(t *testing.T) {
 	}{
 		{name: "superseded version 1", version: protocol.Version1},
 		{name: "superseded version 2", version: protocol.Version2},
+		{name: "superseded version 3", version: protocol.Version3},
+		{name: "superseded version 4", version: protocol.Version4},
+		{name: "superseded version 5", version: protocol.Version5},
+		{name: "superseded version 6", version: protocol.Version6},
+		{name: "superseded version 7", version: protocol.Version7},
 		{name: "unknown future version", version: protocol.CurrentVersion + 1},
 		{name: "zero version", version: 0},
 	}
@@ -356,18 +511,51 @@ func TestReadFrameRejectsUnsupportedVersion(t *testing.T) {
 	}
 }
 
-func TestCurrentVersionIsVersion3(t *testing.T) {
+func TestCurrentVersionIsVersion8(t *testing.T) {
 	t.Parallel()
 
-	if protocol.CurrentVersion != protocol.Version3 {
+	if protocol.CurrentVersion != protocol.Version8 {
 		t.Fatalf(
-			"CurrentVersion = %d, want Version3 (%d)",
+			"CurrentVersion = %d, want Version8 (%d)",
 			protocol.CurrentVersion,
-			protocol.Version3,
+			protocol.Version8,
 		)
 	}
 }
 
+func TestAuthTokenBindsChallengeAndRegistration(t *testing.T) {
+	t.Parallel()
+
+	secret := []byte("shared secret")
+	nonce := protocol.NewAuthNonce()
+	register := &protocol.Register{
+		ProtocolVersion: protocol.CurrentVersion,
+		ExecutorType:    "billing",
+		InstanceID:      "billing-1",
+		Capacity:        4,
+	}
+
+	token := protocol.AuthToken(secret, nonce, register)
+	if !protocol.VerifyAuthToken(secret, nonce, register, token) {
+		t.Fatal("the token should verify for the challenge it answers")
+	}
+
+	if protocol.VerifyAuthToken([]byte("other secret"), nonce, register, token) {
+		t.Error("the token should not verify under another secret")
+	}
+
+	if protocol.VerifyAuthToken(secret, protocol.NewAuthNonce(), register, token) {
+		t.Error("the token should not verify for another challenge")
+	}
+
+	altered := *register
+	altered.Capacity++
+
+	if protocol.VerifyAuthToken(secret, nonce, &altered, token) {
+		t.Error("the token should not verify for an altered registration")
+	}
+}
+
 func TestReadFrameRejectsReservedFlags(t *testing.T) {
 	t.Parallel()
 
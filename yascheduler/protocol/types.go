@@ -75,6 +75,12 @@ type RetryPolicy uint8
 // a previous occurrence of the same job is still running.
 type OverlapPolicy uint8
 
+// TraceParent is the W3C traceparent value of the trace a job was
+// submitted in. It is opaque at this layer: the scheduler stores it with
+// the job and hands it to every execution, and an empty value carries no
+// trace.
+type TraceParent string
+
 // Label is one routing label an executor announces and a job pins to.
 // Labels are compared byte-wise; they carry no structure at this layer.
 type Label string
@@ -86,6 +92,17 @@ type PinPolicy uint8
 // back to the caller that requested the job.
 type ResultMode uint8
 
+// JobAction selects what a JobControl does to the job it addresses.
+type JobAction uint8
+
+// ExecutionState is the lifecycle state of one execution as execution
+// history reports it.
+type ExecutionState uint8
+
+// AttemptState is the lifecycle state of one attempt as execution history
+// reports it.
+type AttemptState uint8
+
 // WireError is the structured error representation carried by protocol
 // messages. Retryable reports whether the sender considers the failed
 // operation safe to retry.
@@ -111,15 +128,23 @@ type FunctionSpec struct {
 // period for fixed-interval jobs and must be zero for one-shot jobs. All
 // times are UTC unix nanoseconds.
 //
+// Cron jobs set CronExpression to a 5-field (minute hour day-of-month
+// month day-of-week) or 6-field (with a leading second) expression and
+// TimeZone to an IANA zone name, empty meaning UTC. Their StartUnixNano
+// is the earliest instant an occurrence may fall on.
+//
 // The anchor participates in occurrence identity across a republish: a
-// pending occurrence survives a job replacement only when it falls a
-// whole number of intervals after the replacement's StartUnixNano, so a
-// moved anchor cancels the pending occurrence and re-phases the schedule
-// from the new anchor.
+// pending occurrence survives a job replacement only when it is still an
+// occurrence of the replacement — a whole number of intervals after its
+// StartUnixNano, or a match of its cron expression — so a moved anchor or
+// a changed expression cancels the pending occurrence and re-phases the
+// schedule.
 type ScheduleSpec struct {
 	Kind           ScheduleKind
 	StartUnixNano  int64
 	IntervalMillis uint64
+	CronExpression string
+	TimeZone       string
 }
 
 // BackfillSpec configures missed-occurrence handling for one job. Mode
@@ -152,3 +177,62 @@ type PinSpec struct {
 	Label  Label
 	Policy PinPolicy
 }
+
+// JobInfo describes one stored job as the scheduler reports it: the
+// definition it was upserted with plus the scheduler's own bookkeeping.
+// Enabled is false for a job upserted disabled or paused since.
+// NextRunUnixNano is when its earliest pending occurrence is due, zero
+// when none is pending or the job is paused. Job listings leave Args empty; a job get carries
+// them.
+type JobInfo struct {
+	JobUUID             JobUUID
+	JobKey              string
+	ExecutorType        ExecutorType
+	Function            FunctionSpec
+	Args                []byte
+	Schedule            ScheduleSpec
+	Enabled             bool
+	Backfill            BackfillSpec
+	Retry               RetrySpec
+	Overlap             OverlapPolicy
+	Pin                 PinSpec
+	ResultMode          ResultMode
+	SubmitterInstanceID InstanceID
+	SkippedOccurrences  uint64
+	NextRunUnixNano     int64
+	CreatedUnixNano     int64
+	UpdatedUnixNano     int64
+}
+
+// ExecutionInfo describes one execution of a job: one materialized
+// occurrence and how far it got. AttemptCount counts its deliveries to
+// executors, and InstanceID names the executor of the latest one, empty
+// when it was never dispatched. StartedUnixNano is when its first attempt
+// was dispatched and FinishedUnixNano when it reached a terminal state,
+// each zero until it happens. LastError is the error text of its latest
+// failure, and WaitReason why it stopped where it did.
+type ExecutionInfo struct {
+	ExecutionID       ExecutionID
+	ScheduledUnixNano int64
+	State             ExecutionState
+	Backfilled        bool
+	AttemptCount      uint32
+	InstanceID        InstanceID
+	StartedUnixNano   int64
+	FinishedUnixNano  int64
+	LastError         string
+	WaitReason        string
+}
+
+// AttemptInfo describes one delivery of an execution to one executor
+// instance. DispatchedUnixNano is when it was sent and UpdatedUnixNano
+// when its state last changed; Error is the failure text it settled with.
+type AttemptInfo struct {
+	AttemptID          AttemptID
+	Number             uint32
+	InstanceID         InstanceID
+	State              AttemptState
+	Error              string
+	DispatchedUnixNano int64
+	UpdatedUnixNano    int64
+}
@@ -120,7 +120,8 @@ func (r *executorRuntime) awaitExecutions(timeout time.Duration) (drained bool)
 }
 
 // handleExecRequest admits or rejects one execution request and, when
-// admitted, runs it on a tracked goroutine.
+// admitted, runs it on a tracked goroutine. The execution context
+// continues the trace the job was upserted in with a span of its own.
 func (r *executorRuntime) handleExecRequest(execCtx context.Context, req *protocol.ExecRequest) {
 	prepared, found := r.registry.lookup(req.Function.Name, req.Function.Version)
 	if !found {
@@ -195,6 +196,9 @@ func (r *executorRuntime) handleExecRequest(execCtx context.Context, req *protoc
 		runCtx, cancel = context.WithCancel(execCtx)
 	}
 
+	runCtx = yalogger.ContextWithChildSpan(
+		yalogger.ContextWithTraceParent(runCtx, string(req.TraceParent)),
+	)
 	runCtx = context.WithValue(runCtx, invocationContextKey{}, &Invocation{
 		JobUUID:       req.JobUUID,
 		ExecutionID:   req.ExecutionID,
@@ -236,7 +240,7 @@ func (r *executorRuntime) runExecution(
 	defer cancel()
 	defer r.untrackCancel(req.ExecutionID, token)
 
-	log := r.log.WithFields(map[string]any{
+	log := yalogger.LoggerFromContext(runCtx, r.log).WithFields(map[string]any{
 		"job_uuid":     req.JobUUID.String(),
 		"execution_id": uint64(req.ExecutionID),
 		"attempt_id":   uint64(req.AttemptID),
@@ -299,6 +299,44 @@ func (s *Store) ListEnabledJobs(
 	return jobs, nil
 }
 
+// ListJobs returns one page of jobs, enabled or not, ordered by identifier:
+// those sorting after the given identifier, of the given executor type
+// unless it is empty, capped by a positive limit.
+func (s *Store) ListJobs(
+	_ context.Context,
+	executorType protocol.ExecutorType,
+	after protocol.JobUUID,
+	limit store.BatchLimit,
+) ([]*store.Job, yaerrors.Error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	jobs := make([]*store.Job, 0, len(s.jobs))
+
+	for _, job := range s.jobs {
+		if executorType != "" && job.ExecutorType != executorType {
+			continue
+		}
+
+		if bytes.Compare(job.ID[:], after[:]) <= 0 {
+			continue
+		}
+
+		copied := *job
+		jobs = append(jobs, &copied)
+	}
+
+	sort.Slice(jobs, func(i, j int) bool {
+		return bytes.Compare(jobs[i].ID[:], jobs[j].ID[:]) < 0
+	})
+
+	if limit > 0 && store.BatchLimit(len(jobs)) > limit {
+		jobs = jobs[:limit]
+	}
+
+	return jobs, nil
+}
+
 // CreateExecution materializes one occurrence of a job. A repeat of an
 // already materialized occurrence returns the stored execution and reports
 // false, so a replayed schedule pass never double-runs a job.
@@ -620,6 +658,30 @@ func (s *Store) HasPendingOccurrence(
 	return false, nil
 }
 
+// NextRunTimes reports the earliest pending due instant of each listed job.
+func (s *Store) NextRunTimes(
+	_ context.Context,
+	jobIDs []protocol.JobUUID,
+) (map[protocol.JobUUID]time.Time, yaerrors.Error) {
+	listed := make(map[protocol.JobUUID]struct{}, len(jobIDs))
+	for _, jobID := range jobIDs {
+		listed[jobID] = struct{}{}
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	pending := make([]*store.Execution, 0)
+
+	for _, execution := range s.executions {
+		if _, ok := listed[execution.JobID]; ok && !execution.State.Terminal() {
+			pending = append(pending, execution)
+		}
+	}
+
+	return store.EarliestPendingDue(pending), nil
+}
+
 // ExpiredLeases returns leased executions whose lease has elapsed.
 func (s *Store) ExpiredLeases(
 	_ context.Context,
@@ -33,6 +33,7 @@ type Job struct {
 	Overlap             protocol.OverlapPolicy
 	Pin                 protocol.PinSpec
 	ResultMode          protocol.ResultMode
+	TraceParent         protocol.TraceParent
 	SubmitterInstanceID protocol.InstanceID
 	SkippedOccurrences  OccurrenceCount
 	Version             Version
@@ -120,6 +121,47 @@ func (s ExecutionState) Terminal() (terminal bool) {
 	}
 }
 
+// PendingDueAt reports when an execution that has not started yet becomes
+// due. Executions waiting for an executor are pending but due already, so
+// their occurrence instant stands.
+func (e *Execution) PendingDueAt() (dueAt time.Time, pending bool) {
+	switch e.State {
+	case StateScheduled, StateWaitingExecutor, StateWaitingCompatible, StateWaitingLabel:
+		return e.ScheduledAt, true
+	case StateReady, StateRetryWait:
+		return e.NextAttemptAt, true
+	case StateDispatching,
+		StateRunning,
+		StateSucceeded,
+		StateFailed,
+		StateCancelled,
+		StateSkipped:
+		return time.Time{}, false
+	default:
+		return time.Time{}, false
+	}
+}
+
+// EarliestPendingDue folds executions into the earliest PendingDueAt of each
+// job they belong to, leaving out jobs with nothing pending. Stores answer
+// ExecutionRepository.NextRunTimes with it.
+func EarliestPendingDue(executions []*Execution) (nextRuns map[protocol.JobUUID]time.Time) {
+	nextRuns = make(map[protocol.JobUUID]time.Time)
+
+	for _, execution := range executions {
+		dueAt, pending := execution.PendingDueAt()
+		if !pending {
+			continue
+		}
+
+		if earliest, seen := nextRuns[execution.JobID]; !seen || dueAt.Before(earliest) {
+			nextRuns[execution.JobID] = dueAt
+		}
+	}
+
+	return nextRuns
+}
+
 // Terminal reports whether the state is settled, so no further transition
 // out of it is legal.
 func (s AttemptState) Terminal() (terminal bool) {
@@ -542,6 +542,43 @@ func (s *Store) HasPendingOccurrence(
 	return count > 0, nil
 }
 
+// NextRunTimes reports the earliest pending due instant of each listed job,
+// reading the pending index of every job in one pipeline and only the
+// executions it holds.
+func (s *Store) NextRunTimes(
+	ctx context.Context,
+	jobIDs []protocol.JobUUID,
+) (nextRuns map[protocol.JobUUID]time.Time, err yaerrors.Error) {
+	const action = "find next run times"
+
+	if len(jobIDs) == 0 {
+		return map[protocol.JobUUID]time.Time{}, nil
+	}
+
+	pipe := s.client.Pipeline()
+
+	commands := make([]*redis.StringSliceCmd, 0, len(jobIDs))
+	for _, jobID := range jobIDs {
+		commands = append(commands, pipe.SMembers(ctx, s.jobPendingKey(uuidHex(jobID))))
+	}
+
+	if _, execErr := pipe.Exec(ctx); execErr != nil {
+		return nil, transportError(execErr, action)
+	}
+
+	members := make([]string, 0, len(commands))
+	for _, command := range commands {
+		members = append(members, command.Val()...)
+	}
+
+	pending, err := s.executionsByMembers(ctx, members, action)
+	if err != nil {
+		return nil, err
+	}
+
+	return store.EarliestPendingDue(pending), nil
+}
+
 // ExpiredLeases returns leased executions whose lease has elapsed, in
 // creation order.
 func (s *Store) ExpiredLeases(
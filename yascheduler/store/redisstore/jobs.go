@@ -43,6 +43,7 @@ func (s *Store) UpsertJob(
 		Overlap:             job.Overlap,
 		Pin:                 job.Pin,
 		ResultMode:          job.ResultMode,
+		TraceParent:         job.TraceParent,
 		SubmitterInstanceID: job.SubmitterInstanceID,
 	})
 	if err != nil {
@@ -410,6 +411,89 @@ func (s *Store) ListEnabledJobs(
 	return jobs, nil
 }
 
+// ListJobs returns one page of jobs, enabled or not, ordered by identifier:
+// those sorting after the given identifier, of the given executor type
+// unless it is empty, capped by a positive limit. Job records are fetched a
+// page at a time, so a small page over a large job set reads little more
+// than it returns.
+func (s *Store) ListJobs(
+	ctx context.Context,
+	executorType protocol.ExecutorType,
+	after protocol.JobUUID,
+	limit store.BatchLimit,
+) (jobs []*store.Job, err yaerrors.Error) {
+	const action = "list jobs"
+
+	idHexes, valuesErr := s.client.HVals(ctx, s.keys.jobKeys).Result()
+	if valuesErr != nil {
+		return nil, transportError(valuesErr, action)
+	}
+
+	afterHex := uuidHex(after)
+
+	candidates := make([]string, 0, len(idHexes))
+	for _, idHex := range idHexes {
+		if idHex > afterHex {
+			candidates = append(candidates, idHex)
+		}
+	}
+
+	sort.Strings(candidates)
+
+	chunk := len(candidates)
+	if limit > 0 && int(limit) < chunk {
+		chunk = int(limit)
+	}
+
+	jobs = make([]*store.Job, 0, chunk)
+
+	for start := 0; start < len(candidates); start += chunk {
+		if limit > 0 && store.BatchLimit(len(jobs)) >= limit {
+			break
+		}
+
+		page := candidates[start:min(start+chunk, len(candidates))]
+
+		keys := make([]string, 0, len(page))
+		for _, idHex := range page {
+			keys = append(keys, s.jobKey(idHex))
+		}
+
+		hashes, fetchErr := s.fetchHashes(ctx, keys, action)
+		if fetchErr != nil {
+			return nil, fetchErr
+		}
+
+		for index, fields := range hashes {
+			if len(fields) == 0 {
+				continue
+			}
+
+			id, idErr := uuidFromHex(page[index])
+			if idErr != nil {
+				return nil, idErr.Wrap(logTag + " failed to " + action)
+			}
+
+			job, jobErr := jobFromHash(id, fields)
+			if jobErr != nil {
+				return nil, jobErr.Wrap(logTag + " failed to " + action)
+			}
+
+			if executorType != "" && job.ExecutorType != executorType {
+				continue
+			}
+
+			if limit > 0 && store.BatchLimit(len(jobs)) >= limit {
+				break
+			}
+
+			jobs = append(jobs, job)
+		}
+	}
+
+	return jobs, nil
+}
+
 func jobFromHash(
 	id protocol.JobUUID,
 	fields map[string]string,
@@ -454,6 +538,7 @@ func jobFromHash(
 		Overlap:             wire.Overlap,
 		Pin:                 wire.Pin,
 		ResultMode:          wire.ResultMode,
+		TraceParent:         wire.TraceParent,
 		SubmitterInstanceID: wire.SubmitterInstanceID,
 		SkippedOccurrences:  store.OccurrenceCount(skipped),
 		Version:             store.Version(version),
@@ -22,6 +22,7 @@ type jobWire struct {
 	Overlap             protocol.OverlapPolicy
 	Pin                 protocol.PinSpec
 	ResultMode          protocol.ResultMode
+	TraceParent         protocol.TraceParent
 	SubmitterInstanceID protocol.InstanceID
 }
 
@@ -0,0 +1,210 @@
+package sqlstore
+
+import (
+	"context"
+	"errors"
+
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yaerrors"
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yascheduler/protocol"
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yascheduler/store"
+	"gorm.io/gorm"
+)
+
+// CreateAttempt records a new delivery of an execution to an instance.
+func (s *Store) CreateAttempt(
+	ctx context.Context,
+	executionID protocol.ExecutionID,
+	number store.AttemptNumber,
+	instanceID protocol.InstanceID,
+) (created *store.Attempt, err yaerrors.Error) {
+	const action = "create attempt"
+
+	err = s.transaction(ctx, action, func(tx *gorm.DB) yaerrors.Error {
+		var executionCount int64
+
+		if countErr := tx.Model(&executionRecord{}).
+			Where(whereID, uint64(executionID)).
+			Count(&executionCount).Error; countErr != nil {
+			return databaseError(countErr, action)
+		}
+
+		if executionCount == 0 {
+			return notFound(store.ErrExecutionNotFound, action)
+		}
+
+		now := unixNano(s.now())
+		record := attemptRecord{
+			ExecutionID:     uint64(executionID),
+			Number:          uint32(number),
+			InstanceID:      string(instanceID),
+			Listed:          true,
+			State:           uint8(store.AttemptDispatched),
+			CreatedUnixNano: now,
+			UpdatedUnixNano: now,
+		}
+
+		if createErr := tx.Create(&record).Error; createErr != nil {
+			return databaseError(createErr, action)
+		}
+
+		created = attemptFromRecord(&record)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// GetAttempt returns the attempt with the given identifier.
+func (s *Store) GetAttempt(
+	ctx context.Context,
+	id protocol.AttemptID,
+) (fetched *store.Attempt, err yaerrors.Error) {
+	const action = "fetch attempt"
+
+	var record attemptRecord
+
+	if findErr := s.db.WithContext(ctx).Where(whereID, uint64(id)).Take(&record).Error; findErr != nil {
+		if errors.Is(findErr, gorm.ErrRecordNotFound) {
+			return nil, notFound(store.ErrAttemptNotFound, action)
+		}
+
+		return nil, databaseError(findErr, action)
+	}
+
+	return attemptFromRecord(&record), nil
+}
+
+// UpdateAttemptState moves an attempt to a new state, optionally only from
+// one of the given states. It reports false when the guard did not match.
+// A settled attempt leaves its instance listing.
+func (s *Store) UpdateAttemptState(
+	ctx context.Context,
+	id protocol.AttemptID,
+	from []store.AttemptState,
+	to store.AttemptState,
+	errorText store.ErrorText,
+) (moved bool, err yaerrors.Error) {
+	const action = "update attempt state"
+
+	err = s.transaction(ctx, action, func(tx *gorm.DB) yaerrors.Error {
+		var record attemptRecord
+
+		if findErr := tx.Where(whereID, uint64(id)).Take(&record).Error; findErr != nil {
+			if errors.Is(findErr, gorm.ErrRecordNotFound) {
+				return notFound(store.ErrAttemptNotFound, action)
+			}
+
+			return databaseError(findErr, action)
+		}
+
+		guard := tx.Model(&attemptRecord{}).Where(whereID, uint64(id))
+
+		if len(from) > 0 {
+			guard = guard.Where(whereStateIn, attemptStateValues(from))
+		}
+
+		fields := map[string]any{
+			columnState:     uint8(to),
+			columnUpdatedAt: unixNano(s.now()),
+		}
+
+		if errorText != "" {
+			fields[columnError] = string(errorText)
+		}
+
+		if to.Terminal() {
+			fields[columnListed] = false
+		}
+
+		written := guard.Updates(fields)
+		if written.Error != nil {
+			return databaseError(written.Error, action)
+		}
+
+		moved = written.RowsAffected > 0
+
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return moved, nil
+}
+
+// AttemptsForExecution returns every attempt of one execution in creation
+// order.
+func (s *Store) AttemptsForExecution(
+	ctx context.Context,
+	executionID protocol.ExecutionID,
+) (attempts []*store.Attempt, err yaerrors.Error) {
+	return findAttempts(
+		"list attempts for execution",
+		s.db.WithContext(ctx).
+			Where(columnExecutionID+" = ?", uint64(executionID)).
+			Order(orderByID),
+	)
+}
+
+// AttemptsOnInstance returns attempts delivered to one instance, in
+// creation order, optionally filtered to the given states. A settled
+// attempt has already left the listing.
+func (s *Store) AttemptsOnInstance(
+	ctx context.Context,
+	instanceID protocol.InstanceID,
+	states ...store.AttemptState,
+) (attempts []*store.Attempt, err yaerrors.Error) {
+	query := s.db.WithContext(ctx).Where(whereInstanceList, string(instanceID), true)
+
+	if len(states) > 0 {
+		query = query.Where(whereStateIn, attemptStateValues(states))
+	}
+
+	return findAttempts("list attempts on instance", query.Order(orderByID))
+}
+
+// DeleteAttempt removes the attempt with the given identifier. It reports
+// false when no attempt was stored, so a replayed delete is idempotent.
+func (s *Store) DeleteAttempt(
+	ctx context.Context,
+	id protocol.AttemptID,
+) (deleted bool, err yaerrors.Error) {
+	removed := s.db.WithContext(ctx).Where(whereID, uint64(id)).Delete(&attemptRecord{})
+	if removed.Error != nil {
+		return false, databaseError(removed.Error, "delete attempt")
+	}
+
+	return removed.RowsAffected > 0, nil
+}
+
+func findAttempts(action string, query *gorm.DB) (attempts []*store.Attempt, err yaerrors.Error) {
+	var records []attemptRecord
+
+	if findErr := query.Find(&records).Error; findErr != nil {
+		return nil, databaseError(findErr, action)
+	}
+
+	attempts = make([]*store.Attempt, 0, len(records))
+	for index := range records {
+		attempts = append(attempts, attemptFromRecord(&records[index]))
+	}
+
+	return attempts, nil
+}
+
+func attemptFromRecord(record *attemptRecord) (attempt *store.Attempt) {
+	return &store.Attempt{
+		ID:          protocol.AttemptID(record.ID),
+		ExecutionID: protocol.ExecutionID(record.ExecutionID),
+		Number:      store.AttemptNumber(record.Number),
+		InstanceID:  protocol.InstanceID(record.InstanceID),
+		State:       store.AttemptState(record.State),
+		Error:       store.ErrorText(record.Error),
+		CreatedAt:   fromUnixNano(record.CreatedUnixNano),
+		UpdatedAt:   fromUnixNano(record.UpdatedUnixNano),
+	}
+}
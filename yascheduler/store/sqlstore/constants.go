@@ -0,0 +1,70 @@
+package sqlstore
+
+import "github.com/YaCodeDev/GoYaCodeDevUtils/yascheduler/store"
+
+const (
+	// DefaultMaxResults caps how many pending results the store holds in
+	// total, matching the memory store's default budget.
+	DefaultMaxResults store.OccurrenceCount = 1024
+
+	// DefaultMaxResultsPerInstance caps how many pending results the store
+	// holds for one submitting instance, so a single disconnected
+	// submitter cannot consume the whole budget.
+	DefaultMaxResultsPerInstance store.OccurrenceCount = 256
+)
+
+const logTag = "[SCHEDULERSQLSTORE]"
+
+const (
+	tableJobs       = "yascheduler_jobs"
+	tableExecutions = "yascheduler_executions"
+	tableAttempts   = "yascheduler_attempts"
+	tableResults    = "yascheduler_results"
+)
+
+const (
+	columnID               = "id"
+	columnExecutorType     = "executor_type"
+	columnJobKey           = "job_key"
+	columnDefinition       = "definition"
+	columnEnabled          = "enabled"
+	columnSkipped          = "skipped_occurrences"
+	columnVersion          = "version"
+	columnCreatedAt        = "created_at"
+	columnUpdatedAt        = "updated_at"
+	columnJobID            = "job_id"
+	columnScheduledAt      = "scheduled_at"
+	columnState            = "state"
+	columnFunctionAttempts = "function_attempts"
+	columnCurrentAttemptID = "current_attempt_id"
+	columnNextAttemptAt    = "next_attempt_at"
+	columnLeaseExpiresAt   = "lease_expires_at"
+	columnWakeAt           = "wake_at"
+	columnLastError        = "last_error"
+	columnWaitReason       = "wait_reason"
+	columnExecutionID      = "execution_id"
+	columnInstanceID       = "instance_id"
+	columnListed           = "listed"
+	columnError            = "error"
+	columnSequence         = "sequence"
+	columnJobUUID          = "job_uuid"
+	columnAttempts         = "attempts"
+	columnLastSentAt       = "last_sent_at"
+)
+
+const (
+	whereID           = columnID + " = ?"
+	whereJobUUID      = columnJobUUID + " = ?"
+	whereScope        = columnExecutorType + " = ? AND " + columnJobKey + " = ?"
+	whereVersion      = columnID + " = ? AND " + columnVersion + " = ?"
+	whereStateIn      = columnState + " IN ?"
+	whereInstanceList = columnInstanceID + " = ? AND " + columnListed + " = ?"
+
+	orderByID          = columnID
+	orderBySequence    = columnSequence
+	orderBySchedule    = columnScheduledAt + ", " + columnID
+	orderBySettle      = columnUpdatedAt + ", " + columnID
+	orderByStorageTime = columnCreatedAt + ", " + columnJobUUID
+)
+
+const upsertRetryLimit = 2
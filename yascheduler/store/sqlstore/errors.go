@@ -0,0 +1,8 @@
+package sqlstore
+
+import "errors"
+
+// ErrConcurrentUpdate reports a job upsert that lost the race for its
+// executor-scoped key on every try, so no transaction ever observed the
+// row a concurrent writer created.
+var ErrConcurrentUpdate = errors.New("concurrent update exhausted retries")
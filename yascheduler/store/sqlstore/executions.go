@@ -0,0 +1,471 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yaerrors"
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yascheduler/protocol"
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yascheduler/store"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CreateExecution materializes one occurrence of a job. A repeat of an
+// already materialized occurrence returns the stored execution and reports
+// false, so a replayed schedule pass never double-runs a job. The
+// occurrence identity is a unique index, so two schedulers materializing
+// the same occurrence at once still create it once.
+func (s *Store) CreateExecution(
+	ctx context.Context,
+	jobID protocol.JobUUID,
+	scheduledAt time.Time,
+	state store.ExecutionState,
+	backfilled store.Backfilled,
+) (created *store.Execution, fresh bool, err yaerrors.Error) {
+	const action = "create execution"
+
+	err = s.transaction(ctx, action, func(tx *gorm.DB) yaerrors.Error {
+		var jobCount int64
+
+		if countErr := tx.Model(&jobRecord{}).Where(whereID, jobID[:]).Count(&jobCount).Error; countErr != nil {
+			return databaseError(countErr, action)
+		}
+
+		if jobCount == 0 {
+			return notFound(store.ErrJobNotFound, action)
+		}
+
+		now := unixNano(s.now())
+		scheduled := unixNano(scheduledAt)
+		record := executionRecord{
+			JobID:             jobID[:],
+			ScheduledUnixNano: scheduled,
+			State:             uint8(state),
+			NextAttemptNano:   scheduled,
+			WakeUnixNano:      wakeColumn(state, scheduled, scheduled),
+			Backfilled:        bool(backfilled),
+			Version:           1,
+			CreatedUnixNano:   now,
+			UpdatedUnixNano:   now,
+		}
+
+		inserted := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: columnJobID}, {Name: columnScheduledAt}},
+			DoNothing: true,
+		}).Create(&record)
+		if inserted.Error != nil {
+			return databaseError(inserted.Error, action)
+		}
+
+		if inserted.RowsAffected > 0 {
+			created, fresh = executionFromRecord(&record), true
+
+			return nil
+		}
+
+		var existing executionRecord
+
+		if findErr := tx.Where(
+			columnJobID+" = ? AND "+columnScheduledAt+" = ?",
+			jobID[:],
+			scheduled,
+		).Take(&existing).Error; findErr != nil {
+			return databaseError(findErr, action)
+		}
+
+		created, fresh = executionFromRecord(&existing), false
+
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	return created, fresh, nil
+}
+
+// GetExecution returns the execution with the given identifier.
+func (s *Store) GetExecution(
+	ctx context.Context,
+	id protocol.ExecutionID,
+) (fetched *store.Execution, err yaerrors.Error) {
+	const action = "fetch execution"
+
+	var record executionRecord
+
+	if findErr := s.db.WithContext(ctx).Where(whereID, uint64(id)).Take(&record).Error; findErr != nil {
+		if errors.Is(findErr, gorm.ErrRecordNotFound) {
+			return nil, notFound(store.ErrExecutionNotFound, action)
+		}
+
+		return nil, databaseError(findErr, action)
+	}
+
+	return executionFromRecord(&record), nil
+}
+
+// UpdateExecution applies a partial update under an optimistic version
+// check, refusing a state change out of a terminal state or one the
+// transition table does not allow. The UPDATE itself states the version
+// it read, so a row a concurrent writer moved first is a version conflict,
+// never a lost write.
+func (s *Store) UpdateExecution(
+	ctx context.Context,
+	id protocol.ExecutionID,
+	expectedVersion store.Version,
+	update store.ExecutionUpdate,
+) (updated *store.Execution, err yaerrors.Error) {
+	const action = "update execution"
+
+	err = s.transaction(ctx, action, func(tx *gorm.DB) yaerrors.Error {
+		var record executionRecord
+
+		if findErr := tx.Where(whereID, uint64(id)).Take(&record).Error; findErr != nil {
+			if errors.Is(findErr, gorm.ErrRecordNotFound) {
+				return notFound(store.ErrExecutionNotFound, action)
+			}
+
+			return databaseError(findErr, action)
+		}
+
+		if store.Version(record.Version) != expectedVersion {
+			return conflict(store.ErrVersionConflict, action)
+		}
+
+		if refusal := applyExecutionUpdate(&record, update); refusal != nil {
+			return conflict(refusal, action)
+		}
+
+		record.WakeUnixNano = wakeColumn(
+			store.ExecutionState(record.State),
+			record.ScheduledUnixNano,
+			record.NextAttemptNano,
+		)
+		record.Version++
+		record.UpdatedUnixNano = unixNano(s.now())
+
+		written := tx.Model(&executionRecord{}).
+			Where(whereVersion, uint64(id), uint64(expectedVersion)).
+			Updates(map[string]any{
+				columnState:            record.State,
+				columnFunctionAttempts: record.FunctionAttempts,
+				columnCurrentAttemptID: record.CurrentAttemptID,
+				columnNextAttemptAt:    record.NextAttemptNano,
+				columnLeaseExpiresAt:   record.LeaseExpiresNano,
+				columnWakeAt:           record.WakeUnixNano,
+				columnLastError:        record.LastError,
+				columnWaitReason:       record.WaitReason,
+				columnVersion:          record.Version,
+				columnUpdatedAt:        record.UpdatedUnixNano,
+			})
+		if written.Error != nil {
+			return databaseError(written.Error, action)
+		}
+
+		if written.RowsAffected == 0 {
+			return conflict(store.ErrVersionConflict, action)
+		}
+
+		updated = executionFromRecord(&record)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+// DueExecutions returns executions whose time has come, ordered by
+// schedule time then identifier, capped by a positive limit.
+func (s *Store) DueExecutions(
+	ctx context.Context,
+	now time.Time,
+	limit store.BatchLimit,
+) (due []*store.Execution, err yaerrors.Error) {
+	return findExecutions(
+		"list due executions",
+		limited(
+			s.db.WithContext(ctx).
+				Where(columnWakeAt+" <= ?", unixNano(now)).
+				Order(orderBySchedule),
+			limit,
+		),
+	)
+}
+
+// NextWakeAt returns the earliest instant at which any execution becomes
+// due, and whether any execution is waiting for one.
+func (s *Store) NextWakeAt(
+	ctx context.Context,
+) (wake time.Time, found bool, err yaerrors.Error) {
+	var earliest sql.NullInt64
+
+	if scanErr := s.db.WithContext(ctx).
+		Model(&executionRecord{}).
+		Select("MIN(" + columnWakeAt + ")").
+		Row().
+		Scan(&earliest); scanErr != nil {
+		return time.Time{}, false, databaseError(scanErr, "read next wake instant")
+	}
+
+	if !earliest.Valid {
+		return time.Time{}, false, nil
+	}
+
+	return fromUnixNano(earliest.Int64), true, nil
+}
+
+// ExecutionsInStates returns every execution in any of the given states,
+// in creation order.
+func (s *Store) ExecutionsInStates(
+	ctx context.Context,
+	states ...store.ExecutionState,
+) (matching []*store.Execution, err yaerrors.Error) {
+	if len(states) == 0 {
+		return []*store.Execution{}, nil
+	}
+
+	return findExecutions(
+		"list executions in states",
+		s.db.WithContext(ctx).
+			Where(whereStateIn, executionStateValues(states)).
+			Order(orderByID),
+	)
+}
+
+// ExecutionsForJob returns every execution of one job in creation order.
+func (s *Store) ExecutionsForJob(
+	ctx context.Context,
+	jobID protocol.JobUUID,
+) (matching []*store.Execution, err yaerrors.Error) {
+	return findExecutions(
+		"list executions for job",
+		s.db.WithContext(ctx).Where(columnJobID+" = ?", jobID[:]).Order(orderByID),
+	)
+}
+
+// HasActiveExecution reports whether any execution of one job besides the
+// excluded one holds a dispatch or run lease.
+func (s *Store) HasActiveExecution(
+	ctx context.Context,
+	jobID protocol.JobUUID,
+	exclude protocol.ExecutionID,
+) (active bool, err yaerrors.Error) {
+	return anyExecution(
+		"check active executions",
+		s.db.WithContext(ctx).Where(
+			columnJobID+" = ? AND "+columnID+" <> ? AND "+whereStateIn,
+			jobID[:],
+			uint64(exclude),
+			leasedExecutionStates(),
+		),
+	)
+}
+
+// HasPendingOccurrence reports whether any occurrence of one job has yet
+// to settle.
+func (s *Store) HasPendingOccurrence(
+	ctx context.Context,
+	jobID protocol.JobUUID,
+) (pending bool, err yaerrors.Error) {
+	return anyExecution(
+		"check pending occurrences",
+		s.db.WithContext(ctx).Where(
+			columnJobID+" = ? AND "+columnState+" NOT IN ?",
+			jobID[:],
+			terminalExecutionStates(),
+		),
+	)
+}
+
+// NextRunTimes reports the earliest pending due instant of each listed job
+// with one query over their unsettled, unleased executions.
+func (s *Store) NextRunTimes(
+	ctx context.Context,
+	jobIDs []protocol.JobUUID,
+) (nextRuns map[protocol.JobUUID]time.Time, err yaerrors.Error) {
+	if len(jobIDs) == 0 {
+		return map[protocol.JobUUID]time.Time{}, nil
+	}
+
+	ids := make([][]byte, 0, len(jobIDs))
+	for _, jobID := range jobIDs {
+		ids = append(ids, jobID[:])
+	}
+
+	pending, err := findExecutions(
+		"find next run times",
+		s.db.WithContext(ctx).Where(
+			columnJobID+" IN ? AND "+columnState+" NOT IN ?",
+			ids,
+			append(terminalExecutionStates(), leasedExecutionStates()...),
+		),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return store.EarliestPendingDue(pending), nil
+}
+
+// ExpiredLeases returns leased executions whose lease has elapsed.
+func (s *Store) ExpiredLeases(
+	ctx context.Context,
+	now time.Time,
+) (expired []*store.Execution, err yaerrors.Error) {
+	return findExecutions(
+		"list expired leases",
+		s.db.WithContext(ctx).
+			Where(
+				whereStateIn+" AND "+columnLeaseExpiresAt+" <= ?",
+				leasedExecutionStates(),
+				unixNano(now),
+			).
+			Order(orderByID),
+	)
+}
+
+// DeleteExecution removes the execution with the given identifier. It
+// reports false when no execution was stored, so a replayed delete is
+// idempotent. Cleaning up the execution's attempts is the caller's job;
+// removing the row frees its occurrence identity, so a later
+// CreateExecution for the same occurrence materializes a fresh execution.
+func (s *Store) DeleteExecution(
+	ctx context.Context,
+	id protocol.ExecutionID,
+) (deleted bool, err yaerrors.Error) {
+	removed := s.db.WithContext(ctx).Where(whereID, uint64(id)).Delete(&executionRecord{})
+	if removed.Error != nil {
+		return false, databaseError(removed.Error, "delete execution")
+	}
+
+	return removed.RowsAffected > 0, nil
+}
+
+// ExpiredExecutions returns terminal executions that settled before the
+// given instant, ordered by settle time then identifier, capped by a
+// positive limit.
+func (s *Store) ExpiredExecutions(
+	ctx context.Context,
+	before time.Time,
+	limit store.BatchLimit,
+) (expired []*store.Execution, err yaerrors.Error) {
+	return findExecutions(
+		"list expired executions",
+		limited(
+			s.db.WithContext(ctx).
+				Where(
+					whereStateIn+" AND "+columnUpdatedAt+" < ?",
+					terminalExecutionStates(),
+					unixNano(before),
+				).
+				Order(orderBySettle),
+			limit,
+		),
+	)
+}
+
+func findExecutions(action string, query *gorm.DB) (executions []*store.Execution, err yaerrors.Error) {
+	var records []executionRecord
+
+	if findErr := query.Find(&records).Error; findErr != nil {
+		return nil, databaseError(findErr, action)
+	}
+
+	executions = make([]*store.Execution, 0, len(records))
+	for index := range records {
+		executions = append(executions, executionFromRecord(&records[index]))
+	}
+
+	return executions, nil
+}
+
+func anyExecution(action string, query *gorm.DB) (found bool, err yaerrors.Error) {
+	var records []executionRecord
+
+	if findErr := query.Select(columnID).Limit(1).Find(&records).Error; findErr != nil {
+		return false, databaseError(findErr, action)
+	}
+
+	return len(records) > 0, nil
+}
+
+// applyExecutionUpdate applies the set fields of update to record, or
+// returns the contract violation that refuses the state change.
+func applyExecutionUpdate(record *executionRecord, update store.ExecutionUpdate) (refusal error) {
+	current := store.ExecutionState(record.State)
+
+	if update.State != nil && *update.State != current {
+		if current.Terminal() {
+			return store.ErrTerminalState
+		}
+
+		if !store.CanTransition(current, *update.State) {
+			return store.ErrIllegalTransition
+		}
+
+		record.State = uint8(*update.State)
+	}
+
+	if update.FunctionAttempts != nil {
+		record.FunctionAttempts = uint32(*update.FunctionAttempts)
+	}
+
+	if update.CurrentAttemptID != nil {
+		record.CurrentAttemptID = uint64(*update.CurrentAttemptID)
+	}
+
+	if update.NextAttemptAt != nil {
+		record.NextAttemptNano = optionalUnixNano(*update.NextAttemptAt)
+	}
+
+	if update.LeaseExpiresAt != nil {
+		record.LeaseExpiresNano = optionalUnixNano(*update.LeaseExpiresAt)
+	}
+
+	if update.LastError != nil {
+		record.LastError = string(*update.LastError)
+	}
+
+	if update.WaitReason != nil {
+		record.WaitReason = string(*update.WaitReason)
+	}
+
+	return nil
+}
+
+func conflict(sentinel error, action string) (err yaerrors.Error) {
+	return yaerrors.FromError(
+		http.StatusConflict,
+		sentinel,
+		logTag+" failed to "+action,
+	)
+}
+
+func executionFromRecord(record *executionRecord) (execution *store.Execution) {
+	execution = &store.Execution{
+		ID:               protocol.ExecutionID(record.ID),
+		ScheduledAt:      fromUnixNano(record.ScheduledUnixNano),
+		State:            store.ExecutionState(record.State),
+		FunctionAttempts: store.FunctionAttempts(record.FunctionAttempts),
+		CurrentAttemptID: protocol.AttemptID(record.CurrentAttemptID),
+		NextAttemptAt:    fromOptionalUnixNano(record.NextAttemptNano),
+		LeaseExpiresAt:   fromOptionalUnixNano(record.LeaseExpiresNano),
+		Backfilled:       store.Backfilled(record.Backfilled),
+		LastError:        store.ErrorText(record.LastError),
+		WaitReason:       store.WaitReason(record.WaitReason),
+		Version:          store.Version(record.Version),
+		CreatedAt:        fromUnixNano(record.CreatedUnixNano),
+		UpdatedAt:        fromUnixNano(record.UpdatedUnixNano),
+	}
+
+	copy(execution.JobID[:], record.JobID)
+
+	return execution
+}
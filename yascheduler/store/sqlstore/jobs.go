@@ -0,0 +1,330 @@
+package sqlstore
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yaencoding"
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yaerrors"
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yascheduler/protocol"
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yascheduler/store"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// UpsertJob creates or replaces the job addressed by its executor type and
+// key, keeping the stored identity, creation time, and skipped-occurrence
+// counter. A create that races another writer for the same key is retried
+// as a replace.
+func (s *Store) UpsertJob(
+	ctx context.Context,
+	job *store.Job,
+) (upserted *store.Job, err yaerrors.Error) {
+	const action = "upsert job"
+
+	if job == nil {
+		return nil, yaerrors.FromError(
+			http.StatusBadRequest,
+			store.ErrNilJob,
+			logTag+" failed to "+action,
+		)
+	}
+
+	definition, err := yaencoding.EncodeMessagePack(jobDefinition{
+		Function:            job.Function,
+		Args:                job.Args,
+		Schedule:            job.Schedule,
+		Backfill:            job.Backfill,
+		Retry:               job.Retry,
+		Overlap:             job.Overlap,
+		Pin:                 job.Pin,
+		ResultMode:          job.ResultMode,
+		TraceParent:         job.TraceParent,
+		SubmitterInstanceID: job.SubmitterInstanceID,
+	})
+	if err != nil {
+		return nil, err.Wrap(logTag + " failed to " + action)
+	}
+
+	for range upsertRetryLimit {
+		var raced bool
+
+		err = s.transaction(ctx, action, func(tx *gorm.DB) yaerrors.Error {
+			upserted, raced, err = s.upsertJobTx(tx, job, definition)
+
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if !raced {
+			return upserted, nil
+		}
+	}
+
+	return nil, yaerrors.FromError(
+		http.StatusConflict,
+		ErrConcurrentUpdate,
+		logTag+" failed to "+action,
+	)
+}
+
+func (s *Store) upsertJobTx(
+	tx *gorm.DB,
+	job *store.Job,
+	definition []byte,
+) (upserted *store.Job, raced bool, err yaerrors.Error) {
+	const action = "upsert job"
+
+	now := unixNano(s.now())
+
+	var existing jobRecord
+
+	findErr := tx.Where(whereScope, string(job.ExecutorType), string(job.Key)).Take(&existing).Error
+
+	switch {
+	case findErr == nil:
+		existing.Definition = definition
+		existing.Enabled = bool(job.Enabled)
+		existing.Version++
+		existing.UpdatedUnixNano = now
+
+		if saveErr := tx.Model(&jobRecord{}).Where(whereID, existing.ID).Updates(map[string]any{
+			columnDefinition: existing.Definition,
+			columnEnabled:    existing.Enabled,
+			columnVersion:    existing.Version,
+			columnUpdatedAt:  existing.UpdatedUnixNano,
+		}).Error; saveErr != nil {
+			return nil, false, databaseError(saveErr, action)
+		}
+
+		upserted, err = jobFromRecord(&existing)
+
+		return upserted, false, err
+	case !errors.Is(findErr, gorm.ErrRecordNotFound):
+		return nil, false, databaseError(findErr, action)
+	}
+
+	if job.ID.IsZero() {
+		return nil, false, yaerrors.FromError(
+			http.StatusBadRequest,
+			store.ErrZeroJobUUID,
+			logTag+" failed to "+action,
+		)
+	}
+
+	created := jobRecord{
+		ID:                 job.ID[:],
+		ExecutorType:       string(job.ExecutorType),
+		Key:                string(job.Key),
+		Definition:         definition,
+		Enabled:            bool(job.Enabled),
+		SkippedOccurrences: 0,
+		Version:            1,
+		CreatedUnixNano:    now,
+		UpdatedUnixNano:    now,
+	}
+
+	inserted := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&created)
+	if inserted.Error != nil {
+		return nil, false, databaseError(inserted.Error, action)
+	}
+
+	if inserted.RowsAffected == 0 {
+		return nil, true, nil
+	}
+
+	upserted, err = jobFromRecord(&created)
+
+	return upserted, false, err
+}
+
+// GetJob returns the job with the given identifier.
+func (s *Store) GetJob(
+	ctx context.Context,
+	id protocol.JobUUID,
+) (fetched *store.Job, err yaerrors.Error) {
+	const action = "fetch job"
+
+	var record jobRecord
+
+	if findErr := s.db.WithContext(ctx).Where(whereID, id[:]).Take(&record).Error; findErr != nil {
+		if errors.Is(findErr, gorm.ErrRecordNotFound) {
+			return nil, notFound(store.ErrJobNotFound, action)
+		}
+
+		return nil, databaseError(findErr, action)
+	}
+
+	return jobFromRecord(&record)
+}
+
+// GetJobByKey returns the job addressed by the given executor type and
+// key.
+func (s *Store) GetJobByKey(
+	ctx context.Context,
+	executorType protocol.ExecutorType,
+	key store.JobKey,
+) (fetched *store.Job, err yaerrors.Error) {
+	const action = "fetch job by key"
+
+	var record jobRecord
+
+	if findErr := s.db.WithContext(ctx).
+		Where(whereScope, string(executorType), string(key)).
+		Take(&record).Error; findErr != nil {
+		if errors.Is(findErr, gorm.ErrRecordNotFound) {
+			return nil, notFound(store.ErrJobNotFound, action)
+		}
+
+		return nil, databaseError(findErr, action)
+	}
+
+	return jobFromRecord(&record)
+}
+
+// DeleteJob removes the job with the given identifier and frees its
+// executor-scoped key, so a later upsert of the key materializes a fresh
+// job. It reports false when no job was stored, so a replayed delete is
+// not an error. Executions and pending results of the job stay untouched:
+// cleaning them is the engine's job, not the store's.
+func (s *Store) DeleteJob(
+	ctx context.Context,
+	id protocol.JobUUID,
+) (deleted bool, err yaerrors.Error) {
+	removed := s.db.WithContext(ctx).Where(whereID, id[:]).Delete(&jobRecord{})
+	if removed.Error != nil {
+		return false, databaseError(removed.Error, "delete job")
+	}
+
+	return removed.RowsAffected > 0, nil
+}
+
+// SetJobEnabled flips the scheduling eligibility of one job.
+func (s *Store) SetJobEnabled(
+	ctx context.Context,
+	id protocol.JobUUID,
+	enabled store.Enabled,
+) yaerrors.Error {
+	const action = "set job enabled state"
+
+	updated := s.db.WithContext(ctx).Model(&jobRecord{}).Where(whereID, id[:]).Updates(map[string]any{
+		columnEnabled:   bool(enabled),
+		columnVersion:   gorm.Expr(columnVersion + " + 1"),
+		columnUpdatedAt: unixNano(s.now()),
+	})
+	if updated.Error != nil {
+		return databaseError(updated.Error, action)
+	}
+
+	if updated.RowsAffected == 0 {
+		return notFound(store.ErrJobNotFound, action)
+	}
+
+	return nil
+}
+
+// AddSkippedOccurrences records occurrences dropped without dispatch.
+func (s *Store) AddSkippedOccurrences(
+	ctx context.Context,
+	id protocol.JobUUID,
+	count store.OccurrenceCount,
+) yaerrors.Error {
+	const action = "record skipped occurrences"
+
+	updated := s.db.WithContext(ctx).Model(&jobRecord{}).Where(whereID, id[:]).Updates(map[string]any{
+		columnSkipped:   gorm.Expr(columnSkipped+" + ?", uint64(count)),
+		columnUpdatedAt: unixNano(s.now()),
+	})
+	if updated.Error != nil {
+		return databaseError(updated.Error, action)
+	}
+
+	if updated.RowsAffected == 0 {
+		return notFound(store.ErrJobNotFound, action)
+	}
+
+	return nil
+}
+
+// ListEnabledJobs returns every schedulable job, ordered by identifier.
+func (s *Store) ListEnabledJobs(
+	ctx context.Context,
+) (jobs []*store.Job, err yaerrors.Error) {
+	return findJobs(
+		"list enabled jobs",
+		s.db.WithContext(ctx).Where(columnEnabled+" = ?", true).Order(orderByID),
+	)
+}
+
+// ListJobs returns one page of jobs, enabled or not, ordered by identifier:
+// those sorting after the given identifier, of the given executor type
+// unless it is empty, capped by a positive limit.
+func (s *Store) ListJobs(
+	ctx context.Context,
+	executorType protocol.ExecutorType,
+	after protocol.JobUUID,
+	limit store.BatchLimit,
+) (jobs []*store.Job, err yaerrors.Error) {
+	query := s.db.WithContext(ctx).Where(columnID+" > ?", after[:])
+
+	if executorType != "" {
+		query = query.Where(columnExecutorType+" = ?", string(executorType))
+	}
+
+	return findJobs("list jobs", limited(query.Order(orderByID), limit))
+}
+
+func findJobs(action string, query *gorm.DB) (jobs []*store.Job, err yaerrors.Error) {
+	var records []jobRecord
+
+	if findErr := query.Find(&records).Error; findErr != nil {
+		return nil, databaseError(findErr, action)
+	}
+
+	jobs = make([]*store.Job, 0, len(records))
+
+	for index := range records {
+		job, decodeErr := jobFromRecord(&records[index])
+		if decodeErr != nil {
+			return nil, decodeErr.Wrap(logTag + " failed to " + action)
+		}
+
+		jobs = append(jobs, job)
+	}
+
+	return jobs, nil
+}
+
+func jobFromRecord(record *jobRecord) (job *store.Job, err yaerrors.Error) {
+	definition, err := yaencoding.DecodeMessagePack[jobDefinition](record.Definition)
+	if err != nil {
+		return nil, err.Wrap(logTag + " failed to decode job definition")
+	}
+
+	job = &store.Job{
+		Key:                 store.JobKey(record.Key),
+		ExecutorType:        protocol.ExecutorType(record.ExecutorType),
+		Function:            definition.Function,
+		Args:                definition.Args,
+		Schedule:            definition.Schedule,
+		Enabled:             store.Enabled(record.Enabled),
+		Backfill:            definition.Backfill,
+		Retry:               definition.Retry,
+		Overlap:             definition.Overlap,
+		Pin:                 definition.Pin,
+		ResultMode:          definition.ResultMode,
+		TraceParent:         definition.TraceParent,
+		SubmitterInstanceID: definition.SubmitterInstanceID,
+		SkippedOccurrences:  store.OccurrenceCount(record.SkippedOccurrences),
+		Version:             store.Version(record.Version),
+		CreatedAt:           fromUnixNano(record.CreatedUnixNano),
+		UpdatedAt:           fromUnixNano(record.UpdatedUnixNano),
+	}
+
+	copy(job.ID[:], record.ID)
+
+	return job, nil
+}
@@ -0,0 +1,104 @@
+package sqlstore
+
+import (
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yascheduler/protocol"
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yascheduler/store"
+)
+
+// jobDefinition is the part of a job the store never queries on. It is
+// kept as one MessagePack column, so a spec field added by a later protocol
+// version persists without a schema change.
+type jobDefinition struct {
+	Function            protocol.FunctionSpec
+	Args                store.Payload
+	Schedule            protocol.ScheduleSpec
+	Backfill            protocol.BackfillSpec
+	Retry               protocol.RetrySpec
+	Overlap             protocol.OverlapPolicy
+	Pin                 protocol.PinSpec
+	ResultMode          protocol.ResultMode
+	TraceParent         protocol.TraceParent
+	SubmitterInstanceID protocol.InstanceID
+}
+
+// Instants are stored as UTC nanoseconds since the unix epoch, so every
+// backend round-trips them exactly whatever its own timestamp precision.
+// Zero marks an unset optional instant.
+
+type jobRecord struct {
+	ID                 []byte `gorm:"column:id;primaryKey"`
+	ExecutorType       string `gorm:"column:executor_type;not null;uniqueIndex:yascheduler_jobs_scope,priority:1"`
+	Key                string `gorm:"column:job_key;not null;uniqueIndex:yascheduler_jobs_scope,priority:2"`
+	Definition         []byte `gorm:"column:definition;not null"`
+	Enabled            bool   `gorm:"column:enabled;not null;index:yascheduler_jobs_enabled"`
+	SkippedOccurrences uint64 `gorm:"column:skipped_occurrences;not null"`
+	Version            uint64 `gorm:"column:version;not null"`
+	CreatedUnixNano    int64  `gorm:"column:created_at;not null"`
+	UpdatedUnixNano    int64  `gorm:"column:updated_at;not null"`
+}
+
+type executionRecord struct {
+	ID                uint64 `gorm:"column:id;primaryKey;autoIncrement"`
+	JobID             []byte `gorm:"column:job_id;not null;uniqueIndex:yascheduler_executions_occurrence,priority:1"`
+	ScheduledUnixNano int64  `gorm:"column:scheduled_at;not null;uniqueIndex:yascheduler_executions_occurrence,priority:2"`
+	State             uint8  `gorm:"column:state;not null;index:yascheduler_executions_state"`
+	FunctionAttempts  uint32 `gorm:"column:function_attempts;not null"`
+	CurrentAttemptID  uint64 `gorm:"column:current_attempt_id;not null"`
+	NextAttemptNano   int64  `gorm:"column:next_attempt_at;not null"`
+	LeaseExpiresNano  int64  `gorm:"column:lease_expires_at;not null"`
+	WakeUnixNano      *int64 `gorm:"column:wake_at;index:yascheduler_executions_wake"`
+	Backfilled        bool   `gorm:"column:backfilled;not null"`
+	LastError         string `gorm:"column:last_error;not null"`
+	WaitReason        string `gorm:"column:wait_reason;not null"`
+	Version           uint64 `gorm:"column:version;not null"`
+	CreatedUnixNano   int64  `gorm:"column:created_at;not null"`
+	UpdatedUnixNano   int64  `gorm:"column:updated_at;not null;index:yascheduler_executions_settled"`
+}
+
+// attemptRecord keeps Listed beside State because an attempt leaves its
+// instance listing for good once it settles, exactly as the other stores
+// drop it from their per-instance index.
+type attemptRecord struct {
+	ID              uint64 `gorm:"column:id;primaryKey;autoIncrement"`
+	ExecutionID     uint64 `gorm:"column:execution_id;not null;index:yascheduler_attempts_execution"`
+	Number          uint32 `gorm:"column:number;not null"`
+	InstanceID      string `gorm:"column:instance_id;not null;index:yascheduler_attempts_instance,priority:1"`
+	Listed          bool   `gorm:"column:listed;not null;index:yascheduler_attempts_instance,priority:2"`
+	State           uint8  `gorm:"column:state;not null"`
+	Error           string `gorm:"column:error;not null"`
+	CreatedUnixNano int64  `gorm:"column:created_at;not null"`
+	UpdatedUnixNano int64  `gorm:"column:updated_at;not null"`
+}
+
+// resultRecord is keyed by a store-minted Sequence rather than by the job,
+// so storage order is the key order and a result moved to another
+// instance, which is re-inserted, lands at the end of that instance's
+// list.
+type resultRecord struct {
+	Sequence        uint64 `gorm:"column:sequence;primaryKey;autoIncrement"`
+	JobUUID         []byte `gorm:"column:job_uuid;not null;uniqueIndex:yascheduler_results_job"`
+	InstanceID      string `gorm:"column:instance_id;not null;index:yascheduler_results_instance"`
+	ExecutionID     uint64 `gorm:"column:execution_id;not null"`
+	Success         bool   `gorm:"column:success;not null"`
+	HasValue        bool   `gorm:"column:has_value;not null"`
+	Payload         []byte `gorm:"column:payload"`
+	HasCause        bool   `gorm:"column:has_cause;not null"`
+	CauseCode       uint16 `gorm:"column:cause_code;not null"`
+	CauseRetryable  bool   `gorm:"column:cause_retryable;not null"`
+	CauseMessage    string `gorm:"column:cause_message;not null"`
+	Attempts        uint32 `gorm:"column:attempts;not null"`
+	CreatedUnixNano int64  `gorm:"column:created_at;not null;index:yascheduler_results_created"`
+	LastSentNano    int64  `gorm:"column:last_sent_at;not null"`
+}
+
+// TableName implements gorm's tabler.
+func (jobRecord) TableName() string { return tableJobs }
+
+// TableName implements gorm's tabler.
+func (executionRecord) TableName() string { return tableExecutions }
+
+// TableName implements gorm's tabler.
+func (attemptRecord) TableName() string { return tableAttempts }
+
+// TableName implements gorm's tabler.
+func (resultRecord) TableName() string { return tableResults }
@@ -0,0 +1,269 @@
+package sqlstore
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yaerrors"
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yascheduler/protocol"
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yascheduler/store"
+	"gorm.io/gorm"
+)
+
+// StoreResult holds a settled result for delivery, replacing any result
+// already held for the same job. It reports false when the store-wide or
+// the per-instance cap refuses a result that would add to it. A
+// replacement keeps the delivery counters and storage time of the result
+// it replaces; one bound for another instance moves to the end of that
+// instance's list.
+func (s *Store) StoreResult(
+	ctx context.Context,
+	result *store.PendingResult,
+) (stored bool, err yaerrors.Error) {
+	const action = "store result"
+
+	if result == nil {
+		return false, yaerrors.FromError(
+			http.StatusBadRequest,
+			store.ErrNilResult,
+			logTag+" failed to "+action,
+		)
+	}
+
+	err = s.transaction(ctx, action, func(tx *gorm.DB) yaerrors.Error {
+		var existing resultRecord
+
+		found := true
+
+		if findErr := tx.Where(whereJobUUID, result.JobUUID[:]).Take(&existing).Error; findErr != nil {
+			if !errors.Is(findErr, gorm.ErrRecordNotFound) {
+				return databaseError(findErr, action)
+			}
+
+			found = false
+		}
+
+		if !found {
+			full, countErr := countAtLeast(tx.Model(&resultRecord{}), s.maxResults)
+			if countErr != nil || full {
+				return countErr
+			}
+		}
+
+		moved := found && existing.InstanceID != string(result.InstanceID)
+
+		if !found || moved {
+			full, countErr := countAtLeast(
+				tx.Model(&resultRecord{}).Where(columnInstanceID+" = ?", string(result.InstanceID)),
+				s.maxResultsPerInstance,
+			)
+			if countErr != nil || full {
+				return countErr
+			}
+		}
+
+		record := resultToRecord(result)
+
+		if found {
+			record.Attempts = existing.Attempts
+			record.CreatedUnixNano = existing.CreatedUnixNano
+			record.LastSentNano = existing.LastSentNano
+		} else {
+			record.CreatedUnixNano = unixNano(s.now())
+		}
+
+		if found && !moved {
+			record.Sequence = existing.Sequence
+
+			if saveErr := tx.Save(&record).Error; saveErr != nil {
+				return databaseError(saveErr, action)
+			}
+
+			stored = true
+
+			return nil
+		}
+
+		if moved {
+			if deleteErr := tx.Where(whereJobUUID, result.JobUUID[:]).
+				Delete(&resultRecord{}).Error; deleteErr != nil {
+				return databaseError(deleteErr, action)
+			}
+		}
+
+		if createErr := tx.Create(&record).Error; createErr != nil {
+			return databaseError(createErr, action)
+		}
+
+		stored = true
+
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return stored, nil
+}
+
+// DeleteResult drops the pending result of one job. It reports false when
+// no result was held, so an acknowledgement replay is not an error.
+func (s *Store) DeleteResult(
+	ctx context.Context,
+	jobUUID protocol.JobUUID,
+) (deleted bool, err yaerrors.Error) {
+	removed := s.db.WithContext(ctx).Where(whereJobUUID, jobUUID[:]).Delete(&resultRecord{})
+	if removed.Error != nil {
+		return false, databaseError(removed.Error, "delete result")
+	}
+
+	return removed.RowsAffected > 0, nil
+}
+
+// ResultsForInstance returns the pending results bound for one instance in
+// storage order, capped by a positive limit.
+func (s *Store) ResultsForInstance(
+	ctx context.Context,
+	id protocol.InstanceID,
+	limit store.BatchLimit,
+) (results []*store.PendingResult, err yaerrors.Error) {
+	return findResults(
+		"list results for instance",
+		limited(
+			s.db.WithContext(ctx).
+				Where(columnInstanceID+" = ?", string(id)).
+				Order(orderBySequence),
+			limit,
+		),
+	)
+}
+
+// MarkResultSent records one delivery attempt of a pending result.
+func (s *Store) MarkResultSent(
+	ctx context.Context,
+	jobUUID protocol.JobUUID,
+	at time.Time,
+) yaerrors.Error {
+	const action = "mark result sent"
+
+	written := s.db.WithContext(ctx).
+		Model(&resultRecord{}).
+		Where(whereJobUUID, jobUUID[:]).
+		Updates(map[string]any{
+			columnAttempts:   gorm.Expr(columnAttempts + " + 1"),
+			columnLastSentAt: unixNano(at),
+		})
+	if written.Error != nil {
+		return databaseError(written.Error, action)
+	}
+
+	if written.RowsAffected == 0 {
+		return notFound(store.ErrResultNotFound, action)
+	}
+
+	return nil
+}
+
+// ExpiredResults returns pending results stored before the given instant,
+// ordered by storage time then job identifier, capped by a positive limit.
+func (s *Store) ExpiredResults(
+	ctx context.Context,
+	before time.Time,
+	limit store.BatchLimit,
+) (expired []*store.PendingResult, err yaerrors.Error) {
+	return findResults(
+		"list expired results",
+		limited(
+			s.db.WithContext(ctx).
+				Where(columnCreatedAt+" < ?", unixNano(before)).
+				Order(orderByStorageTime),
+			limit,
+		),
+	)
+}
+
+// CountResults returns how many pending results the store holds.
+func (s *Store) CountResults(
+	ctx context.Context,
+) (count store.OccurrenceCount, err yaerrors.Error) {
+	var held int64
+
+	if countErr := s.db.WithContext(ctx).Model(&resultRecord{}).Count(&held).Error; countErr != nil {
+		return 0, databaseError(countErr, "count results")
+	}
+
+	return store.OccurrenceCount(held), nil
+}
+
+// countAtLeast reports whether the rows query selects reach limit.
+func countAtLeast(query *gorm.DB, limit store.OccurrenceCount) (full bool, err yaerrors.Error) {
+	var held int64
+
+	if countErr := query.Count(&held).Error; countErr != nil {
+		return false, databaseError(countErr, "count results")
+	}
+
+	return store.OccurrenceCount(held) >= limit, nil
+}
+
+func findResults(action string, query *gorm.DB) (results []*store.PendingResult, err yaerrors.Error) {
+	var records []resultRecord
+
+	if findErr := query.Find(&records).Error; findErr != nil {
+		return nil, databaseError(findErr, action)
+	}
+
+	results = make([]*store.PendingResult, 0, len(records))
+	for index := range records {
+		results = append(results, resultFromRecord(&records[index]))
+	}
+
+	return results, nil
+}
+
+func resultToRecord(result *store.PendingResult) (record resultRecord) {
+	record = resultRecord{
+		JobUUID:     result.JobUUID[:],
+		InstanceID:  string(result.InstanceID),
+		ExecutionID: uint64(result.ExecutionID),
+		Success:     bool(result.Success),
+		HasValue:    bool(result.HasValue),
+		Payload:     result.Payload,
+	}
+
+	if result.Cause != nil {
+		record.HasCause = true
+		record.CauseCode = uint16(result.Cause.Code)
+		record.CauseRetryable = result.Cause.Retryable
+		record.CauseMessage = result.Cause.Message
+	}
+
+	return record
+}
+
+func resultFromRecord(record *resultRecord) (result *store.PendingResult) {
+	result = &store.PendingResult{
+		InstanceID:  protocol.InstanceID(record.InstanceID),
+		ExecutionID: protocol.ExecutionID(record.ExecutionID),
+		Success:     store.Delivered(record.Success),
+		HasValue:    store.HasValue(record.HasValue),
+		Payload:     record.Payload,
+		Attempts:    store.ResultAttempts(record.Attempts),
+		CreatedAt:   fromUnixNano(record.CreatedUnixNano),
+		LastSentAt:  fromOptionalUnixNano(record.LastSentNano),
+	}
+
+	copy(result.JobUUID[:], record.JobUUID)
+
+	if record.HasCause {
+		result.Cause = &protocol.WireError{
+			Code:      protocol.ErrorCode(record.CauseCode),
+			Retryable: record.CauseRetryable,
+			Message:   record.CauseMessage,
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,124 @@
+// Package sqlstore persists the scheduler store in a SQL database through
+// gorm, targeting PostgreSQL and SQLite. It uses four tables named by
+// fixed yascheduler_ identifiers, so one database schema holds one
+// scheduler's records; give each scheduler its own schema to share a
+// server.
+//
+// Every multi-step invariant runs in one transaction: an upsert resolves
+// the executor-scoped key and writes the job together, an occurrence is
+// deduplicated by a unique index instead of a read-then-insert, and an
+// execution update re-checks its optimistic Version in the UPDATE itself,
+// so a concurrent writer that moved the row first turns the update into
+// store.ErrVersionConflict rather than a lost write. The pending-result
+// caps are counted inside the storing transaction; they are exact under
+// SQLite, whose writers serialize, and may be overshot by concurrent
+// writers under weaker PostgreSQL isolation levels.
+//
+// Instants are stored as integer nanoseconds, so they round-trip exactly on
+// every backend, and the job definition the store never queries on is kept
+// as one MessagePack column, so a spec field added by a later protocol
+// version persists without a migration.
+package sqlstore
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yaerrors"
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yascheduler/store"
+	"gorm.io/gorm"
+)
+
+var _ store.Store = (*Store)(nil)
+
+// Config bounds the pending-result storage of a SQL store. A zero field
+// applies its package default.
+type Config struct {
+	// MaxResults caps how many pending results the store holds in total.
+	MaxResults store.OccurrenceCount
+
+	// MaxResultsPerInstance caps how many pending results the store holds
+	// for one submitting instance.
+	MaxResultsPerInstance store.OccurrenceCount
+}
+
+// Store is a gorm-backed store.Store.
+type Store struct {
+	db *gorm.DB
+
+	maxResults            store.OccurrenceCount
+	maxResultsPerInstance store.OccurrenceCount
+
+	mu    sync.RWMutex
+	clock func() time.Time
+}
+
+// NewStore builds a store over an already-configured gorm database,
+// bounded by the given config, and migrates its tables.
+//
+// Example usage:
+//
+//	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+//	// handle err, then:
+//	jobStore, yaerr := sqlstore.NewStore(ctx, db, sqlstore.Config{})
+func NewStore(ctx context.Context, db *gorm.DB, config Config) (*Store, yaerrors.Error) {
+	maxResults := config.MaxResults
+	if maxResults == 0 {
+		maxResults = DefaultMaxResults
+	}
+
+	maxResultsPerInstance := config.MaxResultsPerInstance
+	if maxResultsPerInstance == 0 {
+		maxResultsPerInstance = DefaultMaxResultsPerInstance
+	}
+
+	created := &Store{
+		db:                    db,
+		maxResults:            maxResults,
+		maxResultsPerInstance: maxResultsPerInstance,
+		clock:                 func() time.Time { return time.Now().UTC() },
+	}
+
+	if err := created.Migrate(ctx); err != nil {
+		return nil, err.Wrap(logTag + " failed to build store")
+	}
+
+	return created, nil
+}
+
+// Migrate creates the store's tables and indices, or brings existing ones
+// up to the current schema. It only ever adds, so running it against an
+// up-to-date database changes nothing; NewStore runs it on every start.
+func (s *Store) Migrate(ctx context.Context) yaerrors.Error {
+	if err := s.db.WithContext(ctx).AutoMigrate(
+		&jobRecord{},
+		&executionRecord{},
+		&attemptRecord{},
+		&resultRecord{},
+	); err != nil {
+		return yaerrors.FromError(
+			http.StatusInternalServerError,
+			err,
+			logTag+" failed to migrate tables",
+		)
+	}
+
+	return nil
+}
+
+// SetClock replaces the time source every stored timestamp is read from.
+func (s *Store) SetClock(clock func() time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.clock = clock
+}
+
+func (s *Store) now() (instant time.Time) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.clock()
+}
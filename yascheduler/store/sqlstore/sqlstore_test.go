@@ -0,0 +1,283 @@
+package sqlstore_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yaerrors"
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yascheduler/protocol"
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yascheduler/store"
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yascheduler/store/sqlstore"
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yascheduler/store/storetest"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	_ "modernc.org/sqlite"
+)
+
+const (
+	testExecutorType = protocol.ExecutorType("worker")
+	testFunctionName = protocol.FunctionName("report")
+)
+
+func newTestDB(t *testing.T) (db *gorm.DB) {
+	t.Helper()
+
+	sqlDB, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open sqlite in memory: %v", err)
+	}
+
+	// Every connection to :memory: is a database of its own.
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	db, err = gorm.Open(
+		sqlite.Dialector{Conn: sqlDB, DriverName: "sqlite"},
+		&gorm.Config{Logger: logger.Discard},
+	)
+	if err != nil {
+		t.Fatalf("failed to open gorm over sqlite: %v", err)
+	}
+
+	return db
+}
+
+func newTestStore(t *testing.T, config sqlstore.Config) (sut *sqlstore.Store) {
+	t.Helper()
+
+	sut, err := sqlstore.NewStore(context.Background(), newTestDB(t), config)
+	requireNoTestError(t, err, "store construction should migrate the schema")
+
+	return sut
+}
+
+func testJobUUID(seed store.JobKey) (id protocol.JobUUID) {
+	seedLength := len(seed)
+	if seedLength > len(id)-1 {
+		seedLength = len(id) - 1
+	}
+
+	id[0] = byte(seedLength)
+	copy(id[1:], seed[:seedLength])
+
+	return id
+}
+
+func newTestJob(idSeed store.JobKey, key store.JobKey) (job *store.Job) {
+	return &store.Job{
+		ID:           testJobUUID(idSeed),
+		Key:          key,
+		ExecutorType: testExecutorType,
+		Function:     protocol.FunctionSpec{Name: testFunctionName},
+		Enabled:      true,
+	}
+}
+
+func requireNoTestError(t *testing.T, err yaerrors.Error, intent string) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatalf("%s: %v", intent, err)
+	}
+}
+
+func TestConformance(t *testing.T) {
+	t.Parallel()
+
+	storetest.TestStore(t, func(t *testing.T) store.Store {
+		t.Helper()
+
+		return newTestStore(t, sqlstore.Config{})
+	})
+}
+
+func TestConformanceResultCaps(t *testing.T) {
+	t.Parallel()
+
+	storetest.TestResultCaps(t, func(t *testing.T, caps storetest.Caps) store.Store {
+		t.Helper()
+
+		return newTestStore(t, sqlstore.Config{
+			MaxResults:            caps.MaxResults,
+			MaxResultsPerInstance: caps.MaxResultsPerInstance,
+		})
+	})
+}
+
+func TestMigrate(t *testing.T) {
+	t.Parallel()
+
+	t.Run(
+		"when a store reopens a migrated database / then the stored jobs survive",
+		func(t *testing.T) {
+			t.Parallel()
+
+			const jobKey = store.JobKey("migrate-reopen")
+
+			db := newTestDB(t)
+
+			first, err := sqlstore.NewStore(context.Background(), db, sqlstore.Config{})
+			requireNoTestError(t, err, "the first store should migrate the schema")
+
+			created, err := first.UpsertJob(context.Background(), newTestJob(jobKey, jobKey))
+			requireNoTestError(t, err, "job creation should not fail")
+
+			second, err := sqlstore.NewStore(context.Background(), db, sqlstore.Config{})
+			requireNoTestError(t, err, "migrating an already migrated schema should not fail")
+
+			fetched, err := second.GetJobByKey(context.Background(), testExecutorType, jobKey)
+			requireNoTestError(t, err, "the reopened store should find the job")
+
+			if fetched.ID != created.ID {
+				t.Errorf("the reopened store should keep the job identity: got %s, want %s", fetched.ID, created.ID)
+			}
+		},
+	)
+}
+
+func TestTimePrecision(t *testing.T) {
+	t.Parallel()
+
+	t.Run(
+		"when an execution is created with a nanosecond instant / then the instant round-trips exactly",
+		func(t *testing.T) {
+			t.Parallel()
+
+			const jobKey = store.JobKey("precision-create")
+
+			scheduledAt := time.Date(2023, time.November, 14, 22, 13, 20, 123456789, time.UTC)
+
+			sut := newTestStore(t, sqlstore.Config{})
+
+			job, err := sut.UpsertJob(context.Background(), newTestJob(jobKey, jobKey))
+			requireNoTestError(t, err, "job creation should not fail")
+
+			execution, _, err := sut.CreateExecution(
+				context.Background(),
+				job.ID,
+				scheduledAt,
+				store.StateScheduled,
+				false,
+			)
+			requireNoTestError(t, err, "execution creation should not fail")
+
+			fetched, err := sut.GetExecution(context.Background(), execution.ID)
+			requireNoTestError(t, err, "execution fetch should not fail")
+
+			if fetched.ScheduledAt.UnixNano() != scheduledAt.UnixNano() {
+				t.Errorf(
+					"the schedule instant should keep nanoseconds: got %d, want %d",
+					fetched.ScheduledAt.UnixNano(),
+					scheduledAt.UnixNano(),
+				)
+			}
+		},
+	)
+
+	t.Run(
+		"when a fresh result is stored / then the last sent instant stays zero",
+		func(t *testing.T) {
+			t.Parallel()
+
+			const (
+				resultSeed = store.JobKey("precision-result")
+				instanceID = protocol.InstanceID("exec-1")
+			)
+
+			sut := newTestStore(t, sqlstore.Config{})
+
+			_, err := sut.StoreResult(context.Background(), &store.PendingResult{
+				JobUUID:    testJobUUID(resultSeed),
+				InstanceID: instanceID,
+				Success:    true,
+			})
+			requireNoTestError(t, err, "result storage should not fail")
+
+			held, err := sut.ResultsForInstance(context.Background(), instanceID, 0)
+			requireNoTestError(t, err, "instance result lookup should not fail")
+
+			if len(held) != 1 || !held[0].LastSentAt.IsZero() {
+				t.Errorf("an unsent result should keep the zero instant: got %v", held)
+			}
+		},
+	)
+}
+
+func TestUpdateExecutionContention(t *testing.T) {
+	t.Parallel()
+
+	t.Run(
+		"when two updates race one version / then exactly one wins and the other conflicts",
+		func(t *testing.T) {
+			t.Parallel()
+
+			const (
+				jobKey = store.JobKey("contention")
+				racers = 2
+			)
+
+			sut := newTestStore(t, sqlstore.Config{})
+
+			job, err := sut.UpsertJob(context.Background(), newTestJob(jobKey, jobKey))
+			requireNoTestError(t, err, "job creation should not fail")
+
+			execution, _, err := sut.CreateExecution(
+				context.Background(),
+				job.ID,
+				time.Date(2023, time.November, 14, 22, 13, 20, 0, time.UTC),
+				store.StateScheduled,
+				false,
+			)
+			requireNoTestError(t, err, "execution creation should not fail")
+
+			ready := store.StateReady
+			start := make(chan struct{})
+			outcomes := make(chan yaerrors.Error, racers)
+
+			var group sync.WaitGroup
+
+			for range racers {
+				group.Add(1)
+
+				go func() {
+					defer group.Done()
+					<-start
+
+					_, updateErr := sut.UpdateExecution(
+						context.Background(),
+						execution.ID,
+						execution.Version,
+						store.ExecutionUpdate{State: &ready},
+					)
+					outcomes <- updateErr
+				}()
+			}
+
+			close(start)
+			group.Wait()
+			close(outcomes)
+
+			var wins, conflicts int
+
+			for outcome := range outcomes {
+				switch {
+				case outcome == nil:
+					wins++
+				case errors.Is(outcome, store.ErrVersionConflict):
+					conflicts++
+				default:
+					t.Fatalf("a racing update should win or conflict: got %v", outcome)
+				}
+			}
+
+			if wins != 1 || conflicts != 1 {
+				t.Errorf("exactly one racer should win: got %d wins and %d conflicts", wins, conflicts)
+			}
+		},
+	)
+}
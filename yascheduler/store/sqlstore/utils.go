@@ -0,0 +1,151 @@
+package sqlstore
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yaerrors"
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yascheduler/store"
+	"gorm.io/gorm"
+)
+
+// transaction runs fn in one database transaction, rolling back when fn
+// fails. A failure fn reports is returned as is; only a failure of the
+// transaction itself is wrapped as a database error.
+func (s *Store) transaction(
+	ctx context.Context,
+	action string,
+	fn func(tx *gorm.DB) yaerrors.Error,
+) (err yaerrors.Error) {
+	txErr := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err = fn(tx)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if txErr != nil {
+		return databaseError(txErr, action)
+	}
+
+	return nil
+}
+
+func databaseError(cause error, action string) (err yaerrors.Error) {
+	return yaerrors.FromError(
+		http.StatusInternalServerError,
+		cause,
+		logTag+" failed to "+action,
+	)
+}
+
+func notFound(sentinel error, action string) (err yaerrors.Error) {
+	return yaerrors.FromError(
+		http.StatusNotFound,
+		sentinel,
+		logTag+" failed to "+action,
+	)
+}
+
+func limited(query *gorm.DB, limit store.BatchLimit) (bounded *gorm.DB) {
+	if limit > 0 {
+		return query.Limit(int(limit))
+	}
+
+	return query
+}
+
+func unixNano(instant time.Time) (nanos int64) {
+	return instant.UTC().UnixNano()
+}
+
+func fromUnixNano(nanos int64) (instant time.Time) {
+	return time.Unix(0, nanos).UTC()
+}
+
+// optionalUnixNano stores an unset instant as zero, so it reads back as
+// the zero time rather than as the unix epoch.
+func optionalUnixNano(instant time.Time) (nanos int64) {
+	if instant.IsZero() {
+		return 0
+	}
+
+	return unixNano(instant)
+}
+
+func fromOptionalUnixNano(nanos int64) (instant time.Time) {
+	if nanos == 0 {
+		return time.Time{}
+	}
+
+	return fromUnixNano(nanos)
+}
+
+// wakeColumn returns the instant an execution in the given state becomes
+// due, or nil for a state no timer wakes, so DueExecutions and NextWakeAt
+// read one indexed column.
+func wakeColumn(
+	state store.ExecutionState,
+	scheduledAt int64,
+	nextAttemptAt int64,
+) (wake *int64) {
+	switch state {
+	case store.StateScheduled:
+		return &scheduledAt
+	case store.StateReady, store.StateRetryWait:
+		return &nextAttemptAt
+	case store.StateWaitingExecutor,
+		store.StateWaitingCompatible,
+		store.StateWaitingLabel,
+		store.StateDispatching,
+		store.StateRunning,
+		store.StateSucceeded,
+		store.StateFailed,
+		store.StateCancelled,
+		store.StateSkipped:
+		return nil
+	default:
+		return nil
+	}
+}
+
+// State lists bound to an IN clause are []int, never []uint8: the driver
+// would bind a []uint8 as one blob rather than expand it.
+
+func terminalExecutionStates() (states []int) {
+	return []int{
+		int(store.StateSucceeded),
+		int(store.StateFailed),
+		int(store.StateCancelled),
+		int(store.StateSkipped),
+	}
+}
+
+func leasedExecutionStates() (states []int) {
+	return []int{int(store.StateDispatching), int(store.StateRunning)}
+}
+
+func executionStateValues(states []store.ExecutionState) (values []int) {
+	values = make([]int, 0, len(states))
+	for _, state := range states {
+		values = append(values, int(state))
+	}
+
+	return values
+}
+
+func attemptStateValues(states []store.AttemptState) (values []int) {
+	values = make([]int, 0, len(states))
+	for _, state := range states {
+		values = append(values, int(state))
+	}
+
+	return values
+}
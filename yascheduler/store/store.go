@@ -13,7 +13,11 @@ import (
 // DeleteJob removes the stored job and frees its executor-scoped key,
 // reporting false with no error when no job was stored, so a replayed
 // delete is idempotent; executions and pending results of the job are the
-// engine's to clean.
+// engine's to clean. ListJobs pages through every job, enabled or not, in
+// identifier order: it returns the jobs whose identifier sorts after the
+// given one, so the zero identifier starts from the beginning, restricted
+// to one executor type unless that is empty and capped by a positive
+// limit.
 type JobRepository interface {
 	UpsertJob(ctx context.Context, job *Job) (*Job, yaerrors.Error)
 	GetJob(ctx context.Context, id protocol.JobUUID) (*Job, yaerrors.Error)
@@ -34,6 +38,12 @@ type JobRepository interface {
 		count OccurrenceCount,
 	) yaerrors.Error
 	ListEnabledJobs(ctx context.Context) ([]*Job, yaerrors.Error)
+	ListJobs(
+		ctx context.Context,
+		executorType protocol.ExecutorType,
+		after protocol.JobUUID,
+		limit BatchLimit,
+	) ([]*Job, yaerrors.Error)
 }
 
 // ExecutionRepository persists materialized occurrences of jobs.
@@ -41,7 +51,10 @@ type JobRepository interface {
 // error when none was stored, so a replayed delete is idempotent; cleaning
 // up the execution's attempts is the caller's job, not the store's.
 // ExpiredExecutions returns terminal executions that settled before a
-// cutoff, mirroring ExpiredResults' retention contract.
+// cutoff, mirroring ExpiredResults' retention contract. NextRunTimes
+// reports the earliest Execution.PendingDueAt of each listed job in one
+// query, reading only pending executions, so listing jobs never loads their
+// history; jobs with nothing pending are absent from the map.
 type ExecutionRepository interface {
 	CreateExecution(
 		ctx context.Context,
@@ -83,6 +96,10 @@ type ExecutionRepository interface {
 		ctx context.Context,
 		jobID protocol.JobUUID,
 	) (bool, yaerrors.Error)
+	NextRunTimes(
+		ctx context.Context,
+		jobIDs []protocol.JobUUID,
+	) (map[protocol.JobUUID]time.Time, yaerrors.Error)
 	ExpiredLeases(
 		ctx context.Context,
 		now time.Time,
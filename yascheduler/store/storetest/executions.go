@@ -742,6 +742,45 @@ func TestExecutionRepository(t *testing.T, factory Factory) {
 		},
 	)
 
+	t.Run(
+		"when listed jobs have pending occurrences / then each reports its earliest due instant",
+		func(t *testing.T) {
+			t.Parallel()
+
+			sut := factory(t)
+			job := createJob(t, sut, "job-next-run")
+			settledJob := createJob(t, sut, "job-next-run-settled")
+			unlistedJob := createJob(t, sut, "job-next-run-unlisted")
+
+			driveState(
+				t,
+				sut,
+				createExecution(t, sut, job.ID, baseTime.Add(-time.Minute)),
+				store.StateDispatching,
+				store.StateRunning,
+			)
+			createExecution(t, sut, job.ID, baseTime.Add(time.Hour))
+			createExecution(t, sut, job.ID, baseTime.Add(time.Minute))
+			driveState(
+				t,
+				sut,
+				createExecution(t, sut, settledJob.ID, baseTime),
+				store.StateCancelled,
+			)
+			createExecution(t, sut, unlistedJob.ID, baseTime)
+
+			nextRuns := nextRunTimes(t, sut, job.ID, settledJob.ID)
+
+			if len(nextRuns) != 1 || !nextRuns[job.ID].Equal(baseTime.Add(time.Minute)) {
+				t.Errorf("only the earliest pending occurrence should be reported: %v", nextRuns)
+			}
+
+			if empty := nextRunTimes(t, sut); len(empty) != 0 {
+				t.Errorf("no listed job should report nothing: %v", empty)
+			}
+		},
+	)
+
 	t.Run(
 		"when leases are compared to now / then only elapsed leased executions return",
 		func(t *testing.T) {
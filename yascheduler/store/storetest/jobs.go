@@ -4,12 +4,14 @@ import (
 	"context"
 	"testing"
 
+	"github.com/YaCodeDev/GoYaCodeDevUtils/yascheduler/protocol"
 	"github.com/YaCodeDev/GoYaCodeDevUtils/yascheduler/store"
 )
 
 // TestJobRepository runs the job conformance subtests against stores the
 // factory builds: upsert identity, executor-scoped keys, lookups, deletes,
-// the enabled flag, skipped-occurrence counters, and enabled listing.
+// the enabled flag, skipped-occurrence counters, enabled listing, and
+// paged listing of every job.
 func TestJobRepository(t *testing.T, factory Factory) {
 	t.Helper()
 
@@ -504,4 +506,98 @@ func TestJobRepository(t *testing.T, factory Factory) {
 			}
 		},
 	)
+	t.Run(
+		"when every job is listed / then disabled jobs return too in identifier order",
+		func(t *testing.T) {
+			t.Parallel()
+
+			const (
+				laterKey    = store.JobKey("job-all-b")
+				earlierKey  = store.JobKey("job-all-a")
+				disabledKey = store.JobKey("job-all-c")
+				wantListed  = 3
+			)
+
+			sut := factory(t)
+
+			later := createJob(t, sut, laterKey)
+			earlier := createJob(t, sut, earlierKey)
+
+			disabled := newJob(disabledKey)
+			disabled.Enabled = false
+
+			stored := upsertJob(t, sut, disabled)
+
+			listed := listJobs(t, sut, "", protocol.JobUUID{}, unlimited)
+
+			if len(listed) != wantListed {
+				t.Fatalf("every job should list: got %d", len(listed))
+			}
+
+			if listed[0].ID != earlier.ID || listed[1].ID != later.ID || listed[2].ID != stored.ID {
+				t.Errorf("jobs should order by identifier: got %v", listed)
+			}
+
+			if listed[2].Enabled {
+				t.Error("a disabled job should list as disabled")
+			}
+		},
+	)
+
+	t.Run(
+		"when jobs are paged / then each page resumes after the given identifier",
+		func(t *testing.T) {
+			t.Parallel()
+
+			const pageSize = store.BatchLimit(2)
+
+			sut := factory(t)
+
+			keys := []store.JobKey{"job-page-a", "job-page-b", "job-page-c"}
+			for _, key := range keys {
+				createJob(t, sut, key)
+			}
+
+			first := listJobs(t, sut, "", protocol.JobUUID{}, pageSize)
+			if len(first) != int(pageSize) || first[0].Key != keys[0] || first[1].Key != keys[1] {
+				t.Fatalf("the first page should hold the two lowest identifiers: got %v", first)
+			}
+
+			second := listJobs(t, sut, "", first[len(first)-1].ID, pageSize)
+			if len(second) != 1 || second[0].Key != keys[2] {
+				t.Fatalf("the second page should hold the remaining job: got %v", second)
+			}
+
+			if rest := listJobs(t, sut, "", second[0].ID, pageSize); len(rest) != 0 {
+				t.Errorf("a page after the last job should be empty: got %v", rest)
+			}
+		},
+	)
+
+	t.Run(
+		"when jobs are listed for one executor type / then other types are left out",
+		func(t *testing.T) {
+			t.Parallel()
+
+			const (
+				suiteKey = store.JobKey("job-type-a")
+				otherKey = store.JobKey("job-type-b")
+			)
+
+			sut := factory(t)
+
+			createJob(t, sut, suiteKey)
+
+			other := newJob(otherKey)
+			other.ExecutorType = otherExecutorType
+
+			upsertJob(t, sut, other)
+
+			listed := listJobs(t, sut, otherExecutorType, protocol.JobUUID{}, unlimited)
+
+			if len(listed) != 1 || listed[0].Key != otherKey {
+				t.Errorf("only the other executor type's job should list: got %v", listed)
+			}
+		},
+	)
 }
@@ -114,6 +114,21 @@ func listEnabledJobs(t *testing.T, sut store.Store) (jobs []*store.Job) {
 	return jobs
 }
 
+func listJobs(
+	t *testing.T,
+	sut store.Store,
+	executorType protocol.ExecutorType,
+	after protocol.JobUUID,
+	limit store.BatchLimit,
+) (jobs []*store.Job) {
+	t.Helper()
+
+	jobs, err := sut.ListJobs(context.Background(), executorType, after, limit)
+	requireNoError(t, err, "job listing should not fail")
+
+	return jobs
+}
+
 func createExecution(
 	t *testing.T,
 	sut store.Store,
@@ -263,6 +278,19 @@ func hasPendingOccurrence(
 	return pending
 }
 
+func nextRunTimes(
+	t *testing.T,
+	sut store.Store,
+	jobIDs ...protocol.JobUUID,
+) (nextRuns map[protocol.JobUUID]time.Time) {
+	t.Helper()
+
+	nextRuns, err := sut.NextRunTimes(context.Background(), jobIDs)
+	requireNoError(t, err, "next run lookup should not fail")
+
+	return nextRuns
+}
+
 func expiredLeases(
 	t *testing.T,
 	sut store.Store,
@@ -1,6 +1,7 @@
 package yascheduler
 
 import (
+	"crypto/tls"
 	"net/http"
 	"time"
 
@@ -33,9 +34,22 @@ type Config struct {
 	// it assigns a different one.
 	HeartbeatInterval time.Duration
 
-	// DialTimeout bounds one TCP connect attempt.
+	// DialTimeout bounds one TCP connect attempt, including the TLS
+	// handshake when TLS is set.
 	DialTimeout time.Duration
 
+	// TLS, when set, connects over TLS with this configuration. Set
+	// Certificates to present a client certificate to a scheduler that
+	// requires mutual TLS; leave it nil for plain TCP.
+	TLS *tls.Config
+
+	// AuthSecret is the shared secret this client answers a scheduler's
+	// authentication challenge with. A scheduler that challenges a client
+	// without one is refused locally with ErrAuthSecretMissing. The secret
+	// authenticates this executor to the scheduler; use TLS to
+	// authenticate the scheduler in turn.
+	AuthSecret []byte
+
 	// WriteTimeout bounds one frame write.
 	WriteTimeout time.Duration
 
@@ -149,7 +163,8 @@ type JobSpec struct {
 	// moved anchor cancels the pending occurrence and re-phases the
 	// schedule from the new anchor — a fixed-interval job re-anchored to
 	// the current time on every deploy and republished more often than
-	// its interval never fires.
+	// its interval never fires. Cron schedules (protocol.ScheduleKindCron)
+	// state a cron expression and IANA time zone instead of an interval.
 	Schedule protocol.ScheduleSpec
 
 	// Disabled stores the job without scheduling it.
@@ -186,6 +201,149 @@ type JobSpec struct {
 	ResultMode protocol.ResultMode
 }
 
+// JobInfo describes one stored job as the scheduler reports it. The
+// definition fields mirror JobSpec, with Args left MessagePack-encoded.
+type JobInfo struct {
+	// JobUUID is the scheduler-side job identity.
+	JobUUID protocol.JobUUID
+
+	// Key is the client-chosen job key, scoped by ExecutorType.
+	Key string
+
+	// ExecutorType is the executor pool the job runs on.
+	ExecutorType protocol.ExecutorType
+
+	// Function identifies the target function.
+	Function protocol.FunctionSpec
+
+	// Args is the MessagePack-encoded function argument value. ListJobs
+	// leaves it empty; GetJob returns it.
+	Args []byte
+
+	// Schedule defines when the job runs.
+	Schedule protocol.ScheduleSpec
+
+	// Paused reports a job stored without being scheduled, whether paused
+	// by PauseJob or upserted with JobSpec.Disabled set.
+	Paused bool
+
+	// Backfill configures missed-occurrence handling.
+	Backfill protocol.BackfillSpec
+
+	// Retry configures function-error retries.
+	Retry protocol.RetrySpec
+
+	// Overlap selects what happens when occurrences overlap.
+	Overlap protocol.OverlapPolicy
+
+	// Pin constrains which executors may run the job.
+	Pin protocol.PinSpec
+
+	// ResultMode selects what happens to the final execution result.
+	ResultMode protocol.ResultMode
+
+	// Submitter is the instance whose upsert stored the current definition.
+	Submitter protocol.InstanceID
+
+	// SkippedOccurrences counts the missed occurrences backfill left out.
+	SkippedOccurrences uint64
+
+	// NextRunAt is when the job's earliest pending occurrence becomes due;
+	// the zero time when nothing is pending, as for a paused job.
+	NextRunAt time.Time
+
+	// CreatedAt is when the job was first stored.
+	CreatedAt time.Time
+
+	// UpdatedAt is when the job definition last changed.
+	UpdatedAt time.Time
+}
+
+// ExecutionQuery selects one page of the execution history of one job.
+type ExecutionQuery struct {
+	// ExecutorType and Key address the job as DeleteJob does; an empty
+	// executor type addresses the scheduler's own.
+	ExecutorType protocol.ExecutorType
+	Key          string
+
+	// Before resumes the history before the given execution, newest
+	// first: pass the last ExecutionID of the previous page. Zero starts
+	// from the newest.
+	Before protocol.ExecutionID
+
+	// Limit caps the page; zero takes the scheduler default, and the
+	// scheduler may cap it lower.
+	Limit uint32
+}
+
+// ExecutionPage is one page of a job's execution history, newest first.
+// More reports that older executions follow the last one.
+type ExecutionPage struct {
+	Executions []*ExecutionInfo
+	More       bool
+}
+
+// ExecutionInfo describes one execution of a job: one occurrence and how
+// far it got. Executions are kept until the scheduler's execution
+// retention purges them.
+type ExecutionInfo struct {
+	// ExecutionID identifies the execution; InvocationFromContext reports
+	// the same value to the running function.
+	ExecutionID protocol.ExecutionID
+
+	// ScheduledAt is the occurrence instant.
+	ScheduledAt time.Time
+
+	// State is the execution's lifecycle state.
+	State protocol.ExecutionState
+
+	// Backfilled reports an occurrence replayed after it was missed.
+	Backfilled bool
+
+	// AttemptCount counts deliveries to executors, retries and
+	// redispatches included.
+	AttemptCount uint32
+
+	// Instance is the executor of the latest attempt, empty when the
+	// execution was never dispatched.
+	Instance protocol.InstanceID
+
+	// StartedAt is when the first attempt was dispatched, and FinishedAt
+	// when the execution settled; each is the zero time until it happens.
+	StartedAt  time.Time
+	FinishedAt time.Time
+
+	// LastError is the error text of the latest failure.
+	LastError string
+
+	// WaitReason is why the execution stopped or waits where it does, such
+	// as the overlap policy skipping it or no executor holding its label.
+	WaitReason string
+}
+
+// AttemptInfo describes one delivery of an execution to one executor.
+type AttemptInfo struct {
+	// AttemptID identifies the attempt.
+	AttemptID protocol.AttemptID
+
+	// Number is the one-based ordinal of the attempt within its execution.
+	Number uint32
+
+	// Instance is the executor the attempt was delivered to.
+	Instance protocol.InstanceID
+
+	// State is the attempt's lifecycle state.
+	State protocol.AttemptState
+
+	// Error is the failure text the attempt settled with.
+	Error string
+
+	// DispatchedAt is when the attempt was sent, and UpdatedAt when its
+	// state last changed.
+	DispatchedAt time.Time
+	UpdatedAt    time.Time
+}
+
 // Void marks a registered function as returning no value. A function whose
 // result type is Void reports HasValue false with no payload instead of an
 // encoded empty struct, and DecodeResult on its delivered result answers
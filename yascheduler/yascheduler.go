@@ -79,11 +79,27 @@
 // repeating job's StartUnixNano stable across republishes rather than
 // re-anchoring it to the current time.
 //
+// # Job management
+//
+// ListJobs and GetJob report stored jobs, paused ones included, with the
+// instant each next runs; PauseJob, ResumeJob and TriggerNow act on one
+// job without republishing it. A paused job keeps its definition and its
+// pending occurrence, which is cancelled only if it comes due before the
+// job resumes. TriggerNow adds one run due immediately and leaves the
+// regular schedule alone.
+//
+// ListExecutions pages through one job's execution history, newest first:
+// each occurrence's state, when it was scheduled, started and finished,
+// how many attempts it took, where the latest ran and why it failed or
+// waited. ListAttempts details every attempt of one execution. History
+// reaches back as far as the scheduler's execution retention.
+//
 // # Persistence
 //
 // Local runs on an in-memory store by default, so jobs die with the
 // process. Supplying LocalConfig.Store - store/redisstore over any
-// redis-protocol server, Dragonfly included - makes jobs, executions,
+// redis-protocol server, Dragonfly included, or store/sqlstore over a
+// gorm database - makes jobs, executions,
 // attempts, and held results survive a restart: a new Local over the
 // same backend finds the stored jobs and fires them without a
 // re-upsert, abandoning interrupted attempts into redispatch. What does
@@ -115,7 +131,9 @@ type Scheduler interface {
 	// its executor type and returns the submission handle for it; an
 	// empty key submits an RPC-style one-shot keyed by the minted job
 	// UUID. Under ResultModeDeliver the submission awaits the delivered
-	// result.
+	// result. The trace context of ctx travels with the job, and every
+	// execution context continues it, so yalogger.LoggerFromContext logs
+	// on both sides share one trace_id.
 	UpsertJob(ctx context.Context, spec *JobSpec) (*Submission, yaerrors.Error)
 
 	// DeleteJob withdraws the job addressed by key within the given
@@ -130,6 +148,61 @@ type Scheduler interface {
 		key string,
 	) (bool, yaerrors.Error)
 
+	// ListJobs returns every stored job of the given executor type, paused
+	// ones included, in identifier order. Unlike the calls that address
+	// one job, an empty executor type lists every type. Listed jobs carry
+	// no Args; GetJob returns them.
+	ListJobs(ctx context.Context, executorType protocol.ExecutorType) ([]*JobInfo, yaerrors.Error)
+
+	// GetJob returns the job addressed by key within the given executor
+	// type; an empty executor type addresses this scheduler's own. An
+	// absent job answers ErrJobNotFound.
+	GetJob(
+		ctx context.Context,
+		executorType protocol.ExecutorType,
+		key string,
+	) (*JobInfo, yaerrors.Error)
+
+	// PauseJob stops scheduling the addressed job, reporting whether it
+	// was enabled. An occurrence that comes due while the job is paused is
+	// cancelled; work already running finishes on its own.
+	PauseJob(
+		ctx context.Context,
+		executorType protocol.ExecutorType,
+		key string,
+	) (bool, yaerrors.Error)
+
+	// ResumeJob schedules the addressed paused job again, reporting
+	// whether it was paused. Its backfill policy decides what the pause
+	// missed.
+	ResumeJob(
+		ctx context.Context,
+		executorType protocol.ExecutorType,
+		key string,
+	) (bool, yaerrors.Error)
+
+	// TriggerNow runs the addressed job once more, due immediately, and
+	// returns the execution it created; the regular schedule is left as
+	// it is. A paused job answers ErrJobPaused.
+	TriggerNow(
+		ctx context.Context,
+		executorType protocol.ExecutorType,
+		key string,
+	) (protocol.ExecutionID, yaerrors.Error)
+
+	// ListExecutions returns one page of the execution history of the job
+	// the query addresses, newest first, with a summary of each
+	// execution's attempts. An absent job answers ErrJobNotFound.
+	ListExecutions(ctx context.Context, query ExecutionQuery) (*ExecutionPage, yaerrors.Error)
+
+	// ListAttempts returns every attempt of one execution in
+	// attempt-number order. An absent execution answers
+	// ErrExecutionNotFound.
+	ListAttempts(
+		ctx context.Context,
+		executionID protocol.ExecutionID,
+	) ([]*AttemptInfo, yaerrors.Error)
+
 	// AnnounceLabels adds routing labels to the set this executor holds,
 	// so jobs pinned to them may route here.
 	AnnounceLabels(ctx context.Context, labels ...protocol.Label) yaerrors.Error
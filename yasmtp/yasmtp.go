@@ -82,7 +82,9 @@ func NewMailer(config *Config, log yalogger.Logger) *Mailer {
 
 // Send delivers message, retrying transient failures with an exponential
 // backoff (yabackoff) up to DefaultMaxAttempts times. A stale or broken
-// connection is transparently redialed.
+// connection is transparently redialed. Send logs through
+// yalogger.LoggerFromContext, so its lines carry the request fields and the
+// trace of ctx.
 //
 // Example:
 //
@@ -99,6 +101,8 @@ func (m *Mailer) Send(ctx context.Context, message Message) (err yaerrors.Error)
 		return buildErr.Wrap(logTag + " failed to build message")
 	}
 
+	log := yalogger.LoggerFromContext(ctx, m.log)
+
 	retryBackoff := yabackoff.NewExponential(
 		DefaultRetryInitialInterval,
 		DefaultRetryMultiplier,
@@ -124,7 +128,7 @@ func (m *Mailer) Send(ctx context.Context, message Message) (err yaerrors.Error)
 			return nil
 		}
 
-		m.log.
+		log.
 			WithField("attempt", attempt).
 			Warnf(logTag+" send attempt failed: %v", err)
 	}
@@ -282,7 +286,7 @@ func (m *Mailer) dial(ctx context.Context) (client *smtp.Client, err yaerrors.Er
 		}
 	}
 
-	m.log.Infof(logTag+" connected to %s", addr)
+	yalogger.LoggerFromContext(ctx, m.log).Infof(logTag+" connected to %s", addr)
 
 	return newClient, nil
 }
@@ -56,8 +56,10 @@ func (d *UpdateData) sequencingKeys() []string {
 
 // dispatch processes the update by checking filters and executing the appropriate handler.
 // It also supports nested routers by dispatching to sub-routers if no local route matches.
+// Every update runs in a span of its own, so its logs, and the scheduler jobs and mails its
+// handler starts with ctx, share one trace_id.
 func (r *Dispatcher) dispatch(ctx context.Context, deps *UpdateData) yaerrors.Error {
-	return r.dispatchRouter(ctx, r.MainRouter, deps)
+	return r.dispatchRouter(yalogger.ContextWithChildSpan(ctx), r.MainRouter, deps)
 }
 
 // dispatchRouter processes the update against the provided router tree without
@@ -76,7 +78,9 @@ func (r *Dispatcher) dispatchRouter(
 		strconv.FormatInt(deps.chatID, 10),
 	)
 
-	r.Log.Debugf(
+	log := yalogger.LoggerFromContext(ctx, r.Log)
+
+	log.Debugf(
 		"[YaTGBot] Processing update: %+v with entities: %+v",
 		deps.update,
 		deps.ent,
@@ -97,12 +101,12 @@ func (r *Dispatcher) dispatchRouter(
 				http.StatusInternalServerError,
 				err,
 				"[YaTGBot] failed to apply filters",
-				r.Log,
+				log,
 			)
 		}
 
 		if !ok {
-			r.Log.Debugf("[YaTGBot] Filters not passed for %T", deps.update)
+			log.Debugf("[YaTGBot] Filters not passed for %T", deps.update)
 
 			continue
 		}
@@ -117,14 +121,14 @@ func (r *Dispatcher) dispatchRouter(
 						http.StatusInternalServerError,
 						err,
 						"[YaTGBot] failed to derive localizer",
-						r.Log,
+						log,
 					)
 				}
 
 				localizer = r.Localizer
 			}
 
-			r.Log.Debugf(
+			log.Debugf(
 				"[YaTGBot] Using user %d language: %s",
 				deps.userID,
 				user.LangCode,
@@ -137,7 +141,7 @@ func (r *Dispatcher) dispatchRouter(
 			UserID:       deps.userID,
 			Peer:         deps.inputPeer,
 			StateStorage: userFSMStorage,
-			Log:          r.Log,
+			Log:          log,
 			Dispatcher:   r.MessageDispatcher,
 			Localizer:    localizer,
 			Client:       r.Client,